@@ -0,0 +1,26 @@
+package main
+
+import "flag"
+
+// parseArgs parses the flags in args with fs, allowing them to come before,
+// after or between the positional arguments, which are returned in order.
+// Everything after a -- argument is positional, for file names that start
+// with a dash.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		// Parse stops after consuming a --, or at the first positional argument
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
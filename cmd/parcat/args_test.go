@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantFiles  []string
+		wantFormat string
+		wantLimit  int
+		wantSchema bool
+		wantErr    bool
+	}{
+		{
+			name:       "flags before file",
+			args:       []string{"-f", "csv", "-limit", "5", "data.parquet"},
+			wantFiles:  []string{"data.parquet"},
+			wantFormat: "csv",
+			wantLimit:  5,
+		},
+		{
+			name:       "flags after file",
+			args:       []string{"data.parquet", "-f", "csv", "-limit", "5"},
+			wantFiles:  []string{"data.parquet"},
+			wantFormat: "csv",
+			wantLimit:  5,
+		},
+		{
+			name:       "flags on both sides",
+			args:       []string{"-f=csv", "data.parquet", "--limit=5"},
+			wantFiles:  []string{"data.parquet"},
+			wantFormat: "csv",
+			wantLimit:  5,
+		},
+		{
+			name:       "boolean flag after file",
+			args:       []string{"data.parquet", "-schema"},
+			wantFiles:  []string{"data.parquet"},
+			wantFormat: "jsonl",
+			wantSchema: true,
+		},
+		{
+			name:       "several files",
+			args:       []string{"a.parquet", "-schema", "b.parquet"},
+			wantFiles:  []string{"a.parquet", "b.parquet"},
+			wantFormat: "jsonl",
+			wantSchema: true,
+		},
+		{
+			name:       "standard input",
+			args:       []string{"-", "-f", "csv"},
+			wantFiles:  []string{"-"},
+			wantFormat: "csv",
+		},
+		{
+			name:       "no files",
+			args:       []string{"-f", "csv"},
+			wantFormat: "csv",
+		},
+		{
+			name:       "arguments after -- are files",
+			args:       []string{"-f", "csv", "--", "-odd.parquet", "-limit"},
+			wantFiles:  []string{"-odd.parquet", "-limit"},
+			wantFormat: "csv",
+		},
+		{
+			name:       "-- after a file",
+			args:       []string{"data.parquet", "--", "-schema"},
+			wantFiles:  []string{"data.parquet", "-schema"},
+			wantFormat: "jsonl",
+		},
+		{
+			name:    "unknown flag after file",
+			args:    []string{"data.parquet", "-bogus"},
+			wantErr: true,
+		},
+		{
+			name:    "missing flag value after file",
+			args:    []string{"data.parquet", "-f"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("parcat", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			format := fs.String("f", "jsonl", "")
+			limit := fs.Int("limit", 0, "")
+			schema := fs.Bool("schema", false, "")
+
+			files, err := parseArgs(fs, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(files, tt.wantFiles) {
+				t.Errorf("files = %q, want %q", files, tt.wantFiles)
+			}
+			if *format != tt.wantFormat || *limit != tt.wantLimit || *schema != tt.wantSchema {
+				t.Errorf("flags = -f %s -limit %d -schema %v, want -f %s -limit %d -schema %v",
+					*format, *limit, *schema, tt.wantFormat, tt.wantLimit, tt.wantSchema)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vegasq/parcat/query"
+)
+
+// stringListFlag collects the values of a repeatable string flag
+type stringListFlag []string
+
+func (s *stringListFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// checkAssertions evaluates each assertion over rows, writing one PASS or
+// FAIL line per assertion to w. Returns true if every assertion holds.
+func checkAssertions(w io.Writer, assertions []*query.Assertion, rows []map[string]interface{}) (bool, error) {
+	allPassed := true
+	for _, assertion := range assertions {
+		ok, detail, err := assertion.Check(rows)
+		if err != nil {
+			return false, fmt.Errorf("assertion %q: %w", assertion.Text, err)
+		}
+
+		if ok {
+			fmt.Fprintf(w, "PASS: %s\n", assertion.Text)
+		} else {
+			fmt.Fprintf(w, "FAIL: %s (%s)\n", assertion.Text, detail)
+			allPassed = false
+		}
+	}
+	return allPassed, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/vegasq/parcat/query"
+)
+
+func TestStringListFlag(t *testing.T) {
+	var values stringListFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&values, "assert", "")
+
+	if err := fs.Parse([]string{"-assert", "COUNT(*) > 0", "-assert", "MIN(age) >= 0"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(values) != 2 || values[0] != "COUNT(*) > 0" || values[1] != "MIN(age) >= 0" {
+		t.Errorf("got %q, want both assertions in order", values)
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "Alice", "age": int64(30)},
+		{"name": "Bob", "age": int64(25)},
+	}
+
+	parse := func(texts ...string) []*query.Assertion {
+		t.Helper()
+		var assertions []*query.Assertion
+		for _, text := range texts {
+			assertion, err := query.ParseAssertion(text)
+			if err != nil {
+				t.Fatalf("ParseAssertion(%q) error = %v", text, err)
+			}
+			assertions = append(assertions, assertion)
+		}
+		return assertions
+	}
+
+	t.Run("all pass", func(t *testing.T) {
+		var buf bytes.Buffer
+		passed, err := checkAssertions(&buf, parse("COUNT(*) > 0", "MIN(age) >= 0"), rows)
+		if err != nil {
+			t.Fatalf("checkAssertions() error = %v", err)
+		}
+		if !passed {
+			t.Errorf("expected all assertions to pass, output:\n%s", buf.String())
+		}
+		want := "PASS: COUNT(*) > 0\nPASS: MIN(age) >= 0\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("failure is reported with values", func(t *testing.T) {
+		var buf bytes.Buffer
+		passed, err := checkAssertions(&buf, parse("COUNT(*) > 0", "MAX(age) < 30"), rows)
+		if err != nil {
+			t.Fatalf("checkAssertions() error = %v", err)
+		}
+		if passed {
+			t.Errorf("expected a failed assertion")
+		}
+		want := "PASS: COUNT(*) > 0\nFAIL: MAX(age) < 30 (got 30 < 30)\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("evaluation error", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := checkAssertions(&buf, parse("age > 0"), rows); err == nil {
+			t.Errorf("expected error for column outside aggregate")
+		}
+	})
+}
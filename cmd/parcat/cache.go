@@ -0,0 +1,276 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vegasq/parcat/query"
+	"github.com/vegasq/parcat/reader"
+)
+
+func init() {
+	// Row values are stored as interface{}, so gob needs the concrete
+	// container and time types registered up front
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+	gob.Register(time.Time{})
+	gob.Register(emptyValue{})
+}
+
+// emptyValue stands in for an empty list, map or byte slice in a cache
+// entry, since gob decodes those as nil
+type emptyValue struct {
+	Kind string
+}
+
+// encodeValue replaces empty containers in a value with emptyValue markers
+func encodeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return emptyValue{Kind: "list"}
+		}
+		encoded := make([]interface{}, len(v))
+		for i, elem := range v {
+			encoded[i] = encodeValue(elem)
+		}
+		return encoded
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return emptyValue{Kind: "map"}
+		}
+		encoded := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			encoded[key] = encodeValue(elem)
+		}
+		return encoded
+	case []byte:
+		if len(v) == 0 {
+			return emptyValue{Kind: "bytes"}
+		}
+	}
+	return value
+}
+
+// decodeValue reverses encodeValue
+func decodeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case emptyValue:
+		switch v.Kind {
+		case "list":
+			return []interface{}{}
+		case "map":
+			return map[string]interface{}{}
+		case "bytes":
+			return []byte{}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = decodeValue(elem)
+		}
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = decodeValue(elem)
+		}
+	}
+	return value
+}
+
+// nondeterministicFunctions lists functions whose results change between runs.
+// Queries calling them are never cached.
+var nondeterministicFunctions = map[string]bool{
+	"NOW":          true,
+	"CURRENT_DATE": true,
+	"CURRENT_TIME": true,
+	"RANDOM":       true,
+}
+
+// fileStamp identifies the version of an input file that a cached result was computed from
+type fileStamp struct {
+	Path    string
+	Size    int64
+	ModTime int64 // nanoseconds since the Unix epoch
+}
+
+// cacheEntry is the on-disk form of a cached query result
+type cacheEntry struct {
+	Query  string                 // normalized query, guards against hash collisions
+	Inputs map[string][]fileStamp // file pattern -> files it resolved to when the result was computed
+	Rows   []map[string]interface{}
+}
+
+// resultCache stores query results on disk, keyed by the normalized query text.
+//
+// An entry is only used if every input pattern still resolves to the same
+// files and none of those files changed size or modification time.
+type resultCache struct {
+	dir string
+}
+
+// newResultCache creates a result cache in dir, creating the directory if needed
+func newResultCache(dir string) (*resultCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &resultCache{dir: dir}, nil
+}
+
+// path returns the file holding the entry for a normalized query
+func (c *resultCache) path(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".gob")
+}
+
+// get returns the cached rows for a normalized query if the entry exists
+// and all of its input files are unchanged
+func (c *resultCache) get(normalized string) ([]map[string]interface{}, bool) {
+	f, err := os.Open(c.path(normalized))
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = f.Close() }()
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, false
+	}
+	if entry.Query != normalized || !inputsUnchanged(entry.Inputs) {
+		return nil, false
+	}
+
+	for _, row := range entry.Rows {
+		for col, value := range row {
+			row[col] = decodeValue(value)
+		}
+	}
+	return entry.Rows, true
+}
+
+// put stores rows for a normalized query. The file is written to a temporary
+// name first so concurrent readers never see a partial entry.
+func (c *resultCache) put(normalized string, inputs map[string][]fileStamp, rows []map[string]interface{}) error {
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	encoded := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		encoded[i] = make(map[string]interface{}, len(row))
+		for col, value := range row {
+			encoded[i][col] = encodeValue(value)
+		}
+	}
+
+	entry := cacheEntry{Query: normalized, Inputs: inputs, Rows: encoded}
+	if err := gob.NewEncoder(tmp).Encode(&entry); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(normalized)); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+// inputsUnchanged reports whether every pattern still resolves to the same
+// files with the same size and modification time
+func inputsUnchanged(inputs map[string][]fileStamp) bool {
+	for pattern, stamps := range inputs {
+		files, err := reader.ExpandPattern(pattern)
+		if err != nil || len(files) != len(stamps) {
+			return false
+		}
+		for i, file := range files {
+			current, err := stampFile(file)
+			if err != nil || current != stamps[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stampFile records the size and modification time of a file
+func stampFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{Path: path, Size: info.Size(), ModTime: info.ModTime().UnixNano()}, nil
+}
+
+// inputRecorder collects the files read while a query executes
+type inputRecorder struct {
+	inputs map[string][]fileStamp
+	err    error
+}
+
+// record is a reader.ReadOptions.OnFiles callback. Files are stamped before
+// they are read, so a file modified during the query invalidates the entry.
+func (r *inputRecorder) record(pattern string, files []string) {
+	if r.inputs == nil {
+		r.inputs = make(map[string][]fileStamp)
+	}
+
+	stamps := make([]fileStamp, 0, len(files))
+	for _, file := range files {
+		stamp, err := stampFile(file)
+		if err != nil {
+			r.err = err
+			return
+		}
+		stamps = append(stamps, stamp)
+	}
+	r.inputs[pattern] = stamps
+}
+
+// normalizeQuery returns a canonical form of a query for use as a cache key.
+// Keywords are upper-cased and whitespace is collapsed; identifiers and
+// string literals are kept as written, quoted so that neither can be
+// mistaken for the other or for several tokens. extra holds settings that
+// change the result, such as the TABLESAMPLE seed.
+//
+// Returns false if the query must not be cached because its result can
+// change between runs on the same input.
+func normalizeQuery(queryText string, seeded bool, extra ...string) (string, bool) {
+	var parts []string
+	for _, tok := range query.Tokenize(queryText) {
+		switch tok.Type {
+		case query.TokenEOF:
+			continue
+		case query.TokenError:
+			return "", false
+		case query.TokenString:
+			parts = append(parts, "'"+strings.ReplaceAll(tok.Value, "'", "''")+"'")
+		case query.TokenIdent:
+			if nondeterministicFunctions[strings.ToUpper(tok.Value)] {
+				return "", false
+			}
+			parts = append(parts, query.QuoteIdentifier(tok.Value))
+		case query.TokenNumber:
+			parts = append(parts, tok.Value)
+		case query.TokenTablesample:
+			// Unseeded samples differ on every run
+			if !seeded {
+				return "", false
+			}
+			parts = append(parts, strings.ToUpper(tok.Value))
+		default:
+			parts = append(parts, strings.ToUpper(tok.Value))
+		}
+	}
+
+	return strings.Join(append(parts, extra...), " "), true
+}
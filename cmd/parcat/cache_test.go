@@ -0,0 +1,216 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vegasq/parcat/reader"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		seeded    bool
+		wantSame  bool
+		wantCache bool
+	}{
+		{
+			name:      "keyword case and whitespace are ignored",
+			a:         "select name from 'data.parquet' where age > 30",
+			b:         "SELECT  name\n FROM 'data.parquet'   WHERE age > 30",
+			wantSame:  true,
+			wantCache: true,
+		},
+		{
+			name:      "string literals are compared exactly",
+			a:         "SELECT * FROM 'data.parquet' WHERE name = 'alice'",
+			b:         "SELECT * FROM 'data.parquet' WHERE name = 'Alice'",
+			wantSame:  false,
+			wantCache: true,
+		},
+		{
+			name:      "column names keep their case",
+			a:         "SELECT Name FROM 'data.parquet'",
+			b:         "SELECT name FROM 'data.parquet'",
+			wantSame:  false,
+			wantCache: true,
+		},
+		{
+			name:      "quoted identifiers are not split",
+			a:         `SELECT "a b" FROM 'data.parquet'`,
+			b:         "SELECT a b FROM 'data.parquet'",
+			wantSame:  false,
+			wantCache: true,
+		},
+		{
+			name:      "quoted identifiers differ from strings",
+			a:         `SELECT "a b" FROM 'data.parquet'`,
+			b:         "SELECT 'a b' FROM 'data.parquet'",
+			wantSame:  false,
+			wantCache: true,
+		},
+		{
+			name:      "needlessly quoted identifiers are the same",
+			a:         `SELECT "name" FROM 'data.parquet'`,
+			b:         "SELECT name FROM 'data.parquet'",
+			wantSame:  true,
+			wantCache: true,
+		},
+		{
+			name:      "nondeterministic functions are not cached",
+			a:         "SELECT NOW() as ts FROM 'data.parquet'",
+			wantCache: false,
+		},
+		{
+			name:      "unseeded TABLESAMPLE is not cached",
+			a:         "SELECT * FROM 'data.parquet' TABLESAMPLE (10 PERCENT)",
+			wantCache: false,
+		},
+		{
+			name:      "seeded TABLESAMPLE is cached",
+			a:         "SELECT * FROM 'data.parquet' TABLESAMPLE (10 PERCENT)",
+			seeded:    true,
+			wantCache: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyA, ok := normalizeQuery(tt.a, tt.seeded)
+			if ok != tt.wantCache {
+				t.Fatalf("normalizeQuery(%q) cacheable = %v, want %v", tt.a, ok, tt.wantCache)
+			}
+			if tt.b == "" {
+				return
+			}
+			keyB, _ := normalizeQuery(tt.b, tt.seeded)
+			if (keyA == keyB) != tt.wantSame {
+				t.Errorf("keys %q and %q: same = %v, want %v", keyA, keyB, keyA == keyB, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	dataDir := t.TempDir()
+	createTestParquetFile(t, dataDir, "a.parquet", []TestRow{{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0}})
+	createTestParquetFile(t, dataDir, "b.parquet", []TestRow{{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0}})
+	pattern := filepath.Join(dataDir, "*.parquet")
+
+	cache, err := newResultCache(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("newResultCache() error = %v", err)
+	}
+
+	key, ok := normalizeQuery("SELECT * FROM '"+pattern+"'", false)
+	if !ok {
+		t.Fatalf("query should be cacheable")
+	}
+
+	// populate stores a fresh result computed from the current files
+	populate := func() {
+		t.Helper()
+		var recorder inputRecorder
+		rows, err := reader.ReadMultipleFilesWithOptions(pattern, reader.ReadOptions{OnFiles: recorder.record})
+		if err != nil {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+		}
+		if err := cache.put(key, recorder.inputs, rows); err != nil {
+			t.Fatalf("put() error = %v", err)
+		}
+	}
+
+	if _, hit := cache.get(key); hit {
+		t.Fatalf("get() on empty cache should miss")
+	}
+
+	populate()
+	rows, hit := cache.get(key)
+	if !hit {
+		t.Fatalf("get() after put should hit")
+	}
+	if len(rows) != 2 {
+		t.Fatalf("cached result has %d rows, want 2", len(rows))
+	}
+	if rows[0]["id"] != int64(1) || rows[0]["name"] != "Alice" {
+		t.Errorf("cached row lost its values or types: %#v", rows[0])
+	}
+
+	t.Run("modified file invalidates", func(t *testing.T) {
+		populate()
+		future := time.Now().Add(time.Hour)
+		if err := os.Chtimes(filepath.Join(dataDir, "a.parquet"), future, future); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+		if _, hit := cache.get(key); hit {
+			t.Errorf("get() should miss after an input file changed")
+		}
+	})
+
+	t.Run("new file matching glob invalidates", func(t *testing.T) {
+		populate()
+		createTestParquetFile(t, dataDir, "c.parquet", []TestRow{{ID: 3, Name: "Charlie", Age: 35, Salary: 60000.0}})
+		if _, hit := cache.get(key); hit {
+			t.Errorf("get() should miss after a new file matched the pattern")
+		}
+	})
+
+	t.Run("removed file invalidates", func(t *testing.T) {
+		populate()
+		if err := os.Remove(filepath.Join(dataDir, "b.parquet")); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, hit := cache.get(key); hit {
+			t.Errorf("get() should miss after an input file was removed")
+		}
+	})
+}
+
+func TestResultCache_PreservesValues(t *testing.T) {
+	cache, err := newResultCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("newResultCache() error = %v", err)
+	}
+
+	rows := []map[string]interface{}{
+		{
+			"count":  int32(7),
+			"empty":  "",
+			"list":   []interface{}{},
+			"nested": map[string]interface{}{"inner": []interface{}{}},
+			"null":   nil,
+		},
+	}
+
+	if err := cache.put("key", nil, rows); err != nil {
+		t.Fatalf("put() error = %v", err)
+	}
+	got, hit := cache.get("key")
+	if !hit {
+		t.Fatalf("get() should hit")
+	}
+
+	row := got[0]
+	if row["count"] != int32(7) {
+		t.Errorf("count = %#v, want int32(7)", row["count"])
+	}
+	if row["empty"] != "" {
+		t.Errorf("empty = %#v, want empty string", row["empty"])
+	}
+	if list, ok := row["list"].([]interface{}); !ok || list == nil || len(list) != 0 {
+		t.Errorf("list = %#v, want empty list", row["list"])
+	}
+	nested, ok := row["nested"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("nested = %#v, want map", row["nested"])
+	}
+	if inner, ok := nested["inner"].([]interface{}); !ok || inner == nil {
+		t.Errorf("nested inner = %#v, want empty list", nested["inner"])
+	}
+	if value, exists := row["null"]; !exists || value != nil {
+		t.Errorf("null = %#v (exists %v), want nil", value, exists)
+	}
+}
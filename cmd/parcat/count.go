@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/vegasq/parcat/query"
+	"github.com/vegasq/parcat/reader"
+)
+
+// resultCount returns the number of rows -count reports: the rows of the
+// query result or, without a query, of the input files, with the -limit
+// flag applied. Query errors exit the process like other query runs.
+func resultCount(q *query.Query, queryText, filename string) (int64, error) {
+	if q != nil {
+		return int64(len(loadRows(q, queryText, filename, limitPushdown(q, *limitFlag)))), nil
+	}
+
+	n, err := countFileRows(filename)
+	if err != nil {
+		return 0, err
+	}
+	if *limitFlag > 0 && n > int64(*limitFlag) {
+		n = int64(*limitFlag)
+	}
+	return n, nil
+}
+
+// countFileRows adds up the row counts recorded in the footers of the files
+// a path or glob matches, without reading any rows
+func countFileRows(pattern string) (int64, error) {
+	files, err := reader.ExpandPattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, file := range files {
+		r, err := reader.NewReader(file)
+		if err != nil {
+			return 0, err
+		}
+		meta, err := r.Metadata()
+		_ = r.Close()
+		if err != nil {
+			return 0, err
+		}
+		total += meta.NumRows
+	}
+	return total, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/vegasq/parcat/query"
+)
+
+func TestResultCount(t *testing.T) {
+	dir := t.TempDir()
+	rows := []TestRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0},
+		{ID: 3, Name: "Charlie", Age: 35, Salary: 60000.0},
+	}
+	testFile := createTestParquetFile(t, dir, "a.parquet", rows)
+	createTestParquetFile(t, dir, "b.parquet", rows[:2])
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		file     string
+		limit    int
+		want     int64
+	}{
+		{name: "file without query", file: testFile, want: 3},
+		{name: "glob without query", file: filepath.Join(dir, "*.parquet"), want: 5},
+		{name: "limit", file: testFile, limit: 2, want: 2},
+		{name: "query without filter", queryTpl: "select * from '%s'", want: 3},
+		{name: "where filter", queryTpl: "select * from '%s' where age > 28", want: 2},
+		{name: "no matches", queryTpl: "select * from '%s' where age > 100", want: 0},
+		{name: "query with limit flag", queryTpl: "select * from '%s' where age > 28", limit: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldLimit := *limitFlag
+			defer func() { *limitFlag = oldLimit }()
+			*limitFlag = tt.limit
+
+			// Like main, a query reads the table named in FROM
+			var q *query.Query
+			var queryText string
+			file := tt.file
+			if tt.queryTpl != "" {
+				queryText = fmt.Sprintf(tt.queryTpl, testFile)
+				var err error
+				if q, err = query.Parse(queryText); err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				file = q.TableName
+			}
+
+			got, err := resultCount(q, queryText, file)
+			if err != nil {
+				t.Fatalf("resultCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resultCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/vegasq/parcat/output"
+	"github.com/vegasq/parcat/query"
+	"github.com/vegasq/parcat/reader"
+	"github.com/vegasq/parcat/writer"
+)
+
+var (
+	queryFlag    = flag.String("q", "", "SQL query (e.g., \"select * from file.parquet where age > 30\")")
+	queryFile    = flag.String("qf", "", "Read the SQL query from this file instead of -q")
+	formatFlag   = flag.String("f", "jsonl", "Output format: json, jsonl, csv, table, markdown (or md)")
+	limitFlag    = flag.Int("limit", 0, "Limit number of rows (0 = unlimited)")
+	schemaFlag   = flag.Bool("schema", false, "Show schema information instead of data")
+	countFlag    = flag.Bool("count", false, "Print only the number of result rows instead of the rows")
+	replFlag     = flag.Bool("repl", false, "Start an interactive prompt for running SQL statements against the file")
+	metaFlag     = flag.Bool("meta", false, "Show file metadata (row counts, row groups, writer, key/value metadata) instead of data")
+	progressFlag = flag.Bool("progress", false, "Show read progress on stderr (only when stderr is a terminal)")
+	seedFlag     = flag.Int64("seed", 0, "Random seed for TABLESAMPLE, making samples reproducible")
+	cacheDirFlag = flag.String("cache-dir", "", "Cache query results in this directory, reused until an input file changes")
+	noCacheFlag  = flag.Bool("no-cache", false, "Disable the result cache even if -cache-dir is set")
+	delimFlag    = flag.String("delimiter", ",", "Field delimiter for -f csv, a single character or \\t for tab")
+	nullFlag     = flag.String("null", "", "Text written for NULL values in -f csv output, e.g. \\N")
+	quoteFlag    = flag.String("quote", "minimal", "Which -f csv fields are quoted: minimal, always")
+	widthFlag    = flag.Int("max-width", output.DefaultTableMaxWidth, "Truncate -f table cells wider than this with an ellipsis (0 = no limit)")
+	rawFlag      = flag.Bool("raw", false, "Print bare values of a single-column result, one per line (overrides -f)")
+	perFileFlag  = flag.Bool("per-file", false, "Run the query separately against each file matched by a glob and label the results by file")
+	nanFlag      = flag.String("nan", "null", "How JSON output writes NaN and infinite floats: null, string, error")
+	versionFlag  = flag.Bool("version", false, "Print the parcat, parquet-go and Go versions and exit")
+	inferFlag    = flag.Bool("infer-types", false, "Convert string columns that only hold numbers, booleans or timestamps to those types")
+	strictFlag   = flag.Bool("strict-joins", false, "Fail a JOIN on a column both sides have instead of renaming it")
+	aliasFlag    = flag.Bool("where-aliases", false, "Let WHERE refer to SELECT aliases, as in where total > 100 with price * qty as total (not standard SQL)")
+	compactFlag  = flag.Bool("compact", false, "Merge the files matched by a glob into the single parquet file given by -o")
+	outFlag      = flag.String("o", "", "Write output to this file instead of stdout; the merged file for -compact")
+	compressFlag = flag.String("compress", "", "Compress output: gzip, zstd, none (default: gzip for an -o file ending in .gz, zstd for .zst)")
+	rowGroupFlag = flag.Int64("row-group-size", 0, "Maximum rows per row group written by -compact (0 = parquet-go default)")
+	codecFlag    = flag.String("compression", "snappy", "Compression codec used by -compact: "+strings.Join(writer.CompressionNames(), ", "))
+	maxRowsFlag  = flag.Int64("max-rows", query.DefaultMaxIntermediateRows, "Abort a query whose JOIN or aggregation produces more rows than this (0 = no limit)")
+	timeoutFlag  = flag.Duration("timeout", 0, "Abort a query running longer than this, e.g. 30s or 2m; in -repl, each statement (0 = no limit)")
+)
+
+// assertFlag holds the -assert expressions, which may be given multiple times
+var assertFlag stringListFlag
+
+func init() {
+	flag.BoolVar(replFlag, "i", false, "Shorthand for -repl")
+	flag.Var(&assertFlag, "assert", "Check an aggregate expression over the result, e.g. \"COUNT(*) > 0\" (repeatable); exits non-zero if any fails")
+}
+
+// csvDelimiter and csvQuoting hold the -delimiter and -quote flags,
+// validated before any data is read
+var (
+	csvDelimiter = ','
+	csvQuoting   output.CSVQuoting
+)
+
+// readOptions holds the reader options derived from command line flags.
+// It is applied to every table read by the CLI.
+var readOptions reader.ReadOptions
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/vegasq/parcat/query"
+)
+
+// limitPushdown returns how many rows the main table read may stop after
+// to satisfy the query's LIMIT or, without one, the -limit flag, or 0 if the
+// whole input must be read.
+//
+// Stopping early is only safe when every row read produces exactly one
+// output row in input order (see query.StreamsRows). An OFFSET is read past,
+// since it is applied before either limit.
+func limitPushdown(q *query.Query, limit int) int64 {
+	// A SQL LIMIT takes precedence over the flag
+	if q != nil && q.Limit != nil {
+		return query.PushdownLimit(q)
+	}
+
+	if limit <= 0 {
+		return 0
+	}
+	if q == nil {
+		return int64(limit)
+	}
+	if !query.StreamsRows(q) {
+		return 0
+	}
+
+	rows := int64(limit)
+	if q.Offset != nil {
+		rows += *q.Offset
+	}
+	return rows
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/vegasq/parcat/query"
+)
+
+func TestLimitPushdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  int64
+	}{
+		{"no query", "", 10, 10},
+		{"no limit", "", 0, 0},
+		{"select star", "select * from data.parquet", 10, 10},
+		{"projection and functions", "select name, UPPER(name) as n from data.parquet", 10, 10},
+		{"offset is read past", "select * from data.parquet offset 5", 10, 15},
+		{"rows sample", "select * from data.parquet tablesample (100 rows)", 10, 10},
+		{"sql limit wins", "select * from data.parquet limit 3", 10, 3},
+		{"sql limit without flag", "select * from data.parquet limit 3 offset 2", 0, 5},
+		{"sql limit with where", "select * from data.parquet where age > 30 limit 3", 10, 0},
+		{"sql limit with order by", "select * from data.parquet order by age limit 3", 0, 0},
+		{"where", "select * from data.parquet where age > 30", 10, 0},
+		{"order by", "select * from data.parquet order by age", 10, 0},
+		{"group by", "select name, COUNT(*) from data.parquet group by name", 10, 0},
+		{"aggregate", "select COUNT(*) from data.parquet", 10, 0},
+		{"distinct", "select distinct name from data.parquet", 10, 0},
+		{"window", "select name, ROW_NUMBER() OVER (ORDER BY age) as rn from data.parquet", 10, 0},
+		{"join", "select * from a.parquet a join b.parquet b on a.id = b.id", 10, 0},
+		{"percent sample", "select * from data.parquet tablesample (10 percent)", 10, 0},
+		{"cte", "with t as (select * from data.parquet) select * from t", 10, 0},
+		{"from subquery", "select * from (select * from data.parquet) s", 10, 0},
+		{"union", "select * from a.parquet union all select * from b.parquet", 10, 0},
+		{"scalar subquery", "select name, (select MAX(age) from data.parquet) as m from data.parquet", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q *query.Query
+			if tt.query != "" {
+				var err error
+				q, err = query.Parse(tt.query)
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+			}
+
+			if got := limitPushdown(q, tt.limit); got != tt.want {
+				t.Errorf("limitPushdown() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,37 +13,97 @@ import (
 	"github.com/vegasq/parcat/output"
 	"github.com/vegasq/parcat/query"
 	"github.com/vegasq/parcat/reader"
-)
-
-var (
-	queryFlag  = flag.String("q", "", "SQL query (e.g., \"select * from file.parquet where age > 30\")")
-	formatFlag = flag.String("f", "jsonl", "Output format: json, jsonl, csv")
-	limitFlag  = flag.Int("limit", 0, "Limit number of rows (0 = unlimited)")
-	schemaFlag = flag.Bool("schema", false, "Show schema information instead of data")
+	"github.com/vegasq/parcat/writer"
 )
 
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options] <file.parquet>\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "A tool to read and query Parquet files.\n\n")
-		fmt.Fprintf(os.Stderr, "IMPORTANT: All flags must come BEFORE file arguments.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags may come before or after the file; arguments after -- are always files.\n")
+		fmt.Fprintf(os.Stderr, "Use - as the file to read it from standard input.\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s data.parquet\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -f csv data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s data.parquet -f csv -limit 10\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  cat data.parquet | %s -f csv -\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -q \"select * from data.parquet where age > 30\" data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -qf report.sql data.parquet\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --schema data.parquet\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -f csv --schema data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -meta data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -repl data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -assert \"COUNT(*) > 0\" -assert \"MIN(age) >= 0\" data.parquet\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -compact -o merged.parquet 'shards/*.parquet'\n", os.Args[0])
 	}
 
-	flag.Parse()
+	// Unlike flag.Parse, accept flags after the file argument too
+	args, err := parseArgs(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+
+	if *versionFlag {
+		fmt.Print(versionInfo())
+		os.Exit(0)
+	}
 
 	// Validate flag values
 	if *limitFlag < 0 {
 		fmt.Fprintf(os.Stderr, "Error: -limit must be non-negative, got %d\n", *limitFlag)
 		os.Exit(1)
 	}
+	if *maxRowsFlag < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-rows must be non-negative, got %d\n", *maxRowsFlag)
+		os.Exit(1)
+	}
+	if *timeoutFlag < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -timeout must be non-negative, got %v\n", *timeoutFlag)
+		os.Exit(1)
+	}
+
+	// A query file stands in for -q, so it is checked like one from here on
+	if *queryFile != "" {
+		if *queryFlag != "" {
+			fmt.Fprintf(os.Stderr, "Error: -q and -qf cannot be used together\n")
+			os.Exit(1)
+		}
+		text, err := readQueryFile(*queryFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		*queryFlag = text
+	}
+
+	if *compactFlag {
+		handleCompactMode(args)
+		return
+	}
+
+	nanHandling, err := output.ParseNaNHandling(*nanFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -nan: %v\n", err)
+		os.Exit(1)
+	}
+
+	if csvDelimiter, err = output.ParseDelimiter(*delimFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -delimiter: %v\n", err)
+		os.Exit(1)
+	}
+	if csvQuoting, err = output.ParseCSVQuoting(*quoteFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -quote: %v\n", err)
+		os.Exit(1)
+	}
+	outputCompression = output.CompressionForPath(*outFlag)
+	if *compressFlag != "" {
+		if outputCompression, err = output.ParseCompression(*compressFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -compress: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Validate flag combinations
 	if *schemaFlag && *queryFlag != "" {
@@ -48,10 +111,77 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *schemaFlag && len(assertFlag) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --schema and -assert cannot be used together\n")
+		os.Exit(1)
+	}
+
+	if *perFileFlag && (*schemaFlag || len(assertFlag) > 0) {
+		fmt.Fprintf(os.Stderr, "Error: --per-file cannot be used with --schema or -assert\n")
+		os.Exit(1)
+	}
+
+	if *metaFlag && (*schemaFlag || *queryFlag != "" || len(assertFlag) > 0 || *perFileFlag) {
+		fmt.Fprintf(os.Stderr, "Error: -meta cannot be used with --schema, -q, -assert or --per-file\n")
+		os.Exit(1)
+	}
+
+	if *countFlag && (*schemaFlag || *metaFlag || len(assertFlag) > 0 || *perFileFlag) {
+		fmt.Fprintf(os.Stderr, "Error: -count cannot be used with --schema, -meta, -assert or --per-file\n")
+		os.Exit(1)
+	}
+
+	if *replFlag && (*queryFlag != "" || *schemaFlag || *metaFlag || len(assertFlag) > 0 || *perFileFlag || *countFlag || *outFlag != "") {
+		fmt.Fprintf(os.Stderr, "Error: -repl cannot be used with -q, --schema, -meta, -assert, --per-file, -count or -o\n")
+		os.Exit(1)
+	}
+
+	// Parse assertions up front so a typo fails before any data is read
+	assertions := make([]*query.Assertion, 0, len(assertFlag))
+	for _, text := range assertFlag {
+		assertion, err := query.ParseAssertion(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing assertion %q: %v\n", text, err)
+			os.Exit(1)
+		}
+		assertions = append(assertions, assertion)
+	}
+
+	// Render read progress only when asked for and stderr is an interactive terminal
+	if *progressFlag && isTerminal(os.Stderr) {
+		readOptions.OnProgress = newProgressPrinter(os.Stderr)
+	}
+
+	readOptions.InferTypes = *inferFlag
+
+	// Seed TABLESAMPLE only when --seed is given; otherwise samples differ per run
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			readOptions.Rand = rand.New(rand.NewSource(*seedFlag))
+		}
+	})
+
 	// Get filename from positional args (optional if query has FROM clause)
 	var filename string
-	if flag.NArg() >= 1 {
-		filename = flag.Arg(0)
+	if len(args) >= 1 {
+		filename = args[0]
+	}
+
+	// The timeout covers the whole run; the REPL applies it per statement
+	if *timeoutFlag > 0 && !*replFlag {
+		var cancel context.CancelFunc
+		queryContext, cancel = withTimeout(*timeoutFlag)
+		defer cancel()
+	}
+
+	// In REPL mode, run statements typed at a prompt until \q
+	if *replFlag {
+		if filename == reader.StdinPath {
+			fmt.Fprintf(os.Stderr, "Error: -repl reads statements from standard input, so it cannot also read the file from it\n")
+			os.Exit(1)
+		}
+		runREPL(os.Stdin, os.Stdout, os.Stderr, filename, nanHandling, isTerminal(os.Stdin))
+		return
 	}
 
 	// Handle schema mode
@@ -65,6 +195,28 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle metadata mode
+	if *metaFlag {
+		if filename == "" {
+			fmt.Fprintf(os.Stderr, "Error: missing parquet file argument\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		handleMetaMode(filename, *formatFlag)
+		os.Exit(0)
+	}
+
+	// A script of several statements runs each in order, printing each
+	// result as a labeled section
+	if statements := query.SplitStatements(*queryFlag); len(statements) > 1 {
+		if *perFileFlag || len(assertions) > 0 || *countFlag {
+			fmt.Fprintf(os.Stderr, "Error: a query with multiple statements cannot be used with --per-file, -assert or -count\n")
+			os.Exit(1)
+		}
+		runScript(statements, filename, nanHandling)
+		return
+	}
+
 	// Parse query if specified to determine if we need a filename
 	var q *query.Query
 	if *queryFlag != "" {
@@ -83,769 +235,306 @@ func main() {
 		}
 	}
 
-	// Declare rows variable before conditional logic
-	var rows []map[string]interface{}
-	var err error
-
-	// Materialize CTEs FIRST (before loading main table) as they may be referenced in FROM
-	ctx := query.NewExecutionContext(nil)
-	if q != nil && len(q.CTEs) > 0 {
-		// Use the executor's CTE materialization logic which includes circular dependency detection
-		if err := ctx.MaterializeCTEs(q.CTEs, executeCTEQuery); err != nil {
-			fmt.Fprintf(os.Stderr, "Error materializing CTEs: %v\n", err)
-			os.Exit(1)
-		}
-	}
-
-	// Handle FROM subquery case
-	if q != nil && q.Subquery != nil {
-		// Execute the subquery to get initial rows
-		rows, err = executeCTEQuery(q.Subquery, ctx)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error executing FROM subquery: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Apply table alias if specified
-		if q.TableAlias != "" {
-			rows = applyTableAliasHelper(rows, q.TableAlias)
-		}
-	} else if q != nil && filename != "" && len(ctx.CTEs) > 0 {
-		// Check if main table is a CTE reference
-		if cteRows, exists := ctx.CTEs[q.TableName]; exists {
-			rows = cteRows
-			// Apply table alias if specified
-			if q.TableAlias != "" {
-				rows = applyTableAliasHelper(rows, q.TableAlias)
-			}
-		} else {
-			// Not a CTE, read from file
-			rows, err = reader.ReadMultipleFiles(filename)
-			if err != nil {
-				if os.IsNotExist(err) {
-					fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", filename)
-					fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
-				} else {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				}
-				os.Exit(1)
-			}
-			// Apply table alias if specified
-			if q.TableAlias != "" {
-				rows = applyTableAliasHelper(rows, q.TableAlias)
-			}
-		}
-	} else {
-		// Ensure we have a filename
-		if filename == "" {
+	// In count mode, print the number of result rows instead of the rows
+	if *countFlag {
+		if filename == "" && q == nil {
 			fmt.Fprintf(os.Stderr, "Error: missing parquet file argument\n\n")
 			flag.Usage()
 			os.Exit(1)
 		}
-
-		// Read all rows (supports glob patterns)
-		rows, err = reader.ReadMultipleFiles(filename)
+		n, err := resultCount(q, *queryFlag, filename)
 		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", filename)
-				fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		// Apply table alias if specified
-		if q != nil && q.TableAlias != "" {
-			rows = applyTableAliasHelper(rows, q.TableAlias)
-		}
+		fmt.Println(n)
+		return
 	}
 
-	// Apply query if specified
-	if q != nil {
-		// CTEs already materialized above in ctx
-		// Table alias already applied in the paths above
-
-		// Handle JOINs - need to read additional files
-		if len(q.Joins) > 0 {
-
-			// Execute JOINs
-			for _, join := range q.Joins {
-				var joinRows []map[string]interface{}
-
-				if join.Subquery != nil {
-					// JOIN with subquery
-					joinRows, err = executeCTEQuery(join.Subquery, ctx)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Error executing JOIN subquery: %v\n", err)
-						os.Exit(1)
-					}
-				} else if join.TableName != "" {
-					// Check if it's a CTE reference
-					if cteRows, exists := ctx.CTEs[join.TableName]; exists {
-						joinRows = cteRows
-					} else if ctx.AllCTENames[join.TableName] {
-						// This is a forward CTE reference (CTE defined but not yet materialized)
-						fmt.Fprintf(os.Stderr, "Error: forward CTE reference in JOIN: %s is defined but not yet materialized (CTEs must be referenced in order)\n", join.TableName)
-						os.Exit(1)
-					} else {
-						// Read from parquet file (supports glob)
-						joinRows, err = reader.ReadMultipleFiles(join.TableName)
-						if err != nil {
-							fmt.Fprintf(os.Stderr, "Error reading JOIN table %s: %v\n", join.TableName, err)
-							os.Exit(1)
-						}
-					}
-				}
-
-				// Apply alias to joined table if specified
-				if join.Alias != "" {
-					joinRows = applyTableAliasHelper(joinRows, join.Alias)
-				}
-
-				// Execute the join
-				rows, err = executeJoinHelper(rows, joinRows, join)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error executing JOIN: %v\n", err)
-					os.Exit(1)
-				}
-			}
-		}
-
-		// Apply WHERE filter first (with context for subquery support)
-		if q.Filter != nil {
-			rows, err = query.ApplyFilterWithContext(rows, q.Filter, ctx)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying filter: %v\n", err)
-				// List available columns to help user
-				if len(rows) > 0 {
-					columns := query.GetColumnNames(rows)
-					fmt.Fprintf(os.Stderr, "\nAvailable columns: ")
-					for i, col := range columns {
-						if i > 0 {
-							fmt.Fprintf(os.Stderr, ", ")
-						}
-						fmt.Fprintf(os.Stderr, "%s", col)
-					}
-					fmt.Fprintf(os.Stderr, "\n")
-				}
-				os.Exit(1)
-			}
-		}
-
-		// Apply window functions if present (before aggregation and projection)
-		hasWindowFunc := query.HasWindowFunction(q.SelectList)
-		if hasWindowFunc {
-			rows, err = query.ApplyWindowFunctions(rows, q.SelectList)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying window functions: %v\n", err)
-				os.Exit(1)
-			}
-			// After window functions, we need final projection but must not re-evaluate window exprs
-			// ApplyWindowFunctions already added window results as columns
-			// Now project to final SELECT list, treating window exprs as column references
-			rows, err = query.ApplySelectListAfterWindows(rows, q.SelectList)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying select list after windows: %v\n", err)
-				os.Exit(1)
-			}
-		} else if len(q.GroupBy) > 0 || query.HasAggregateFunction(q.SelectList) {
-			// Apply GROUP BY and aggregation if present
-			rows, err = query.ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying aggregation: %v\n", err)
-				os.Exit(1)
-			}
-
-			// Apply HAVING filter if present
-			if q.Having != nil {
-				rows, err = query.EvaluateHaving(rows, q.Having)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error applying HAVING clause: %v\n", err)
-					os.Exit(1)
-				}
-			}
-		} else {
-			// Apply SELECT list projection (only if no aggregation or windows) with context for scalar subquery support
-			if len(q.SelectList) > 0 {
-				rows, err = query.ApplySelectListWithContext(rows, q.SelectList, ctx)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error applying select list: %v\n", err)
-					os.Exit(1)
-				}
-			}
-		}
-
-		// Apply DISTINCT if present
-		if q.Distinct {
-			rows, err = query.ApplyDistinct(rows)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying DISTINCT: %v\n", err)
-				os.Exit(1)
-			}
-		}
-
-		// Apply ORDER BY if present
-		if len(q.OrderBy) > 0 {
-			rows, err = query.ApplyOrderBy(rows, q.OrderBy)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying ORDER BY: %v\n", err)
-				os.Exit(1)
-			}
+	// Without a query, print the rows of a single file as they are read
+	if q == nil && !*perFileFlag && len(assertions) == 0 && canStream(filename) {
+		out, closeOut := mustOpenOutput()
+		err := streamFile(filename, *limitFlag, newFormatter(out, nanHandling))
+		if closeErr := closeOut(); err == nil {
+			err = closeErr
 		}
-
-		// Apply SQL LIMIT and OFFSET if present
-		if q.Limit != nil || q.Offset != nil {
-			rows, err = query.ApplyLimitOffset(rows, q.Limit, q.Offset)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error applying LIMIT/OFFSET: %v\n", err)
-				os.Exit(1)
-			}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	// Apply flag-based limit only if SQL LIMIT was not specified
-	if *limitFlag > 0 && (q == nil || q.Limit == nil) && len(rows) > *limitFlag {
-		rows = rows[:*limitFlag]
-	}
-
-	// Format and output
-	var formatter output.Formatter
-	switch *formatFlag {
-	case "json", "jsonl":
-		formatter = output.NewJSONFormatter(os.Stdout)
-	case "csv":
-		formatter = output.NewCSVFormatter(os.Stdout)
-	default:
-		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s'\n", *formatFlag)
-		fmt.Fprintf(os.Stderr, "Supported formats: json, jsonl, csv\n")
-		os.Exit(1)
-	}
-
-	if err := formatter.Format(rows); err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
-		os.Exit(1)
-	}
-}
+	// Stop reading at the -limit when the query can't need the rows after it
+	maxRows := limitPushdown(q, *limitFlag)
 
-// executeCTEQuery executes a CTE or subquery
-func executeCTEQuery(q *query.Query, ctx *query.ExecutionContext) ([]map[string]interface{}, error) {
+	// In per-file mode, run the query separately against each matched file
+	var sections []output.Section
 	var rows []map[string]interface{}
-	var err error
-
-	// Materialize any CTEs defined in this subquery FIRST
-	// Use a child context to prevent CTE scope leaking to parent
-	if len(q.CTEs) > 0 {
-		childCtx := ctx.NewChildContext()
-		if err := childCtx.MaterializeCTEs(q.CTEs, executeCTEQuery); err != nil {
-			return nil, fmt.Errorf("failed to materialize CTEs in subquery: %w", err)
+	if *perFileFlag {
+		if filename == "" {
+			fmt.Fprintf(os.Stderr, "Error: missing parquet file argument\n\n")
+			flag.Usage()
+			os.Exit(1)
 		}
-		// Use child context for the rest of this subquery
-		ctx = childCtx
-	}
-
-	// Handle subqueries in FROM clause
-	if q.Subquery != nil {
-		rows, err = executeCTEQuery(q.Subquery, ctx)
+		files, err := reader.ExpandPattern(filename)
 		if err != nil {
-			return nil, err
-		}
-	} else if q.TableName != "" {
-		// Check if this table is currently being materialized (circular dependency)
-		if ctx.InProgress[q.TableName] {
-			return nil, fmt.Errorf("circular CTE dependency detected: %s references itself", q.TableName)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-
-		// Check if it's a CTE reference
-		if cteRows, exists := ctx.CTEs[q.TableName]; exists {
-			rows = cteRows
-		} else if ctx.AllCTENames[q.TableName] {
-			// This is a forward CTE reference (CTE defined but not yet materialized)
-			return nil, fmt.Errorf("forward CTE reference: %s is defined but not yet materialized (CTEs must be referenced in order)", q.TableName)
-		} else {
-			// Read from parquet file
-			rows, err = reader.ReadMultipleFiles(q.TableName)
-			if err != nil {
-				return nil, err
-			}
+		for _, file := range files {
+			sections = append(sections, output.Section{Label: file, Rows: loadRows(q, *queryFlag, file, maxRows)})
 		}
 	} else {
-		return nil, fmt.Errorf("no data source specified (table, CTE, or subquery)")
-	}
-
-	// Apply table alias to main table rows if specified (BEFORE filtering/joins)
-	if q.TableAlias != "" {
-		rows = applyTableAliasHelper(rows, q.TableAlias)
-	}
-
-	// Handle JOINs if present
-	if len(q.Joins) > 0 {
-		for _, join := range q.Joins {
-			var joinRows []map[string]interface{}
-			if join.Subquery != nil {
-				joinRows, err = executeCTEQuery(join.Subquery, ctx)
-				if err != nil {
-					return nil, err
-				}
-			} else if join.TableName != "" {
-				// Check for circular dependency
-				if ctx.InProgress[join.TableName] {
-					return nil, fmt.Errorf("circular CTE dependency detected: JOIN references %s which is currently being materialized", join.TableName)
-				}
-
-				if cteRows, exists := ctx.CTEs[join.TableName]; exists {
-					joinRows = cteRows
-				} else if ctx.AllCTENames[join.TableName] {
-					// This is a forward CTE reference (CTE defined but not yet materialized)
-					return nil, fmt.Errorf("forward CTE reference in JOIN: %s is defined but not yet materialized (CTEs must be referenced in order)", join.TableName)
-				} else {
-					joinRows, err = reader.ReadMultipleFiles(join.TableName)
-					if err != nil {
-						return nil, err
-					}
-				}
-			}
-
-			if join.Alias != "" {
-				joinRows = applyTableAliasHelper(joinRows, join.Alias)
-			}
-
-			rows, err = executeJoinHelper(rows, joinRows, join)
-			if err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	// Apply WHERE filter if present (with context for subquery support)
-	if q.Filter != nil {
-		rows, err = query.ApplyFilterWithContext(rows, q.Filter, ctx)
-		if err != nil {
-			return nil, err
-		}
+		rows = loadRows(q, *queryFlag, filename, maxRows)
 	}
 
-	// Apply window functions if present (before aggregation and projection)
-	hasWindowFunc := query.HasWindowFunction(q.SelectList)
-	if hasWindowFunc {
-		rows, err = query.ApplyWindowFunctions(rows, q.SelectList)
+	// In assertion mode, report the checks instead of printing rows
+	if len(assertions) > 0 {
+		passed, err := checkAssertions(os.Stdout, assertions, rows)
 		if err != nil {
-			return nil, err
-		}
-		// After window functions, we need final projection but must not re-evaluate window exprs
-		// ApplyWindowFunctions already added window results as columns
-		// Now project to final SELECT list, treating window exprs as column references
-		rows, err = query.ApplySelectListAfterWindows(rows, q.SelectList)
-		if err != nil {
-			return nil, err
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-	} else if len(q.GroupBy) > 0 || query.HasAggregateFunction(q.SelectList) {
-		// Apply GROUP BY and aggregation if present (BEFORE projection)
-		rows, err = query.ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
-		if err != nil {
-			return nil, err
+		if !passed {
+			os.Exit(1)
 		}
+		return
+	}
 
-		// Apply HAVING filter if present
-		if q.Having != nil {
-			rows, err = query.EvaluateHaving(rows, q.Having)
-			if err != nil {
-				return nil, err
-			}
-		}
+	// Format and output
+	out, closeOut := mustOpenOutput()
+	formatter := newFormatter(out, nanHandling)
+	orderColumns(formatter, q, filename)
+	if *perFileFlag {
+		err = output.FormatSections(formatter, out, sections)
 	} else {
-		// Apply SELECT list projection (only if no aggregation or windows) with context for scalar subquery support
-		if len(q.SelectList) > 0 {
-			rows, err = query.ApplySelectListWithContext(rows, q.SelectList, ctx)
-			if err != nil {
-				return nil, err
-			}
-		}
+		err = formatter.Format(rows)
 	}
-
-	// Apply DISTINCT if present
-	if q.Distinct {
-		rows, err = query.ApplyDistinct(rows)
-		if err != nil {
-			return nil, err
-		}
+	if closeErr := closeOut(); err == nil {
+		err = closeErr
 	}
-
-	// Apply ORDER BY if present
-	if len(q.OrderBy) > 0 {
-		rows, err = query.ApplyOrderBy(rows, q.OrderBy)
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	// Apply LIMIT/OFFSET if present
-	if q.Limit != nil || q.Offset != nil {
-		rows, err = query.ApplyLimitOffset(rows, q.Limit, q.Offset)
-		if err != nil {
-			return nil, err
-		}
+// newFormatter returns the formatter selected by the -f and -raw flags,
+// writing to w. An unsupported format is reported on stderr and exits the
+// process.
+func newFormatter(w io.Writer, nanHandling output.NaNHandling) output.Formatter {
+	switch {
+	case *rawFlag:
+		return output.NewRawFormatter(w)
+	case *formatFlag == "json" || *formatFlag == "jsonl":
+		jsonFormatter := output.NewJSONFormatter(w)
+		jsonFormatter.SetNaNHandling(nanHandling)
+		return jsonFormatter
+	case *formatFlag == "csv":
+		return newCSVFormatter(w)
+	case *formatFlag == "table":
+		tableFormatter := output.NewTableFormatter(w)
+		tableFormatter.SetMaxWidth(*widthFlag)
+		return tableFormatter
+	case *formatFlag == "markdown" || *formatFlag == "md":
+		return output.NewMarkdownFormatter(w)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s'\n", *formatFlag)
+		fmt.Fprintf(os.Stderr, "Supported formats: json, jsonl, csv, table, markdown\n")
+		os.Exit(1)
+		return nil
 	}
-
-	return rows, nil
 }
 
-// applyTableAliasHelper prefixes all column names with table alias
-func applyTableAliasHelper(rows []map[string]interface{}, alias string) []map[string]interface{} {
-	if alias == "" {
-		return rows
-	}
-
-	aliasedRows := make([]map[string]interface{}, len(rows))
-	for i, row := range rows {
-		aliasedRow := make(map[string]interface{})
-		for col, val := range row {
-			// Don't alias the special _file column
-			if col == "_file" {
-				aliasedRow[col] = val
-			} else {
-				aliasedRow[alias+"."+col] = val
-			}
-		}
-		aliasedRows[i] = aliasedRow
+// handleCompactMode handles the --compact flag by merging the files matched by
+// the positional argument into the -o file
+func handleCompactMode(args []string) {
+	if *outFlag == "" || len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: --compact requires -o <output.parquet> and one input file or glob pattern\n")
+		os.Exit(1)
+	}
+	if *queryFlag != "" || *schemaFlag || len(assertFlag) > 0 || *perFileFlag {
+		fmt.Fprintf(os.Stderr, "Error: --compact cannot be used with -q, --schema, -assert or --per-file\n")
+		os.Exit(1)
 	}
-	return aliasedRows
-}
 
-// executeJoinHelper executes a JOIN operation
-func executeJoinHelper(leftRows, rightRows []map[string]interface{}, join query.Join) ([]map[string]interface{}, error) {
-	switch join.Type {
-	case query.JoinInner:
-		return executeInnerJoinHelper(leftRows, rightRows, join.Condition)
-	case query.JoinLeft:
-		return executeLeftJoinHelper(leftRows, rightRows, join.Condition)
-	case query.JoinRight:
-		return executeRightJoinHelper(leftRows, rightRows, join.Condition)
-	case query.JoinFull:
-		return executeFullJoinHelper(leftRows, rightRows, join.Condition)
-	case query.JoinCross:
-		return executeCrossJoinHelper(leftRows, rightRows)
-	default:
-		return nil, fmt.Errorf("unsupported join type: %v", join.Type)
+	n, err := writer.Compact(args[0], *outFlag, writer.CompactOptions{
+		RowGroupSize: *rowGroupFlag,
+		Compression:  *codecFlag,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error compacting files: %v\n", err)
+		os.Exit(1)
 	}
+	fmt.Fprintf(os.Stderr, "Wrote %d rows to %s\n", n, *outFlag)
 }
 
-// executeInnerJoinHelper performs an INNER JOIN
-func executeInnerJoinHelper(leftRows, rightRows []map[string]interface{}, condition query.Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	for _, leftRow := range leftRows {
-		for _, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRowsHelper(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-			}
-		}
+// handleSchemaMode handles the --schema flag by extracting and displaying schema information
+func handleSchemaMode(filename string, format string) {
+	filePath := firstMatch(filename, "schema")
+	rows, err := schemaRows(filePath)
+	if err != nil {
+		exitOpenError(filePath, err)
 	}
-
-	return result, nil
+	writeInfo(rows, format)
 }
 
-// executeLeftJoinHelper performs a LEFT OUTER JOIN
-func executeLeftJoinHelper(leftRows, rightRows []map[string]interface{}, condition query.Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Special case: if right side is empty, return all left rows unchanged
-	// (we can't add NULL columns without knowing the right side schema)
-	if len(rightRows) == 0 {
-		return append([]map[string]interface{}{}, leftRows...), nil
+// schemaRows returns one row per column of a file describing its type,
+// repetition and statistics
+func schemaRows(filePath string) ([]map[string]interface{}, error) {
+	// Extract schema information using reader package
+	schemaInfos, err := reader.ExtractSchemaInfo(filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, leftRow := range leftRows {
-		matched := false
-
-		for _, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRowsHelper(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-			}
+	// Convert reader.SchemaInfo to []map[string]interface{} for formatter compatibility
+	rows := make([]map[string]interface{}, len(schemaInfos))
+	for i, field := range schemaInfos {
+		rows[i] = map[string]interface{}{
+			"name":          field.Name,
+			"type":          field.Type,
+			"physical_type": field.PhysicalType,
+			"logical_type":  field.LogicalType,
+			"required":      field.Required,
+			"optional":      field.Optional,
+			"repeated":      field.Repeated,
+			"min":           field.Min,
+			"max":           field.Max,
+			"null_count":    nil,
 		}
-
-		// If no match, include left row with NULL values for right columns
-		if !matched {
-			merged, err := mergeRowsHelper(leftRow, createNullRowHelper(rightRows))
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
+		if field.NullCount != nil {
+			rows[i]["null_count"] = *field.NullCount
 		}
 	}
-
-	return result, nil
+	return rows, nil
 }
 
-// executeRightJoinHelper performs a RIGHT OUTER JOIN
-func executeRightJoinHelper(leftRows, rightRows []map[string]interface{}, condition query.Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
+// handleMetaMode prints the footer metadata of a file, or of the first file
+// matched by a glob, as a single row
+func handleMetaMode(filename string, format string) {
+	filePath := firstMatch(filename, "metadata")
 
-	// Special case: if left side is empty, return all right rows unchanged
-	// (we can't add NULL columns without knowing the left side schema)
-	if len(leftRows) == 0 {
-		return append([]map[string]interface{}{}, rightRows...), nil
+	r, err := reader.NewReader(filePath)
+	if err != nil {
+		exitOpenError(filePath, err)
 	}
+	defer func() { _ = r.Close() }()
 
-	for _, rightRow := range rightRows {
-		matched := false
-
-		for _, leftRow := range leftRows {
-			// Merge rows
-			merged, err := mergeRowsHelper(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-			}
-		}
-
-		// If no match, include right row with NULL values for left columns
-		if !matched {
-			merged, err := mergeRowsHelper(createNullRowHelper(leftRows), rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
+	meta, err := r.Metadata()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading metadata: %v\n", err)
+		os.Exit(1)
 	}
 
-	return result, nil
+	writeInfo([]map[string]interface{}{{
+		"num_rows":           meta.NumRows,
+		"num_row_groups":     meta.NumRowGroups,
+		"created_by":         meta.CreatedBy,
+		"row_group_rows":     meta.RowGroupRows,
+		"key_value_metadata": meta.KeyValue,
+	}}, format)
 }
 
-// executeFullJoinHelper performs a FULL OUTER JOIN
-func executeFullJoinHelper(leftRows, rightRows []map[string]interface{}, condition query.Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Special cases: if one side is empty, return the other side unchanged
-	if len(leftRows) == 0 {
-		return append([]map[string]interface{}{}, rightRows...), nil
-	}
-	if len(rightRows) == 0 {
-		return append([]map[string]interface{}{}, leftRows...), nil
+// firstMatch resolves a glob pattern to its first match, noting on stderr
+// which file the shown information (what) comes from when several match.
+// Paths without wildcards are returned as-is.
+func firstMatch(filename, what string) string {
+	if !strings.ContainsAny(filename, "*?[]{}") {
+		return filename
 	}
 
-	// Track which right rows have been matched
-	rightMatched := make([]bool, len(rightRows))
-
-	// Process left rows
-	for _, leftRow := range leftRows {
-		matched := false
-
-		for i, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRowsHelper(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-				rightMatched[i] = true
-			}
-		}
-
-		// If no match, include left row with NULL values for right columns
-		if !matched {
-			merged, err := mergeRowsHelper(leftRow, createNullRowHelper(rightRows))
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
+	matches, err := filepath.Glob(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid glob pattern: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Add unmatched right rows with NULL values for left columns
-	for i, rightRow := range rightRows {
-		if !rightMatched[i] {
-			merged, err := mergeRowsHelper(createNullRowHelper(leftRows), rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no files match pattern: %s\n", filename)
+		os.Exit(1)
 	}
 
-	return result, nil
-}
-
-// executeCrossJoinHelper performs a CROSS JOIN (Cartesian product)
-func executeCrossJoinHelper(leftRows, rightRows []map[string]interface{}) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	for _, leftRow := range leftRows {
-		for _, rightRow := range rightRows {
-			merged, err := mergeRowsHelper(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
+	// Print informational message to stderr
+	if len(matches) > 1 {
+		fmt.Fprintf(os.Stderr, "# Showing %s from: %s (%d files matched)\n", what, matches[0], len(matches))
 	}
-
-	return result, nil
+	return matches[0]
 }
 
-// mergeRowsHelper combines two rows into one
-// If both left and right have the same column name, returns an error
-func mergeRowsHelper(left, right map[string]interface{}) (map[string]interface{}, error) {
-	merged := make(map[string]interface{})
-
-	// Copy left row
-	for k, v := range left {
-		merged[k] = v
-	}
-
-	// Copy right row - check for collisions (except _file which is allowed to be duplicated)
-	for k, v := range right {
-		if _, exists := merged[k]; exists {
-			// Allow _file column to be duplicated - it's added by glob reads
-			// When both sides have _file, we keep both but suffix them with the table position
-			if k == "_file" {
-				// Keep left as _file_left and right as _file_right
-				if leftFile, ok := merged["_file"]; ok {
-					delete(merged, "_file")
-					merged["_file_left"] = leftFile
-					merged["_file_right"] = v
-				}
-				continue
-			}
-			return nil, fmt.Errorf("column name collision in JOIN: %q exists in both tables. Use table aliases to disambiguate (e.g., SELECT t1.%s, t2.%s FROM ...)", k, k, k)
-		}
-		merged[k] = v
+// exitOpenError reports a file that could not be opened and exits
+func exitOpenError(filePath string, err error) {
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", filePath)
+		fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
+	} else {
+		fmt.Fprintf(os.Stderr, "Error opening file: %v\n", err)
 	}
-
-	return merged, nil
+	os.Exit(1)
 }
 
-// createNullRowHelper creates a row with NULL values for all columns from a sample row set.
-// For empty row sets, we need to infer columns from the schema or accept that we cannot
-// create proper NULL rows. In practice, this means OUTER JOINs with one empty side will
-// not include NULL columns for that side - this is a known limitation when joining empty
-// result sets without schema information.
-func createNullRowHelper(rows []map[string]interface{}) map[string]interface{} {
-	if len(rows) == 0 {
-		// Cannot create NULL row without knowing column names
-		// This is a limitation: outer joins with empty sides won't have NULL columns
-		return make(map[string]interface{})
-	}
-
-	nullRow := make(map[string]interface{})
-	// Use first row as template to get column names
-	for col := range rows[0] {
-		nullRow[col] = nil
+// orderColumns puts the columns of a query's SELECT list first in the
+// output, in the order they are selected. For SELECT * or no query, the
+// columns of the table read come first in the field order of its schema,
+// which is skipped for standard input, which can only be read once, and for
+// a table that isn't a file, such as a CTE.
+func orderColumns(formatter output.Formatter, q *query.Query, filename string) {
+	orderer, ok := formatter.(output.ColumnOrderer)
+	if !ok {
+		return
 	}
-
-	return nullRow
-}
-
-// handleSchemaMode handles the --schema flag by extracting and displaying schema information
-func handleSchemaMode(filename string, format string) {
-	// Resolve filename - for glob patterns, use first match
-	var filePath string
-
-	// Check if pattern contains glob wildcards
-	if strings.ContainsAny(filename, "*?[]{}") {
-		matches, err := filepath.Glob(filename)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: invalid glob pattern: %v\n", err)
-			os.Exit(1)
-		}
-
-		if len(matches) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: no files match pattern: %s\n", filename)
-			os.Exit(1)
-		}
-
-		filePath = matches[0]
-		// Print informational message to stderr
-		if len(matches) > 1 {
-			fmt.Fprintf(os.Stderr, "# Showing schema from: %s (%d files matched)\n", filePath, len(matches))
+	if q != nil {
+		if columns := query.SelectColumns(q.SelectList); columns != nil {
+			orderer.SetColumnOrder(columns)
+			return
 		}
-	} else {
-		filePath = filename
 	}
-
-	// Extract schema information using reader package
-	schemaInfos, err := reader.ExtractSchemaInfo(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", filePath)
-			fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
-		} else {
-			fmt.Fprintf(os.Stderr, "Error opening file: %v\n", err)
-		}
-		os.Exit(1)
+	if filename == "" || filename == reader.StdinPath {
+		return
 	}
-
-	// Convert reader.SchemaInfo to []map[string]interface{} for formatter compatibility
-	rows := make([]map[string]interface{}, len(schemaInfos))
-	for i, field := range schemaInfos {
-		rows[i] = map[string]interface{}{
-			"name":          field.Name,
-			"type":          field.Type,
-			"physical_type": field.PhysicalType,
-			"logical_type":  field.LogicalType,
-			"required":      field.Required,
-			"optional":      field.Optional,
-			"repeated":      field.Repeated,
-		}
+	if columns, err := query.TableColumns(filename); err == nil {
+		orderer.SetColumnOrder(columns)
 	}
+}
 
-	// Format and output
+// newCSVFormatter returns a CSV formatter writing to w, configured by the
+// -delimiter, -null and -quote flags
+func newCSVFormatter(w io.Writer) *output.CSVFormatter {
+	formatter := output.NewCSVFormatter(w)
+	// The delimiter was validated by ParseDelimiter
+	_ = formatter.SetDelimiter(csvDelimiter)
+	formatter.SetNullString(*nullFlag)
+	formatter.SetQuoting(csvQuoting)
+	return formatter
+}
+
+// writeInfo writes schema or metadata rows to the output in the given format
+func writeInfo(rows []map[string]interface{}, format string) {
+	out, closeOut := mustOpenOutput()
 	var formatter output.Formatter
 	switch format {
 	case "json", "jsonl":
-		formatter = output.NewJSONFormatter(os.Stdout)
+		formatter = output.NewJSONFormatter(out)
 	case "csv":
-		formatter = output.NewCSVFormatter(os.Stdout)
+		formatter = newCSVFormatter(out)
+	case "table":
+		tableFormatter := output.NewTableFormatter(out)
+		tableFormatter.SetMaxWidth(*widthFlag)
+		formatter = tableFormatter
+	case "markdown", "md":
+		formatter = output.NewMarkdownFormatter(out)
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s'\n", format)
-		fmt.Fprintf(os.Stderr, "Supported formats: json, jsonl, csv\n")
+		fmt.Fprintf(os.Stderr, "Supported formats: json, jsonl, csv, table, markdown\n")
 		os.Exit(1)
 	}
 
-	if err := formatter.Format(rows); err != nil {
+	err := formatter.Format(rows)
+	if closeErr := closeOut(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vegasq/parcat/output"
+)
+
+// outputCompression holds the compression of formatted output, chosen by the
+// -compress flag or, without it, by the extension of the -o file
+var outputCompression output.Compression
+
+// mustOpenOutput is openOutput, reporting an error on stderr and exiting
+func mustOpenOutput() (io.Writer, func() error) {
+	w, closeOut, err := openOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return w, closeOut
+}
+
+// openOutput returns the writer formatted output goes to: the -o file, or
+// standard output without one, compressed as outputCompression selects.
+// Call the returned function once formatting is done to flush the
+// compressor and close the file; its error reports data that wasn't written.
+func openOutput() (io.Writer, func() error, error) {
+	var dest io.Writer = os.Stdout
+	var file *os.File
+	if *outFlag != "" {
+		f, err := os.Create(*outFlag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot create output file %s: %w", *outFlag, err)
+		}
+		file = f
+		dest = f
+	}
+
+	w, err := output.NewCompressedWriter(dest, outputCompression)
+	if err != nil {
+		if file != nil {
+			_ = file.Close()
+		}
+		return nil, nil, err
+	}
+
+	return w, func() error {
+		err := w.Close()
+		if file != nil {
+			if closeErr := file.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("failed to close output file %s: %w", file.Name(), closeErr)
+			}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vegasq/parcat/output"
+)
+
+func TestOpenOutput_GzipFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl.gz")
+	oldOut, oldCompression := *outFlag, outputCompression
+	defer func() { *outFlag, outputCompression = oldOut, oldCompression }()
+	*outFlag = path
+	outputCompression = output.CompressionForPath(path)
+
+	out, closeOut, err := openOutput()
+	if err != nil {
+		t.Fatalf("openOutput() error = %v", err)
+	}
+	rows := []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}}
+	if err := output.NewJSONFormatter(out).Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if err := closeOut(); err != nil {
+		t.Fatalf("close error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("output is not gzip: %v", err)
+	}
+	got, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress output: %v", err)
+	}
+
+	want := "{\"id\":1}\n{\"id\":2}\n"
+	if string(got) != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestOpenOutput_PlainFileAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	oldOut, oldCompression := *outFlag, outputCompression
+	defer func() { *outFlag, outputCompression = oldOut, oldCompression }()
+
+	// A file without a compressed extension is written as is
+	*outFlag = filepath.Join(dir, "out.csv")
+	outputCompression = output.CompressionForPath(*outFlag)
+	out, closeOut, err := openOutput()
+	if err != nil {
+		t.Fatalf("openOutput() error = %v", err)
+	}
+	if err := output.NewCSVFormatter(out).Format([]map[string]interface{}{{"id": int64(1)}}); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if err := closeOut(); err != nil {
+		t.Fatalf("close error = %v", err)
+	}
+	got, err := os.ReadFile(*outFlag)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(got) != "id\n1\n" {
+		t.Errorf("output = %q, want %q", got, "id\n1\n")
+	}
+
+	// A file in a missing directory can't be created
+	*outFlag = filepath.Join(dir, "missing", "out.csv")
+	if _, _, err := openOutput(); err == nil || !strings.Contains(err.Error(), "cannot create output file") {
+		t.Errorf("openOutput() error = %v, want cannot create output file", err)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// isTerminal reports whether f refers to an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// newProgressPrinter returns a progress callback that redraws a single
+// status line on w, ending it with a newline once all files are read.
+func newProgressPrinter(w io.Writer) func(filesDone, filesTotal int, rowsRead int64) {
+	return func(filesDone, filesTotal int, rowsRead int64) {
+		// \r returns to the start of the line, \033[K clears what was left of the previous update
+		fmt.Fprintf(w, "\r\033[KReading files: %d/%d, %d rows", filesDone, filesTotal, rowsRead)
+		if filesDone == filesTotal {
+			fmt.Fprintln(w)
+		}
+	}
+}
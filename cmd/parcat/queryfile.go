@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vegasq/parcat/query"
+)
+
+// readQueryFile returns the query in the file given by -qf, without
+// surrounding whitespace or a trailing semicolon. A file holding only
+// comments counts as empty.
+func readQueryFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read query file %s: %w", path, err)
+	}
+
+	text := strings.TrimSpace(string(data))
+	text = strings.TrimSpace(strings.TrimSuffix(text, ";"))
+	if len(query.SplitStatements(text)) == 0 {
+		return "", fmt.Errorf("query file %s is empty", path)
+	}
+	return text, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vegasq/parcat/query"
+)
+
+func TestReadQueryFile(t *testing.T) {
+	dir := t.TempDir()
+	testFile := createTestParquetFile(t, dir, "people.parquet", []TestRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0},
+		{ID: 3, Name: "Charlie", Age: 35, Salary: 60000.0},
+	})
+
+	queryPath := filepath.Join(dir, "report.sql")
+	sql := "-- Everyone over 28, oldest first\nWITH older AS (\n  SELECT name, age FROM '" + testFile + "' WHERE age > 28\n)\nSELECT name, age FROM older ORDER BY age DESC;\n"
+	if err := os.WriteFile(queryPath, []byte(sql), 0o644); err != nil {
+		t.Fatalf("failed to write query file: %v", err)
+	}
+
+	text, err := readQueryFile(queryPath)
+	if err != nil {
+		t.Fatalf("readQueryFile() error = %v", err)
+	}
+	if strings.HasSuffix(text, ";") {
+		t.Errorf("readQueryFile() = %q, want the trailing semicolon stripped", text)
+	}
+
+	q, err := query.Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	rows := runQuery(q, q.TableName, 0)
+
+	var names []string
+	for _, row := range rows {
+		names = append(names, row["name"].(string))
+	}
+	if got := strings.Join(names, ","); got != "Charlie,Alice" {
+		t.Errorf("query file result = %s, want Charlie,Alice", got)
+	}
+}
+
+func TestReadQueryFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	emptyPath := filepath.Join(dir, "empty.sql")
+	if err := os.WriteFile(emptyPath, []byte(" \n;\n"), 0o644); err != nil {
+		t.Fatalf("failed to write query file: %v", err)
+	}
+	commentPath := filepath.Join(dir, "comment.sql")
+	if err := os.WriteFile(commentPath, []byte("-- TODO: write the report\n"), 0o644); err != nil {
+		t.Fatalf("failed to write query file: %v", err)
+	}
+	missingPath := filepath.Join(dir, "missing.sql")
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "missing file", path: missingPath, wantErr: "cannot read query file " + missingPath},
+		{name: "empty file", path: emptyPath, wantErr: "query file " + emptyPath + " is empty"},
+		{name: "only comments", path: commentPath, wantErr: "query file " + commentPath + " is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readQueryFile(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readQueryFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/vegasq/parcat/output"
+	"github.com/vegasq/parcat/query"
+)
+
+// Prompts shown by the REPL for a new statement and for the lines that
+// continue one
+const (
+	replPrompt             = "parcat> "
+	replContinuationPrompt = "   ...> "
+)
+
+// replFormats lists the formats \f accepts
+var replFormats = []string{"json", "jsonl", "csv", "table", "markdown", "md"}
+
+// repl is an interactive session running statements against a file. Its
+// execution context lives as long as the session, so CTEs defined by one
+// statement can be used by the statements after it.
+type repl struct {
+	out         io.Writer
+	errOut      io.Writer
+	filename    string
+	nanHandling output.NaNHandling
+	ctx         *query.ExecutionContext
+}
+
+// runREPL reads statements from in until \q or end of input, writing
+// results to out and errors to errOut. A statement may span several lines
+// and ends with a semicolon. Lines starting with a backslash are commands:
+// \q quits, \f <format> switches the output format and \d [file] shows the
+// schema of the file, by default the one given on the command line. With
+// interactive set, prompts and row counts are written to out.
+func runREPL(in io.Reader, out, errOut io.Writer, filename string, nanHandling output.NaNHandling, interactive bool) {
+	r := &repl{out: out, errOut: errOut, filename: filename, nanHandling: nanHandling, ctx: newExecutionContext()}
+
+	scanner := bufio.NewScanner(in)
+	var buf strings.Builder
+	for {
+		if interactive {
+			if buf.Len() == 0 {
+				fmt.Fprint(out, replPrompt)
+			} else {
+				fmt.Fprint(out, replContinuationPrompt)
+			}
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Text()
+
+		if buf.Len() == 0 && strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			if quit := r.command(strings.Fields(line)); quit {
+				return
+			}
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if !query.StatementComplete(buf.String()) {
+			continue
+		}
+		for _, statement := range query.SplitStatements(buf.String()) {
+			r.run(statement, interactive)
+		}
+		buf.Reset()
+	}
+	if interactive {
+		fmt.Fprintln(out)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(errOut, "Error reading input: %v\n", err)
+	}
+}
+
+// command runs a backslash command and reports whether it ends the session
+func (r *repl) command(args []string) (quit bool) {
+	switch args[0] {
+	case `\q`:
+		return true
+	case `\f`:
+		if len(args) == 1 {
+			fmt.Fprintln(r.out, *formatFlag)
+		} else if !slices.Contains(replFormats, args[1]) {
+			fmt.Fprintf(r.errOut, "Error: unsupported format '%s'\n", args[1])
+			fmt.Fprintf(r.errOut, "Supported formats: json, jsonl, csv, table, markdown\n")
+		} else {
+			*formatFlag = args[1]
+		}
+	case `\d`:
+		file := r.filename
+		if len(args) > 1 {
+			file = args[1]
+		}
+		if file == "" {
+			fmt.Fprintf(r.errOut, "Error: \\d needs a file when none was given on the command line\n")
+			break
+		}
+		rows, err := schemaRows(file)
+		if err == nil {
+			err = newFormatter(r.out, r.nanHandling).Format(rows)
+		}
+		if err != nil {
+			fmt.Fprintf(r.errOut, "Error: %v\n", err)
+		}
+	default:
+		fmt.Fprintf(r.errOut, "Error: unknown command %s (use \\q, \\f <format> or \\d [file])\n", args[0])
+	}
+	return false
+}
+
+// run executes a statement and prints its result, or the error it failed with
+func (r *repl) run(statement string, interactive bool) {
+	q, rows, err := r.execute(statement)
+	if err != nil {
+		fmt.Fprintf(r.errOut, "Error: %v\n", err)
+		return
+	}
+
+	formatter := newFormatter(r.out, r.nanHandling)
+	orderColumns(formatter, q, r.filename)
+	if err := formatter.Format(rows); err != nil {
+		fmt.Fprintf(r.errOut, "Error formatting output: %v\n", err)
+		return
+	}
+	if interactive {
+		fmt.Fprintf(r.out, "(%d rows)\n", len(rows))
+	}
+}
+
+// execute parses and runs a statement in the session's context. The CTEs
+// of its WITH clause are kept in the context for later statements. Like
+// with -q, the file given on the command line is the main table unless
+// the statement reads a CTE or subquery.
+func (r *repl) execute(statement string) (*query.Query, []map[string]interface{}, error) {
+	// -timeout limits each statement rather than the session
+	if *timeoutFlag > 0 {
+		c, cancel := withTimeout(*timeoutFlag)
+		defer cancel()
+		queryContext = c
+		defer func() { queryContext = context.Background() }()
+	}
+	r.ctx.Context = queryContext
+	// Tables are cached per statement, so each one sees changes to the files
+	r.ctx.ClearTableCache()
+
+	q, err := query.Parse(statement)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing query: %w", err)
+	}
+
+	rows, err := r.ctx.Execute(withFile(q, r.filename, r.ctx))
+	if err != nil {
+		// Forget the names of the CTEs that failed, so that later
+		// statements don't see them as forward references
+		for name := range r.ctx.AllCTENames {
+			if _, ok := r.ctx.CTEs[name]; !ok {
+				delete(r.ctx.AllCTENames, name)
+			}
+		}
+		return nil, nil, err
+	}
+	if *limitFlag > 0 && q.Limit == nil && len(rows) > *limitFlag {
+		rows = rows[:*limitFlag]
+	}
+	return q, rows, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vegasq/parcat/output"
+)
+
+func TestRunREPL(t *testing.T) {
+	dir := t.TempDir()
+	testFile := createTestParquetFile(t, dir, "people.parquet", []TestRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0},
+	})
+
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			name:    "multi-line statement",
+			input:   "select name\nfrom people\nwhere age > 26;\n",
+			wantOut: "name\nAlice\n",
+		},
+		{
+			name:    "several statements on a line",
+			input:   "select id from t where id = 1; select id from t where id = 2;\n",
+			wantOut: "id\n1\nid\n2\n",
+		},
+		{
+			// As with -q, only the main table is replaced by the file
+			name:    "CTE carries to later statements",
+			input:   "with young as (select name from '" + testFile + "' where age < 28) select * from young;\nselect count(*) as n from young;\n",
+			wantOut: "name\nBob\nn\n1\n",
+		},
+		{
+			name:    "errors return to the prompt",
+			input:   "select from t;\nselect id from t where id = 2;\n",
+			wantOut: "id\n2\n",
+			wantErr: "Error: parsing query:",
+		},
+		{
+			name:    "switch format",
+			input:   "\\f jsonl\nselect id from t where id = 1;\n\\f xml\n",
+			wantOut: "{\"id\":1}\n",
+			wantErr: "Error: unsupported format 'xml'",
+		},
+		{
+			name:    "schema",
+			input:   "\\f jsonl\n\\d\n",
+			wantOut: `"name":"salary"`,
+		},
+		{
+			name:    "quit",
+			input:   "\\q\nselect id from t;\n",
+			wantOut: "",
+		},
+		{
+			name:    "unknown command",
+			input:   "\\x\n",
+			wantErr: "Error: unknown command \\x",
+		},
+		{
+			name:        "interactive prompts and row counts",
+			input:       "select id\nfrom t where id = 1;\n",
+			interactive: true,
+			wantOut:     replPrompt + replContinuationPrompt + "id\n1\n(1 rows)\n" + replPrompt + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldFormat := *formatFlag
+			defer func() { *formatFlag = oldFormat }()
+			*formatFlag = "csv"
+
+			var out, errOut bytes.Buffer
+			runREPL(strings.NewReader(tt.input), &out, &errOut, testFile, output.NaNNull, tt.interactive)
+
+			if tt.wantOut == "" && out.Len() > 0 || !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if tt.wantErr == "" && errOut.Len() > 0 || !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("errors = %q, want %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunREPL_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	testFile := createTestParquetFile(t, dir, "people.parquet", []TestRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+	})
+
+	oldFormat, oldTimeout := *formatFlag, *timeoutFlag
+	defer func() { *formatFlag, *timeoutFlag = oldFormat, oldTimeout }()
+	*formatFlag = "csv"
+
+	// Each statement gets its own timeout, so both fail rather than the
+	// second one finding an expired session
+	*timeoutFlag = time.Nanosecond
+	var out, errOut bytes.Buffer
+	runREPL(strings.NewReader("select id from t;\nselect id from t;\n"), &out, &errOut, testFile, output.NaNNull, false)
+	if got := strings.Count(errOut.String(), "query timed out after 1ns"); got != 2 {
+		t.Errorf("errors = %q, want 2 timeouts", errOut.String())
+	}
+
+	*timeoutFlag = time.Minute
+	out.Reset()
+	errOut.Reset()
+	runREPL(strings.NewReader("select id from t;\n"), &out, &errOut, testFile, output.NaNNull, false)
+	if out.String() != "id\n1\n" || errOut.Len() > 0 {
+		t.Errorf("output = %q, errors = %q, want the row", out.String(), errOut.String())
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/vegasq/parcat/output"
+	"github.com/vegasq/parcat/query"
+)
+
+// queryContext bounds the running query; -timeout cancels it
+var queryContext = context.Background()
+
+// withTimeout returns a context canceled after d, whose cause names the
+// -timeout so errors read "query timed out after 30s"
+func withTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(context.Background(), d, fmt.Errorf("query timed out after %v", d))
+}
+
+// runScript runs each statement of a multi-statement query in order and
+// prints the results as sections labeled with the statement text. Every
+// statement is parsed before any is run, so a typo fails before data is read.
+func runScript(statements []string, filename string, nanHandling output.NaNHandling) {
+	queries := make([]*query.Query, len(statements))
+	for i, statement := range statements {
+		q, err := query.Parse(statement)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing statement %d: %v\n", i+1, err)
+			os.Exit(1)
+		}
+		queries[i] = q
+	}
+
+	sections := make([]output.Section, len(queries))
+	for i, q := range queries {
+		// A positional file replaces the FROM table of every statement, as it
+		// does for a single query; without one each statement reads its own
+		file := filename
+		if q.TableName != "" && file == "" {
+			file = q.TableName
+		}
+		sections[i] = output.Section{Label: statements[i], Rows: loadRows(q, statements[i], file, limitPushdown(q, *limitFlag))}
+	}
+
+	out, closeOut := mustOpenOutput()
+	err := output.FormatSections(newFormatter(out, nanHandling), out, sections)
+	if closeErr := closeOut(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadRows returns the result of the query, parsed from queryText, against
+// filename with the -limit flag applied, serving it from the result cache
+// when enabled
+func loadRows(q *query.Query, queryText, filename string, maxRows int64) []map[string]interface{} {
+	var rows []map[string]interface{}
+
+	// Serve repeated queries from the result cache when enabled
+	var cache *resultCache
+	var cacheKey string
+	var recorder inputRecorder
+	if q != nil && *cacheDirFlag != "" && !*noCacheFlag {
+		// Relative paths in the query resolve against the working directory
+		cwd, _ := os.Getwd()
+		extra := []string{"file=" + filename, "cwd=" + cwd}
+		if readOptions.Rand != nil {
+			extra = append(extra, fmt.Sprintf("seed=%d", *seedFlag))
+		}
+		// A limited read caches a truncated result
+		if maxRows > 0 {
+			extra = append(extra, fmt.Sprintf("maxrows=%d", maxRows))
+		}
+		if readOptions.InferTypes {
+			extra = append(extra, "infer-types")
+		}
+		if *strictFlag {
+			extra = append(extra, "strict-joins")
+		}
+		if *aliasFlag {
+			extra = append(extra, "where-aliases")
+		}
+		if key, ok := normalizeQuery(queryText, readOptions.Rand != nil, extra...); ok {
+			var err error
+			cache, err = newResultCache(*cacheDirFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: result cache disabled: %v\n", err)
+			} else {
+				cacheKey = key
+				readOptions.OnFiles = recorder.record
+				defer func() { readOptions.OnFiles = nil }()
+			}
+		}
+	}
+
+	cached := false
+	if cache != nil {
+		rows, cached = cache.get(cacheKey)
+	}
+	if !cached {
+		rows = runQuery(q, filename, maxRows)
+
+		if cache != nil && recorder.err == nil {
+			if err := cache.put(cacheKey, recorder.inputs, rows); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache result: %v\n", err)
+			}
+		}
+	}
+
+	// Apply flag-based limit only if SQL LIMIT was not specified
+	if *limitFlag > 0 && (q == nil || q.Limit == nil) && len(rows) > *limitFlag {
+		rows = rows[:*limitFlag]
+	}
+	return rows
+}
+
+// runQuery reads the input and applies the query, if any, returning the
+// result rows. Errors are reported on stderr and exit the process.
+//
+// If maxRows is positive, reading the main table stops after that many rows.
+func runQuery(q *query.Query, filename string, maxRows int64) []map[string]interface{} {
+	if q == nil {
+		if filename == "" {
+			fmt.Fprintf(os.Stderr, "Error: missing parquet file argument\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		opts := readOptions
+		opts.MaxRows = maxRows
+		opts.Context = queryContext
+		rows, err := query.ReadTable(filename, opts, nil)
+		if err != nil {
+			exitQueryError(err)
+		}
+		return rows
+	}
+
+	ctx := newExecutionContext()
+	// limitPushdown only allows a limit for a single table read
+	ctx.ReadOptions.MaxRows = maxRows
+	rows, err := ctx.Execute(withFile(q, filename, ctx))
+	if err != nil {
+		exitQueryError(err)
+	}
+	return rows
+}
+
+// newExecutionContext returns a query execution context configured by the
+// command line flags
+func newExecutionContext() *query.ExecutionContext {
+	ctx := query.NewExecutionContext(nil)
+	ctx.ReadOptions = readOptions
+	ctx.StrictJoins = *strictFlag
+	ctx.WhereAliases = *aliasFlag
+	ctx.Context = queryContext
+	ctx.MaxIntermediateRows = *maxRowsFlag
+	return ctx
+}
+
+// withFile returns q reading filename, the file given on the command line,
+// as its FROM table. q is returned unchanged if filename is empty or q reads
+// a CTE, a subquery or a UNION, whose arms name their own tables.
+func withFile(q *query.Query, filename string, ctx *query.ExecutionContext) *query.Query {
+	if filename == "" || q.Subquery != nil || len(q.SetOperations) > 0 {
+		return q
+	}
+	if _, isCTE := ctx.CTEs[q.TableName]; isCTE {
+		return q
+	}
+	for _, cte := range q.CTEs {
+		if cte.Name == q.TableName {
+			return q
+		}
+	}
+	fromFile := *q
+	fromFile.TableName = filename
+	return &fromFile
+}
+
+// exitQueryError reports an error running a query on stderr and exits
+func exitQueryError(err error) {
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && errors.As(err, &pathErr):
+		fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", pathErr.Path)
+		fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
+	case errors.Is(err, query.ErrTooManyRows):
+		fmt.Fprintf(os.Stderr, "Error executing query: %v (raise -max-rows to allow more)\n", err)
+	default:
+		fmt.Fprintf(os.Stderr, "Error executing query: %v\n", err)
+	}
+	os.Exit(1)
+}
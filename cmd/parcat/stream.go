@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/vegasq/parcat/output"
+	"github.com/vegasq/parcat/reader"
+)
+
+// canStream reports whether the rows of filename can be formatted as they
+// are read rather than loaded first. That holds for a single file, not a
+// glob whose rows are tagged by file, read without options that need every
+// row (type inference) or report on the whole read (progress).
+func canStream(filename string) bool {
+	if filename == "" || readOptions.InferTypes || readOptions.OnProgress != nil {
+		return false
+	}
+	files, err := reader.ExpandPattern(filename)
+	return err == nil && len(files) == 1 && files[0] == filename
+}
+
+// streamFile formats the rows of a single file as they are read, stopping
+// after limit rows when limit is positive, so files larger than memory can
+// be printed. Open errors exit the process; read and format errors are
+// returned.
+func streamFile(filename string, limit int, formatter output.Formatter) error {
+	r, err := reader.NewReader(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: file '%s' not found\n", filename)
+			fmt.Fprintf(os.Stderr, "Please check the file path and try again.\n")
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+	defer func() { _ = r.Close() }()
+
+	if orderer, ok := formatter.(output.ColumnOrderer); ok {
+		var columns []string
+		for _, field := range r.Schema().Fields() {
+			columns = append(columns, field.Name())
+		}
+		orderer.SetColumnOrder(columns)
+	}
+
+	it, err := r.Rows()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = it.Close() }()
+
+	rows := make(chan map[string]interface{})
+	done := make(chan error)
+	go func() { done <- formatter.FormatStream(rows) }()
+
+	sent := 0
+	for (limit <= 0 || sent < limit) && it.Next() {
+		// The iterator reuses its map for the next row
+		rows <- maps.Clone(it.Row())
+		sent++
+	}
+	close(rows)
+
+	if err := <-done; err != nil {
+		return err
+	}
+	return it.Err()
+}
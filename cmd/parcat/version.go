@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// parquetModule is the module path of the parquet library reported by -version
+const parquetModule = "github.com/parquet-go/parquet-go"
+
+// versionInfo describes the running binary using the build info embedded by
+// the Go toolchain
+func versionInfo() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return formatVersion(info)
+	}
+	return formatVersion(nil)
+}
+
+// formatVersion formats the parcat version, the parquet-go version and the Go
+// version from build info. Binaries built from a checkout report "(devel)"
+// plus the VCS revision when the toolchain recorded one. info may be nil if
+// the binary carries no build info.
+func formatVersion(info *debug.BuildInfo) string {
+	version, parquetVersion, goVersion := "unknown", "unknown", runtime.Version()
+
+	if info != nil {
+		if info.Main.Version != "" {
+			version = info.Main.Version
+		}
+		if revision := vcsRevision(info); revision != "" && version == "(devel)" {
+			version += " " + revision
+		}
+		for _, dep := range info.Deps {
+			if dep.Path != parquetModule {
+				continue
+			}
+			parquetVersion = dep.Version
+			if dep.Replace != nil {
+				parquetVersion = fmt.Sprintf("%s => %s %s", dep.Version, dep.Replace.Path, dep.Replace.Version)
+			}
+		}
+		if info.GoVersion != "" {
+			goVersion = info.GoVersion
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "parcat %s\n", version)
+	fmt.Fprintf(&b, "parquet-go %s\n", parquetVersion)
+	fmt.Fprintf(&b, "go %s\n", strings.TrimPrefix(goVersion, "go"))
+	return b.String()
+}
+
+// vcsRevision returns the short VCS revision recorded in build info, marked
+// dirty if the tree had uncommitted changes, or "" if none was recorded
+func vcsRevision(info *debug.BuildInfo) string {
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return ""
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
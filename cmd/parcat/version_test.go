@@ -0,0 +1,77 @@
+package main
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestFormatVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		info *debug.BuildInfo
+		want string
+	}{
+		{
+			name: "release build",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.24.1",
+				Main:      debug.Module{Path: "github.com/vegasq/parcat", Version: "v1.2.0"},
+				Deps: []*debug.Module{
+					{Path: "github.com/google/uuid", Version: "v1.6.0"},
+					{Path: parquetModule, Version: "v0.27.0"},
+				},
+			},
+			want: "parcat v1.2.0\nparquet-go v0.27.0\ngo 1.24.1\n",
+		},
+		{
+			name: "development build with VCS info",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.24.1",
+				Main:      debug.Module{Path: "github.com/vegasq/parcat", Version: "(devel)"},
+				Deps:      []*debug.Module{{Path: parquetModule, Version: "v0.27.0"}},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			want: "parcat (devel) 0123456789ab-dirty\nparquet-go v0.27.0\ngo 1.24.1\n",
+		},
+		{
+			name: "replaced dependency",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.24.1",
+				Main:      debug.Module{Path: "github.com/vegasq/parcat", Version: "v1.2.0"},
+				Deps: []*debug.Module{{
+					Path:    parquetModule,
+					Version: "v0.27.0",
+					Replace: &debug.Module{Path: "../parquet-go", Version: "(devel)"},
+				}},
+			},
+			want: "parcat v1.2.0\nparquet-go v0.27.0 => ../parquet-go (devel)\ngo 1.24.1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatVersion(tt.info); got != tt.want {
+				t.Errorf("formatVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatVersion_NoBuildInfo(t *testing.T) {
+	got := formatVersion(nil)
+	if !strings.HasPrefix(got, "parcat unknown\nparquet-go unknown\ngo ") {
+		t.Errorf("formatVersion(nil) = %q", got)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	// Test binaries carry build info, so the real parquet-go version is reported
+	got := versionInfo()
+	if !strings.Contains(got, "parquet-go v") {
+		t.Errorf("versionInfo() = %q, want the parquet-go module version", got)
+	}
+}
@@ -2,12 +2,14 @@ module github.com/vegasq/parcat
 
 go 1.24.9
 
-require github.com/parquet-go/parquet-go v0.27.0
+require (
+	github.com/klauspost/compress v1.18.4
+	github.com/parquet-go/parquet-go v0.27.0
+)
 
 require (
 	github.com/andybalholm/brotli v1.2.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/klauspost/compress v1.18.4 // indirect
 	github.com/parquet-go/bitpack v1.0.0 // indirect
 	github.com/parquet-go/jsonlite v1.0.0 // indirect
 	github.com/pierrec/lz4/v4 v4.1.25 // indirect
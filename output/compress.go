@@ -0,0 +1,95 @@
+package output
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression selects how formatted output is compressed
+type Compression int
+
+const (
+	// CompressNone writes output as is (the default)
+	CompressNone Compression = iota
+	// CompressGzip compresses output with gzip
+	CompressGzip
+	// CompressZstd compresses output with zstd
+	CompressZstd
+)
+
+// ParseCompression parses "none", "gzip" or "zstd" into a Compression
+func ParseCompression(s string) (Compression, error) {
+	switch s {
+	case "none":
+		return CompressNone, nil
+	case "gzip":
+		return CompressGzip, nil
+	case "zstd":
+		return CompressZstd, nil
+	default:
+		return CompressNone, fmt.Errorf("invalid compression %q (expected none, gzip or zstd)", s)
+	}
+}
+
+// CompressionForPath returns the compression implied by a file name's
+// extension: gzip for .gz, zstd for .zst and none otherwise
+func CompressionForPath(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return CompressGzip
+	case strings.HasSuffix(path, ".zst"):
+		return CompressZstd
+	default:
+		return CompressNone
+	}
+}
+
+// NewGzipWriter returns a writer that gzip-compresses what a formatter
+// writes to it before writing it to w. Close it once formatting is done to
+// flush the compressed data; w itself is not closed.
+//
+//	gz := output.NewGzipWriter(file)
+//	formatter.SetOutput(gz)
+//	if err := formatter.Format(rows); err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := gz.Close(); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewGzipWriter(w io.Writer) io.WriteCloser {
+	return gzip.NewWriter(w)
+}
+
+// NewCompressedWriter returns a writer compressing to w with c. Like
+// NewGzipWriter it must be closed to flush the compressed data, and does not
+// close w. With CompressNone, writes go straight to w and Close does nothing.
+func NewCompressedWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case CompressNone:
+		return nopWriteCloser{w}, nil
+	case CompressGzip:
+		return NewGzipWriter(w), nil
+	case CompressZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		return zw, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", c)
+	}
+}
+
+// nopWriteCloser adds a Close method that does nothing to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close implements io.Closer
+func (nopWriteCloser) Close() error {
+	return nil
+}
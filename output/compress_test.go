@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNewCompressedWriter(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(2), "name": "bob"},
+	}
+	want := "id,name\n1,alice\n2,bob\n"
+
+	tests := []struct {
+		name        string
+		compression Compression
+		decompress  func(r io.Reader) (io.Reader, error)
+	}{
+		{
+			name:        "none",
+			compression: CompressNone,
+			decompress:  func(r io.Reader) (io.Reader, error) { return r, nil },
+		},
+		{
+			name:        "gzip",
+			compression: CompressGzip,
+			decompress:  func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+		{
+			name:        "zstd",
+			compression: CompressZstd,
+			decompress:  func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCompressedWriter(&buf, tt.compression)
+			if err != nil {
+				t.Fatalf("NewCompressedWriter() error = %v", err)
+			}
+
+			formatter := NewCSVFormatter(nil)
+			formatter.SetOutput(w)
+			if err := formatter.Format(rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			r, err := tt.decompress(&buf)
+			if err != nil {
+				t.Fatalf("failed to open compressed output: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to decompress output: %v", err)
+			}
+			if string(got) != want {
+				t.Errorf("decompressed output = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Compression
+		wantErr bool
+	}{
+		{input: "none", want: CompressNone},
+		{input: "gzip", want: CompressGzip},
+		{input: "zstd", want: CompressZstd},
+		{input: "brotli", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCompression(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCompression(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCompression(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressionForPath(t *testing.T) {
+	tests := map[string]Compression{
+		"out.jsonl.gz": CompressGzip,
+		"out.csv.zst":  CompressZstd,
+		"out.csv":      CompressNone,
+		"gz":           CompressNone,
+	}
+
+	for path, want := range tests {
+		if got := CompressionForPath(path); got != want {
+			t.Errorf("CompressionForPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -1,21 +1,78 @@
 package output
 
 import (
+	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
-	"sort"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// CSVQuoting controls which CSV fields are enclosed in double quotes
+type CSVQuoting int
+
+const (
+	// QuoteMinimal quotes only fields containing the delimiter, a quote or a
+	// line break (the default)
+	QuoteMinimal CSVQuoting = iota
+	// QuoteAlways quotes every field except NULLs, so loaders can tell an
+	// empty string from a NULL
+	QuoteAlways
+)
+
+// ParseCSVQuoting parses "minimal" or "always" into a CSVQuoting
+func ParseCSVQuoting(s string) (CSVQuoting, error) {
+	switch s {
+	case "minimal":
+		return QuoteMinimal, nil
+	case "always":
+		return QuoteAlways, nil
+	default:
+		return QuoteMinimal, fmt.Errorf("invalid CSV quoting %q (expected minimal or always)", s)
+	}
+}
+
+// ParseDelimiter parses a CSV delimiter, which must be a single character.
+// The escape \t is accepted for a tab.
+func ParseDelimiter(s string) (rune, error) {
+	if s == `\t` {
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("delimiter %q must be a single character", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	if err := validDelimiter(r); err != nil {
+		return 0, err
+	}
+	return r, nil
+}
+
+// validDelimiter reports whether r can separate CSV fields
+func validDelimiter(r rune) error {
+	if r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError || !utf8.ValidRune(r) {
+		return fmt.Errorf("invalid delimiter %q", r)
+	}
+	return nil
+}
+
 // CSVFormatter outputs rows as CSV format
 type CSVFormatter struct {
-	writer io.Writer
+	writer     io.Writer
+	delimiter  rune
+	nullString string
+	quoting    CSVQuoting
+	columns    []string
 }
 
 // NewCSVFormatter creates a new CSV formatter
 func NewCSVFormatter(w io.Writer) *CSVFormatter {
-	return &CSVFormatter{writer: w}
+	return &CSVFormatter{writer: w, delimiter: ','}
 }
 
 // SetOutput sets the output writer
@@ -23,59 +80,181 @@ func (c *CSVFormatter) SetOutput(w io.Writer) {
 	c.writer = w
 }
 
-// Format writes rows as CSV
-func (c *CSVFormatter) Format(rows []map[string]interface{}) error {
-	csvWriter := csv.NewWriter(c.writer)
+// SetDelimiter sets the field delimiter (comma by default). It returns an
+// error for a double quote, a line break or an invalid rune.
+func (c *CSVFormatter) SetDelimiter(r rune) error {
+	if err := validDelimiter(r); err != nil {
+		return err
+	}
+	c.delimiter = r
+	return nil
+}
+
+// SetNullString sets the text written for NULL values, such as \N for
+// database bulk loaders (empty by default)
+func (c *CSVFormatter) SetNullString(s string) {
+	c.nullString = s
+}
+
+// SetColumnOrder sets the order of the header columns, see ColumnOrderer
+func (c *CSVFormatter) SetColumnOrder(columns []string) {
+	c.columns = columns
+}
+
+// SetQuoting sets which fields are quoted
+func (c *CSVFormatter) SetQuoting(q CSVQuoting) {
+	c.quoting = q
+}
 
+// Format writes rows as CSV. The header is the union of the keys of all
+// rows, in column order (see SetColumnOrder), and NULLs and keys missing from a row are written as the null
+// string.
+func (c *CSVFormatter) Format(rows []map[string]interface{}) error {
 	if len(rows) == 0 {
-		csvWriter.Flush()
-		if err := csvWriter.Error(); err != nil {
-			return fmt.Errorf("failed to flush CSV writer: %w", err)
-		}
 		return nil
 	}
 
-	// Extract all unique column names from all rows (in case of heterogeneous schemas)
-	// This handles cases like OUTER JOINs or sparse data where different rows may have different columns
-	columnSet := make(map[string]bool)
+	w := c.newRecordWriter(unionColumns(rows, c.columns))
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
 	for _, row := range rows {
-		for col := range row {
-			columnSet[col] = true
+		if err := w.writeRow(row); err != nil {
+			return err
 		}
 	}
+	return w.flush()
+}
 
-	// Convert to sorted slice for consistent ordering
-	columns := make([]string, 0, len(columnSet))
-	for col := range columnSet {
-		columns = append(columns, col)
+// FormatStream writes each row as CSV as soon as it is received. The header
+// is the keys of the first row, in column order, so a later row with a column the
+// first lacks is an error, while columns missing from a row are written as
+// the null string.
+func (c *CSVFormatter) FormatStream(rows <-chan map[string]interface{}) error {
+	var w *csvRecordWriter
+	i := 0
+	for row := range rows {
+		if w == nil {
+			w = c.newRecordWriter(unionColumns([]map[string]interface{}{row}, c.columns))
+			if err := w.writeHeader(); err != nil {
+				drain(rows)
+				return err
+			}
+		}
+		if col, ok := w.unknownColumn(row); ok {
+			drain(rows)
+			return fmt.Errorf("row %d has column %q, which is not in the CSV header", i+1, col)
+		}
+		if err := w.writeRow(row); err != nil {
+			drain(rows)
+			return err
+		}
+		if err := w.flush(); err != nil {
+			drain(rows)
+			return err
+		}
+		i++
 	}
-	sort.Strings(columns)
+	return nil
+}
 
-	// Write header
-	if err := csvWriter.Write(columns); err != nil {
-		return err
+// csvRecordWriter writes CSV records with a fixed set of columns. Minimally
+// quoted records are written by encoding/csv, which cannot quote every
+// field, so QuoteAlways records are written directly.
+type csvRecordWriter struct {
+	formatter *CSVFormatter
+	columns   []string
+	csv       *csv.Writer   // for QuoteMinimal
+	out       *bufio.Writer // for QuoteAlways
+}
+
+// newRecordWriter returns a writer for records with the given columns
+func (c *CSVFormatter) newRecordWriter(columns []string) *csvRecordWriter {
+	w := &csvRecordWriter{formatter: c, columns: columns}
+	if c.quoting == QuoteAlways {
+		w.out = bufio.NewWriter(c.writer)
+	} else {
+		w.csv = csv.NewWriter(c.writer)
+		w.csv.Comma = c.delimiter
 	}
+	return w
+}
 
-	// Write rows
-	for _, row := range rows {
-		record := make([]string, len(columns))
-		for i, col := range columns {
-			record[i] = formatValue(row[col])
+// writeHeader writes the column names
+func (w *csvRecordWriter) writeHeader() error {
+	if w.csv != nil {
+		return w.csv.Write(w.columns)
+	}
+	for i, col := range w.columns {
+		if i > 0 {
+			w.out.WriteRune(w.formatter.delimiter)
 		}
-		if err := csvWriter.Write(record); err != nil {
-			return err
+		w.out.WriteString(quoteField(col))
+	}
+	_, err := w.out.WriteString("\n")
+	return err
+}
+
+// writeRow writes the values of a row in column order
+func (w *csvRecordWriter) writeRow(row map[string]interface{}) error {
+	if w.csv != nil {
+		record := make([]string, len(w.columns))
+		for i, col := range w.columns {
+			if row[col] == nil {
+				record[i] = w.formatter.nullString
+			} else {
+				record[i] = formatValue(row[col])
+			}
 		}
+		return w.csv.Write(record)
 	}
 
-	// Flush and check for errors
-	csvWriter.Flush()
-	if err := csvWriter.Error(); err != nil {
-		return fmt.Errorf("failed to flush CSV writer: %w", err)
+	// Every field is quoted except NULLs, which are written as the null string
+	for i, col := range w.columns {
+		if i > 0 {
+			w.out.WriteRune(w.formatter.delimiter)
+		}
+		if row[col] == nil {
+			w.out.WriteString(w.formatter.nullString)
+		} else {
+			w.out.WriteString(quoteField(formatValue(row[col])))
+		}
 	}
+	_, err := w.out.WriteString("\n")
+	return err
+}
 
+// unknownColumn returns a column of row that is not one of the writer's
+// columns
+func (w *csvRecordWriter) unknownColumn(row map[string]interface{}) (string, bool) {
+	for col := range row {
+		if !slices.Contains(w.columns, col) {
+			return col, true
+		}
+	}
+	return "", false
+}
+
+// flush writes any buffered records to the output
+func (w *csvRecordWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return fmt.Errorf("failed to flush CSV writer: %w", err)
+		}
+		return nil
+	}
+	if err := w.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
 	return nil
 }
 
+// quoteField encloses a field in double quotes, doubling any quotes inside it
+func quoteField(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
 // formatValue converts a value to string for CSV output
 func formatValue(v interface{}) string {
 	if v == nil {
@@ -102,8 +281,19 @@ func formatValue(v interface{}) string {
 		return fmt.Sprintf("%g", val)
 	case bool:
 		return fmt.Sprintf("%t", val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
 	default:
-		// For complex types, use JSON representation
+		// For complex types (lists, maps), use JSON representation
+		if _, isBytes := val.([]byte); isBytes {
+			return fmt.Sprintf("%v", val)
+		}
+		kind := reflect.ValueOf(val).Kind()
+		if kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+			if encoded, err := json.Marshal(val); err == nil {
+				return string(encoded)
+			}
+		}
 		return fmt.Sprintf("%v", val)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/csv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCSVFormatter_Format(t *testing.T) {
@@ -114,6 +115,30 @@ func TestCSVFormatter_ColumnOrder(t *testing.T) {
 	}
 }
 
+func TestCSVFormatter_HeterogeneousRows(t *testing.T) {
+	// Rows from files with different schemas must align to a single header
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(2), "email": "bob@example.com", "score": 9.5},
+		{"name": "carol"},
+	}
+
+	var buf bytes.Buffer
+	formatter := NewCSVFormatter(&buf)
+
+	if err := formatter.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := "email,id,name,score\n" +
+		",1,alice,\n" +
+		"bob@example.com,2,,9.5\n" +
+		",,carol,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
 func TestCSVFormatter_TypeFormatting(t *testing.T) {
 	rows := []map[string]interface{}{
 		{
@@ -122,6 +147,7 @@ func TestCSVFormatter_TypeFormatting(t *testing.T) {
 			"float":  float64(3.14),
 			"bool":   true,
 			"nil":    nil,
+			"time":   time.Date(2024, 3, 15, 10, 30, 0, 500000000, time.UTC),
 		},
 	}
 
@@ -170,6 +196,35 @@ func TestCSVFormatter_TypeFormatting(t *testing.T) {
 	if getValue("nil") != "" {
 		t.Errorf("nil column should be empty, got %q", getValue("nil"))
 	}
+	if getValue("time") != "2024-03-15T10:30:00.5Z" {
+		t.Errorf("time column should be '2024-03-15T10:30:00.5Z', got %q", getValue("time"))
+	}
+}
+
+func TestCSVFormatter_ListValues(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"names": []interface{}{"alice", "bob", nil}},
+	}
+
+	var buf bytes.Buffer
+	formatter := NewCSVFormatter(&buf)
+
+	if err := formatter.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records (header + data), got %d", len(records))
+	}
+
+	if records[1][0] != `["alice","bob",null]` {
+		t.Errorf("list column should be JSON-encoded, got %q", records[1][0])
+	}
 }
 
 func TestCSVFormatter_SpecialCharacters(t *testing.T) {
@@ -244,3 +299,89 @@ func TestCSVFormatter_SetOutput(t *testing.T) {
 		t.Error("Second buffer should have content")
 	}
 }
+
+func TestCSVFormatter_Options(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "Smith; John", "note": nil},
+		{"id": int64(2), "name": `say "hi"`, "note": ""},
+	}
+
+	tests := []struct {
+		name       string
+		delimiter  rune
+		nullString string
+		quoting    CSVQuoting
+		want       string
+	}{
+		{
+			name:      "semicolon delimiter",
+			delimiter: ';',
+			want:      "id;name;note\n1;\"Smith; John\";\n2;\"say \"\"hi\"\"\";\n",
+		},
+		{
+			name:       "custom null string",
+			delimiter:  ',',
+			nullString: `\N`,
+			want:       "id,name,note\n1,Smith; John,\\N\n2,\"say \"\"hi\"\"\",\n",
+		},
+		{
+			name:       "always quote",
+			delimiter:  '|',
+			nullString: `\N`,
+			quoting:    QuoteAlways,
+			want:       "\"id\"|\"name\"|\"note\"\n\"1\"|\"Smith; John\"|\\N\n\"2\"|\"say \"\"hi\"\"\"|\"\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := NewCSVFormatter(&buf)
+			if err := formatter.SetDelimiter(tt.delimiter); err != nil {
+				t.Fatalf("SetDelimiter() error = %v", err)
+			}
+			formatter.SetNullString(tt.nullString)
+			formatter.SetQuoting(tt.quoting)
+
+			if err := formatter.Format(rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    rune
+		wantErr bool
+	}{
+		{input: ";", want: ';'},
+		{input: "|", want: '|'},
+		{input: `\t`, want: '\t'},
+		{input: "§", want: '§'},
+		{input: "", wantErr: true},
+		{input: ";;", wantErr: true},
+		{input: `"`, wantErr: true},
+		{input: "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDelimiter(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDelimiter(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDelimiter(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if err := NewCSVFormatter(nil).SetDelimiter('"'); err == nil {
+		t.Error("SetDelimiter('\"') expected error")
+	}
+}
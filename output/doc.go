@@ -8,6 +8,9 @@
 //
 //   - JSON Lines: One JSON object per line (suitable for streaming)
 //   - CSV: Comma-separated values with header row
+//   - Raw: Bare values of a single-column result, one per line
+//   - Table: Bordered table with aligned columns for reading in a terminal
+//   - Markdown: GitHub-flavored Markdown table
 //
 // # Basic Usage
 //
@@ -43,6 +46,20 @@
 //	    log.Fatal(err)
 //	}
 //
+// # Compressing Output
+//
+// Wrap the destination with NewGzipWriter or NewCompressedWriter, and close
+// the wrapper after formatting to flush the compressed data:
+//
+//	gz := output.NewGzipWriter(file)
+//	formatter.SetOutput(gz)
+//	if err := formatter.Format(rows); err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := gz.Close(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
 // # Using as String
 //
 // Write to a bytes buffer to get string output:
@@ -60,9 +77,26 @@
 //
 //	type Formatter interface {
 //	    Format(rows []map[string]interface{}) error
+//	    FormatStream(rows <-chan map[string]interface{}) error
 //	    SetOutput(w io.Writer)
 //	}
 //
+// # Column Order
+//
+// Rows are maps, so columns are sorted by default. Formatters implementing
+// ColumnOrderer accept an explicit order, such as the fields of a file's
+// schema, with other columns sorted after the listed ones:
+//
+//	formatter.SetColumnOrder([]string{"id", "name", "age"})
+//
+// # Streaming
+//
+// FormatStream writes rows received from a channel until it is closed. The
+// JSON, CSV and raw formatters write each row as it arrives, so a file read
+// with a row iterator can be printed in constant memory; the CSV header is
+// then taken from the first row. The table and Markdown formatters collect
+// all rows first, since their layout depends on every row.
+//
 // # Type Handling
 //
 // The formatters handle common Go types automatically:
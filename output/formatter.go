@@ -3,6 +3,9 @@
 // Currently supported formats:
 //   - JSON Lines: One JSON object per line
 //   - CSV: Comma-separated values with header row
+//   - Raw: Bare values of a single-column result, one per line
+//   - Table: Bordered table with aligned columns for reading in a terminal
+//   - Markdown: GitHub-flavored Markdown table
 //
 // Example usage:
 //
@@ -12,7 +15,10 @@
 //	}
 package output
 
-import "io"
+import (
+	"io"
+	"sort"
+)
 
 // Formatter defines the interface for output formatters.
 //
@@ -22,6 +28,63 @@ type Formatter interface {
 	// Format writes rows in the formatter's specific format
 	Format(rows []map[string]interface{}) error
 
+	// FormatStream writes rows received from the channel until it is
+	// closed. Formatters that can write a row without seeing the others do
+	// so as each row arrives. On error the remaining rows are drained so
+	// the sender is not blocked.
+	FormatStream(rows <-chan map[string]interface{}) error
+
 	// SetOutput changes the output writer
 	SetOutput(w io.Writer)
 }
+
+// drain discards the rows remaining in a channel until it is closed
+func drain(rows <-chan map[string]interface{}) {
+	for range rows {
+	}
+}
+
+// collect returns all rows received from a channel, for formatters that
+// need every row before writing any
+func collect(rows <-chan map[string]interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	for row := range rows {
+		result = append(result, row)
+	}
+	return result
+}
+
+// ColumnOrderer is implemented by formatters whose column order can be set,
+// such as to the field order of a file's schema
+type ColumnOrderer interface {
+	// SetColumnOrder sets the order of the listed columns. Columns that
+	// aren't listed follow them in sorted order, and listed columns that no
+	// row has are left out. Without an order all columns are sorted.
+	SetColumnOrder(columns []string)
+}
+
+// unionColumns returns the union of the keys of all rows, with the columns
+// in order first and the rest sorted. Rows may have different columns, such
+// as after an OUTER JOIN or across globbed files with different schemas.
+func unionColumns(rows []map[string]interface{}, order []string) []string {
+	columnSet := make(map[string]bool)
+	for _, row := range rows {
+		for col := range row {
+			columnSet[col] = true
+		}
+	}
+
+	columns := make([]string, 0, len(columnSet))
+	for _, col := range order {
+		if columnSet[col] {
+			columns = append(columns, col)
+			delete(columnSet, col)
+		}
+	}
+	rest := make([]string, 0, len(columnSet))
+	for col := range columnSet {
+		rest = append(rest, col)
+	}
+	sort.Strings(rest)
+	return append(columns, rest...)
+}
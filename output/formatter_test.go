@@ -0,0 +1,226 @@
+package output
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// sendRows streams rows over an unbuffered channel to FormatStream and
+// returns its error
+func sendRows(f Formatter, rows []map[string]interface{}) error {
+	ch := make(chan map[string]interface{})
+	done := make(chan error)
+	go func() { done <- f.FormatStream(ch) }()
+	for _, row := range rows {
+		ch <- row
+	}
+	close(ch)
+	return <-done
+}
+
+func TestFormatStream_MatchesFormat(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "alice", "score": 9.5},
+		{"id": int64(2), "name": "bob", "score": nil},
+	}
+
+	formatters := map[string]func(buf *bytes.Buffer) Formatter{
+		"json":     func(buf *bytes.Buffer) Formatter { return NewJSONFormatter(buf) },
+		"csv":      func(buf *bytes.Buffer) Formatter { return NewCSVFormatter(buf) },
+		"table":    func(buf *bytes.Buffer) Formatter { return NewTableFormatter(buf) },
+		"markdown": func(buf *bytes.Buffer) Formatter { return NewMarkdownFormatter(buf) },
+		"csv always quoted": func(buf *bytes.Buffer) Formatter {
+			f := NewCSVFormatter(buf)
+			f.SetQuoting(QuoteAlways)
+			return f
+		},
+	}
+
+	for name, newFormatter := range formatters {
+		t.Run(name, func(t *testing.T) {
+			var want, got bytes.Buffer
+			if err := newFormatter(&want).Format(rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if err := sendRows(newFormatter(&got), rows); err != nil {
+				t.Fatalf("FormatStream() error = %v", err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("FormatStream() = %q, want %q", got.String(), want.String())
+			}
+		})
+	}
+}
+
+// chanWriter passes each write to a channel, blocking until it is received
+type chanWriter chan string
+
+func (c chanWriter) Write(p []byte) (int, error) {
+	c <- string(p)
+	return len(p), nil
+}
+
+func TestFormatStream_WritesRowsAsTheyArrive(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter func(w chanWriter) Formatter
+		want      []string
+	}{
+		{
+			name:      "json",
+			formatter: func(w chanWriter) Formatter { return NewJSONFormatter(w) },
+			want:      []string{`{"id":1}` + "\n", `{"id":2}` + "\n"},
+		},
+		{
+			name:      "csv",
+			formatter: func(w chanWriter) Formatter { return NewCSVFormatter(w) },
+			want:      []string{"id\n1\n", "2\n"},
+		},
+		{
+			name:      "raw",
+			formatter: func(w chanWriter) Formatter { return NewRawFormatter(w) },
+			want:      []string{"1\n", "2\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes := make(chanWriter)
+			ch := make(chan map[string]interface{})
+			done := make(chan error)
+			go func() { done <- tt.formatter(writes).FormatStream(ch) }()
+
+			// Each row is written before the next one is sent
+			for i, want := range tt.want {
+				ch <- map[string]interface{}{"id": int64(i + 1)}
+				if got := <-writes; got != want {
+					t.Errorf("write %d = %q, want %q", i+1, got, want)
+				}
+			}
+
+			close(ch)
+			if err := <-done; err != nil {
+				t.Fatalf("FormatStream() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFormatStream_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter Formatter
+		rows      []map[string]interface{}
+		wantErr   string
+	}{
+		{
+			name:      "csv column not in header",
+			formatter: NewCSVFormatter(&bytes.Buffer{}),
+			rows: []map[string]interface{}{
+				{"id": int64(1)},
+				{"id": int64(2), "extra": "x"},
+				{"id": int64(3)},
+			},
+			wantErr: `row 2 has column "extra", which is not in the CSV header`,
+		},
+		{
+			name:      "raw with two columns",
+			formatter: NewRawFormatter(&bytes.Buffer{}),
+			rows: []map[string]interface{}{
+				{"a": 1, "b": 2},
+				{"a": 3},
+			},
+			wantErr: "raw output requires exactly one column, row 1 has 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The remaining rows are drained, so sending them does not block
+			err := sendRows(tt.formatter, tt.rows)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("FormatStream() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetColumnOrder(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"zeta": int64(1), "id": int64(7), "name": "alice", "beta": true},
+		{"zeta": int64(2), "id": int64(8), "name": "bob", "alpha": "x"},
+	}
+	// "missing" is in no row and "alpha" and "beta" aren't in the order
+	order := []string{"zeta", "name", "missing", "id"}
+
+	tests := []struct {
+		name      string
+		formatter func(buf *bytes.Buffer) Formatter
+		want      string
+	}{
+		{
+			name:      "json",
+			formatter: func(buf *bytes.Buffer) Formatter { return NewJSONFormatter(buf) },
+			want: `{"zeta":1,"name":"alice","id":7,"beta":true}` + "\n" +
+				`{"zeta":2,"name":"bob","id":8,"alpha":"x"}` + "\n",
+		},
+		{
+			name:      "csv",
+			formatter: func(buf *bytes.Buffer) Formatter { return NewCSVFormatter(buf) },
+			want:      "zeta,name,id,alpha,beta\n1,alice,7,,true\n2,bob,8,x,\n",
+		},
+		{
+			name:      "markdown",
+			formatter: func(buf *bytes.Buffer) Formatter { return NewMarkdownFormatter(buf) },
+			want: "| zeta | name | id | alpha | beta |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| 1 | alice | 7 |  | true |\n" +
+				"| 2 | bob | 8 | x |  |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order differs between runs, so format repeatedly
+			for run := 0; run < 20; run++ {
+				var buf bytes.Buffer
+				formatter := tt.formatter(&buf)
+				formatter.(ColumnOrderer).SetColumnOrder(order)
+				if err := formatter.Format(rows); err != nil {
+					t.Fatalf("Format() error = %v", err)
+				}
+				if got := buf.String(); got != tt.want {
+					t.Fatalf("run %d: Format() = %q, want %q", run, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnionColumns(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"c": 1, "a": 2},
+		{"b": 3, "a": 4},
+	}
+
+	tests := []struct {
+		name  string
+		order []string
+		want  []string
+	}{
+		{name: "no order sorts", order: nil, want: []string{"a", "b", "c"}},
+		{name: "ordered then sorted", order: []string{"c", "x"}, want: []string{"c", "a", "b"}},
+		{name: "repeated column", order: []string{"b", "b", "a"}, want: []string{"b", "a", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for run := 0; run < 20; run++ {
+				if got := unionColumns(rows, tt.order); !slices.Equal(got, tt.want) {
+					t.Fatalf("run %d: unionColumns() = %v, want %v", run, got, tt.want)
+				}
+			}
+		})
+	}
+}
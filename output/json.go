@@ -1,13 +1,45 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"math"
 )
 
+// NaNHandling controls how the JSON formatter writes NaN and infinite
+// floats, which have no JSON representation
+type NaNHandling int
+
+const (
+	// NaNNull writes NaN and ±Inf as null (the default)
+	NaNNull NaNHandling = iota
+	// NaNString writes them as the strings "NaN", "+Inf" and "-Inf"
+	NaNString
+	// NaNError fails formatting when a row contains one
+	NaNError
+)
+
+// ParseNaNHandling parses "null", "string" or "error" into a NaNHandling
+func ParseNaNHandling(s string) (NaNHandling, error) {
+	switch s {
+	case "null":
+		return NaNNull, nil
+	case "string":
+		return NaNString, nil
+	case "error":
+		return NaNError, nil
+	default:
+		return NaNNull, fmt.Errorf("invalid NaN handling %q (expected null, string or error)", s)
+	}
+}
+
 // JSONFormatter outputs rows as JSON Lines format
 type JSONFormatter struct {
-	writer io.Writer
+	writer  io.Writer
+	nan     NaNHandling
+	columns []string
 }
 
 // NewJSONFormatter creates a new JSON Lines formatter
@@ -20,13 +52,165 @@ func (j *JSONFormatter) SetOutput(w io.Writer) {
 	j.writer = w
 }
 
+// SetNaNHandling sets how NaN and infinite floats are written
+func (j *JSONFormatter) SetNaNHandling(h NaNHandling) {
+	j.nan = h
+}
+
+// SetColumnOrder sets the order of the keys of each JSON object, see
+// ColumnOrderer
+func (j *JSONFormatter) SetColumnOrder(columns []string) {
+	j.columns = columns
+}
+
 // Format writes rows as JSON Lines (one JSON object per line)
 func (j *JSONFormatter) Format(rows []map[string]interface{}) error {
 	encoder := json.NewEncoder(j.writer)
-	for _, row := range rows {
-		if err := encoder.Encode(row); err != nil {
+	for i, row := range rows {
+		if err := j.writeRow(encoder, row, i); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// FormatStream writes each row as a JSON line as soon as it is received
+func (j *JSONFormatter) FormatStream(rows <-chan map[string]interface{}) error {
+	encoder := json.NewEncoder(j.writer)
+	i := 0
+	for row := range rows {
+		if err := j.writeRow(encoder, row, i); err != nil {
+			drain(rows)
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
+// writeRow encodes the row at index i as one JSON line
+func (j *JSONFormatter) writeRow(encoder *json.Encoder, row map[string]interface{}, i int) error {
+	value, err := j.encodable(row, i)
+	if err != nil {
+		return err
+	}
+	return encoder.Encode(value)
+}
+
+// encodable returns the row at index i with NaN and infinite floats handled
+// according to the formatter's NaNHandling and its keys in column order
+func (j *JSONFormatter) encodable(row map[string]interface{}, i int) (interface{}, error) {
+	if hasNonFinite(row) {
+		if j.nan == NaNError {
+			return nil, fmt.Errorf("row %d contains a NaN or infinite float, which JSON cannot represent", i+1)
+		}
+		row = replaceNonFinite(row, j.nan).(map[string]interface{})
+	}
+	if j.columns != nil {
+		return orderedRow{row: row, columns: j.columns}, nil
+	}
+	return row, nil
+}
+
+// orderedRow is a row encoded as a JSON object with its keys in column
+// order, since encoding/json always sorts the keys of a map
+type orderedRow struct {
+	row     map[string]interface{}
+	columns []string
+}
+
+// MarshalJSON implements json.Marshaler
+func (o orderedRow) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, col := range unionColumns([]map[string]interface{}{o.row}, o.columns) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(col)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.row[col])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col, err)
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// nonFinite reports whether v is a NaN or infinite float, returning it as a float64
+func nonFinite(v interface{}) (float64, bool) {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case float32:
+		f = float64(val)
+	default:
+		return 0, false
+	}
+	return f, math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+// hasNonFinite reports whether a value contains a NaN or infinite float,
+// looking inside lists and maps
+func hasNonFinite(v interface{}) bool {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, elem := range val {
+			if hasNonFinite(elem) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, elem := range val {
+			if hasNonFinite(elem) {
+				return true
+			}
+		}
+	default:
+		_, ok := nonFinite(v)
+		return ok
+	}
+	return false
+}
+
+// replaceNonFinite returns a copy of v with NaN and infinite floats replaced
+// according to h. The input is not modified.
+func replaceNonFinite(v interface{}, h NaNHandling) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		replaced := make(map[string]interface{}, len(val))
+		for key, elem := range val {
+			replaced[key] = replaceNonFinite(elem, h)
+		}
+		return replaced
+	case []interface{}:
+		replaced := make([]interface{}, len(val))
+		for i, elem := range val {
+			replaced[i] = replaceNonFinite(elem, h)
+		}
+		return replaced
+	}
+
+	f, ok := nonFinite(v)
+	if !ok {
+		return v
+	}
+	if h != NaNString {
+		return nil
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f > 0:
+		return "+Inf"
+	default:
+		return "-Inf"
+	}
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 	"strings"
 	"testing"
 )
@@ -161,3 +162,86 @@ func TestJSONFormatter_OutputFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestJSONFormatter_NaNHandling(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(1), "score": math.NaN()},
+		{"id": int64(2), "score": math.Inf(1), "scores": []interface{}{float32(math.Inf(-1)), 1.5}},
+		{"id": int64(3), "score": 2.5, "nested": map[string]interface{}{"v": math.NaN()}},
+	}
+
+	tests := []struct {
+		name     string
+		handling NaNHandling
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "null",
+			handling: NaNNull,
+			want: `{"id":1,"score":null}
+{"id":2,"score":null,"scores":[null,1.5]}
+{"id":3,"nested":{"v":null},"score":2.5}
+`,
+		},
+		{
+			name:     "string",
+			handling: NaNString,
+			want: `{"id":1,"score":"NaN"}
+{"id":2,"score":"+Inf","scores":["-Inf",1.5]}
+{"id":3,"nested":{"v":"NaN"},"score":2.5}
+`,
+		},
+		{
+			name:     "error",
+			handling: NaNError,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := NewJSONFormatter(&buf)
+			formatter.SetNaNHandling(tt.handling)
+
+			err := formatter.Format(rows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("Format() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	// The input rows must not be modified
+	if !math.IsNaN(rows[0]["score"].(float64)) {
+		t.Errorf("Format() modified the input row: %v", rows[0])
+	}
+}
+
+func TestParseNaNHandling(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    NaNHandling
+		wantErr bool
+	}{
+		{"null", NaNNull, false},
+		{"string", NaNString, false},
+		{"error", NaNError, false},
+		{"zero", NaNNull, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNaNHandling(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNaNHandling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNaNHandling() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"io"
+	"strings"
+)
+
+// markdownEscaper keeps a value inside its table cell: pipes would end the
+// cell and newlines the row
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// MarkdownFormatter outputs rows as a GitHub-flavored Markdown table, for
+// pasting query results into documentation and issues
+type MarkdownFormatter struct {
+	writer  io.Writer
+	columns []string
+}
+
+// NewMarkdownFormatter creates a new Markdown table formatter
+func NewMarkdownFormatter(w io.Writer) *MarkdownFormatter {
+	return &MarkdownFormatter{writer: w}
+}
+
+// SetOutput sets the output writer
+func (m *MarkdownFormatter) SetOutput(w io.Writer) {
+	m.writer = w
+}
+
+// SetColumnOrder sets the order of the table's columns, see ColumnOrderer
+func (m *MarkdownFormatter) SetColumnOrder(columns []string) {
+	m.columns = columns
+}
+
+// Format writes rows as a Markdown table with a header row and a --- separator
+// row. The columns are the union of the keys of all rows, in column order
+// (see SetColumnOrder), and NULLs and keys missing from a row are written as
+// empty cells. An empty result is written as just the header of the columns
+// given to SetColumnOrder, or as nothing if there are none.
+func (m *MarkdownFormatter) Format(rows []map[string]interface{}) error {
+	columns := unionColumns(rows, m.columns)
+	if len(rows) == 0 {
+		columns = m.columns
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	separator := make([]string, len(columns))
+	for i := range columns {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&b, columns)
+	writeMarkdownRow(&b, separator)
+
+	cells := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			cells[i] = rawValue(row[col])
+		}
+		writeMarkdownRow(&b, cells)
+	}
+
+	_, err := io.WriteString(m.writer, b.String())
+	return err
+}
+
+// FormatStream collects all rows and writes them as a Markdown table, since
+// the header lists the columns of every row
+func (m *MarkdownFormatter) FormatStream(rows <-chan map[string]interface{}) error {
+	return m.Format(collect(rows))
+}
+
+// writeMarkdownRow writes a | a | b | row with each cell escaped
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	for _, cell := range cells {
+		b.WriteString("| ")
+		b.WriteString(markdownEscaper.Replace(cell))
+		b.WriteString(" ")
+	}
+	b.WriteString("|\n")
+}
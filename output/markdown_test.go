@@ -0,0 +1,66 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarkdownFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []map[string]interface{}
+		columns []string
+		want    string
+	}{
+		{
+			name: "empty rows",
+			rows: []map[string]interface{}{},
+			want: "",
+		},
+		{
+			name:    "empty rows with column order",
+			rows:    []map[string]interface{}{},
+			columns: []string{"id", "name"},
+			want: "" +
+				"| id | name |\n" +
+				"| --- | --- |\n",
+		},
+		{
+			name: "rows with different columns",
+			rows: []map[string]interface{}{
+				{"id": int64(1), "name": "Alice", "tags": []interface{}{"a", "b"}},
+				{"id": int64(2), "name": nil, "score": 9.5},
+			},
+			want: "" +
+				"| id | name | score | tags |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| 1 | Alice |  | [\"a\",\"b\"] |\n" +
+				"| 2 |  | 9.5 |  |\n",
+		},
+		{
+			name: "pipes and newlines are escaped",
+			rows: []map[string]interface{}{
+				{"a|b": "x | y", "note": "line one\nline two"},
+			},
+			want: "" +
+				"| a\\|b | note |\n" +
+				"| --- | --- |\n" +
+				"| x \\| y | line one<br>line two |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := NewMarkdownFormatter(&buf)
+			formatter.SetColumnOrder(tt.columns)
+
+			if err := formatter.Format(tt.rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// RawFormatter outputs the values of a single-column result, one per line,
+// without column names or quoting. It is intended for piping into shell tools.
+type RawFormatter struct {
+	writer io.Writer
+}
+
+// NewRawFormatter creates a new raw value formatter
+func NewRawFormatter(w io.Writer) *RawFormatter {
+	return &RawFormatter{writer: w}
+}
+
+// SetOutput sets the output writer
+func (r *RawFormatter) SetOutput(w io.Writer) {
+	r.writer = w
+}
+
+// Format writes the single value of each row on its own line.
+// Returns an error if any row has more or fewer than one column.
+func (r *RawFormatter) Format(rows []map[string]interface{}) error {
+	// Validate every row first so nothing is written for an invalid result
+	for i, row := range rows {
+		if len(row) != 1 {
+			return fmt.Errorf("raw output requires exactly one column, row %d has %d", i+1, len(row))
+		}
+	}
+
+	for _, row := range rows {
+		if err := r.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatStream writes the value of each row as soon as it is received.
+// Unlike Format, rows before one with more or fewer than one column have
+// already been written when the error is returned.
+func (r *RawFormatter) FormatStream(rows <-chan map[string]interface{}) error {
+	i := 0
+	for row := range rows {
+		if len(row) != 1 {
+			drain(rows)
+			return fmt.Errorf("raw output requires exactly one column, row %d has %d", i+1, len(row))
+		}
+		if err := r.writeRow(row); err != nil {
+			drain(rows)
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
+// writeRow writes the single value of a row on its own line
+func (r *RawFormatter) writeRow(row map[string]interface{}) error {
+	for _, value := range row {
+		if _, err := fmt.Fprintln(r.writer, rawValue(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawValue converts a value to its unquoted string form. NULL becomes an
+// empty line and lists or maps are written as JSON.
+func rawValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case float32, float64:
+		return fmt.Sprintf("%g", val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	}
+
+	kind := reflect.ValueOf(v).Kind()
+	if kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+		if encoded, err := json.Marshal(v); err == nil {
+			return string(encoded)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
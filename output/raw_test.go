@@ -0,0 +1,79 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRawFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name: "empty rows",
+			rows: []map[string]interface{}{},
+			want: "",
+		},
+		{
+			name: "strings are not quoted",
+			rows: []map[string]interface{}{
+				{"name": "alice"},
+				{"name": "bob smith"},
+				{"name": "=formula"},
+			},
+			want: "alice\nbob smith\n=formula\n",
+		},
+		{
+			name: "various types",
+			rows: []map[string]interface{}{
+				{"v": int64(42)},
+				{"v": int32(-7)},
+				{"v": float64(3.5)},
+				{"v": true},
+				{"v": nil},
+				{"v": []interface{}{"a", "b"}},
+				{"v": time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)},
+			},
+			want: "42\n-7\n3.5\ntrue\n\n[\"a\",\"b\"]\n2024-03-15T10:30:00Z\n",
+		},
+		{
+			name: "more than one column",
+			rows: []map[string]interface{}{
+				{"name": "alice", "age": int64(30)},
+			},
+			wantErr: "exactly one column",
+		},
+		{
+			name: "column count checked on every row",
+			rows: []map[string]interface{}{
+				{"name": "alice"},
+				{"name": "bob", "age": int64(25)},
+			},
+			wantErr: "row 2 has 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewRawFormatter(&buf).Format(tt.rows)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Format() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Format() output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
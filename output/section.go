@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Section is a labeled group of rows, such as the results of a query run
+// against one input file
+type Section struct {
+	Label string
+	Rows  []map[string]interface{}
+}
+
+// SectionFormatter is implemented by formatters with their own representation
+// of labeled sections, such as a JSON object keyed by label
+type SectionFormatter interface {
+	FormatSections(sections []Section) error
+}
+
+// FormatSections writes labeled sections of rows. If f implements
+// SectionFormatter it formats the sections itself. Otherwise each section is
+// written to w as a "==> label <==" header line followed by the rows in f's
+// format, with a blank line between sections. f must write to w.
+func FormatSections(f Formatter, w io.Writer, sections []Section) error {
+	if sf, ok := f.(SectionFormatter); ok {
+		return sf.FormatSections(sections)
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "==> %s <==\n", section.Label); err != nil {
+			return err
+		}
+		if err := f.Format(section.Rows); err != nil {
+			return fmt.Errorf("%s: %w", section.Label, err)
+		}
+	}
+	return nil
+}
+
+// FormatSections writes all sections as a single JSON object mapping each
+// label to the array of its rows, in section order
+func (j *JSONFormatter) FormatSections(sections []Section) error {
+	if _, err := io.WriteString(j.writer, "{"); err != nil {
+		return err
+	}
+
+	for i, section := range sections {
+		label, err := json.Marshal(section.Label)
+		if err != nil {
+			return err
+		}
+
+		rows := make([]interface{}, len(section.Rows))
+		for k, row := range section.Rows {
+			if rows[k], err = j.encodable(row, k); err != nil {
+				return fmt.Errorf("%s: %w", section.Label, err)
+			}
+		}
+		value, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+
+		sep := ","
+		if i == 0 {
+			sep = ""
+		}
+		if _, err := fmt.Fprintf(j.writer, "%s%s:%s", sep, label, value); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(j.writer, "}\n")
+	return err
+}
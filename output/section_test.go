@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestFormatSections(t *testing.T) {
+	sections := []Section{
+		{Label: "data/2024-01-01.parquet", Rows: []map[string]interface{}{
+			{"name": "alice", "total": int64(3)},
+		}},
+		{Label: "data/2024-01-02.parquet", Rows: []map[string]interface{}{}},
+		{Label: "data/2024-01-03.parquet", Rows: []map[string]interface{}{
+			{"name": "bob", "total": int64(1)},
+			{"name": "carol", "total": math.NaN()},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		formatter func(buf *bytes.Buffer) Formatter
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "json object keyed by label",
+			formatter: func(buf *bytes.Buffer) Formatter {
+				return NewJSONFormatter(buf)
+			},
+			want: `{"data/2024-01-01.parquet":[{"name":"alice","total":3}],"data/2024-01-02.parquet":[],"data/2024-01-03.parquet":[{"name":"bob","total":1},{"name":"carol","total":null}]}` + "\n",
+		},
+		{
+			name: "csv labeled sections",
+			formatter: func(buf *bytes.Buffer) Formatter {
+				return NewCSVFormatter(buf)
+			},
+			want: "==> data/2024-01-01.parquet <==\nname,total\nalice,3\n\n" +
+				"==> data/2024-01-02.parquet <==\n\n" +
+				"==> data/2024-01-03.parquet <==\nname,total\nbob,1\ncarol,NaN\n",
+		},
+		{
+			// Raw output rejects the two-column rows
+			name: "raw formatter error",
+			formatter: func(buf *bytes.Buffer) Formatter {
+				return NewRawFormatter(buf)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := FormatSections(tt.formatter(&buf), &buf, sections)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatSections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("FormatSections() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFormatter_FormatSectionsNaNError(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewJSONFormatter(&buf)
+	formatter.SetNaNHandling(NaNError)
+
+	err := formatter.FormatSections([]Section{
+		{Label: "a.parquet", Rows: []map[string]interface{}{{"v": math.Inf(1)}}},
+	})
+	if err == nil {
+		t.Fatal("FormatSections() expected error for infinite float, got nil")
+	}
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTableMaxWidth is the default maximum width of a table cell
+const DefaultTableMaxWidth = 40
+
+// tableEscaper keeps multi-line values on a single table line
+var tableEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// TableFormatter outputs rows as a bordered table with aligned columns, like
+// the mysql client. Numbers are right-aligned and other values left-aligned.
+type TableFormatter struct {
+	writer   io.Writer
+	maxWidth int
+	columns  []string
+}
+
+// NewTableFormatter creates a new table formatter
+func NewTableFormatter(w io.Writer) *TableFormatter {
+	return &TableFormatter{writer: w, maxWidth: DefaultTableMaxWidth}
+}
+
+// SetOutput sets the output writer
+func (t *TableFormatter) SetOutput(w io.Writer) {
+	t.writer = w
+}
+
+// SetMaxWidth sets the width past which cells are truncated with an
+// ellipsis. A width of 0 or less disables truncation.
+func (t *TableFormatter) SetMaxWidth(n int) {
+	t.maxWidth = n
+}
+
+// SetColumnOrder sets the order of the table's columns, see ColumnOrderer
+func (t *TableFormatter) SetColumnOrder(columns []string) {
+	t.columns = columns
+}
+
+// Format writes rows as a table. The columns are the union of the keys of
+// all rows, in column order (see SetColumnOrder), and NULLs and keys missing from a row are written as empty
+// cells. Nothing is written for an empty result.
+func (t *TableFormatter) Format(rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := unionColumns(rows, t.columns)
+
+	header := make([]string, len(columns))
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		header[i] = t.cell(col)
+		widths[i] = utf8.RuneCountInString(header[i])
+	}
+
+	cells := make([][]string, len(rows))
+	for r, row := range rows {
+		cells[r] = make([]string, len(columns))
+		for i, col := range columns {
+			cells[r][i] = t.cell(rawValue(row[col]))
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+		}
+	}
+
+	var b strings.Builder
+	border := tableBorder(widths)
+	b.WriteString(border)
+	writeTableLine(&b, header, widths, nil)
+	b.WriteString(border)
+	for r, row := range rows {
+		rightAlign := make([]bool, len(columns))
+		for i, col := range columns {
+			rightAlign[i] = isNumber(row[col])
+		}
+		writeTableLine(&b, cells[r], widths, rightAlign)
+	}
+	b.WriteString(border)
+
+	_, err := io.WriteString(t.writer, b.String())
+	return err
+}
+
+// FormatStream collects all rows and writes them as a table, since column
+// widths depend on every row
+func (t *TableFormatter) FormatStream(rows <-chan map[string]interface{}) error {
+	return t.Format(collect(rows))
+}
+
+// cell escapes a value for a single table line and truncates it to the
+// maximum width
+func (t *TableFormatter) cell(s string) string {
+	s = tableEscaper.Replace(s)
+	if t.maxWidth <= 0 || utf8.RuneCountInString(s) <= t.maxWidth {
+		return s
+	}
+	if t.maxWidth == 1 {
+		return "…"
+	}
+	return string([]rune(s)[:t.maxWidth-1]) + "…"
+}
+
+// tableBorder returns a +---+---+ line for the given column widths
+func tableBorder(widths []int) string {
+	var b strings.Builder
+	for _, w := range widths {
+		b.WriteString("+")
+		b.WriteString(strings.Repeat("-", w+2))
+	}
+	b.WriteString("+\n")
+	return b.String()
+}
+
+// writeTableLine writes a | a | b | line, padding each cell to its column width
+func writeTableLine(b *strings.Builder, cells []string, widths []int, rightAlign []bool) {
+	for i, cell := range cells {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if rightAlign != nil && rightAlign[i] {
+			fmt.Fprintf(b, "| %s%s ", pad, cell)
+		} else {
+			fmt.Fprintf(b, "| %s%s ", cell, pad)
+		}
+	}
+	b.WriteString("|\n")
+}
+
+// isNumber reports whether v is an integer or floating point value
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
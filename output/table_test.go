@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []map[string]interface{}
+		maxWidth int
+		want     string
+	}{
+		{
+			name: "empty rows",
+			rows: []map[string]interface{}{},
+			want: "",
+		},
+		{
+			name: "aligned columns",
+			rows: []map[string]interface{}{
+				{"id": int64(1), "name": "Alice", "score": 9.5, "tags": []interface{}{"a", "b"}},
+				{"id": int64(20), "name": "Bob", "score": nil, "tags": []interface{}{}},
+				{"id": int64(300), "name": "Carol"},
+			},
+			want: "" +
+				"+-----+-------+-------+-----------+\n" +
+				"| id  | name  | score | tags      |\n" +
+				"+-----+-------+-------+-----------+\n" +
+				"|   1 | Alice |   9.5 | [\"a\",\"b\"] |\n" +
+				"|  20 | Bob   |       | []        |\n" +
+				"| 300 | Carol |       |           |\n" +
+				"+-----+-------+-------+-----------+\n",
+		},
+		{
+			name: "long cells are truncated and newlines escaped",
+			rows: []map[string]interface{}{
+				{"description": "a very long description", "k": "a\nb"},
+			},
+			maxWidth: 8,
+			want: "" +
+				"+----------+------+\n" +
+				"| descrip… | k    |\n" +
+				"+----------+------+\n" +
+				"| a very … | a\\nb |\n" +
+				"+----------+------+\n",
+		},
+		{
+			name: "unicode widths",
+			rows: []map[string]interface{}{
+				{"city": "Zürich"},
+				{"city": "Oslo"},
+			},
+			want: "" +
+				"+--------+\n" +
+				"| city   |\n" +
+				"+--------+\n" +
+				"| Zürich |\n" +
+				"| Oslo   |\n" +
+				"+--------+\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter := NewTableFormatter(&buf)
+			if tt.maxWidth > 0 {
+				formatter.SetMaxWidth(tt.maxWidth)
+			}
+
+			if err := formatter.Format(tt.rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Group represents a group of rows for aggregation
@@ -19,10 +20,40 @@ func ApplyGroupByAndAggregate(rows []map[string]interface{}, groupByColumns []st
 		return nil, err
 	}
 
+	return groupAndAggregate(rows, groupByColumns, selectList, nil)
+}
+
+// ApplyGroupByRollup applies GROUP BY ROLLUP(groupByColumns): the rows of
+// ApplyGroupByAndAggregate, then subtotal rows grouped on each shorter
+// prefix of groupByColumns, then a grand total row. The columns a subtotal
+// rolls up are NULL in it.
+func ApplyGroupByRollup(rows []map[string]interface{}, groupByColumns []string, selectList []SelectItem) ([]map[string]interface{}, error) {
+	if err := validateSelectListWithGroupBy(selectList, groupByColumns); err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+	for n := len(groupByColumns); n >= 0; n-- {
+		rolledUp := make(map[string]bool)
+		for _, col := range groupByColumns[n:] {
+			rolledUp[col] = true
+		}
+		level, err := groupAndAggregate(rows, groupByColumns[:n], selectList, rolledUp)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, level...)
+	}
+	return result, nil
+}
+
+// groupAndAggregate groups rows on groupByColumns and computes selectList
+// for each group. Columns in rolledUp are NULL in the results.
+func groupAndAggregate(rows []map[string]interface{}, groupByColumns []string, selectList []SelectItem, rolledUp map[string]bool) ([]map[string]interface{}, error) {
 	// If no GROUP BY, treat all rows as one group (for aggregates without GROUP BY)
 	// This should return one aggregate row even when input is empty (e.g., COUNT(*) = 0)
 	if len(groupByColumns) == 0 {
-		return aggregateWithoutGroupBy(rows, selectList)
+		return aggregateWithoutGroupBy(rows, selectList, rolledUp)
 	}
 
 	// For GROUP BY queries, empty input returns empty output
@@ -55,7 +86,7 @@ func ApplyGroupByAndAggregate(rows []map[string]interface{}, groupByColumns []st
 	// Compute aggregates for each group
 	result := make([]map[string]interface{}, 0, len(groups))
 	for _, group := range groups {
-		aggregatedRow, err := computeAggregates(group, selectList)
+		aggregatedRow, err := computeAggregates(group, selectList, rolledUp)
 		if err != nil {
 			return nil, err
 		}
@@ -65,6 +96,31 @@ func ApplyGroupByAndAggregate(rows []map[string]interface{}, groupByColumns []st
 	return result, nil
 }
 
+// ApplyGroupKeys computes each GROUP BY expression of keys into the column
+// named by its alias. The rows returned are copies, so rows is unchanged.
+func ApplyGroupKeys(rows []map[string]interface{}, keys []SelectItem) ([]map[string]interface{}, error) {
+	if len(keys) == 0 {
+		return rows, nil
+	}
+
+	keyed := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		newRow := make(map[string]interface{}, len(row)+len(keys))
+		for col, val := range row {
+			newRow[col] = val
+		}
+		for _, key := range keys {
+			value, err := key.Expr.EvaluateSelect(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute GROUP BY key %s: %w", key.Alias, err)
+			}
+			newRow[key.Alias] = value
+		}
+		keyed[i] = newRow
+	}
+	return keyed, nil
+}
+
 // computeGroupKey computes a hash key for a group based on GROUP BY columns
 func computeGroupKey(row map[string]interface{}, groupByColumns []string) (string, map[string]interface{}, error) {
 	var keyBuilder strings.Builder
@@ -90,14 +146,14 @@ func computeGroupKey(row map[string]interface{}, groupByColumns []string) (strin
 }
 
 // aggregateWithoutGroupBy handles aggregation without GROUP BY (all rows as one group)
-func aggregateWithoutGroupBy(rows []map[string]interface{}, selectList []SelectItem) ([]map[string]interface{}, error) {
+func aggregateWithoutGroupBy(rows []map[string]interface{}, selectList []SelectItem, rolledUp map[string]bool) ([]map[string]interface{}, error) {
 	group := &Group{
 		Key:    "",
 		Values: make(map[string]interface{}),
 		Rows:   rows,
 	}
 
-	aggregatedRow, err := computeAggregates(group, selectList)
+	aggregatedRow, err := computeAggregates(group, selectList, rolledUp)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +161,9 @@ func aggregateWithoutGroupBy(rows []map[string]interface{}, selectList []SelectI
 	return []map[string]interface{}{aggregatedRow}, nil
 }
 
-// computeAggregates computes aggregate values for a group
-func computeAggregates(group *Group, selectList []SelectItem) (map[string]interface{}, error) {
+// computeAggregates computes aggregate values for a group. Columns in
+// rolledUp, the columns a ROLLUP subtotal spans, are NULL.
+func computeAggregates(group *Group, selectList []SelectItem, rolledUp map[string]bool) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	// Compute each SELECT item
@@ -121,7 +178,10 @@ func computeAggregates(group *Group, selectList []SelectItem) (map[string]interf
 			if err != nil {
 				return nil, err
 			}
-		} else if colRef, ok := item.Expr.(*ColumnRef); ok {
+		} else if colRef, ok := item.Expr.(*ColumnRef); ok && rolledUp[colRef.Column] {
+			// A subtotal leaves the columns it rolls up NULL
+			value = nil
+		} else if ok {
 			// For non-aggregate columns, use the value from the first row in the group
 			// (should be the same for all rows in the group if it's a GROUP BY column)
 			if len(group.Rows) == 0 {
@@ -136,19 +196,7 @@ func computeAggregates(group *Group, selectList []SelectItem) (map[string]interf
 			return nil, fmt.Errorf("non-aggregate expression in SELECT with GROUP BY is not supported")
 		}
 
-		// Determine column name
-		columnName := item.Alias
-		if columnName == "" {
-			if aggExpr, ok := item.Expr.(*AggregateExpr); ok {
-				columnName = strings.ToLower(aggExpr.Function)
-			} else if colRef, ok := item.Expr.(*ColumnRef); ok {
-				columnName = colRef.Column
-			} else {
-				columnName = fmt.Sprintf("col_%d", len(result))
-			}
-		}
-
-		result[columnName] = value
+		result[uniqueColumnName(result, selectItemName(item, len(result)))] = value
 	}
 
 	return result, nil
@@ -156,6 +204,17 @@ func computeAggregates(group *Group, selectList []SelectItem) (map[string]interf
 
 // evaluateAggregate evaluates an aggregate function over a set of rows
 func evaluateAggregate(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
+	if aggExpr.Distinct {
+		rows = distinctArgRows(aggExpr, rows)
+	}
+	if len(aggExpr.OrderBy) > 0 {
+		var err error
+		rows, err = ApplyOrderBy(rows, aggExpr.OrderBy)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", aggExpr.Function, err)
+		}
+	}
+
 	switch aggExpr.Function {
 	case "COUNT":
 		return evaluateCount(aggExpr, rows)
@@ -167,11 +226,45 @@ func evaluateAggregate(aggExpr *AggregateExpr, rows []map[string]interface{}) (i
 		return evaluateMin(aggExpr, rows)
 	case "MAX":
 		return evaluateMax(aggExpr, rows)
+	case "ARRAY_AGG":
+		return evaluateArrayAgg(aggExpr, rows)
+	case "STRING_AGG":
+		return evaluateStringAgg(aggExpr, rows)
+	case "APPROX_COUNT_DISTINCT":
+		return evaluateApproxCountDistinct(aggExpr, rows)
 	default:
 		return nil, fmt.Errorf("unknown aggregate function: %s", aggExpr.Function)
 	}
 }
 
+// distinctArgRows keeps the first row for each distinct value of the aggregate
+// argument. It is applied to the rows of a single group, so distinctness is per group.
+func distinctArgRows(aggExpr *AggregateExpr, rows []map[string]interface{}) []map[string]interface{} {
+	if aggExpr.Arg == nil {
+		return rows
+	}
+
+	seen := make(map[string]bool)
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		value, err := aggExpr.Arg.EvaluateSelect(row)
+		if err != nil {
+			// Keep the row so the aggregate reports or skips it as usual
+			result = append(result, row)
+			continue
+		}
+
+		key := fmt.Sprintf("%#v", value) // %#v keeps values of different types apart
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, row)
+	}
+
+	return result
+}
+
 // evaluateCount evaluates COUNT aggregate
 func evaluateCount(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
 	// COUNT(*) counts all rows
@@ -195,6 +288,31 @@ func evaluateCount(aggExpr *AggregateExpr, rows []map[string]interface{}) (inter
 	return count, nil
 }
 
+// aggregateNumber converts a value of a numeric aggregate's argument to a
+// number, reporting a TypeError naming the column if the value isn't numeric
+func aggregateNumber(aggExpr *AggregateExpr, value interface{}) (float64, error) {
+	num, err := valueToNumber(value)
+	if err == nil {
+		return num, nil
+	}
+	if _, isNum := toFloat64(value); isNum {
+		// Numeric values only fail on precision loss, which isn't a type error
+		return 0, err
+	}
+
+	return 0, aggregateTypeError(aggExpr, "number", value)
+}
+
+// aggregateTypeError reports a value of the wrong type passed to an
+// aggregate, naming the column when the argument is a plain column reference
+func aggregateTypeError(aggExpr *AggregateExpr, expected string, value interface{}) error {
+	typeErr := &TypeError{Expected: expected, Value: value}
+	if ref, ok := aggExpr.Arg.(*ColumnRef); ok {
+		typeErr.Column = ref.Column
+	}
+	return typeErr
+}
+
 // evaluateSum evaluates SUM aggregate
 func evaluateSum(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
 	if aggExpr.Arg == nil {
@@ -213,7 +331,7 @@ func evaluateSum(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 			continue
 		}
 
-		num, err := valueToNumber(value)
+		num, err := aggregateNumber(aggExpr, value)
 		if err != nil {
 			return nil, fmt.Errorf("SUM: %w", err)
 		}
@@ -247,7 +365,7 @@ func evaluateAvg(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 			continue
 		}
 
-		num, err := valueToNumber(value)
+		num, err := aggregateNumber(aggExpr, value)
 		if err != nil {
 			return nil, fmt.Errorf("AVG: %w", err)
 		}
@@ -270,6 +388,7 @@ func evaluateMin(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 	}
 
 	var min *float64
+	var minTime *time.Time
 
 	for _, row := range rows {
 		value, err := aggExpr.Arg.EvaluateSelect(row)
@@ -280,7 +399,21 @@ func evaluateMin(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 			continue
 		}
 
-		num, err := valueToNumber(value)
+		// Timestamps are compared as times, but can't be mixed with numbers
+		if t, ok := value.(time.Time); ok {
+			if min != nil {
+				return nil, fmt.Errorf("MIN: %w", aggregateTypeError(aggExpr, "number", value))
+			}
+			if minTime == nil || t.Before(*minTime) {
+				minTime = &t
+			}
+			continue
+		}
+		if minTime != nil {
+			return nil, fmt.Errorf("MIN: %w", aggregateTypeError(aggExpr, "timestamp", value))
+		}
+
+		num, err := aggregateNumber(aggExpr, value)
 		if err != nil {
 			return nil, fmt.Errorf("MIN: %w", err)
 		}
@@ -290,6 +423,9 @@ func evaluateMin(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 		}
 	}
 
+	if minTime != nil {
+		return *minTime, nil
+	}
 	if min == nil {
 		return nil, nil // Return NULL if no values
 	}
@@ -304,6 +440,7 @@ func evaluateMax(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 	}
 
 	var max *float64
+	var maxTime *time.Time
 
 	for _, row := range rows {
 		value, err := aggExpr.Arg.EvaluateSelect(row)
@@ -314,7 +451,21 @@ func evaluateMax(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 			continue
 		}
 
-		num, err := valueToNumber(value)
+		// Timestamps are compared as times, but can't be mixed with numbers
+		if t, ok := value.(time.Time); ok {
+			if max != nil {
+				return nil, fmt.Errorf("MAX: %w", aggregateTypeError(aggExpr, "number", value))
+			}
+			if maxTime == nil || t.After(*maxTime) {
+				maxTime = &t
+			}
+			continue
+		}
+		if maxTime != nil {
+			return nil, fmt.Errorf("MAX: %w", aggregateTypeError(aggExpr, "timestamp", value))
+		}
+
+		num, err := aggregateNumber(aggExpr, value)
 		if err != nil {
 			return nil, fmt.Errorf("MAX: %w", err)
 		}
@@ -324,6 +475,9 @@ func evaluateMax(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 		}
 	}
 
+	if maxTime != nil {
+		return *maxTime, nil
+	}
 	if max == nil {
 		return nil, nil // Return NULL if no values
 	}
@@ -331,6 +485,82 @@ func evaluateMax(aggExpr *AggregateExpr, rows []map[string]interface{}) (interfa
 	return *max, nil
 }
 
+// evaluateArrayAgg evaluates ARRAY_AGG aggregate, collecting values in input
+// order, or in the order given by ORDER BY inside the call
+func evaluateArrayAgg(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
+	if aggExpr.Arg == nil {
+		return nil, fmt.Errorf("ARRAY_AGG requires an argument")
+	}
+
+	if len(rows) == 0 {
+		return nil, nil // Return NULL for an empty group
+	}
+
+	// NULL values are kept so the array lines up with the input rows
+	values := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		value, err := aggExpr.Arg.EvaluateSelect(row)
+		if err != nil {
+			return nil, fmt.Errorf("ARRAY_AGG: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// evaluateStringAgg evaluates STRING_AGG aggregate, joining non-null values
+// with the separator in input order, or in the order given by ORDER BY inside the call
+func evaluateStringAgg(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
+	if aggExpr.Arg == nil {
+		return nil, fmt.Errorf("STRING_AGG requires an argument")
+	}
+
+	var parts []string
+	for _, row := range rows {
+		value, err := aggExpr.Arg.EvaluateSelect(row)
+		if err != nil {
+			return nil, fmt.Errorf("STRING_AGG: %w", err)
+		}
+		if value == nil {
+			continue
+		}
+		str, err := valueToString(value)
+		if err != nil {
+			return nil, fmt.Errorf("STRING_AGG: %w", err)
+		}
+		parts = append(parts, str)
+	}
+
+	if len(parts) == 0 {
+		return nil, nil // Return NULL if no values
+	}
+
+	return strings.Join(parts, aggExpr.Separator), nil
+}
+
+// evaluateApproxCountDistinct evaluates APPROX_COUNT_DISTINCT aggregate,
+// estimating the number of distinct non-null values with a HyperLogLog sketch
+func evaluateApproxCountDistinct(aggExpr *AggregateExpr, rows []map[string]interface{}) (interface{}, error) {
+	if aggExpr.Arg == nil {
+		return nil, fmt.Errorf("APPROX_COUNT_DISTINCT requires an argument")
+	}
+
+	var sketch hyperLogLog
+	for _, row := range rows {
+		value, err := aggExpr.Arg.EvaluateSelect(row)
+		if err != nil {
+			// Skip rows where column doesn't exist or errors, like COUNT
+			continue
+		}
+		if value != nil {
+			sketch.add(value)
+		}
+	}
+
+	return sketch.estimate(), nil
+}
+
 // EvaluateHaving evaluates the HAVING clause on aggregated rows
 func EvaluateHaving(rows []map[string]interface{}, having Expression) ([]map[string]interface{}, error) {
 	if having == nil {
@@ -351,6 +581,34 @@ func EvaluateHaving(rows []map[string]interface{}, having Expression) ([]map[str
 	return filtered, nil
 }
 
+// AggregateSelectList returns the items to compute per group: the SELECT list
+// and ORDER BY keys followed by the aggregates used only in HAVING
+func (q *Query) AggregateSelectList() []SelectItem {
+	if len(q.HavingAggregates) == 0 {
+		return q.OutputSelectList()
+	}
+	items := make([]SelectItem, 0, len(q.SelectList)+len(q.OrderKeys)+len(q.HavingAggregates))
+	items = append(items, q.OutputSelectList()...)
+	return append(items, q.HavingAggregates...)
+}
+
+// DropHavingAggregates removes the columns computed for HAVING aggregates
+// from aggregated rows, in place
+func DropHavingAggregates(rows []map[string]interface{}, havingAggregates []SelectItem) []map[string]interface{} {
+	return dropItemColumns(rows, havingAggregates)
+}
+
+// dropItemColumns removes the columns named by the aliases of items from
+// rows, in place
+func dropItemColumns(rows []map[string]interface{}, items []SelectItem) []map[string]interface{} {
+	for _, row := range rows {
+		for _, item := range items {
+			delete(row, item.Alias)
+		}
+	}
+	return rows
+}
+
 // hasAggregateFunction checks if the SELECT list contains any aggregate functions
 func HasAggregateFunction(selectList []SelectItem) bool {
 	for _, item := range selectList {
@@ -1,7 +1,10 @@
 package query
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAggregateCount(t *testing.T) {
@@ -261,6 +264,50 @@ func TestAggregateMinMax(t *testing.T) {
 	}
 }
 
+func TestAggregateMinMaxTimes(t *testing.T) {
+	jan := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	rows := []map[string]interface{}{
+		{"ts": feb, "n": int64(1)},
+		{"ts": nil, "n": int64(2)},
+		{"ts": mar, "n": jan},
+		{"ts": jan, "n": int64(3)},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{"earliest and latest", "SELECT MIN(ts), MAX(ts) FROM t", map[string]interface{}{"min": jan, "max": mar}, ""},
+		{"timestamps mixed with numbers", "SELECT MIN(n) FROM t", nil, `MIN: column "n": expected number, got time.Time`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			result, err := ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyGroupByAndAggregate() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyGroupByAndAggregate() error = %v", err)
+			}
+			if len(result) != 1 || !reflect.DeepEqual(result[0], tt.want) {
+				t.Errorf("result = %v, want [%v]", result, tt.want)
+			}
+		})
+	}
+}
+
 func TestGroupByMultipleColumns(t *testing.T) {
 	query := "SELECT department, status, COUNT(*) FROM data.parquet GROUP BY department, status"
 	rows := []map[string]interface{}{
@@ -378,15 +425,30 @@ func TestParseGroupByErrors(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "HAVING without GROUP BY",
-			query:   "SELECT status, COUNT(*) FROM data.parquet HAVING COUNT(*) > 1",
+			name:    "HAVING without GROUP BY or aggregates",
+			query:   "SELECT status FROM data.parquet HAVING status = 'active'",
 			wantErr: true,
 		},
+		{
+			name:    "HAVING over whole-table aggregates",
+			query:   "SELECT COUNT(*) FROM data.parquet HAVING COUNT(*) > 1",
+			wantErr: false,
+		},
 		{
 			name:    "Empty GROUP BY",
 			query:   "SELECT status, COUNT(*) FROM data.parquet GROUP BY",
 			wantErr: true,
 		},
+		{
+			name:    "GROUP BY constant",
+			query:   "SELECT COUNT(*) FROM data.parquet GROUP BY 1",
+			wantErr: true,
+		},
+		{
+			name:    "GROUP BY aggregate",
+			query:   "SELECT COUNT(*) FROM data.parquet GROUP BY COUNT(*)",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -398,3 +460,206 @@ func TestParseGroupByErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseGroupByExpression(t *testing.T) {
+	q, err := Parse("SELECT age % 10, age / 10 AS decade, COUNT(*) AS n FROM data.parquet GROUP BY decade, age % 10, status")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if want := []string{"decade", "GROUP BY #2", "status"}; !reflect.DeepEqual(q.GroupBy, want) {
+		t.Errorf("GroupBy = %v, want %v", q.GroupBy, want)
+	}
+	if len(q.GroupKeys) != 2 {
+		t.Fatalf("GroupKeys = %v, want 2 keys", q.GroupKeys)
+	}
+
+	// SELECT items computing a key read its column instead
+	want := []SelectItem{
+		{Expr: &ColumnRef{Column: "GROUP BY #2"}, Alias: "col_0"},
+		{Expr: &ColumnRef{Column: "decade"}, Alias: "decade"},
+	}
+	if !reflect.DeepEqual(q.SelectList[:2], want) {
+		t.Errorf("SelectList = %v, want %v", q.SelectList[:2], want)
+	}
+}
+
+func TestParseGroupByRollup(t *testing.T) {
+	q, err := Parse("SELECT dept, team, COUNT(*) FROM data.parquet GROUP BY rollup(dept, team)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !q.GroupByRollup {
+		t.Error("GroupByRollup = false, want true")
+	}
+	if want := []string{"dept", "team"}; !reflect.DeepEqual(q.GroupBy, want) {
+		t.Errorf("GroupBy = %v, want %v", q.GroupBy, want)
+	}
+
+	if _, err := Parse("SELECT dept, COUNT(*) FROM data.parquet GROUP BY ROLLUP(dept"); err == nil {
+		t.Error("Parse() expected error for unclosed ROLLUP")
+	}
+}
+
+func TestAggregateDistinct(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"dept": "eng", "team": "platform"},
+		{"dept": "eng", "team": "platform"},
+		{"dept": "eng", "team": "data"},
+		{"dept": "eng", "team": nil},
+		{"dept": "sales", "team": "platform"},
+	}
+
+	q, err := Parse("SELECT dept, COUNT(DISTINCT team) as teams, COUNT(team) as with_team, COUNT(*) as total FROM data.parquet GROUP BY dept")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	result, err := ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
+	if err != nil {
+		t.Fatalf("ApplyGroupByAndAggregate() error = %v", err)
+	}
+
+	expected := map[string][3]int64{
+		"eng":   {2, 3, 4}, // NULL team is not counted, repeated platform counted once
+		"sales": {1, 1, 1}, // platform also appears in eng, but distinctness is per group
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("got %d groups, want %d", len(result), len(expected))
+	}
+	for _, row := range result {
+		want := expected[row["dept"].(string)]
+		got := [3]int64{row["teams"].(int64), row["with_team"].(int64), row["total"].(int64)}
+		if got != want {
+			t.Errorf("dept %v: got (teams, with_team, total) = %v, want %v", row["dept"], got, want)
+		}
+	}
+}
+
+func TestParseAggregateDistinct(t *testing.T) {
+	q, err := Parse("SELECT COUNT(DISTINCT team), COUNT(team) FROM data.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if agg := q.SelectList[0].Expr.(*AggregateExpr); !agg.Distinct {
+		t.Errorf("expected COUNT(DISTINCT team) to be marked distinct")
+	}
+	if agg := q.SelectList[1].Expr.(*AggregateExpr); agg.Distinct {
+		t.Errorf("expected COUNT(team) not to be marked distinct")
+	}
+
+	if _, err := Parse("SELECT COUNT(DISTINCT *) FROM data.parquet"); err == nil {
+		t.Errorf("expected error for COUNT(DISTINCT *)")
+	}
+	if _, err := Parse("SELECT APPROX_COUNT_DISTINCT(DISTINCT team) FROM data.parquet"); err == nil {
+		t.Errorf("expected error for APPROX_COUNT_DISTINCT(DISTINCT team)")
+	}
+}
+
+func TestParseOrderedAggregate(t *testing.T) {
+	q, err := Parse("SELECT STRING_AGG(name, ', ' ORDER BY age DESC, name), ARRAY_AGG(id ORDER BY id) FROM data.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	agg := q.SelectList[0].Expr.(*AggregateExpr)
+	if agg.Separator != ", " {
+		t.Errorf("Separator = %q, want \", \"", agg.Separator)
+	}
+	wantOrder := []OrderByItem{{Column: "age", Desc: true}, {Column: "name"}}
+	if !reflect.DeepEqual(agg.OrderBy, wantOrder) {
+		t.Errorf("OrderBy = %v, want %v", agg.OrderBy, wantOrder)
+	}
+	if agg := q.SelectList[1].Expr.(*AggregateExpr); len(agg.OrderBy) != 1 || agg.OrderBy[0].Column != "id" {
+		t.Errorf("ARRAY_AGG OrderBy = %v, want [id]", agg.OrderBy)
+	}
+
+	errorQueries := []string{
+		"SELECT STRING_AGG(name) FROM data.parquet",
+		"SELECT STRING_AGG(name, sep) FROM data.parquet",
+		"SELECT SUM(age ORDER BY age) FROM data.parquet",
+		"SELECT ARRAY_AGG(id ORDER BY) FROM data.parquet",
+	}
+	for _, query := range errorQueries {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q) expected error", query)
+		}
+	}
+}
+
+func TestHavingAggregates(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"dept": "eng", "salary": int64(100), "name": "a"},
+		{"dept": "eng", "salary": int64(120), "name": "b"},
+		{"dept": "sales", "salary": int64(50), "name": "c"},
+		{"dept": "sales", "salary": int64(70), "name": "d"},
+		{"dept": "sales", "salary": int64(60), "name": "d"},
+		{"dept": "hr", "salary": int64(40), "name": "e"},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "alias of selected aggregate",
+			query: "SELECT dept, SUM(salary) AS total FROM data GROUP BY dept HAVING total > 100 ORDER BY dept",
+			want: []map[string]interface{}{
+				{"dept": "eng", "total": float64(220)},
+				{"dept": "sales", "total": float64(180)},
+			},
+		},
+		{
+			name:  "unselected aggregate",
+			query: "SELECT dept FROM data GROUP BY dept HAVING SUM(salary) > 200",
+			want:  []map[string]interface{}{{"dept": "eng"}},
+		},
+		{
+			name:  "selected aggregate repeated in having",
+			query: "SELECT dept, COUNT(*) FROM data GROUP BY dept HAVING COUNT(*) >= 2 AND MAX(salary) < 100",
+			want:  []map[string]interface{}{{"dept": "sales", "count": int64(3)}},
+		},
+		{
+			name:  "same aggregate twice",
+			query: "SELECT dept FROM data GROUP BY dept HAVING AVG(salary) > 50 AND AVG(salary) < 100",
+			want:  []map[string]interface{}{{"dept": "sales"}},
+		},
+		{
+			name:  "distinct aggregate with BETWEEN",
+			query: "SELECT dept FROM data GROUP BY dept HAVING COUNT(DISTINCT name) BETWEEN 2 AND 2 ORDER BY dept",
+			want:  []map[string]interface{}{{"dept": "eng"}, {"dept": "sales"}},
+		},
+		{
+			name:    "type error names the aggregate",
+			query:   "SELECT dept FROM data GROUP BY dept HAVING SUM(salary) > 'high'",
+			wantErr: `column "SUM(salary)"`,
+		},
+		{
+			name:    "aggregate in WHERE",
+			query:   "SELECT dept FROM data WHERE COUNT(*) > 1 GROUP BY dept",
+			wantErr: "aggregate function COUNT is not allowed here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err == nil {
+				var results []map[string]interface{}
+				results, err = ExecuteOnRows(q, rows)
+				if err == nil && tt.wantErr == "" && !reflect.DeepEqual(results, tt.want) {
+					t.Errorf("got %#v, want %#v", results, tt.want)
+				}
+			}
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
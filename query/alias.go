@@ -0,0 +1,151 @@
+package query
+
+import (
+	"fmt"
+	"maps"
+)
+
+// WithSelectAliases returns a WHERE clause that may refer to the aliases of
+// selectList, as in SELECT salary * 2 AS bonus FROM t WHERE bonus > 1000.
+// Strict SQL rejects this, since WHERE runs before the SELECT list, so it
+// is only done for ExecutionContext.WhereAliases (see WhereFilter).
+//
+// When a row lacks a column the filter names, the SELECT item of that
+// alias is evaluated on the row instead; columns of the table take
+// precedence over aliases. Aliases of aggregates and window functions
+// can't be evaluated per row and fail. filter is returned unchanged if it
+// names no alias.
+func WithSelectAliases(filter Expression, selectList []SelectItem) Expression {
+	if filter == nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	expressionColumns(filter, names)
+
+	var aliases []SelectItem
+	for _, item := range selectList {
+		if item.Alias != "" && names[item.Alias] {
+			aliases = append(aliases, item)
+			// Later items with the same alias are renamed alias_1, ...
+			delete(names, item.Alias)
+		}
+	}
+	if len(aliases) == 0 {
+		return filter
+	}
+	return &aliasFilter{filter: filter, aliases: aliases}
+}
+
+// WhereFilter returns the WHERE clause of q to apply to its rows: q.Filter,
+// resolving SELECT aliases if WhereAliases is set
+func (ctx *ExecutionContext) WhereFilter(q *Query) Expression {
+	if ctx == nil || !ctx.WhereAliases {
+		return q.Filter
+	}
+	return WithSelectAliases(q.Filter, q.SelectList)
+}
+
+// aliasFilter is a WHERE clause naming SELECT aliases (see WithSelectAliases)
+type aliasFilter struct {
+	filter  Expression
+	aliases []SelectItem
+}
+
+// Evaluate evaluates the filter on the row extended with the aliases it lacks
+func (a *aliasFilter) Evaluate(row map[string]interface{}) (bool, error) {
+	extended, err := a.withAliases(row, func(row map[string]interface{}, expr SelectExpression) (interface{}, error) {
+		return expr.EvaluateSelect(row)
+	})
+	if err != nil {
+		return false, err
+	}
+	return a.filter.Evaluate(extended)
+}
+
+// withAliases returns row with the value of each alias it has no column
+// for, computed by eval. row itself is not modified.
+func (a *aliasFilter) withAliases(row map[string]interface{}, eval func(map[string]interface{}, SelectExpression) (interface{}, error)) (map[string]interface{}, error) {
+	extended, cloned := row, false
+	for _, item := range a.aliases {
+		if _, ok := row[item.Alias]; ok {
+			continue
+		}
+		value, err := eval(row, item.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating SELECT alias %s for WHERE: %w", item.Alias, err)
+		}
+		if !cloned {
+			extended, cloned = maps.Clone(row), true
+		}
+		extended[item.Alias] = value
+	}
+	return extended, nil
+}
+
+// expressionColumns adds the columns a filter expression names to names
+func expressionColumns(expr Expression, names map[string]bool) {
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		expressionColumns(e.Left, names)
+		expressionColumns(e.Right, names)
+	case *NotExpr:
+		expressionColumns(e.Expr, names)
+	case *ComparisonExpr:
+		names[e.Column] = true
+	case *ColumnComparisonExpr:
+		names[e.LeftColumn] = true
+		names[e.RightColumn] = true
+	case *ExpressionComparisonExpr:
+		selectExpressionColumns(e.Left, names)
+		selectExpressionColumns(e.Right, names)
+	case *DistinctExpr:
+		selectExpressionColumns(e.Left, names)
+		selectExpressionColumns(e.Right, names)
+	case *InExpr:
+		names[e.Column] = true
+		for _, col := range e.Columns {
+			names[col] = true
+		}
+	case *LikeExpr:
+		names[e.Column] = true
+	case *BetweenExpr:
+		names[e.Column] = true
+	case *IsNullExpr:
+		names[e.Column] = true
+	case *IsBoolExpr:
+		names[e.Column] = true
+	case *InSubqueryExpr:
+		names[e.Column] = true
+	case *QuantifiedSubqueryExpr:
+		names[e.Column] = true
+	}
+}
+
+// selectExpressionColumns adds the columns an expression names to names
+func selectExpressionColumns(expr SelectExpression, names map[string]bool) {
+	switch e := expr.(type) {
+	case *ColumnRef:
+		names[e.Column] = true
+	case *FunctionCall:
+		for _, arg := range e.Args {
+			selectExpressionColumns(arg, names)
+		}
+	case *ArithmeticExpr:
+		selectExpressionColumns(e.Left, names)
+		selectExpressionColumns(e.Right, names)
+	case *ConcatExpr:
+		selectExpressionColumns(e.Left, names)
+		selectExpressionColumns(e.Right, names)
+	case *IndexExpr:
+		selectExpressionColumns(e.Expr, names)
+		selectExpressionColumns(e.Index, names)
+	case *ExtractExpr:
+		selectExpressionColumns(e.Expr, names)
+	case *CaseExpr:
+		for _, when := range e.WhenClauses {
+			expressionColumns(when.Condition, names)
+			selectExpressionColumns(when.Result, names)
+		}
+		selectExpressionColumns(e.ElseExpr, names)
+	}
+}
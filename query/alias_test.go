@@ -0,0 +1,129 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWhereAliases(t *testing.T) {
+	file := createNamedBasicParquetFile(t, t.TempDir(), "people.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0},
+		{ID: 3, Name: "Carol", Age: 35, Salary: 60000.0},
+	})
+	from := " FROM '" + file + "' "
+
+	tests := []struct {
+		name          string
+		query         string
+		want          []map[string]interface{}
+		wantErr       string
+		wantStrictErr string // error without WhereAliases; none means the same result
+	}{
+		{
+			name:          "arithmetic alias",
+			query:         "SELECT name, salary * 2 AS bonus" + from + "WHERE bonus > 95000 ORDER BY name",
+			want:          []map[string]interface{}{{"name": "Alice", "bonus": 100000.0}, {"name": "Carol", "bonus": 120000.0}},
+			wantStrictErr: `column "bonus" not found`,
+		},
+		{
+			name:          "aliases in several conditions",
+			query:         "SELECT UPPER(name) AS shout, age + 1 AS next" + from + "WHERE shout LIKE 'C%' OR next BETWEEN 24 AND 26",
+			want:          []map[string]interface{}{{"shout": "BOB", "next": 26.0}, {"shout": "CAROL", "next": 36.0}},
+			wantStrictErr: `column "shout" not found`,
+		},
+		{
+			name:          "alias in an expression",
+			query:         "SELECT id, CASE WHEN age > 28 THEN 'senior' ELSE 'junior' END AS level" + from + "WHERE UPPER(level) = 'JUNIOR'",
+			want:          []map[string]interface{}{{"id": int64(2), "level": "junior"}},
+			wantStrictErr: `column "level" not found`,
+		},
+		{
+			name:  "columns take precedence over aliases",
+			query: "SELECT id, age * 10 AS age" + from + "WHERE age < 30",
+			want:  []map[string]interface{}{{"id": int64(2), "age": 250.0}},
+		},
+		{
+			name:          "aggregate alias",
+			query:         "SELECT COUNT(*) AS n" + from + "WHERE n > 1",
+			wantErr:       "evaluating SELECT alias n for WHERE: aggregate function COUNT cannot be evaluated on individual rows",
+			wantStrictErr: `column "n" not found`,
+		},
+		{
+			name:          "subquery",
+			query:         "SELECT id FROM (SELECT id, age - 20 AS years" + from + "WHERE years >= 10) ORDER BY id",
+			want:          []map[string]interface{}{{"id": int64(1)}, {"id": int64(3)}},
+			wantStrictErr: `column "years" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, lenient := range []bool{true, false} {
+			name := tt.name + "/strict"
+			want, wantErr := tt.want, tt.wantErr
+			if lenient {
+				name = tt.name + "/lenient"
+			} else if tt.wantStrictErr != "" {
+				want, wantErr = nil, tt.wantStrictErr
+			}
+
+			t.Run(name, func(t *testing.T) {
+				q, err := Parse(tt.query)
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				ctx := NewExecutionContext(nil)
+				ctx.WhereAliases = lenient
+
+				rows, err := ctx.executeSelect(q)
+				if wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), wantErr) {
+						t.Fatalf("executeSelect() error = %v, want %q", err, wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("executeSelect() error = %v", err)
+				}
+				if !reflect.DeepEqual(rows, want) {
+					t.Errorf("executeSelect() = %v, want %v", rows, want)
+				}
+			})
+		}
+	}
+}
+
+func TestWithSelectAliases(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"price": 2.0, "qty": int64(3)},
+		{"price": 10.0, "qty": int64(1)},
+		{"price": 4.0, "qty": int64(5), "total": 1.0},
+	}
+	q, err := Parse("SELECT price * qty AS total FROM t WHERE total > 5")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Without a context, as ApplyFilter runs it; the third row's own total
+	// column is used instead of the alias
+	got, err := ApplyFilter(rows, WithSelectAliases(q.Filter, q.SelectList))
+	if err != nil {
+		t.Fatalf("ApplyFilter() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rows[:2]) {
+		t.Errorf("ApplyFilter() = %v, want %v", got, rows[:2])
+	}
+	if _, ok := rows[0]["total"]; ok {
+		t.Error("ApplyFilter() added the alias to the input row")
+	}
+
+	// A filter without aliases is returned as is
+	q, err = Parse("SELECT price * qty AS total FROM t WHERE qty > 2")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if filter := WithSelectAliases(q.Filter, q.SelectList); filter != q.Filter {
+		t.Errorf("WithSelectAliases() = %#v, want the filter unchanged", filter)
+	}
+}
@@ -0,0 +1,363 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_ArithmeticPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		row  map[string]interface{}
+		want interface{}
+	}{
+		{
+			name: "multiplication before addition",
+			sql:  "select a + b * c from t.parquet",
+			row:  map[string]interface{}{"a": int64(1), "b": int64(2), "c": int64(3)},
+			want: 7.0,
+		},
+		{
+			name: "left to right subtraction",
+			sql:  "select a - b - c from t.parquet",
+			row:  map[string]interface{}{"a": int64(10), "b": int64(3), "c": int64(2)},
+			want: 5.0,
+		},
+		{
+			name: "left to right division",
+			sql:  "select a / b / c from t.parquet",
+			row:  map[string]interface{}{"a": int64(24), "b": int64(4), "c": int64(2)},
+			want: 3.0,
+		},
+		{
+			name: "parentheses override precedence",
+			sql:  "select (a + b) * c from t.parquet",
+			row:  map[string]interface{}{"a": int64(1), "b": int64(2), "c": int64(3)},
+			want: 9.0,
+		},
+		{
+			name: "minus without spaces",
+			sql:  "select a -1 from t.parquet",
+			row:  map[string]interface{}{"a": int64(5)},
+			want: 4.0,
+		},
+		{
+			name: "negative literal operand",
+			sql:  "select a * -2 from t.parquet",
+			row:  map[string]interface{}{"a": 1.5},
+			want: -3.0,
+		},
+		{
+			name: "negated column",
+			sql:  "select -a from t.parquet",
+			row:  map[string]interface{}{"a": int64(4)},
+			want: -4.0,
+		},
+		{
+			name: "negated parenthesized expression",
+			sql:  "select -(a + 1) * 2 from t.parquet",
+			row:  map[string]interface{}{"a": int64(4)},
+			want: -10.0,
+		},
+		{
+			name: "minus separated from its number",
+			sql:  "select - 2 from t.parquet",
+			row:  map[string]interface{}{},
+			want: int64(-2),
+		},
+		{
+			name: "subtracting a negated column",
+			sql:  "select a - -a from t.parquet",
+			row:  map[string]interface{}{"a": 1.5},
+			want: 3.0,
+		},
+		{
+			name: "negated null",
+			sql:  "select -a from t.parquet",
+			row:  map[string]interface{}{"a": nil},
+			want: nil,
+		},
+		{
+			name: "function operand",
+			sql:  "select ABS(a) + 1 from t.parquet",
+			row:  map[string]interface{}{"a": int64(-4)},
+			want: 5.0,
+		},
+		{
+			name: "arithmetic in function argument",
+			sql:  "select ROUND(a / 3, 2) from t.parquet",
+			row:  map[string]interface{}{"a": int64(10)},
+			want: 3.33,
+		},
+		{
+			name: "integer modulo stays integer",
+			sql:  "select a % b from t.parquet",
+			row:  map[string]interface{}{"a": int64(17), "b": int32(5)},
+			want: int64(2),
+		},
+		{
+			name: "negative integer modulo",
+			sql:  "select a % 5 from t.parquet",
+			row:  map[string]interface{}{"a": int64(-7)},
+			want: int64(-2),
+		},
+		{
+			name: "float modulo",
+			sql:  "select a % 2 from t.parquet",
+			row:  map[string]interface{}{"a": 5.5},
+			want: 1.5,
+		},
+		{
+			name: "modulo binds like multiplication",
+			sql:  "select a + b % c * 2 from t.parquet",
+			row:  map[string]interface{}{"a": int64(1), "b": int64(7), "c": int64(4)},
+			want: 7.0,
+		},
+		{
+			name: "null operand",
+			sql:  "select a + b from t.parquet",
+			row:  map[string]interface{}{"a": int64(1), "b": nil},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(q.SelectList) != 1 {
+				t.Fatalf("expected 1 select item, got %d", len(q.SelectList))
+			}
+			got, err := q.SelectList[0].Expr.EvaluateSelect(tt.row)
+			if err != nil {
+				t.Fatalf("EvaluateSelect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestArithmeticExpr_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		row     map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "division by zero",
+			sql:     "select a / b from t.parquet",
+			row:     map[string]interface{}{"a": int64(1), "b": int64(0)},
+			wantErr: "division by zero",
+		},
+		{
+			name:    "integer modulo by zero",
+			sql:     "select a % b from t.parquet",
+			row:     map[string]interface{}{"a": int64(1), "b": int64(0)},
+			wantErr: "modulo by zero",
+		},
+		{
+			name:    "float modulo by zero",
+			sql:     "select a % 0.0 from t.parquet",
+			row:     map[string]interface{}{"a": 1.5},
+			wantErr: "modulo by zero",
+		},
+		{
+			name:    "string operand",
+			sql:     "select a + 1 from t.parquet",
+			row:     map[string]interface{}{"a": "x"},
+			wantErr: "non-numeric",
+		},
+		{
+			name:    "missing column",
+			sql:     "select a + 1 from t.parquet",
+			row:     map[string]interface{}{},
+			wantErr: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = q.SelectList[0].Expr.EvaluateSelect(tt.row)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParser_ArithmeticInWhere(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "Alice", "salary": 60000.0, "age": int64(30)},
+		{"name": "Bob", "salary": 30000.0, "age": int64(25)},
+		{"name": "Carol", "salary": nil, "age": int64(40)},
+	}
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "expression on the left",
+			sql:  "select name from t.parquet where salary / 12 > 4000",
+			want: []string{"Alice"},
+		},
+		{
+			name: "parenthesized expression",
+			sql:  "select name from t.parquet where (salary / age) > 1500 and age < 40",
+			want: []string{"Alice"},
+		},
+		{
+			name: "expression on both sides",
+			sql:  "select name from t.parquet where salary - 1000 < age * 1000 + 5000",
+			want: []string{"Bob"},
+		},
+		{
+			name: "modulo filters even values",
+			sql:  "select name from t.parquet where age % 2 = 0",
+			want: []string{"Alice", "Carol"},
+		},
+		{
+			name: "modulo on the right",
+			sql:  "select name from t.parquet where age = 130 % 50",
+			want: []string{"Alice"},
+		},
+		{
+			name: "function on the left",
+			sql:  "select name from t.parquet where LENGTH(name) = 5",
+			want: []string{"Alice", "Carol"},
+		},
+		{
+			name: "negative exponent threshold",
+			sql:  "select name from t.parquet where age - 30 < -2.5e0",
+			want: []string{"Bob"},
+		},
+		{
+			name: "negated column on the left",
+			sql:  "select name from t.parquet where -age < -35",
+			want: []string{"Carol"},
+		},
+		{
+			name: "negated column on the right",
+			sql:  "select name from t.parquet where age - 60 > -age",
+			want: []string{"Carol"},
+		},
+		{
+			name: "expression on the right",
+			sql:  "select name from t.parquet where salary > 1000 * 45",
+			want: []string{"Alice"},
+		},
+		{
+			name: "negated sum on the right",
+			sql:  "select name from t.parquet where age = -5 + 35",
+			want: []string{"Alice"},
+		},
+		{
+			name: "function on the right",
+			sql:  "select name from t.parquet where age = LENGTH(name) * 6",
+			want: []string{"Alice"},
+		},
+		{
+			name:    "missing comparison operator",
+			sql:     "select name from t.parquet where salary * 2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected parse error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var got []string
+			for _, row := range rows {
+				match, err := q.Filter.Evaluate(row)
+				if err != nil {
+					t.Fatalf("Evaluate() error = %v", err)
+				}
+				if match {
+					got = append(got, row["name"].(string))
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_ComparisonRightSide(t *testing.T) {
+	tests := []struct {
+		where string
+		want  Expression
+	}{
+		{
+			where: "age > 30",
+			want:  &ComparisonExpr{Column: "age", Operator: TokenGreater, Value: int64(30)},
+		},
+		{
+			where: "age < -5",
+			want:  &ComparisonExpr{Column: "age", Operator: TokenLess, Value: int64(-5)},
+		},
+		{
+			where: "age = limit_age",
+			want:  &ColumnComparisonExpr{LeftColumn: "age", Operator: TokenEqual, RightColumn: "limit_age"},
+		},
+		{
+			where: "salary > 1000 * 45",
+			want: &ExpressionComparisonExpr{
+				Left:     &ColumnRef{Column: "salary"},
+				Operator: TokenGreater,
+				Right:    &ArithmeticExpr{Left: &LiteralExpr{Value: int64(1000)}, Operator: TokenStar, Right: &LiteralExpr{Value: int64(45)}},
+			},
+		},
+		{
+			where: "id = -1 + 2",
+			want: &ExpressionComparisonExpr{
+				Left:     &ColumnRef{Column: "id"},
+				Operator: TokenEqual,
+				Right:    &ArithmeticExpr{Left: &LiteralExpr{Value: int64(-1)}, Operator: TokenPlus, Right: &LiteralExpr{Value: int64(2)}},
+			},
+		},
+		{
+			where: "name = UPPER(name)",
+			want: &ExpressionComparisonExpr{
+				Left:     &ColumnRef{Column: "name"},
+				Operator: TokenEqual,
+				Right:    &FunctionCall{Name: "UPPER", Args: []SelectExpression{&ColumnRef{Column: "name"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			q, err := Parse("select * from t.parquet where " + tt.where)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(q.Filter, tt.want) {
+				t.Errorf("Filter = %#v, want %#v", q.Filter, tt.want)
+			}
+		})
+	}
+}
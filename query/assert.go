@@ -0,0 +1,121 @@
+package query
+
+import (
+	"fmt"
+)
+
+// Assertion is a data-quality check evaluated over a whole result set,
+// such as "COUNT(*) > 0" or "MIN(age) >= 0".
+//
+// An assertion is either a comparison between two expressions or a single
+// expression that must be true or non-zero. Column references must appear
+// inside aggregate functions.
+type Assertion struct {
+	Text     string           // Original assertion text
+	Left     SelectExpression // Left side, or the whole expression if there is no comparison
+	Operator Token            // Comparison operator (only meaningful when Right is set)
+	Right    SelectExpression // Right side of the comparison (nil if there is none)
+}
+
+// ParseAssertion parses an assertion expression
+func ParseAssertion(text string) (*Assertion, error) {
+	if err := ValidateQuery(text); err != nil {
+		return nil, err
+	}
+
+	tokens := Tokenize(text)
+	if err := ValidateTokens(tokens); err != nil {
+		return nil, err
+	}
+	if err := tokenError(tokens, "assertion"); err != nil {
+		return nil, err
+	}
+
+	parser := NewParser(tokens)
+	left, err := parser.parseSelectExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	assertion := &Assertion{Text: text, Left: left}
+
+	switch parser.current().Type {
+	case TokenEqual, TokenNotEqual, TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual:
+		assertion.Operator = parser.current()
+		parser.advance()
+
+		right, err := parser.parseSelectExpression()
+		if err != nil {
+			return nil, err
+		}
+		assertion.Right = right
+	}
+
+	if parser.current().Type != TokenEOF {
+		return nil, fmt.Errorf("unexpected trailing tokens after assertion: %s", parser.current().Value)
+	}
+
+	return assertion, nil
+}
+
+// Check evaluates the assertion over rows. It returns whether the assertion
+// holds and a description of the evaluated values for reporting.
+func (a *Assertion) Check(rows []map[string]interface{}) (bool, string, error) {
+	left, err := evaluateOverRows(a.Left, rows)
+	if err != nil {
+		return false, "", err
+	}
+
+	if a.Right == nil {
+		detail := fmt.Sprintf("got %v", left)
+		switch v := left.(type) {
+		case nil:
+			return false, detail, nil
+		case bool:
+			return v, detail, nil
+		}
+		num, err := valueToNumber(left)
+		if err != nil {
+			return false, "", fmt.Errorf("assertion result must be boolean or numeric, got %T", left)
+		}
+		return num != 0, detail, nil
+	}
+
+	right, err := evaluateOverRows(a.Right, rows)
+	if err != nil {
+		return false, "", err
+	}
+
+	detail := fmt.Sprintf("got %v %s %v", left, a.Operator.Value, right)
+	ok, err := compare(left, a.Operator.Type, right)
+	if err != nil {
+		return false, "", err
+	}
+	return ok, detail, nil
+}
+
+// evaluateOverRows evaluates an expression whose column references are all
+// inside aggregates, treating rows as a single group
+func evaluateOverRows(expr SelectExpression, rows []map[string]interface{}) (interface{}, error) {
+	switch e := expr.(type) {
+	case *AggregateExpr:
+		return evaluateAggregate(e, rows)
+	case *LiteralExpr:
+		return e.Value, nil
+	case *FunctionCall:
+		// Evaluate arguments over all rows, then apply the function to the results
+		args := make([]SelectExpression, len(e.Args))
+		for i, arg := range e.Args {
+			value, err := evaluateOverRows(arg, rows)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = &LiteralExpr{Value: value}
+		}
+		return (&FunctionCall{Name: e.Name, Args: args}).EvaluateSelect(nil)
+	case *ColumnRef:
+		return nil, fmt.Errorf("column %q must be used in an aggregate function", e.Column)
+	default:
+		return nil, fmt.Errorf("unsupported expression in assertion: %T", expr)
+	}
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAssertion_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion string
+	}{
+		{name: "missing right side", assertion: "COUNT(*) >"},
+		{name: "trailing tokens", assertion: "COUNT(*) > 0 AND"},
+		{name: "invalid character", assertion: "COUNT(*) ! 0"},
+		{name: "empty", assertion: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAssertion(tt.assertion); err == nil {
+				t.Errorf("ParseAssertion(%q) expected error", tt.assertion)
+			}
+		})
+	}
+}
+
+func TestAssertion_Check(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "Alice", "age": int64(30), "active": true},
+		{"name": "Bob", "age": int64(-1), "active": false},
+		{"name": "Charlie", "age": nil, "active": true},
+	}
+
+	tests := []struct {
+		name       string
+		assertion  string
+		rows       []map[string]interface{}
+		want       bool
+		wantDetail string
+		wantErr    string
+	}{
+		{name: "count positive", assertion: "COUNT(*) > 0", rows: rows, want: true, wantDetail: "got 3 > 0"},
+		{name: "count on empty input", assertion: "COUNT(*) > 0", rows: nil, want: false, wantDetail: "got 0 > 0"},
+		{name: "min violated", assertion: "MIN(age) >= 0", rows: rows, want: false, wantDetail: "got -1 >= 0"},
+		{name: "aggregates on both sides", assertion: "COUNT(name) = COUNT(*)", rows: rows, want: true},
+		{name: "nulls counted", assertion: "COUNT(age) = 3", rows: rows, want: false, wantDetail: "got 2 = 3"},
+		{name: "function around aggregate", assertion: "ROUND(AVG(age)) = 15", rows: rows, want: true},
+		{name: "single numeric expression", assertion: "COUNT(*)", rows: rows, want: true, wantDetail: "got 3"},
+		{name: "single numeric expression zero", assertion: "COUNT(*)", rows: nil, want: false},
+		{name: "null result fails", assertion: "MAX(age)", rows: nil, want: false, wantDetail: "got <nil>"},
+		{name: "bare column", assertion: "age > 0", rows: rows, wantErr: "must be used in an aggregate"},
+		{name: "non-numeric result", assertion: "ARRAY_AGG(name)", rows: rows, wantErr: "boolean or numeric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertion, err := ParseAssertion(tt.assertion)
+			if err != nil {
+				t.Fatalf("ParseAssertion() error = %v", err)
+			}
+
+			got, detail, err := assertion.Check(tt.rows)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Check() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Check() = %v (%s), want %v", got, detail, tt.want)
+			}
+			if tt.wantDetail != "" && detail != tt.wantDetail {
+				t.Errorf("Check() detail = %q, want %q", detail, tt.wantDetail)
+			}
+		})
+	}
+}
@@ -30,6 +30,26 @@ func TestParser_CaseExpression(t *testing.T) {
 			sql:     "select case when age > 18 then 'adult' else 'minor' end as age_group from users.parquet",
 			wantErr: false,
 		},
+		{
+			name:    "boolean column condition",
+			sql:     "select case when active then 'yes' else 'no' end from users.parquet",
+			wantErr: false,
+		},
+		{
+			name:    "nested case",
+			sql:     "select case when active then case when age > 18 then 'adult' end else 'inactive' end from users.parquet",
+			wantErr: false,
+		},
+		{
+			name:    "case in where",
+			sql:     "select name from users.parquet where case when age < 18 then 'minor' else 'adult' end = 'minor'",
+			wantErr: false,
+		},
+		{
+			name:    "case in where without operator",
+			sql:     "select name from users.parquet where case when age < 18 then 'minor' end 'minor'",
+			wantErr: true,
+		},
 		{
 			name:    "missing when",
 			sql:     "select case then 'result' end from users.parquet",
@@ -259,3 +279,74 @@ func TestIntegration_CaseExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestCaseExpr_InWhere(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "Alice", "age": int64(25), "active": true},
+		{"name": "Bob", "age": int64(15), "active": false},
+		{"name": "Charlie", "age": int64(12), "active": true},
+		{"name": "Diana", "age": nil, "active": nil},
+	}
+
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "case compared with literal",
+			sql:  "select name from users.parquet where case when age < 18 then 'minor' else 'adult' end = 'minor'",
+			want: []string{"Bob", "Charlie"},
+		},
+		{
+			name: "case without else yields null",
+			sql:  "select name from users.parquet where case when age > 20 then 'adult' end != 'adult'",
+			want: []string{"Bob", "Charlie", "Diana"},
+		},
+		{
+			name: "boolean column",
+			sql:  "select name from users.parquet where active",
+			want: []string{"Alice", "Charlie"},
+		},
+		{
+			name: "boolean column with and",
+			sql:  "select name from users.parquet where active and age < 18",
+			want: []string{"Charlie"},
+		},
+		{
+			name: "nested case",
+			sql:  "select name from users.parquet where case when active then case when age < 18 then 'young' else 'old' end else 'none' end = 'young'",
+			want: []string{"Charlie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var got []string
+			for _, row := range rows {
+				match, err := query.Filter.Evaluate(row)
+				if err != nil {
+					t.Fatalf("Evaluate() error = %v", err)
+				}
+				if match {
+					got = append(got, row["name"].(string))
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
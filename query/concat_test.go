@@ -0,0 +1,109 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_ConcatOperator(t *testing.T) {
+	row := map[string]interface{}{
+		"first": "Ada",
+		"last":  "Lovelace",
+		"id":    int64(7),
+		"score": 9.5,
+		"ok":    true,
+		"none":  nil,
+	}
+
+	tests := []struct {
+		name string
+		sql  string
+		want interface{}
+	}{
+		{name: "strings", sql: "select first || ' ' || last from t.parquet", want: "Ada Lovelace"},
+		{name: "integer operand", sql: "select first || '#' || id from t.parquet", want: "Ada#7"},
+		{name: "number first", sql: "select id || first from t.parquet", want: "7Ada"},
+		{name: "float operand", sql: "select 'score: ' || score from t.parquet", want: "score: 9.5"},
+		{name: "bool operand", sql: "select first || ok from t.parquet", want: "Adatrue"},
+		{name: "number literals", sql: "select 1 || 2 from t.parquet", want: "12"},
+		{name: "arithmetic binds tighter", sql: "select 'n=' || id * 2 + 1 from t.parquet", want: "n=15"},
+		{name: "function operand", sql: "select UPPER(first) || LOWER(last) from t.parquet", want: "ADAlovelace"},
+		{name: "parentheses", sql: "select (first || last) || '!' from t.parquet", want: "AdaLovelace!"},
+		{name: "null on the right", sql: "select first || none from t.parquet", want: nil},
+		{name: "null in the middle", sql: "select first || none || last from t.parquet", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := q.SelectList[0].Expr.EvaluateSelect(row)
+			if err != nil {
+				t.Fatalf("EvaluateSelect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_ConcatIsLeftAssociative(t *testing.T) {
+	q, err := Parse("select a || b || c from t.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	outer, ok := q.SelectList[0].Expr.(*ConcatExpr)
+	if !ok {
+		t.Fatalf("expected *ConcatExpr, got %T", q.SelectList[0].Expr)
+	}
+	if _, ok := outer.Left.(*ConcatExpr); !ok {
+		t.Errorf("expected a || b to be grouped first, got left %T", outer.Left)
+	}
+	if ref, ok := outer.Right.(*ColumnRef); !ok || ref.Column != "c" {
+		t.Errorf("expected c on the right, got %#v", outer.Right)
+	}
+}
+
+func TestConcatOperator_InQuery(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"first": "Ada", "last": "Lovelace", "id": int64(1)},
+		{"first": "Alan", "last": "Turing", "id": int64(2)},
+	}
+
+	q, err := Parse("select first || ' ' || last as full_name from t.parquet where last || '-' || id = 'Turing-2'")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	result, err := ExecuteOnRows(q, rows)
+	if err != nil {
+		t.Fatalf("ExecuteOnRows() error = %v", err)
+	}
+	if len(result) != 1 || result[0]["full_name"] != "Alan Turing" {
+		t.Errorf("got %v, want one row with full_name Alan Turing", result)
+	}
+
+	// The operator works on the right side of a comparison too
+	q, err = Parse("select id from t.parquet where first = 'A' || 'lan'")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	result, err = ExecuteOnRows(q, rows)
+	if err != nil {
+		t.Fatalf("ExecuteOnRows() error = %v", err)
+	}
+	if len(result) != 1 || result[0]["id"] != int64(2) {
+		t.Errorf("got %v, want one row with id 2", result)
+	}
+
+	q, err = Parse("select first || tags from t.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	_, err = q.SelectList[0].Expr.EvaluateSelect(map[string]interface{}{"first": "Ada", "tags": []interface{}{"x"}})
+	if err == nil || !strings.Contains(err.Error(), "||") {
+		t.Errorf("expected an || error for a list operand, got %v", err)
+	}
+}
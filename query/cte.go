@@ -0,0 +1,147 @@
+package query
+
+import "fmt"
+
+// MaxRecursiveIterations is the number of times the recursive part of a
+// WITH RECURSIVE CTE may run before the query fails, so that a recursion
+// that never stops producing rows is reported instead of running forever
+const MaxRecursiveIterations = 1000
+
+// materializeRecursive materializes a WITH RECURSIVE CTE that refers to
+// itself. Its query is split into the anchor, the UNION arms that don't
+// refer to the CTE, and the recursive arms that do. The anchor runs once,
+// then the recursive arms run repeatedly with the CTE holding only the rows
+// of the previous iteration, until an iteration adds no rows. UNION arms
+// drop rows already produced, which also stops recursion over cycles, while
+// UNION ALL arms keep them. ORDER BY, LIMIT and OFFSET apply to the result.
+func (ctx *ExecutionContext) materializeRecursive(cte CTE, executeFn func(*Query, *ExecutionContext) ([]map[string]interface{}, error)) error {
+	name := cte.Name
+
+	anchor := *cte.Query
+	anchor.SetOperations = nil
+	anchor.OrderBy = nil
+	anchor.Limit = nil
+	anchor.Offset = nil
+	if referencesTable(&anchor, name) {
+		return fmt.Errorf("recursive CTE %s must start with a query that does not refer to %s", name, name)
+	}
+
+	var recursive []SetOperation
+	for _, op := range cte.Query.SetOperations {
+		if referencesTable(op.Query, name) {
+			recursive = append(recursive, op)
+		} else if len(recursive) > 0 {
+			return fmt.Errorf("recursive CTE %s must list the queries that refer to it after those that don't", name)
+		} else {
+			anchor.SetOperations = append(anchor.SetOperations, op)
+		}
+	}
+
+	// A reference to the CTE in the anchor is a cycle, as in other CTEs
+	ctx.InProgress[name] = true
+	rows, err := executeFn(&anchor, ctx)
+	delete(ctx.InProgress, name)
+	if err != nil {
+		return fmt.Errorf("failed to execute CTE %s: %w", name, err)
+	}
+	columns, ordered := ctx.setColumns(&anchor, rows)
+
+	// UNION arms compare new rows against everything produced so far
+	var seen map[string]bool
+	for _, op := range recursive {
+		if op.Operator == SetUnion {
+			seen = make(map[string]bool, len(rows))
+			for _, row := range rows {
+				seen[rowToKey(row)] = true
+			}
+			break
+		}
+	}
+
+	result := rows
+	for iteration := 1; len(rows) > 0; iteration++ {
+		if iteration > MaxRecursiveIterations {
+			return fmt.Errorf("recursive CTE %s did not finish after %d iterations; add a condition that stops it", name, MaxRecursiveIterations)
+		}
+
+		ctx.CTEs[name] = rows
+		var next []map[string]interface{}
+		for i, op := range recursive {
+			arm := len(anchor.SetOperations) + i + 2
+			armRows, err := executeFn(op.Query, ctx)
+			if err != nil {
+				return fmt.Errorf("failed to execute %s query %d of CTE %s: %w", op.Operator, arm, name, err)
+			}
+			if armColumns, armOrdered := ctx.setColumns(op.Query, armRows); columns == nil {
+				columns, ordered = armColumns, armOrdered
+			} else if armColumns != nil {
+				if err := checkSetColumns(columns, armColumns, ordered && armOrdered, op.Operator, arm); err != nil {
+					return fmt.Errorf("CTE %s: %w", name, err)
+				}
+				if ordered && armOrdered {
+					armRows = renameSetColumns(armRows, armColumns, columns)
+				}
+			}
+
+			for _, row := range armRows {
+				if op.Operator == SetUnion {
+					key := rowToKey(row)
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+				}
+				next = append(next, row)
+			}
+		}
+
+		result = append(result, next...)
+		rows = next
+	}
+
+	if len(cte.Query.OrderBy) > 0 {
+		result, err = ApplyOrderBy(result, cte.Query.OrderBy)
+		if err != nil {
+			return fmt.Errorf("CTE %s: failed to apply ORDER BY: %w", name, err)
+		}
+	}
+	if cte.Query.Limit != nil || cte.Query.Offset != nil {
+		result, err = ApplyLimitOffset(result, cte.Query.Limit, cte.Query.Offset)
+		if err != nil {
+			return fmt.Errorf("CTE %s: failed to apply LIMIT/OFFSET: %w", name, err)
+		}
+	}
+
+	ctx.CTEs[name] = result
+	return nil
+}
+
+// referencesTable reports whether q reads the table or CTE called name in
+// its FROM clause, a FROM or JOIN subquery, a JOIN or a UNION arm
+func referencesTable(q *Query, name string) bool {
+	if q == nil {
+		return false
+	}
+	if q.TableName == name || referencesTable(q.Subquery, name) || joinsReference(q.Joins, name) {
+		return true
+	}
+	for _, op := range q.SetOperations {
+		if referencesTable(op.Query, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinsReference reports whether any of joins reads the table or CTE called name
+func joinsReference(joins []Join, name string) bool {
+	for _, join := range joins {
+		if join.TableName == name || referencesTable(join.Subquery, name) {
+			return true
+		}
+		if join.Group != nil && (join.Group.TableName == name || joinsReference(join.Group.Joins, name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -26,9 +26,9 @@ func TestParseCTE(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "recursive CTE not supported",
-			query:   "WITH RECURSIVE cte AS (SELECT * FROM data.parquet) SELECT * FROM cte",
-			wantErr: true,
+			name:    "recursive CTE",
+			query:   "WITH RECURSIVE cte AS (SELECT id FROM data.parquet UNION ALL SELECT id FROM cte) SELECT * FROM cte",
+			wantErr: false,
 		},
 	}
 
@@ -48,6 +48,24 @@ func TestParseCTE(t *testing.T) {
 	}
 }
 
+func TestParseRecursiveCTE(t *testing.T) {
+	q, err := Parse("WITH RECURSIVE a AS (SELECT id FROM data.parquet UNION ALL SELECT id FROM a), b AS (SELECT id FROM a) SELECT * FROM b")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(q.CTEs) != 2 || !q.CTEs[0].Recursive || !q.CTEs[1].Recursive {
+		t.Errorf("Expected both CTEs of a WITH RECURSIVE clause to be recursive, got %+v", q.CTEs)
+	}
+
+	q, err = Parse("WITH a AS (SELECT id FROM data.parquet) SELECT * FROM a")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if q.CTEs[0].Recursive {
+		t.Errorf("Expected CTE without RECURSIVE not to be recursive")
+	}
+}
+
 func TestParseCTEStructure(t *testing.T) {
 	query := "WITH active_users AS (SELECT name, age FROM users.parquet WHERE active = true) SELECT * FROM active_users"
 	q, err := Parse(query)
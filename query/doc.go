@@ -4,15 +4,18 @@
 //   - SELECT with column projection and aliases
 //   - WHERE clauses with complex conditions
 //   - JOINs (INNER, LEFT, RIGHT, FULL, CROSS)
+//   - UNION and UNION ALL
 //   - GROUP BY and HAVING for aggregations
 //   - ORDER BY for sorting results
 //   - LIMIT and OFFSET for pagination
 //   - Common Table Expressions (CTEs with WITH clause)
-//   - Subqueries (IN, EXISTS, scalar)
-//   - Window functions (ROW_NUMBER, RANK, LAG, LEAD, etc.)
-//   - Aggregate functions (COUNT, SUM, AVG, MIN, MAX)
+//   - Subqueries (IN, EXISTS, ANY/ALL, scalar)
+//   - Window functions (ROW_NUMBER, RANK, LAG, LEAD, etc.) and QUALIFY
+//   - Aggregate functions (COUNT, SUM, AVG, MIN, MAX, ARRAY_AGG, STRING_AGG, APPROX_COUNT_DISTINCT)
 //   - Built-in functions (string and math operations)
 //   - Multi-file queries with glob patterns
+//   - Comments: -- to the end of a line and /* ... */ blocks
+//   - Double-quoted identifiers for names with spaces or keywords ("first name")
 //
 // # Basic Usage
 //
@@ -34,6 +37,32 @@
 //	    log.Fatal(err)
 //	}
 //
+// ExecuteQueryContext stops a query once a context is done, checking it
+// while rows are read and filtered, for each outer row of a JOIN and
+// between stages. It then fails with the context's error:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	results, err := query.ExecuteQueryContext(ctx, query, reader)
+//
+// For more control, configure an ExecutionContext and call its Execute
+// method. The CTEs of each query stay in the context, so a later query can
+// read them:
+//
+//	ec := query.NewExecutionContext(nil)
+//	ec.StrictJoins = true
+//	results, err := ec.Execute(query)
+//
+// To run a query against rows already in memory, use ExecuteOnRows. The
+// FROM table is not read, so any name will do:
+//
+//	q, err := query.Parse("SELECT dept, COUNT(*) AS n FROM data GROUP BY dept")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	results, err := query.ExecuteOnRows(q, rows)
+//
 // # Filter Operations
 //
 // Apply filters to existing row data:
@@ -54,6 +83,10 @@
 //	    log.Fatal(err)
 //	}
 //
+// Unlike standard SQL, WHERE may refer to SELECT aliases when
+// ExecutionContext.WhereAliases is set, evaluating the aliased expression
+// for each row (see WithSelectAliases).
+//
 // # Aggregation and GROUP BY
 //
 // Execute queries with aggregation:
@@ -70,6 +103,12 @@
 //	    log.Fatal(err)
 //	}
 //
+// GROUP BY also accepts SELECT aliases and expressions, which are computed
+// for each row before grouping, such as GROUP BY DATE_TRUNC('month', ts).
+// GROUP BY ROLLUP(a, b) adds a subtotal row for each a, with b NULL, and a
+// grand total row with both NULL. Without GROUP BY, HAVING filters the single
+// summary row of an aggregate query.
+//
 // # Window Functions
 //
 // Use window functions for advanced analytics:
@@ -91,6 +130,26 @@
 //	    WHERE o.amount > 100
 //	`
 //
+// Columns that both sides of a join share are renamed: unaliased tables
+// qualify them with their file or CTE name (users.id, orders.id), and
+// otherwise the right one gets a suffix (id_1). Set
+// ExecutionContext.StrictJoins to make a shared column an error instead.
+//
+// USING (col, ...) joins on the named columns instead of an ON condition
+// and keeps a single copy of each, taken from whichever side has a value:
+//
+//	SELECT * FROM users.parquet u LEFT JOIN orders.parquet o USING (id)
+//
+// UNNEST(expr) joins each row with the elements of an array, one row per
+// element, as in counting tags:
+//
+//	SELECT tag, COUNT(*) FROM posts.parquet CROSS JOIN UNNEST(tags) AS tag GROUP BY tag
+//
+// u.* selects every column of the table aliased u, keeping their qualified
+// names (u.id, u.name, ...):
+//
+//	SELECT u.*, o.amount FROM users.parquet u JOIN orders.parquet o ON u.id = o.user_id
+//
 // # Multi-file Queries
 //
 // Query multiple files using glob patterns:
@@ -101,6 +160,31 @@
 //	    GROUP BY date
 //	`
 //
+// # ORDER BY
+//
+// ORDER BY takes columns, SELECT aliases, 1-based positions in the SELECT
+// list, or expressions, which are computed for sorting without being
+// returned:
+//
+//	SELECT name, salary * 2 AS bonus FROM data.parquet ORDER BY 2 DESC, LENGTH(name)
+//
+// NULLs sort last for ASC and first for DESC unless NULLS FIRST or NULLS
+// LAST follows the direction.
+//
+// # UNION
+//
+// UNION ALL appends the rows of one query to another, and UNION also removes
+// duplicate rows. ORDER BY, LIMIT and OFFSET after the last query apply to
+// the combined rows. Columns are matched by position and named after those
+// of the first query, so user_id below is returned as id:
+//
+//	sql := `
+//	    SELECT id, name FROM 'old/*.parquet'
+//	    UNION ALL
+//	    SELECT user_id, username FROM 'new/*.parquet'
+//	    ORDER BY id
+//	`
+//
 // # Common Table Expressions (CTEs)
 //
 // Use CTEs for complex queries:
@@ -112,30 +196,86 @@
 //	    SELECT status, COUNT(*) FROM active_users GROUP BY status
 //	`
 //
+// WITH RECURSIVE CTEs may refer to themselves to walk hierarchies. The
+// recursive queries run until they add no rows, or fail after
+// MaxRecursiveIterations runs:
+//
+//	sql := `
+//	    WITH RECURSIVE reports AS (
+//	        SELECT id FROM employees.parquet WHERE manager_id = 1
+//	        UNION ALL
+//	        SELECT e.id AS id FROM employees.parquet e JOIN reports r ON e.manager_id = r.id
+//	    )
+//	    SELECT * FROM reports
+//	`
+//
 // # Supported Operators
 //
 // WHERE clause operators:
 //   - Comparison: =, !=, <, >, <=, >=
-//   - Logical: AND, OR
-//   - Special: IN, LIKE, BETWEEN, IS NULL, IS NOT NULL
-//   - Subquery: IN (subquery), EXISTS (subquery)
+//   - Logical: AND, OR, NOT, with parentheses to group conditions
+//   - Special: IN, LIKE, ILIKE, BETWEEN, IS NULL, IS NOT NULL
+//   - Null-safe: IS [NOT] DISTINCT FROM and <=>, where NULL <=> NULL is true
+//   - LIKE 'pattern' ESCAPE 'c' matches % and _ literally after c
+//   - Subquery: IN (subquery), EXISTS (subquery), op ANY/SOME/ALL (subquery)
+//   - A bare boolean column or function call (WHERE active,
+//     WHERE REGEXP_MATCH(email, '@example\\.com$')) and an expression compared with
+//     a value (WHERE salary / 12 > 4000, WHERE CASE WHEN ... END = 'x')
+//
+// NOT binds tighter than AND and follows three-valued logic: a comparison
+// with NULL is unknown, and so is its negation, so neither matches.
+//
+// Arithmetic operators +, -, *, / and % work in the SELECT list, in function
+// arguments and in WHERE comparisons. *, / and % bind tighter than + and -.
+// Results are float64, except that % of two integers is an integer, and NULL
+// on either side gives NULL.
+//
+// The || operator concatenates the text of its operands, as in
+// first || ' ' || last. It binds looser than arithmetic and gives NULL if
+// either side is NULL.
 //
 // # Built-in Functions
 //
 // String functions:
 //   - UPPER(str), LOWER(str), TRIM(str)
-//   - CONCAT(str1, str2, ...), LENGTH(str)
+//   - CONCAT(str1, str2, ...), CONCAT_WS(sep, str1, ...), LENGTH(str)
+//   - FORMAT(fmt, args...) (alias PRINTF), with a subset of Go's fmt verbs
+//   - LEFT(str, n), RIGHT(str, n), LPAD(str, len, pad), RPAD(str, len, pad), REVERSE(str)
+//   - REGEXP_MATCH(str, pattern), REGEXP_REPLACE(str, pattern, replacement)
 //
 // Math functions:
 //   - ABS(num), ROUND(num, decimals), FLOOR(num), CEIL(num)
 //   - MOD(dividend, divisor)
+//   - SQRT(num), POW(base, exponent), EXP(num), LOG(num), LOG10(num)
+//
+// Type conversion:
+//   - CAST(expr AS type) to INT, FLOAT, STRING, BOOL or DATE
+//   - TRY_CAST(expr AS type), which gives NULL instead of an error
+//
+// Date/time functions:
+//   - NOW(), returning a time.Time
+//   - DATE_TRUNC(unit, ts) to year, quarter, month, week, day, hour, minute or second
+//   - EXTRACT(field FROM ts) or EXTRACT('field', ts), returning an int64
+//
+// # Custom Functions
+//
+// Applications can add scalar functions with RegisterFunction. Register them
+// before executing queries:
+//
+//	err := query.RegisterFunction("GEOHASH", 2, func(args []interface{}) (interface{}, error) {
+//	    return geohash(args[0], args[1])
+//	})
 //
 // # Type System
 //
 // The query engine automatically handles type coercion for comparisons:
-//   - String comparisons are case-sensitive
-//   - Numeric values are converted to float64 for comparison
+//   - String comparisons are case-sensitive; ILIKE matches a pattern ignoring case
+//   - Integers compare exactly, with each other and with floats, so
+//     age = 25, age = 25.0 and age IN (25, 26) all match an int64 25 and
+//     large int64 IDs never round to equal; two floats compare as float64
+//     with a small tolerance
 //   - Boolean values use direct equality
+//   - Timestamps (time.Time) compare in time order, with each other and with date strings
 //   - Type mismatches return false
 //
 // # Performance Considerations
@@ -143,8 +283,16 @@
 //   - Filters are applied during row reading when possible
 //   - Aggregations load all data into memory
 //   - Window functions require sorting and partitioning
-//   - JOINs may require loading multiple files
-//   - Use LIMIT to restrict result set size
+//   - JOINs may require loading multiple files; an INNER JOIN on the
+//     equality of two columns is a hash join, other joins compare every
+//     pair of rows (see ExecutionContext.HashInnerJoin)
+//   - A table referenced several times in a query, such as by a self-join,
+//     is read once per ExecutionContext (see ExecutionContext.ReadTable)
+//   - A JOIN or aggregation producing more than MaxIntermediateRows rows
+//     (DefaultMaxIntermediateRows unless changed on the ExecutionContext)
+//     fails with ErrTooManyRows, so a careless CROSS JOIN can't exhaust memory
+//   - Use LIMIT to restrict result set size; without filtering, sorting,
+//     grouping or joins, reading stops once enough rows are read
 //
 // # Error Handling
 //
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vegasq/parcat/reader"
@@ -18,6 +19,60 @@ type ExecutionContext struct {
 	AllCTENames map[string]bool
 	// ScalarSubqueryCache caches results of non-correlated scalar subqueries to avoid re-execution
 	ScalarSubqueryCache map[*ScalarSubqueryExpr]interface{}
+	// quantifiedCache caches the values of ANY/ALL subqueries to avoid re-execution
+	quantifiedCache map[*QuantifiedSubqueryExpr][]interface{}
+	// ReadOptions are applied when reading parquet files (e.g. a seeded Rand for TABLESAMPLE)
+	ReadOptions reader.ReadOptions
+	// StrictJoins makes a column present on both sides of a JOIN an error
+	// instead of renaming it (see DisambiguateJoinColumns)
+	StrictJoins bool
+	// Context, if set, cancels execution once it is done (see Err). It is
+	// also passed to the reader, so reads stop too.
+	Context context.Context
+	// MaxIntermediateRows, if positive, fails a JOIN or aggregation
+	// producing more rows than this with ErrTooManyRows, so a runaway
+	// query stops before exhausting memory
+	MaxIntermediateRows int64
+	// WhereAliases lets WHERE refer to SELECT aliases, which strict SQL
+	// rejects (see WithSelectAliases)
+	WhereAliases bool
+	// tables caches the rows read by ReadTable. Child contexts share it, so
+	// a table referenced several times in a query is read once.
+	tables map[tableKey][]map[string]interface{}
+}
+
+// cancelCheckInterval is the number of rows processed between checks of
+// ExecutionContext.Context in loops doing little work per row
+const cancelCheckInterval = 1024
+
+// Err returns nil while execution may continue. Once Context is done it
+// returns the cause of the cancellation (see context.Cause), which is
+// Context.Err() unless a cause was given. It is safe to call on a nil
+// context.
+func (ctx *ExecutionContext) Err() error {
+	if ctx == nil || ctx.Context == nil || ctx.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(ctx.Context)
+}
+
+// checkRowLimit returns an error once n, the number of rows a stage such as
+// "JOIN" is producing, exceeds MaxIntermediateRows
+func (ctx *ExecutionContext) checkRowLimit(n int, stage string) error {
+	if ctx == nil || ctx.MaxIntermediateRows <= 0 || int64(n) <= ctx.MaxIntermediateRows {
+		return nil
+	}
+	return fmt.Errorf("%w: %s produced more than %d rows", ErrTooManyRows, stage, ctx.MaxIntermediateRows)
+}
+
+// readOptions returns ReadOptions with Context applied unless it already
+// sets one
+func (ctx *ExecutionContext) readOptions() reader.ReadOptions {
+	opts := ctx.ReadOptions
+	if opts.Context == nil {
+		opts.Context = ctx.Context
+	}
+	return opts
 }
 
 // NewExecutionContext creates a new execution context
@@ -28,6 +83,8 @@ func NewExecutionContext(r *reader.Reader) *ExecutionContext {
 		InProgress:          make(map[string]bool),
 		AllCTENames:         make(map[string]bool),
 		ScalarSubqueryCache: make(map[*ScalarSubqueryExpr]interface{}),
+		MaxIntermediateRows: DefaultMaxIntermediateRows,
+		tables:              make(map[tableKey][]map[string]interface{}),
 	}
 }
 
@@ -40,6 +97,12 @@ func (ctx *ExecutionContext) NewChildContext() *ExecutionContext {
 		InProgress:          make(map[string]bool),
 		AllCTENames:         make(map[string]bool),
 		ScalarSubqueryCache: make(map[*ScalarSubqueryExpr]interface{}),
+		ReadOptions:         ctx.ReadOptions,
+		StrictJoins:         ctx.StrictJoins,
+		Context:             ctx.Context,
+		MaxIntermediateRows: ctx.MaxIntermediateRows,
+		WhereAliases:        ctx.WhereAliases,
+		tables:              ctx.tables,
 	}
 	// Copy parent CTEs to make them accessible in child scope
 	for name, rows := range ctx.CTEs {
@@ -49,26 +112,63 @@ func (ctx *ExecutionContext) NewChildContext() *ExecutionContext {
 	for name := range ctx.AllCTENames {
 		child.AllCTENames[name] = true
 	}
-	// Note: We don't copy ScalarSubqueryCache to child - each subquery context
+	// Note: We don't copy the subquery caches to child - each subquery context
 	// should have its own cache since subquery results may differ in different contexts
 	return child
 }
 
 // ExecuteQuery executes a query with CTE support
 func ExecuteQuery(q *Query, r *reader.Reader) ([]map[string]interface{}, error) {
+	return ExecuteQueryContext(context.Background(), q, r)
+}
+
+// ExecuteQueryContext executes a query like ExecuteQuery, stopping once c is
+// done. Execution checks c while reading and filtering rows, for each row of
+// the outer side of a JOIN and between stages such as aggregation and
+// ordering, and then fails with c.Err(), or the cause given to c.
+//
+//	c, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	rows, err := query.ExecuteQueryContext(c, q, r)
+func ExecuteQueryContext(c context.Context, q *Query, r *reader.Reader) ([]map[string]interface{}, error) {
 	ctx := NewExecutionContext(r)
+	ctx.Context = c
+	return ctx.Execute(q)
+}
 
-	// Materialize CTEs first
+// Execute executes a query in ctx, materializing the CTEs of its WITH
+// clause first. The CTEs stay in ctx, so later queries executed in it can
+// refer to them.
+func (ctx *ExecutionContext) Execute(q *Query) ([]map[string]interface{}, error) {
 	if len(q.CTEs) > 0 {
 		if err := ctx.materializeCTEs(q.CTEs); err != nil {
 			return nil, fmt.Errorf("failed to materialize CTEs: %w", err)
 		}
 	}
-
-	// Execute the main query
 	return ctx.executeSelect(q)
 }
 
+// ExecuteOnRows executes a query against rows supplied by the caller instead
+// of reading its FROM table. The table name, any FROM subquery and
+// TABLESAMPLE are ignored; everything else (CTEs, JOINs, WHERE, windows,
+// GROUP BY, HAVING, ORDER BY and LIMIT) runs as in ExecuteQuery. JOINs still
+// read their own tables. Queries with UNION are not supported.
+func ExecuteOnRows(q *Query, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	if len(q.SetOperations) > 0 {
+		return nil, fmt.Errorf("%s queries read their own tables and cannot run on supplied rows", q.SetOperations[0].Operator)
+	}
+
+	ctx := NewExecutionContext(nil)
+
+	if len(q.CTEs) > 0 {
+		if err := ctx.materializeCTEs(q.CTEs); err != nil {
+			return nil, fmt.Errorf("failed to materialize CTEs: %w", err)
+		}
+	}
+
+	return ctx.executeOnRows(q, rows, nil)
+}
+
 // materializeCTEs evaluates and materializes all CTEs
 func (ctx *ExecutionContext) materializeCTEs(ctes []CTE) error {
 	return ctx.MaterializeCTEs(ctes, func(q *Query, c *ExecutionContext) ([]map[string]interface{}, error) {
@@ -114,7 +214,20 @@ func (ctx *ExecutionContext) MaterializeCTEs(ctes []CTE, executeFn func(*Query,
 	}
 
 	for _, cte := range ctes {
-		if err := materialize(cte.Name, cte.Query); err != nil {
+		var err error
+		_, shadowed := ctx.CTEs[cte.Name]
+		switch {
+		case !referencesTable(cte.Query, cte.Name):
+			err = materialize(cte.Name, cte.Query)
+		case cte.Recursive:
+			err = ctx.materializeRecursive(cte, executeFn)
+		case shadowed:
+			// Without RECURSIVE the name refers to the CTE of an outer query
+			err = materialize(cte.Name, cte.Query)
+		default:
+			err = fmt.Errorf("CTE %s refers to itself, which requires WITH RECURSIVE", cte.Name)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -124,7 +237,14 @@ func (ctx *ExecutionContext) MaterializeCTEs(ctes []CTE, executeFn func(*Query,
 
 // executeSelect executes a SELECT query
 func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{}, error) {
+	if len(q.SetOperations) > 0 {
+		return ctx.ExecuteSetOperations(q, func(arm *Query, c *ExecutionContext) ([]map[string]interface{}, error) {
+			return c.executeSelect(arm)
+		})
+	}
+
 	var rows []map[string]interface{}
+	var columns []string // columns of an empty source, for outer joins
 	var err error
 
 	// Read data from source (table, CTE, or subquery)
@@ -143,16 +263,29 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute FROM subquery: %w", err)
 		}
+		if len(q.Joins) > 0 && len(rows) == 0 {
+			columns = aliasColumns(subqueryCtx.resultColumns(q.Subquery), q.TableAlias)
+		}
 	} else if q.TableName != "" {
 		// Check if it's a CTE reference
 		if cteRows, exists := ctx.CTEs[q.TableName]; exists {
+			if q.Sample != nil {
+				return nil, fmt.Errorf("TABLESAMPLE is only supported on parquet files, not CTE %s", q.TableName)
+			}
 			rows = cteRows
 		} else if ctx.AllCTENames[q.TableName] {
 			// This is a forward CTE reference (CTE defined but not yet materialized)
 			return nil, fmt.Errorf("forward CTE reference: %s is defined but not yet materialized (CTEs must be referenced in order)", q.TableName)
 		} else {
-			// Read from parquet file
-			rows, err = reader.ReadMultipleFiles(q.TableName)
+			// Read from parquet file, skipping pages the WHERE clause rules out
+			opts := ctx.readOptions()
+			opts.Range = PushdownRange(q)
+
+			// Stop reading once there are enough rows for the LIMIT
+			if limit := PushdownLimit(q); limit > 0 && (opts.MaxRows <= 0 || limit < opts.MaxRows) {
+				opts.MaxRows = limit
+			}
+			rows, err = ctx.ReadTable(q.TableName, opts, q.Sample)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read table %s: %w", q.TableName, err)
 			}
@@ -161,6 +294,20 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 		return nil, fmt.Errorf("no data source specified (table, CTE, or subquery)")
 	}
 
+	// Outer joins need the columns of an empty table to fill with NULLs
+	if len(q.Joins) > 0 && q.Subquery == nil {
+		columns = ctx.emptyTableColumns(rows, q.TableName, q.TableAlias)
+	}
+	return ctx.executeOnRows(q, rows, columns)
+}
+
+// executeOnRows runs the rest of a SELECT query (alias, joins, filtering,
+// aggregation, projection, ordering and limits) on rows read from its source.
+// columns are the source's columns, with its alias applied, for when it has
+// no rows; they may be nil.
+func (ctx *ExecutionContext) executeOnRows(q *Query, rows []map[string]interface{}, columns []string) ([]map[string]interface{}, error) {
+	var err error
+
 	// Apply table alias to main table rows if specified
 	if q.TableAlias != "" {
 		rows = applyTableAlias(rows, q.TableAlias)
@@ -168,43 +315,72 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 
 	// Execute JOINs if present
 	if len(q.Joins) > 0 {
+		// Only the first join knows which table its left columns came from
+		var leftName string
+		if q.Subquery == nil {
+			leftName = JoinName(q.TableName, q.TableAlias)
+		}
 		for _, join := range q.Joins {
-			rows, err = ctx.executeJoin(rows, q.TableAlias, join)
+			rows, columns, err = ctx.executeJoin(rows, columns, leftName, join)
 			if err != nil {
 				return nil, fmt.Errorf("failed to execute JOIN: %w", err)
 			}
+			leftName = ""
 		}
 	}
 
 	// Apply WHERE filter
 	if q.Filter != nil {
 		// Check if filter contains subqueries and evaluate them
-		rows, err = ctx.applyFilterWithSubqueries(rows, q.Filter)
+		rows, err = ctx.applyFilterWithSubqueries(rows, ctx.WhereFilter(q))
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply filter: %w", err)
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Apply window functions if present (before aggregation and projection)
-	hasWindowFunc := HasWindowFunction(q.SelectList)
+	selectList := q.OutputSelectList()
+	hasWindowFunc := HasWindowFunction(selectList)
 	if hasWindowFunc {
-		rows, err = ApplyWindowFunctions(rows, q.SelectList)
+		rows, err = ApplyWindowFunctions(rows, selectList)
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply window functions: %w", err)
 		}
+		// Apply QUALIFY filter while the window results and all input columns are available
+		if q.Qualify != nil {
+			rows, err = ctx.applyFilterWithSubqueries(rows, q.Qualify)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply QUALIFY clause: %w", err)
+			}
+		}
 		// After window functions, we need final projection but must not re-evaluate window exprs
 		// ApplyWindowFunctions already added window results as columns
 		// Now project to final SELECT list, treating window exprs as column references
-		rows, err = ApplySelectListAfterWindows(rows, q.SelectList)
+		rows, err = ApplySelectListAfterWindows(rows, selectList)
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply select list after windows: %w", err)
 		}
-	} else if len(q.GroupBy) > 0 || HasAggregateFunction(q.SelectList) {
+	} else if len(q.GroupBy) > 0 || HasAggregateFunction(selectList) {
 		// Apply GROUP BY and aggregation if present (BEFORE projection)
-		rows, err = ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
+		rows, err = ApplyGroupKeys(rows, q.GroupKeys)
+		if err != nil {
+			return nil, err
+		}
+		if q.GroupByRollup {
+			rows, err = ApplyGroupByRollup(rows, q.GroupBy, q.AggregateSelectList())
+		} else {
+			rows, err = ApplyGroupByAndAggregate(rows, q.GroupBy, q.AggregateSelectList())
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply aggregation: %w", err)
 		}
+		if err := ctx.checkRowLimit(len(rows), "aggregation"); err != nil {
+			return nil, err
+		}
 
 		// Apply HAVING filter if present
 		if q.Having != nil {
@@ -212,17 +388,22 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 			if err != nil {
 				return nil, fmt.Errorf("failed to apply HAVING clause: %w", err)
 			}
+			rows = DropHavingAggregates(rows, q.HavingAggregates)
 		}
 	} else {
 		// Apply SELECT list projection (only if no aggregation or windows) with context for scalar subquery support
-		if len(q.SelectList) > 0 {
-			rows, err = ApplySelectListWithContext(rows, q.SelectList, ctx)
+		if len(selectList) > 0 {
+			rows, err = ApplySelectListWithContext(rows, selectList, ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed to apply select list: %w", err)
 			}
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Apply DISTINCT if present
 	if q.Distinct {
 		rows, err = ApplyDistinct(rows)
@@ -238,6 +419,7 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 			return nil, fmt.Errorf("failed to apply ORDER BY: %w", err)
 		}
 	}
+	rows = DropOrderKeys(rows, q.OrderKeys)
 
 	// Apply LIMIT/OFFSET if present
 	if q.Limit != nil || q.Offset != nil {
@@ -254,7 +436,12 @@ func (ctx *ExecutionContext) executeSelect(q *Query) ([]map[string]interface{},
 func (ctx *ExecutionContext) applyFilterWithSubqueries(rows []map[string]interface{}, filter Expression) ([]map[string]interface{}, error) {
 	var result []map[string]interface{}
 
-	for _, row := range rows {
+	for i, row := range rows {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		match, err := ctx.EvaluateExpression(row, filter)
 		if err != nil {
 			return nil, err
@@ -274,6 +461,14 @@ func (ctx *ExecutionContext) EvaluateExpression(row map[string]interface{}, expr
 		return ctx.evaluateExists(row, e)
 	case *InSubqueryExpr:
 		return ctx.evaluateInSubquery(row, e)
+	case *QuantifiedSubqueryExpr:
+		return ctx.evaluateQuantifiedSubquery(row, e)
+	case *aliasFilter:
+		extended, err := e.withAliases(row, ctx.EvaluateSelectExpression)
+		if err != nil {
+			return false, err
+		}
+		return ctx.EvaluateExpression(extended, e.filter)
 	case *BinaryExpr:
 		// Recursively evaluate both sides with context to support nested subqueries
 		left, err := ctx.EvaluateExpression(row, e.Left)
@@ -292,6 +487,33 @@ func (ctx *ExecutionContext) EvaluateExpression(row map[string]interface{}, expr
 		default:
 			return false, fmt.Errorf("unsupported binary operator: %v", e.Operator)
 		}
+	case *DistinctExpr:
+		// Either side may contain a scalar subquery
+		left, err := ctx.EvaluateSelectExpression(row, e.Left)
+		if err != nil {
+			return false, err
+		}
+		right, err := ctx.EvaluateSelectExpression(row, e.Right)
+		if err != nil {
+			return false, err
+		}
+		return e.compare(left, right)
+	case *NotExpr:
+		truth, err := evaluateTruth(row, e, func(expr Expression, row map[string]interface{}) (bool, error) {
+			return ctx.EvaluateExpression(row, expr)
+		})
+		return truth == truthTrue, err
+	case *ExpressionComparisonExpr:
+		// Either side may contain a scalar subquery
+		left, err := ctx.EvaluateSelectExpression(row, e.Left)
+		if err != nil {
+			return false, err
+		}
+		right, err := ctx.EvaluateSelectExpression(row, e.Right)
+		if err != nil {
+			return false, err
+		}
+		return compare(left, e.Operator, right)
 	default:
 		// Use the standard Evaluate method for non-subquery expressions
 		return expr.Evaluate(row)
@@ -381,7 +603,7 @@ func (ctx *ExecutionContext) evaluateInSubquery(row map[string]interface{}, expr
 		// Compare values
 		match, err := compare(value, TokenEqual, subValue)
 		if err != nil {
-			return false, err
+			return false, withColumn(err, expr.Column)
 		}
 		if match {
 			found = true
@@ -396,6 +618,80 @@ func (ctx *ExecutionContext) evaluateInSubquery(row map[string]interface{}, expr
 	return found, nil
 }
 
+// evaluateQuantifiedSubquery evaluates column op ANY/ALL (subquery). The
+// subquery is not correlated, so it runs once and its values are reused for
+// every row. A comparison involving NULL is unknown: it never satisfies ANY
+// and always fails ALL, while an empty subquery makes ALL true and ANY false.
+func (ctx *ExecutionContext) evaluateQuantifiedSubquery(row map[string]interface{}, expr *QuantifiedSubqueryExpr) (bool, error) {
+	values, err := ctx.quantifiedSubqueryValues(expr)
+	if err != nil {
+		return false, err
+	}
+
+	value := row[expr.Column]
+	for _, subValue := range values {
+		if value == nil || subValue == nil {
+			if expr.All {
+				return false, nil
+			}
+			continue
+		}
+
+		match, err := compare(value, expr.Operator, subValue)
+		if err != nil {
+			return false, withColumn(err, expr.Column)
+		}
+		// ANY is decided by the first match, ALL by the first mismatch
+		if match != expr.All {
+			return match, nil
+		}
+	}
+	return expr.All, nil
+}
+
+// quantifiedSubqueryValues returns the values of an ANY/ALL subquery, running
+// it the first time
+func (ctx *ExecutionContext) quantifiedSubqueryValues(expr *QuantifiedSubqueryExpr) ([]interface{}, error) {
+	if values, exists := ctx.quantifiedCache[expr]; exists {
+		return values, nil
+	}
+
+	quantifier := "ANY"
+	if expr.All {
+		quantifier = "ALL"
+	}
+
+	// Materialize subquery-local CTEs first if present
+	subqueryCtx := ctx
+	if len(expr.Subquery.CTEs) > 0 {
+		subqueryCtx = ctx.NewChildContext()
+		if err := subqueryCtx.materializeCTEs(expr.Subquery.CTEs); err != nil {
+			return nil, fmt.Errorf("%s subquery CTE materialization failed: %w", quantifier, err)
+		}
+	}
+
+	rows, err := subqueryCtx.executeSelect(expr.Subquery)
+	if err != nil {
+		return nil, fmt.Errorf("%s subquery failed: %w", quantifier, err)
+	}
+
+	values := make([]interface{}, 0, len(rows))
+	for _, subRow := range rows {
+		if len(subRow) != 1 {
+			return nil, fmt.Errorf("%s subquery must return exactly one column, got %d", quantifier, len(subRow))
+		}
+		for _, v := range subRow {
+			values = append(values, v)
+		}
+	}
+
+	if ctx.quantifiedCache == nil {
+		ctx.quantifiedCache = make(map[*QuantifiedSubqueryExpr][]interface{})
+	}
+	ctx.quantifiedCache[expr] = values
+	return values, nil
+}
+
 // EvaluateSelectExpression evaluates any SelectExpression with context support for nested subqueries
 func (ctx *ExecutionContext) EvaluateSelectExpression(row map[string]interface{}, expr SelectExpression) (interface{}, error) {
 	switch e := expr.(type) {
@@ -433,6 +729,42 @@ func (ctx *ExecutionContext) EvaluateSelectExpression(row map[string]interface{}
 
 		// Call the function
 		return fn.Evaluate(args)
+	case *IndexExpr:
+		array, err := ctx.EvaluateSelectExpression(row, e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		index, err := ctx.EvaluateSelectExpression(row, e.Index)
+		if err != nil {
+			return nil, err
+		}
+		return indexArray(array, index)
+	case *ExtractExpr:
+		value, err := ctx.EvaluateSelectExpression(row, e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return extractValue(e.Field, value)
+	case *ArithmeticExpr:
+		left, err := ctx.EvaluateSelectExpression(row, e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ctx.EvaluateSelectExpression(row, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(left, e.Operator, right)
+	case *ConcatExpr:
+		left, err := ctx.EvaluateSelectExpression(row, e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ctx.EvaluateSelectExpression(row, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return concat(left, right)
 	case *CaseExpr:
 		// Evaluate WHEN clauses
 		for _, whenClause := range e.WhenClauses {
@@ -520,331 +852,3 @@ func (ctx *ExecutionContext) EvaluateScalarSubquery(row map[string]interface{},
 	ctx.ScalarSubqueryCache[expr] = result
 	return result, nil
 }
-
-// executeJoin executes a JOIN operation
-func (ctx *ExecutionContext) executeJoin(leftRows []map[string]interface{}, leftAlias string, join Join) ([]map[string]interface{}, error) {
-	// Get right-side data
-	var rightRows []map[string]interface{}
-	var err error
-
-	if join.Subquery != nil {
-		// JOIN with subquery - use child context if subquery has CTEs to prevent scope leaking
-		var subqueryCtx *ExecutionContext
-		if len(join.Subquery.CTEs) > 0 {
-			subqueryCtx = ctx.NewChildContext()
-			if err := subqueryCtx.materializeCTEs(join.Subquery.CTEs); err != nil {
-				return nil, fmt.Errorf("failed to materialize CTEs in JOIN subquery: %w", err)
-			}
-		} else {
-			subqueryCtx = ctx
-		}
-		rightRows, err = subqueryCtx.executeSelect(join.Subquery)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute JOIN subquery: %w", err)
-		}
-	} else if join.TableName != "" {
-		// Check if it's a CTE reference
-		if cteRows, exists := ctx.CTEs[join.TableName]; exists {
-			rightRows = cteRows
-		} else if ctx.AllCTENames[join.TableName] {
-			// This is a forward CTE reference (CTE defined but not yet materialized)
-			return nil, fmt.Errorf("forward CTE reference in JOIN: %s is defined but not yet materialized (CTEs must be referenced in order)", join.TableName)
-		} else {
-			// Read from parquet file
-			rightRows, err = reader.ReadMultipleFiles(join.TableName)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read JOIN table %s: %w", join.TableName, err)
-			}
-		}
-	} else {
-		return nil, fmt.Errorf("JOIN requires table name or subquery")
-	}
-
-	// Apply alias to right table rows if specified
-	if join.Alias != "" {
-		rightRows = applyTableAlias(rightRows, join.Alias)
-	}
-
-	// Execute the appropriate join algorithm
-	switch join.Type {
-	case JoinInner:
-		return executeInnerJoin(leftRows, rightRows, join.Condition)
-	case JoinLeft:
-		return executeLeftJoin(leftRows, rightRows, join.Condition)
-	case JoinRight:
-		return executeRightJoin(leftRows, rightRows, join.Condition)
-	case JoinFull:
-		return executeFullJoin(leftRows, rightRows, join.Condition)
-	case JoinCross:
-		return executeCrossJoin(leftRows, rightRows)
-	default:
-		return nil, fmt.Errorf("unsupported join type: %v", join.Type)
-	}
-}
-
-// applyTableAlias prefixes all column names with table alias
-func applyTableAlias(rows []map[string]interface{}, alias string) []map[string]interface{} {
-	if alias == "" {
-		return rows
-	}
-
-	aliasedRows := make([]map[string]interface{}, len(rows))
-	for i, row := range rows {
-		aliasedRow := make(map[string]interface{})
-		for col, val := range row {
-			// Don't alias the special _file column
-			if col == "_file" {
-				aliasedRow[col] = val
-			} else {
-				aliasedRow[alias+"."+col] = val
-			}
-		}
-		aliasedRows[i] = aliasedRow
-	}
-	return aliasedRows
-}
-
-// executeInnerJoin performs an INNER JOIN using hash join algorithm
-func executeInnerJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Use nested loop join for simplicity (can be optimized to hash join for equi-joins)
-	for _, leftRow := range leftRows {
-		for _, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRows(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-			}
-		}
-	}
-
-	return result, nil
-}
-
-// executeLeftJoin performs a LEFT OUTER JOIN
-func executeLeftJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Special case: if right side is empty, return all left rows unchanged
-	// (we can't add NULL columns without knowing the right side schema)
-	if len(rightRows) == 0 {
-		return append([]map[string]interface{}{}, leftRows...), nil
-	}
-
-	for _, leftRow := range leftRows {
-		matched := false
-
-		for _, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRows(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-			}
-		}
-
-		// If no match, include left row with NULL values for right columns
-		if !matched {
-			merged, err := mergeRows(leftRow, createNullRow(rightRows))
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
-	}
-
-	return result, nil
-}
-
-// executeRightJoin performs a RIGHT OUTER JOIN
-func executeRightJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Special case: if left side is empty, return all right rows unchanged
-	// (we can't add NULL columns without knowing the left side schema)
-	if len(leftRows) == 0 {
-		return append([]map[string]interface{}{}, rightRows...), nil
-	}
-
-	for _, rightRow := range rightRows {
-		matched := false
-
-		for _, leftRow := range leftRows {
-			// Merge rows
-			merged, err := mergeRows(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-			}
-		}
-
-		// If no match, include right row with NULL values for left columns
-		if !matched {
-			merged, err := mergeRows(createNullRow(leftRows), rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
-	}
-
-	return result, nil
-}
-
-// executeFullJoin performs a FULL OUTER JOIN
-func executeFullJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	// Special cases: if one side is empty, return the other side unchanged
-	if len(leftRows) == 0 {
-		return append([]map[string]interface{}{}, rightRows...), nil
-	}
-	if len(rightRows) == 0 {
-		return append([]map[string]interface{}{}, leftRows...), nil
-	}
-
-	// Track which right rows have been matched
-	rightMatched := make([]bool, len(rightRows))
-
-	// Process left rows
-	for _, leftRow := range leftRows {
-		matched := false
-
-		for i, rightRow := range rightRows {
-			// Merge rows
-			merged, err := mergeRows(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-
-			// Evaluate join condition
-			match, err := condition.Evaluate(merged)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
-			}
-
-			if match {
-				result = append(result, merged)
-				matched = true
-				rightMatched[i] = true
-			}
-		}
-
-		// If no match, include left row with NULL values for right columns
-		if !matched {
-			merged, err := mergeRows(leftRow, createNullRow(rightRows))
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
-	}
-
-	// Add unmatched right rows with NULL values for left columns
-	for i, rightRow := range rightRows {
-		if !rightMatched[i] {
-			merged, err := mergeRows(createNullRow(leftRows), rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
-	}
-
-	return result, nil
-}
-
-// executeCrossJoin performs a CROSS JOIN (Cartesian product)
-func executeCrossJoin(leftRows, rightRows []map[string]interface{}) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-
-	for _, leftRow := range leftRows {
-		for _, rightRow := range rightRows {
-			merged, err := mergeRows(leftRow, rightRow)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, merged)
-		}
-	}
-
-	return result, nil
-}
-
-// mergeRows combines two rows into one
-// If both left and right have the same column name, returns an error
-func mergeRows(left, right map[string]interface{}) (map[string]interface{}, error) {
-	merged := make(map[string]interface{})
-
-	// Copy left row
-	for k, v := range left {
-		merged[k] = v
-	}
-
-	// Copy right row - check for collisions (except _file which is allowed to be duplicated)
-	for k, v := range right {
-		if _, exists := merged[k]; exists {
-			// Allow _file column to be duplicated - it's added by glob reads
-			// When both sides have _file, we keep both but suffix them with the table position
-			if k == "_file" {
-				// Keep left as _file_left and right as _file_right
-				if leftFile, ok := merged["_file"]; ok {
-					delete(merged, "_file")
-					merged["_file_left"] = leftFile
-					merged["_file_right"] = v
-				}
-				continue
-			}
-			return nil, fmt.Errorf("column name collision in JOIN: %q exists in both tables. Use table aliases to disambiguate (e.g., SELECT t1.%s, t2.%s FROM ...)", k, k, k)
-		}
-		merged[k] = v
-	}
-
-	return merged, nil
-}
-
-// createNullRow creates a row with NULL values for all columns from a sample row set
-func createNullRow(rows []map[string]interface{}) map[string]interface{} {
-	if len(rows) == 0 {
-		return make(map[string]interface{})
-	}
-
-	nullRow := make(map[string]interface{})
-	// Use first row as template to get column names
-	for col := range rows[0] {
-		nullRow[col] = nil
-	}
-
-	return nullRow
-}
@@ -1,7 +1,9 @@
 package query
 
 import (
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -822,3 +824,97 @@ func TestEvaluateExpression_InSubquery(t *testing.T) {
 		t.Errorf("Expected 'exactly one column' error, got: %v", err)
 	}
 }
+
+func TestRecursiveCTE(t *testing.T) {
+	tmpDir := t.TempDir()
+	// Employees and their managers: 1 manages 2 and 3, 2 manages 4, 4 manages 5
+	orgFile := createNamedWidthParquetFile(t, tmpDir, "org.parquet", []WidthDataRow{
+		{ID: 1}, {ID: 2, UserID: 1}, {ID: 3, UserID: 1}, {ID: 4, UserID: 2}, {ID: 5, UserID: 4},
+	})
+	// Edges of a cycle: 1 -> 2 -> 3 -> 1
+	cycleFile := createNamedWidthParquetFile(t, tmpDir, "cycle.parquet", []WidthDataRow{
+		{ID: 1, UserID: 2}, {ID: 2, UserID: 3}, {ID: 3, UserID: 1},
+	})
+
+	tests := []struct {
+		name    string
+		query   string
+		column  string
+		want    []float64
+		wantErr string
+	}{
+		{
+			name:   "numeric sequence",
+			query:  fmt.Sprintf("WITH RECURSIVE seq AS (SELECT 1 AS n FROM '%s' WHERE id = 1 UNION ALL SELECT n + 1 AS n FROM seq WHERE n < 10) SELECT n FROM seq", orgFile),
+			column: "n",
+			want:   []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name: "org chart",
+			query: fmt.Sprintf(`WITH RECURSIVE reports AS (
+				SELECT id FROM '%[1]s' WHERE user_id = 1
+				UNION ALL
+				SELECT e.id AS id FROM '%[1]s' e JOIN reports r ON e.user_id = r.id
+			) SELECT id FROM reports ORDER BY id`, orgFile),
+			column: "id",
+			want:   []float64{2, 3, 4, 5},
+		},
+		{
+			name: "UNION stops at a cycle",
+			query: fmt.Sprintf(`WITH RECURSIVE reach AS (
+				SELECT user_id AS node FROM '%[1]s' WHERE id = 1
+				UNION
+				SELECT e.user_id AS node FROM '%[1]s' e JOIN reach r ON e.id = r.node
+			) SELECT node FROM reach ORDER BY node`, cycleFile),
+			column: "node",
+			want:   []float64{1, 2, 3},
+		},
+		{
+			name:    "recursion without an end",
+			query:   fmt.Sprintf("WITH RECURSIVE seq AS (SELECT 1 AS n FROM '%s' WHERE id = 1 UNION ALL SELECT n + 1 AS n FROM seq) SELECT n FROM seq", orgFile),
+			wantErr: "did not finish after 1000 iterations",
+		},
+		{
+			name:    "anchor refers to the CTE",
+			query:   fmt.Sprintf("WITH RECURSIVE seq AS (SELECT n FROM seq UNION ALL SELECT id AS n FROM '%s') SELECT n FROM seq", orgFile),
+			wantErr: "must start with a query that does not refer to seq",
+		},
+		{
+			name:    "self reference without RECURSIVE",
+			query:   fmt.Sprintf("WITH seq AS (SELECT id AS n FROM '%s' UNION ALL SELECT n FROM seq) SELECT n FROM seq", orgFile),
+			wantErr: "CTE seq refers to itself, which requires WITH RECURSIVE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			results, err := ExecuteQuery(q, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExecuteQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var got []float64
+			for _, row := range results {
+				value, ok := toFloat64(row[tt.column])
+				if !ok {
+					t.Fatalf("%s = %#v, want a number", tt.column, row[tt.column])
+				}
+				got = append(got, value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.column, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package query
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/parquet-go/parquet-go"
 	"github.com/vegasq/parcat/reader"
@@ -239,6 +243,139 @@ func TestExecuteQuery_WithCrossJoin(t *testing.T) {
 	}
 }
 
+func TestExecuteQueryContext_CancelsCrossJoin(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Neither side is a multiple of the reader's cancellation check
+	// interval, so the reads finish and the join sees the cancellation
+	const n = 300
+	data := make([]BasicDataRow, n)
+	for i := range data {
+		data[i] = BasicDataRow{ID: int64(i), Name: "row"}
+	}
+	leftFile := createNamedBasicParquetFile(t, tmpDir, "left.parquet", data)
+	rightFile := createNamedBasicParquetFile(t, tmpDir, "right.parquet", data)
+
+	parse := func(t *testing.T) *Query {
+		q, err := Parse("SELECT l.id, r.id FROM left.parquet l CROSS JOIN right.parquet r")
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		q.TableName = leftFile
+		q.Joins[0].TableName = rightFile
+		return q
+	}
+
+	t.Run("canceled once both tables are read", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ctx := NewExecutionContext(nil)
+		ctx.Context = c
+		read := 0
+		ctx.ReadOptions.Transform = func(row map[string]interface{}) (map[string]interface{}, error) {
+			if read++; read == 2*n {
+				cancel()
+			}
+			return row, nil
+		}
+
+		rows, err := ctx.executeSelect(parse(t))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("executeSelect() error = %v, want %v", err, context.Canceled)
+		}
+		if rows != nil {
+			t.Errorf("executeSelect() returned %d rows, want none", len(rows))
+		}
+	})
+
+	t.Run("deadline already passed", func(t *testing.T) {
+		c, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		_, err := ExecuteQueryContext(c, parse(t), nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("ExecuteQueryContext() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("not canceled", func(t *testing.T) {
+		rows, err := ExecuteQueryContext(context.Background(), parse(t), nil)
+		if err != nil {
+			t.Fatalf("ExecuteQueryContext() error = %v", err)
+		}
+		if len(rows) != n*n {
+			t.Errorf("ExecuteQueryContext() returned %d rows, want %d", len(rows), n*n)
+		}
+	})
+}
+
+func TestExecuteQuery_MaxIntermediateRows(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	const n = 100
+	data := make([]BasicDataRow, n)
+	for i := range data {
+		data[i] = BasicDataRow{ID: int64(i), Name: "row"}
+	}
+	files := make([]string, 3)
+	for i, name := range []string{"a.parquet", "b.parquet", "c.parquet"} {
+		files[i] = createNamedBasicParquetFile(t, tmpDir, name, data)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		max     int64
+		wantErr string
+	}{
+		{
+			// The first join's 10,000 rows fit, the second join's 1,000,000 don't
+			name:    "three-way cross join",
+			query:   "SELECT a.id FROM a.parquet a CROSS JOIN b.parquet b CROSS JOIN c.parquet c",
+			max:     50000,
+			wantErr: "JOIN produced more than 50000 rows",
+		},
+		{
+			name:    "rollup",
+			query:   "SELECT id, COUNT(*) AS n FROM a.parquet GROUP BY ROLLUP(id)",
+			max:     n,
+			wantErr: "aggregation produced more than 100 rows",
+		},
+		{
+			name:  "within the limit",
+			query: "SELECT a.id FROM a.parquet a CROSS JOIN b.parquet b",
+			max:   n * n,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			q.TableName = files[0]
+			for i := range q.Joins {
+				q.Joins[i].TableName = files[i+1]
+			}
+
+			ctx := NewExecutionContext(nil)
+			ctx.MaxIntermediateRows = tt.max
+			_, err = ctx.executeSelect(q)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeSelect() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTooManyRows) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("executeSelect() error = %v, want %v containing %q", err, ErrTooManyRows, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestExecuteQuery_WithRightJoin(t *testing.T) {
 	tmpDir := t.TempDir()
 	usersFile := filepath.Join(tmpDir, "users.parquet")
@@ -433,7 +570,7 @@ func TestExecuteJoin_WithSubquery(t *testing.T) {
 		},
 	}
 
-	results, err := ctx.executeJoin(leftData, "", join)
+	results, _, err := ctx.executeJoin(leftData, nil, "", join)
 	if err != nil {
 		t.Errorf("executeJoin(with subquery) error = %v", err)
 	}
@@ -488,6 +625,9 @@ func TestMergeRows_FileColumn(t *testing.T) {
 	if err != nil {
 		t.Errorf("mergeRows() error = %v", err)
 	}
+	if merged["id"] != int64(1) || merged["val"] != int64(100) {
+		t.Errorf("merged row = %v, want the columns of both sides", merged)
+	}
 
 	// _file columns should be renamed to _file_left and _file_right
 	if merged["_file_left"] != "left.parquet" {
@@ -502,13 +642,139 @@ func TestMergeRows_FileColumn(t *testing.T) {
 }
 
 // TestCreateNullRow tests createNullRow helper
+func TestJoinName(t *testing.T) {
+	tests := []struct {
+		tableName string
+		alias     string
+		want      string
+	}{
+		{"users.parquet", "", "users"},
+		{"data/2024/orders.parquet", "", "orders"},
+		{"recent", "", "recent"},
+		{"users.parquet", "u", ""},
+		{"data/*.parquet", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := JoinName(tt.tableName, tt.alias); got != tt.want {
+			t.Errorf("JoinName(%q, %q) = %q, want %q", tt.tableName, tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestDisambiguateJoinColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		left      JoinInput
+		right     JoinInput
+		wantLeft  map[string]interface{}
+		wantRight map[string]interface{}
+	}{
+		{
+			name:      "named sides are qualified",
+			left:      JoinInput{Rows: []map[string]interface{}{{"id": 1, "name": "Alice"}}, Name: "users"},
+			right:     JoinInput{Rows: []map[string]interface{}{{"id": 1, "total": 10}}, Name: "orders"},
+			wantLeft:  map[string]interface{}{"users.id": 1, "name": "Alice"},
+			wantRight: map[string]interface{}{"orders.id": 1, "total": 10},
+		},
+		{
+			name:      "unnamed right side gets a suffix",
+			left:      JoinInput{Rows: []map[string]interface{}{{"id": 1, "id_1": 2}}},
+			right:     JoinInput{Rows: []map[string]interface{}{{"id": 3}}},
+			wantLeft:  map[string]interface{}{"id": 1, "id_1": 2},
+			wantRight: map[string]interface{}{"id_2": 3},
+		},
+		{
+			name:      "unnamed left side keeps its column",
+			left:      JoinInput{Rows: []map[string]interface{}{{"id": 1}}},
+			right:     JoinInput{Rows: []map[string]interface{}{{"id": 2}}, Name: "orders"},
+			wantLeft:  map[string]interface{}{"id": 1},
+			wantRight: map[string]interface{}{"orders.id": 2},
+		},
+		{
+			name:      "no shared columns and _file are unchanged",
+			left:      JoinInput{Rows: []map[string]interface{}{{"a": 1, "_file": "l"}}, Name: "l"},
+			right:     JoinInput{Rows: []map[string]interface{}{{"b": 2, "_file": "r"}}, Name: "r"},
+			wantLeft:  map[string]interface{}{"a": 1, "_file": "l"},
+			wantRight: map[string]interface{}{"b": 2, "_file": "r"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := DisambiguateJoinColumns(tt.left, tt.right)
+			if !reflect.DeepEqual(left.Rows[0], tt.wantLeft) {
+				t.Errorf("left row = %v, want %v", left.Rows[0], tt.wantLeft)
+			}
+			if !reflect.DeepEqual(right.Rows[0], tt.wantRight) {
+				t.Errorf("right row = %v, want %v", right.Rows[0], tt.wantRight)
+			}
+		})
+	}
+
+	// Columns of an empty side are renamed too, and the input rows are left alone
+	leftRows := []map[string]interface{}{{"id": 1}}
+	left, right := DisambiguateJoinColumns(
+		JoinInput{Rows: leftRows, Name: "users"},
+		JoinInput{Columns: []string{"id", "total"}, Name: "orders"},
+	)
+	if want := []string{"orders.id", "total"}; !reflect.DeepEqual(right.Columns, want) {
+		t.Errorf("right columns = %v, want %v", right.Columns, want)
+	}
+	if _, ok := left.Rows[0]["users.id"]; !ok {
+		t.Errorf("left row = %v, want users.id", left.Rows[0])
+	}
+	if _, ok := leftRows[0]["id"]; !ok {
+		t.Errorf("input row was modified: %v", leftRows[0])
+	}
+}
+
+func TestResolveUsing(t *testing.T) {
+	left := JoinInput{Rows: []map[string]interface{}{{"u.id": 1, "name": "Alice"}}}
+	right := JoinInput{Rows: []map[string]interface{}{{"id": 1, "total": 10}}}
+
+	u, err := ResolveUsing([]string{"id"}, left, right)
+	if err != nil {
+		t.Fatalf("ResolveUsing() error = %v", err)
+	}
+	want := &ColumnComparisonExpr{LeftColumn: "u.id", Operator: TokenEqual, RightColumn: "id"}
+	if !reflect.DeepEqual(u.Condition, want) {
+		t.Errorf("Condition = %#v, want %#v", u.Condition, want)
+	}
+
+	// A right key equal to the left one is renamed so the rows can be merged
+	u, err = ResolveUsing([]string{"id"}, JoinInput{Rows: right.Rows}, right)
+	if err != nil {
+		t.Fatalf("ResolveUsing() error = %v", err)
+	}
+	if _, ok := u.Right.Rows[0]["id_1"]; !ok {
+		t.Errorf("right row = %v, want id_1", u.Right.Rows[0])
+	}
+
+	// NULL left values, as in rows an outer join added, take the right value
+	rows := []map[string]interface{}{{"id": nil, "id_1": 3, "total": 10}}
+	columns := u.Coalesce(rows, []string{"id", "total", "id_1"})
+	if wantRow := map[string]interface{}{"id": 3, "total": 10}; !reflect.DeepEqual(rows[0], wantRow) {
+		t.Errorf("Coalesce() row = %v, want %v", rows[0], wantRow)
+	}
+	if wantCols := []string{"total", "id"}; !reflect.DeepEqual(columns, wantCols) {
+		t.Errorf("Coalesce() columns = %v, want %v", columns, wantCols)
+	}
+
+	ambiguous := JoinInput{Rows: []map[string]interface{}{{"a.id": 1, "b.id": 2}}}
+	if _, err := ResolveUsing([]string{"id"}, ambiguous, right); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ResolveUsing() error = %v, want ambiguous column", err)
+	}
+}
+
 func TestCreateNullRow(t *testing.T) {
 	// Test with non-empty rows
 	rows := []map[string]interface{}{
 		{"col1": "value1", "col2": int64(123)},
 	}
 
-	nullRow := createNullRow(rows)
+	nullRow := createNullRow(rows, nil)
 	if nullRow == nil {
 		t.Fatal("createNullRow returned nil")
 	}
@@ -522,13 +788,24 @@ func TestCreateNullRow(t *testing.T) {
 	}
 
 	// Test with empty rows
-	emptyNullRow := createNullRow([]map[string]interface{}{})
+	emptyNullRow := createNullRow([]map[string]interface{}{}, nil)
 	if emptyNullRow == nil {
 		t.Fatal("createNullRow with empty input returned nil")
 	}
 	if len(emptyNullRow) != 0 {
 		t.Errorf("createNullRow with empty input should return empty map, got %v", emptyNullRow)
 	}
+
+	// Test with empty rows and known columns
+	schemaNullRow := createNullRow([]map[string]interface{}{}, []string{"o.id", "o.amount"})
+	if len(schemaNullRow) != 2 {
+		t.Fatalf("createNullRow with columns should return 2 columns, got %v", schemaNullRow)
+	}
+	for _, col := range []string{"o.id", "o.amount"} {
+		if value, ok := schemaNullRow[col]; !ok || value != nil {
+			t.Errorf("%s should be present and nil, got %v (present %v)", col, value, ok)
+		}
+	}
 }
 
 // TestApplyTableAlias tests applyTableAlias helper
@@ -570,8 +847,232 @@ func TestApplyTableAlias(t *testing.T) {
 	}
 }
 
+func TestExecuteCrossJoin(t *testing.T) {
+	tests := []struct {
+		name      string
+		leftRows  []map[string]interface{}
+		rightRows []map[string]interface{}
+		maxRows   int64
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:      "2x2 cross join",
+			leftRows:  []map[string]interface{}{{"a": 1}, {"a": 2}},
+			rightRows: []map[string]interface{}{{"b": 3}, {"b": 4}},
+			wantCount: 4, // Cartesian product: 2 * 2
+		},
+		{
+			name:      "empty left",
+			leftRows:  []map[string]interface{}{},
+			rightRows: []map[string]interface{}{{"b": 1}},
+			wantCount: 0,
+		},
+		{
+			name:      "empty right",
+			leftRows:  []map[string]interface{}{{"a": 1}},
+			rightRows: []map[string]interface{}{},
+			wantCount: 0,
+		},
+		{
+			name:      "product at the row limit",
+			leftRows:  []map[string]interface{}{{"a": 1}, {"a": 2}},
+			rightRows: []map[string]interface{}{{"b": 3}, {"b": 4}},
+			maxRows:   4,
+			wantCount: 4,
+		},
+		{
+			name:      "product over the row limit",
+			leftRows:  []map[string]interface{}{{"a": 1}, {"a": 2}},
+			rightRows: []map[string]interface{}{{"b": 3}, {"b": 4}},
+			maxRows:   3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewExecutionContext(nil)
+			ctx.MaxIntermediateRows = tt.maxRows
+			got, err := ctx.executeCrossJoin(tt.leftRows, tt.rightRows)
+			if tt.wantErr {
+				if !errors.Is(err, ErrTooManyRows) {
+					t.Errorf("executeCrossJoin() error = %v, want %v", err, ErrTooManyRows)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeCrossJoin() error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("executeCrossJoin() got %d rows, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestExecuteOuterJoins(t *testing.T) {
+	// t1.id = t2.id
+	condition := &ColumnComparisonExpr{
+		LeftColumn:  "t1.id",
+		Operator:    TokenEqual,
+		RightColumn: "t2.id",
+	}
+	ctx := NewExecutionContext(nil)
+	left := func(l, r []map[string]interface{}) ([]map[string]interface{}, error) {
+		return ctx.executeLeftJoin(l, r, nil, condition)
+	}
+	right := func(l, r []map[string]interface{}) ([]map[string]interface{}, error) {
+		return ctx.executeRightJoin(l, r, nil, condition)
+	}
+	full := func(l, r []map[string]interface{}) ([]map[string]interface{}, error) {
+		return ctx.executeFullJoin(l, r, nil, nil, condition)
+	}
+
+	alice := map[string]interface{}{"t1.id": int64(1), "t1.name": "Alice"}
+	bob := map[string]interface{}{"t1.id": int64(2), "t1.name": "Bob"}
+	eng := map[string]interface{}{"t2.id": int64(1), "t2.dept": "Engineering"}
+	sales := map[string]interface{}{"t2.id": int64(2), "t2.dept": "Sales"}
+	other := map[string]interface{}{"t2.id": int64(99), "t2.dept": "Other"}
+	none := []map[string]interface{}{}
+	allKeys := []string{"t1.id", "t1.name", "t2.id", "t2.dept"}
+
+	tests := []struct {
+		name      string
+		join      func(l, r []map[string]interface{}) ([]map[string]interface{}, error)
+		leftRows  []map[string]interface{}
+		rightRows []map[string]interface{}
+		wantCount int
+		wantKeys  []string // Keys to verify in first result row
+	}{
+		{
+			name:      "left join with matches",
+			join:      left,
+			leftRows:  []map[string]interface{}{alice, bob},
+			rightRows: []map[string]interface{}{eng},
+			wantCount: 2, // Both left rows (one matched, one with nulls)
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "left join with no matches",
+			join:      left,
+			leftRows:  []map[string]interface{}{alice, bob},
+			rightRows: []map[string]interface{}{other},
+			wantCount: 2, // Both left rows with null right columns
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "left join with empty right side",
+			join:      left,
+			leftRows:  []map[string]interface{}{alice, bob},
+			rightRows: none,
+			wantCount: 2,
+			wantKeys:  []string{"t1.id", "t1.name"},
+		},
+		{
+			name:      "left join with empty left side",
+			join:      left,
+			leftRows:  none,
+			rightRows: []map[string]interface{}{eng},
+			wantCount: 0,
+		},
+		{
+			name:      "right join with matches",
+			join:      right,
+			leftRows:  []map[string]interface{}{alice},
+			rightRows: []map[string]interface{}{eng, sales},
+			wantCount: 2, // Both right rows (one matched, one with nulls)
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "right join with no matches",
+			join:      right,
+			leftRows:  []map[string]interface{}{alice},
+			rightRows: []map[string]interface{}{other},
+			wantCount: 1, // The right row with null left columns
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "right join with empty left side",
+			join:      right,
+			leftRows:  none,
+			rightRows: []map[string]interface{}{eng, sales},
+			wantCount: 2,
+			wantKeys:  []string{"t2.id", "t2.dept"},
+		},
+		{
+			name:      "right join with empty right side",
+			join:      right,
+			leftRows:  []map[string]interface{}{alice},
+			rightRows: none,
+			wantCount: 0,
+		},
+		{
+			name:      "full join with partial matches",
+			join:      full,
+			leftRows:  []map[string]interface{}{alice, bob},
+			rightRows: []map[string]interface{}{eng, other},
+			wantCount: 3, // 1 matched + 1 unmatched left + 1 unmatched right
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "full join with no matches",
+			join:      full,
+			leftRows:  []map[string]interface{}{alice},
+			rightRows: []map[string]interface{}{other},
+			wantCount: 2, // 1 left with nulls + 1 right with nulls
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "full join with all matches",
+			join:      full,
+			leftRows:  []map[string]interface{}{alice, bob},
+			rightRows: []map[string]interface{}{eng, sales},
+			wantCount: 2,
+			wantKeys:  allKeys,
+		},
+		{
+			name:      "full join with empty left side",
+			join:      full,
+			leftRows:  none,
+			rightRows: []map[string]interface{}{eng},
+			wantCount: 1,
+			wantKeys:  []string{"t2.id", "t2.dept"},
+		},
+		{
+			name:      "full join with empty right side",
+			join:      full,
+			leftRows:  []map[string]interface{}{alice},
+			rightRows: none,
+			wantCount: 1,
+			wantKeys:  []string{"t1.id", "t1.name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.join(tt.leftRows, tt.rightRows)
+			if err != nil {
+				t.Fatalf("join error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("join got %d rows, want %d", len(got), tt.wantCount)
+			}
+			if len(got) > 0 {
+				for _, key := range tt.wantKeys {
+					if _, exists := got[0][key]; !exists {
+						t.Errorf("first row %v missing key %q", got[0], key)
+					}
+				}
+			}
+		})
+	}
+}
+
 // TestExecuteJoin_EmptySides tests join operations with empty sides
 func TestExecuteJoin_EmptySides(t *testing.T) {
+	ctx := NewExecutionContext(nil)
+
 	// LEFT JOIN with empty right side
 	leftRows := []map[string]interface{}{
 		{"id": int64(1), "name": "Alice"},
@@ -584,7 +1085,7 @@ func TestExecuteJoin_EmptySides(t *testing.T) {
 		Value:    int64(1),
 	}
 
-	result, err := executeLeftJoin(leftRows, rightRowsEmpty, condition)
+	result, err := ctx.executeLeftJoin(leftRows, rightRowsEmpty, nil, condition)
 	if err != nil {
 		t.Errorf("executeLeftJoin with empty right error = %v", err)
 	}
@@ -594,7 +1095,7 @@ func TestExecuteJoin_EmptySides(t *testing.T) {
 	}
 
 	// RIGHT JOIN with empty left side
-	result, err = executeRightJoin([]map[string]interface{}{}, rightRowsEmpty, condition)
+	result, err = ctx.executeRightJoin([]map[string]interface{}{}, rightRowsEmpty, nil, condition)
 	if err != nil {
 		t.Errorf("executeRightJoin with empty left error = %v", err)
 	}
@@ -607,7 +1108,7 @@ func TestExecuteJoin_EmptySides(t *testing.T) {
 	rightRows := []map[string]interface{}{
 		{"id": int64(1), "val": int64(100)},
 	}
-	result, err = executeFullJoin([]map[string]interface{}{}, rightRows, condition)
+	result, err = ctx.executeFullJoin([]map[string]interface{}{}, rightRows, nil, nil, condition)
 	if err != nil {
 		t.Errorf("executeFullJoin with empty left error = %v", err)
 	}
@@ -617,7 +1118,7 @@ func TestExecuteJoin_EmptySides(t *testing.T) {
 	}
 
 	// FULL JOIN with empty right side
-	result, err = executeFullJoin(leftRows, []map[string]interface{}{}, condition)
+	result, err = ctx.executeFullJoin(leftRows, []map[string]interface{}{}, nil, nil, condition)
 	if err != nil {
 		t.Errorf("executeFullJoin with empty right error = %v", err)
 	}
@@ -625,6 +1126,29 @@ func TestExecuteJoin_EmptySides(t *testing.T) {
 	if len(result) != 1 {
 		t.Errorf("Expected 1 row, got %d", len(result))
 	}
+
+	// With the columns of the empty side known, they are added as NULLs
+	result, err = ctx.executeLeftJoin(leftRows, rightRowsEmpty, []string{"val"}, condition)
+	if err != nil {
+		t.Errorf("executeLeftJoin with right columns error = %v", err)
+	}
+	if len(result) != 1 || result[0]["name"] != "Alice" {
+		t.Fatalf("Expected the left row, got %v", result)
+	}
+	if value, ok := result[0]["val"]; !ok || value != nil {
+		t.Errorf("val should be present and nil, got %v (present %v)", value, ok)
+	}
+
+	result, err = ctx.executeFullJoin([]map[string]interface{}{}, rightRows, []string{"name"}, nil, condition)
+	if err != nil {
+		t.Errorf("executeFullJoin with left columns error = %v", err)
+	}
+	if len(result) != 1 || result[0]["val"] != int64(100) {
+		t.Fatalf("Expected the right row, got %v", result)
+	}
+	if value, ok := result[0]["name"]; !ok || value != nil {
+		t.Errorf("name should be present and nil, got %v (present %v)", value, ok)
+	}
 }
 
 // TestExecuteJoin_ErrorHandling tests join error conditions
@@ -659,7 +1183,7 @@ func TestExecuteJoin_ErrorHandling(t *testing.T) {
 		},
 	}
 
-	_, err = ctx.executeJoin(leftData, "", joinNoSource)
+	_, _, err = ctx.executeJoin(leftData, nil, "", joinNoSource)
 	if err == nil {
 		t.Error("Expected error for JOIN with no table name or subquery, got nil")
 	}
@@ -680,7 +1204,7 @@ func TestExecuteJoin_ErrorHandling(t *testing.T) {
 		},
 	}
 
-	_, err = ctx.executeJoin(leftData, "", joinForwardCTE)
+	_, _, err = ctx.executeJoin(leftData, nil, "", joinForwardCTE)
 	if err == nil {
 		t.Error("Expected error for forward CTE reference in JOIN, got nil")
 	}
@@ -699,7 +1223,7 @@ func TestExecuteJoin_ErrorHandling(t *testing.T) {
 		},
 	}
 
-	_, err = ctx.executeJoin(leftData, "", joinUnsupported)
+	_, _, err = ctx.executeJoin(leftData, nil, "", joinUnsupported)
 	if err == nil {
 		t.Error("Expected error for unsupported join type, got nil")
 	}
@@ -707,3 +1231,88 @@ func TestExecuteJoin_ErrorHandling(t *testing.T) {
 		t.Errorf("Expected 'unsupported join type' error, got: %v", err)
 	}
 }
+
+func TestExecutionContext_ReadTableOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := createNamedBasicParquetFile(t, tmpDir, "people.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+		{ID: 3, Name: "Carol", Age: 35},
+	})
+
+	tests := []struct {
+		name      string
+		query     string
+		wantRows  int
+		wantReads int
+	}{
+		{
+			name:      "self-join",
+			query:     "SELECT a.id, b.name FROM '" + file + "' a JOIN '" + file + "' b ON a.id = b.id",
+			wantRows:  3,
+			wantReads: 1,
+		},
+		{
+			name:      "CTEs reading the same table",
+			query:     "WITH o AS (SELECT id FROM '" + file + "' WHERE age > 26), y AS (SELECT id FROM '" + file + "' WHERE age > 26) SELECT * FROM o UNION ALL SELECT * FROM y",
+			wantRows:  4,
+			wantReads: 1,
+		},
+		{
+			name:      "subquery reading the outer table",
+			query:     "SELECT b.name FROM '" + file + "' a JOIN '" + file + "' b ON a.id = b.id WHERE a.age > (SELECT MIN(age) FROM '" + file + "')",
+			wantRows:  2,
+			wantReads: 1,
+		},
+		{
+			name:      "different page filters read again",
+			query:     "SELECT * FROM '" + file + "' WHERE id IN (SELECT id FROM '" + file + "' WHERE id >= 2) AND id <= 2",
+			wantRows:  1,
+			wantReads: 2,
+		},
+		{
+			name:      "samples are drawn on each read",
+			query:     "SELECT id FROM '" + file + "' TABLESAMPLE (2 ROWS) UNION ALL SELECT id FROM '" + file + "' TABLESAMPLE (2 ROWS)",
+			wantRows:  4,
+			wantReads: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ctx := NewExecutionContext(nil)
+			reads := 0
+			ctx.ReadOptions.OnFiles = func(pattern string, files []string) { reads++ }
+			if len(q.CTEs) > 0 {
+				if err := ctx.materializeCTEs(q.CTEs); err != nil {
+					t.Fatalf("materializeCTEs() error = %v", err)
+				}
+			}
+			rows, err := ctx.executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+			if len(rows) != tt.wantRows {
+				t.Errorf("executeSelect() returned %d rows, want %d", len(rows), tt.wantRows)
+			}
+			if reads != tt.wantReads {
+				t.Errorf("table read %d times, want %d", reads, tt.wantReads)
+			}
+
+			// A cleared cache reads the table again
+			ctx.ClearTableCache()
+			reads = 0
+			if _, err := ctx.ReadTable(file, ctx.ReadOptions, nil); err != nil {
+				t.Fatalf("ReadTable() error = %v", err)
+			}
+			if reads != 1 {
+				t.Errorf("table read %d times after ClearTableCache(), want 1", reads)
+			}
+		})
+	}
+}
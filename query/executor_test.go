@@ -3,6 +3,7 @@ package query
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -303,6 +304,35 @@ func TestExecuteQuery_WithFilter(t *testing.T) {
 	}
 }
 
+func TestExecuteOnRows_GroupBy(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"dept": "eng", "salary": int64(100)},
+		{"dept": "sales", "salary": int64(50)},
+		{"dept": "eng", "salary": int64(120)},
+		{"dept": "hr", "salary": int64(40)},
+		{"dept": "sales", "salary": int64(70)},
+	}
+
+	// The table name is never opened, so it need not exist
+	q, err := Parse("SELECT dept, COUNT(*) AS n, SUM(salary) AS total FROM missing.parquet WHERE salary > 40 GROUP BY dept HAVING n > 1 ORDER BY total DESC")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	results, err := ExecuteOnRows(q, rows)
+	if err != nil {
+		t.Fatalf("ExecuteOnRows() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"dept": "eng", "n": int64(2), "total": float64(220)},
+		{"dept": "sales", "n": int64(2), "total": float64(120)},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("ExecuteOnRows() = %#v, want %#v", results, want)
+	}
+}
+
 func TestNewExecutionContext(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "test.parquet")
@@ -1,10 +1,13 @@
 package query
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
+	"time"
 )
 
 // abs returns the absolute value of a float64
@@ -12,6 +15,48 @@ func abs(x float64) float64 {
 	return math.Abs(x)
 }
 
+// TypeError reports a value whose type doesn't fit how it is used, such as a
+// string compared with a number after reading files whose schemas differ
+type TypeError struct {
+	Column   string      // Column holding the value, empty if not known
+	Expected string      // Expected kind of value: "number", "string", "boolean", ...
+	Value    interface{} // The offending value
+}
+
+func (e *TypeError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("expected %s, got %T value %v", e.Expected, e.Value, e.Value)
+	}
+	return fmt.Sprintf("column %q: expected %s, got %T value %v", e.Column, e.Expected, e.Value, e.Value)
+}
+
+// withColumn fills in the column of a TypeError that doesn't name one yet,
+// returning err unchanged otherwise
+func withColumn(err error, column string) error {
+	var typeErr *TypeError
+	if errors.As(err, &typeErr) && typeErr.Column == "" {
+		typeErr.Column = column
+	}
+	return err
+}
+
+// kindOf describes the kind of value a comparison expects, for TypeError
+func kindOf(v interface{}) string {
+	if _, ok := toFloat64(v); ok {
+		return "number"
+	}
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case time.Time:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
 // compare compares two values using the given operator
 func compare(left interface{}, operator TokenType, right interface{}) (bool, error) {
 	// Handle nil values
@@ -25,7 +70,15 @@ func compare(left interface{}, operator TokenType, right interface{}) (bool, err
 		return false, nil
 	}
 
-	// Try numeric comparison
+	// Try timestamp comparison
+	if leftTime, rightTime, ok := toTimes(left, right); ok {
+		return compareTimes(leftTime, operator, rightTime), nil
+	}
+
+	// Try numeric comparison, exact when either side is an integer
+	if order, ok := compareIntegers(left, right); ok {
+		return orderMatches(order, operator), nil
+	}
 	leftNum, leftIsNum := toFloat64(left)
 	rightNum, rightIsNum := toFloat64(right)
 
@@ -49,8 +102,13 @@ func compare(left interface{}, operator TokenType, right interface{}) (bool, err
 		return compareBools(leftBool, operator, rightBool), nil
 	}
 
-	// Type mismatch
-	return false, fmt.Errorf("cannot compare %T with %T", left, right)
+	// A string compared with a timestamp must be a date
+	if err := timeStringError(left, right); err != nil {
+		return false, err
+	}
+
+	// Type mismatch: report the left value, which is the column side in filters
+	return false, &TypeError{Expected: kindOf(right), Value: left}
 }
 
 // toFloat64 converts a value to float64 if possible
@@ -85,6 +143,99 @@ func toFloat64(v interface{}) (float64, bool) {
 	}
 }
 
+// toInt64 converts a signed or unsigned integer to int64 if it fits
+func toInt64(v interface{}) (int64, bool) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), true
+	case int8:
+		return int64(val), true
+	case int16:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case uint:
+		return int64(val), uint64(val) <= math.MaxInt64
+	case uint8:
+		return int64(val), true
+	case uint16:
+		return int64(val), true
+	case uint32:
+		return int64(val), true
+	case uint64:
+		return int64(val), val <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// compareIntegers orders two numbers when at least one is an integer,
+// returning -1, 0 or 1. Converting both to float64 would lose the low
+// digits of large int64 values and let the tolerance of compareNumbers
+// make nearby integers equal, so an integer is compared exactly: with
+// another integer or a whole float as int64, and otherwise as float64
+// without tolerance. ok is false if neither is an integer, or for NaN.
+func compareIntegers(left, right interface{}) (order int, ok bool) {
+	leftInt, leftIsInt := toInt64(left)
+	rightInt, rightIsInt := toInt64(right)
+	switch {
+	case leftIsInt && rightIsInt:
+		return cmp.Compare(leftInt, rightInt), true
+	case leftIsInt:
+		return compareIntegerWithFloat(leftInt, right)
+	case rightIsInt:
+		order, ok := compareIntegerWithFloat(rightInt, left)
+		return -order, ok
+	default:
+		return 0, false
+	}
+}
+
+// compareIntegerWithFloat orders an integer and a float
+func compareIntegerWithFloat(i int64, v interface{}) (int, bool) {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case float32:
+		f = float64(val)
+	default:
+		return 0, false
+	}
+	if math.IsNaN(f) {
+		return 0, false
+	}
+
+	// -2^63 is exact as a float64, but 2^63 is already out of range
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return cmp.Compare(i, int64(f)), true
+	}
+	return cmp.Compare(float64(i), f), true
+}
+
+// orderMatches reports whether the order of two values, as returned by
+// cmp.Compare, satisfies a comparison operator
+func orderMatches(order int, operator TokenType) bool {
+	switch operator {
+	case TokenEqual:
+		return order == 0
+	case TokenNotEqual:
+		return order != 0
+	case TokenLess:
+		return order < 0
+	case TokenGreater:
+		return order > 0
+	case TokenLessEqual:
+		return order <= 0
+	case TokenGreaterEqual:
+		return order >= 0
+	default:
+		return false
+	}
+}
+
 // toString converts a value to string if possible
 func toString(v interface{}) (string, bool) {
 	if str, ok := v.(string); ok {
@@ -101,11 +252,89 @@ func toBool(v interface{}) (bool, bool) {
 	return false, false
 }
 
+// toTimes converts two values to time.Time when at least one of them is a
+// time.Time and the other is a time.Time or a date string parseDate accepts
+func toTimes(a, b interface{}) (time.Time, time.Time, bool) {
+	aTime, aIsTime := a.(time.Time)
+	bTime, bIsTime := b.(time.Time)
+	if !aIsTime && !bIsTime {
+		return time.Time{}, time.Time{}, false
+	}
+
+	var err error
+	if !aIsTime {
+		if _, isStr := a.(string); !isStr {
+			return time.Time{}, time.Time{}, false
+		}
+		if aTime, err = parseDate(a); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if !bIsTime {
+		if _, isStr := b.(string); !isStr {
+			return time.Time{}, time.Time{}, false
+		}
+		if bTime, err = parseDate(b); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return aTime, bTime, true
+}
+
+// timeStringError returns why a string compared with a timestamp isn't a
+// valid date, or nil if the values aren't a timestamp and a string
+func timeStringError(a, b interface{}) error {
+	if _, ok := b.(time.Time); ok {
+		a, b = b, a
+	}
+	if _, ok := a.(time.Time); !ok {
+		return nil
+	}
+	if _, ok := b.(string); !ok {
+		return nil
+	}
+	_, err := parseDate(b)
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("comparing with a timestamp: %w", err)
+}
+
+// compareTimes compares two timestamps
+func compareTimes(left time.Time, operator TokenType, right time.Time) bool {
+	switch operator {
+	case TokenEqual:
+		return left.Equal(right)
+	case TokenNotEqual:
+		return !left.Equal(right)
+	case TokenLess:
+		return left.Before(right)
+	case TokenGreater:
+		return left.After(right)
+	case TokenLessEqual:
+		return !left.After(right)
+	case TokenGreaterEqual:
+		return !left.Before(right)
+	default:
+		return false
+	}
+}
+
 // compareNumbers compares two numbers
 func compareNumbers(left float64, operator TokenType, right float64) bool {
 	const epsilon = 1e-9 // Use small epsilon for floating point comparison
+
+	// IEEE semantics: NaN is unequal and unordered relative to everything, itself included
+	if math.IsNaN(left) || math.IsNaN(right) {
+		return operator == TokenNotEqual
+	}
+
 	switch operator {
 	case TokenEqual:
+		// Exact match first: the epsilon test below is NaN for two equal infinities
+		if left == right {
+			return true
+		}
 		// Use relative epsilon for large numbers, absolute for small
 		diff := abs(left - right)
 		maxAbs := max(abs(left), abs(right))
@@ -113,6 +342,9 @@ func compareNumbers(left float64, operator TokenType, right float64) bool {
 		threshold := epsilon * max(1.0, maxAbs)
 		return diff < threshold
 	case TokenNotEqual:
+		if left == right {
+			return false
+		}
 		// Use relative epsilon for large numbers, absolute for small
 		diff := abs(left - right)
 		maxAbs := max(abs(left), abs(right))
@@ -176,10 +408,16 @@ func ApplyFilterWithContext(rows []map[string]interface{}, filter Expression, ct
 	}
 
 	filtered := make([]map[string]interface{}, 0)
-	for _, row := range rows {
+	for i, row := range rows {
 		var match bool
 		var err error
 
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		// Use context-aware evaluation if context is provided (handles nested subqueries in compound expressions)
 		if ctx != nil {
 			match, err = ctx.EvaluateExpression(row, filter)
@@ -226,41 +464,48 @@ func ApplySelectListAfterWindows(rows []map[string]interface{}, selectList []Sel
 		return rows, nil
 	}
 
+	// Window results were added to the rows as columns, so * must not expand them
+	windowColumns := make(map[string]bool)
+	for _, item := range selectList {
+		if winExpr, ok := item.Expr.(*WindowExpr); ok {
+			if item.Alias != "" {
+				windowColumns[item.Alias] = true
+			} else {
+				windowColumns[winExpr.Function] = true
+			}
+		}
+	}
+
 	projected := make([]map[string]interface{}, 0, len(rows))
 
 	for _, row := range rows {
 		newRow := make(map[string]interface{})
 
 		for _, item := range selectList {
-			// Determine the column name for the result
-			columnName := item.Alias
-			if columnName == "" {
-				if colRef, ok := item.Expr.(*ColumnRef); ok {
-					columnName = colRef.Column
-				} else if winExpr, ok := item.Expr.(*WindowExpr); ok {
-					// Window functions already computed, use function name as column name
-					columnName = winExpr.Function
-				} else if funcCall, ok := item.Expr.(*FunctionCall); ok {
-					columnName = funcCall.Name
-				} else {
-					columnName = fmt.Sprintf("col_%d", len(newRow))
+			if expanded, err := expandWildcard(newRow, row, item, windowColumns); expanded || err != nil {
+				if err != nil {
+					return nil, err
 				}
+				continue
 			}
 
+			// Window functions were already computed under this name
+			columnName := selectItemName(item, len(newRow))
+
 			// For window expressions, the value is already in the row with the column name
 			if _, ok := item.Expr.(*WindowExpr); ok {
 				value, exists := row[columnName]
 				if !exists {
 					return nil, fmt.Errorf("window function result %q not found in row", columnName)
 				}
-				newRow[columnName] = value
+				newRow[uniqueColumnName(newRow, columnName)] = value
 			} else {
 				// Evaluate other expressions normally
 				value, err := item.Expr.EvaluateSelect(row)
 				if err != nil {
 					return nil, err
 				}
-				newRow[columnName] = value
+				newRow[uniqueColumnName(newRow, columnName)] = value
 			}
 		}
 
@@ -299,11 +544,10 @@ func ApplySelectListWithContext(rows []map[string]interface{}, selectList []Sele
 		newRow := make(map[string]interface{})
 
 		for _, item := range selectList {
-			// Special handling for SELECT * in mixed select lists
-			if colRef, ok := item.Expr.(*ColumnRef); ok && colRef.Column == "*" {
-				// Expand all columns from the row instead of treating * as a column
-				for col, val := range row {
-					newRow[col] = val
+			// Special handling for SELECT * and u.* in mixed select lists
+			if expanded, err := expandWildcard(newRow, row, item, nil); expanded || err != nil {
+				if err != nil {
+					return nil, err
 				}
 				continue
 			}
@@ -323,25 +567,7 @@ func ApplySelectListWithContext(rows []map[string]interface{}, selectList []Sele
 				return nil, err
 			}
 
-			// Determine the column name to use
-			columnName := item.Alias
-			if columnName == "" {
-				// If no alias, try to derive name from expression
-				if colRef, ok := item.Expr.(*ColumnRef); ok {
-					columnName = colRef.Column
-				} else if funcCall, ok := item.Expr.(*FunctionCall); ok {
-					// For function calls, use the function name as column name
-					columnName = funcCall.Name
-				} else if _, ok := item.Expr.(*LiteralExpr); ok {
-					// For literals, use a generated name
-					columnName = fmt.Sprintf("literal_%d", len(newRow))
-				} else {
-					// Fallback: use a generated name
-					columnName = fmt.Sprintf("col_%d", len(newRow))
-				}
-			}
-
-			newRow[columnName] = value
+			newRow[uniqueColumnName(newRow, selectItemName(item, len(newRow)))] = value
 		}
 
 		projected = append(projected, newRow)
@@ -350,6 +576,105 @@ func ApplySelectListWithContext(rows []map[string]interface{}, selectList []Sele
 	return projected, nil
 }
 
+// expandWildcard adds the columns of row selected by a * or u.* item to
+// newRow instead of treating the wildcard as a column, and reports whether
+// item was one. u.* selects the columns prefixed with "u.", those of the
+// table aliased u in a JOIN, keeping their names. Columns in skip are left
+// out. They are visited in sorted order so duplicate suffixes are
+// deterministic.
+func expandWildcard(newRow, row map[string]interface{}, item SelectItem, skip map[string]bool) (bool, error) {
+	colRef, ok := item.Expr.(*ColumnRef)
+	if !ok || !colRef.isWildcard() {
+		return false, nil
+	}
+	table, qualified := colRef.wildcardTable()
+
+	found := false
+	for _, col := range sortedColumns(row) {
+		if skip[col] || qualified && !strings.HasPrefix(col, table+".") {
+			continue
+		}
+		newRow[uniqueColumnName(newRow, col)] = row[col]
+		found = true
+	}
+	if qualified && !found {
+		return true, fmt.Errorf("%s matches no columns: no table is named %s", colRef.Column, table)
+	}
+	return true, nil
+}
+
+// selectItemName returns the output column name of a SELECT item, before
+// duplicates are renamed by uniqueColumnName. Items without an alias are
+// named after the column or function they compute; others get a generated
+// name from their position, the number of columns already in the row.
+func selectItemName(item SelectItem, position int) string {
+	if item.Alias != "" {
+		return item.Alias
+	}
+	switch expr := item.Expr.(type) {
+	case *ColumnRef:
+		return expr.Column
+	case *AggregateExpr:
+		return strings.ToLower(expr.Function)
+	case *WindowExpr:
+		return expr.Function
+	case *FunctionCall:
+		return expr.Name
+	case *LiteralExpr:
+		return fmt.Sprintf("literal_%d", position)
+	}
+	return fmt.Sprintf("col_%d", position)
+}
+
+// SelectColumns returns the output columns of a SELECT list in order, as
+// the rows of the query are named. Returns nil for an empty list or one
+// with a * or u.* wildcard, whose columns depend on the table.
+func SelectColumns(selectList []SelectItem) []string {
+	if len(selectList) == 0 {
+		return nil
+	}
+	taken := make(map[string]interface{}, len(selectList))
+	columns := make([]string, 0, len(selectList))
+	for _, item := range selectList {
+		if colRef, ok := item.Expr.(*ColumnRef); ok && colRef.isWildcard() {
+			return nil
+		}
+		name := uniqueColumnName(taken, selectItemName(item, len(columns)))
+		taken[name] = nil
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// uniqueColumnName returns name if it is not yet used in row, otherwise the first
+// free name of the form name_1, name_2, ...
+//
+// Output rows are maps, so two SELECT items with the same output name (e.g.
+// "SELECT age AS x, salary AS x") would otherwise silently overwrite each other.
+// The first occurrence keeps its name and later duplicates are suffixed in
+// SELECT list order.
+func uniqueColumnName(row map[string]interface{}, name string) string {
+	if _, exists := row[name]; !exists {
+		return name
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if _, exists := row[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// sortedColumns returns the column names of a row in sorted order
+func sortedColumns(row map[string]interface{}) []string {
+	columns := make([]string, 0, len(row))
+	for col := range row {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
 // ApplyOrderBy sorts rows based on ORDER BY clause
 func ApplyOrderBy(rows []map[string]interface{}, orderBy []OrderByItem) ([]map[string]interface{}, error) {
 	if len(rows) == 0 || len(orderBy) == 0 {
@@ -360,30 +685,11 @@ func ApplyOrderBy(rows []map[string]interface{}, orderBy []OrderByItem) ([]map[s
 	sorted := make([]map[string]interface{}, len(rows))
 	copy(sorted, rows)
 
-	// Sort the rows
-	sort.Slice(sorted, func(i, j int) bool {
+	// Sort the rows; stable, so rows that tie keep their input order
+	sort.SliceStable(sorted, func(i, j int) bool {
 		for _, item := range orderBy {
-			// Get values for the column
-			valI, existsI := sorted[i][item.Column]
-			valJ, existsJ := sorted[j][item.Column]
-
-			// Handle missing columns (treat as NULL, which sorts first)
-			if !existsI && !existsJ {
-				continue // Both NULL, try next column
-			}
-			if !existsI {
-				return !item.Desc // NULL sorts first (or last if DESC)
-			}
-			if !existsJ {
-				return item.Desc // NULL sorts first (or last if DESC)
-			}
-
-			// Compare the values
-			cmp := compareValues(valI, valJ)
-			if cmp != 0 {
-				if item.Desc {
-					return cmp > 0
-				}
+			// A missing column is treated as NULL
+			if cmp := compareOrderBy(sorted[i][item.Column], sorted[j][item.Column], item); cmp != 0 {
 				return cmp < 0
 			}
 			// Values are equal, continue to next ORDER BY column
@@ -394,6 +700,46 @@ func ApplyOrderBy(rows []map[string]interface{}, orderBy []OrderByItem) ([]map[s
 	return sorted, nil
 }
 
+// compareOrderBy compares two values of an ORDER BY item, returning a
+// negative number if a sorts before b, a positive one if after and 0 if
+// they tie. NULLs sort as item.Nulls says, by default last for ASC and
+// first for DESC.
+func compareOrderBy(a, b interface{}, item OrderByItem) int {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0
+		}
+		nullsFirst := item.Nulls == NullsFirst || (item.Nulls == NullsDefault && item.Desc)
+		if (a == nil) == nullsFirst {
+			return -1
+		}
+		return 1
+	}
+
+	cmp := compareValues(a, b)
+	if item.Desc {
+		return -cmp
+	}
+	return cmp
+}
+
+// OutputSelectList returns the items to project: the SELECT list followed by
+// the ORDER BY keys it doesn't name
+func (q *Query) OutputSelectList() []SelectItem {
+	if len(q.OrderKeys) == 0 {
+		return q.SelectList
+	}
+	items := make([]SelectItem, 0, len(q.SelectList)+len(q.OrderKeys))
+	items = append(items, q.SelectList...)
+	return append(items, q.OrderKeys...)
+}
+
+// DropOrderKeys removes the columns computed for ORDER BY keys from sorted
+// rows, in place
+func DropOrderKeys(rows []map[string]interface{}, orderKeys []SelectItem) []map[string]interface{} {
+	return dropItemColumns(rows, orderKeys)
+}
+
 // compareValues compares two values and returns:
 // -1 if a < b
 //
@@ -412,7 +758,15 @@ func compareValues(a, b interface{}) int {
 		return 1
 	}
 
+	// Try timestamp comparison
+	if aTime, bTime, ok := toTimes(a, b); ok {
+		return aTime.Compare(bTime)
+	}
+
 	// Try numeric comparison
+	if order, ok := compareIntegers(a, b); ok {
+		return order
+	}
 	aNum, aIsNum := toFloat64(a)
 	bNum, bIsNum := toFloat64(b)
 	if aIsNum && bIsNum {
@@ -536,68 +890,64 @@ func rowToKey(row map[string]interface{}) string {
 // matchLikePattern matches a string against a SQL LIKE pattern
 // % matches any sequence of characters
 // _ matches any single character
-func matchLikePattern(str, pattern string) bool {
-	// Convert pattern to segments split by %
-	segments := strings.Split(pattern, "%")
-
-	// Track position in the string
-	pos := 0
-
-	for i, segment := range segments {
-		if segment == "" {
-			// Empty segment means % was at start/end or consecutive %%
-			continue
-		}
-
-		// Match the segment (handling _ wildcards)
-		matchPos := findSegmentMatch(str[pos:], segment)
-		if matchPos == -1 {
-			return false
-		}
-
-		// For the first segment, it must match at the start (unless pattern starts with %)
-		if i == 0 && !strings.HasPrefix(pattern, "%") && matchPos != 0 {
+// escape, unless 0, makes the character after it match itself, as in 100\%
+func matchLikePattern(str, pattern string, escape rune) bool {
+	elems := compileLikePattern(pattern, escape)
+	runes := []rune(str)
+
+	// Match left to right, going back to the last % to let it take one more
+	// character when the rest of the pattern fails
+	i, j := 0, 0
+	star, starPos := -1, 0
+	for i < len(runes) {
+		switch {
+		case j < len(elems) && elems[j].anySeq:
+			star, starPos = j, i
+			j++
+		case j < len(elems) && (elems[j].anyOne || elems[j].r == runes[i]):
+			i++
+			j++
+		case star >= 0:
+			starPos++
+			i, j = starPos, star+1
+		default:
 			return false
 		}
-
-		pos += matchPos + len(segment)
 	}
 
-	// For the last segment, it must match at the end (unless pattern ends with %)
-	if !strings.HasSuffix(pattern, "%") && pos != len(str) {
-		return false
+	// Only % can match what is left of the pattern
+	for j < len(elems) && elems[j].anySeq {
+		j++
 	}
-
-	return true
+	return j == len(elems)
 }
 
-// findSegmentMatch finds the position where a segment matches in the string
-// Returns -1 if no match found
-// Handles _ wildcard matching any single character
-func findSegmentMatch(str, segment string) int {
-	if len(segment) == 0 {
-		return 0
-	}
-
-	// If no _ wildcards, use simple string search
-	if !strings.Contains(segment, "_") {
-		idx := strings.Index(str, segment)
-		return idx
-	}
+// likeElem is a compiled element of a LIKE pattern: a character matching
+// itself, or the _ or % wildcard
+type likeElem struct {
+	r      rune
+	anyOne bool // _
+	anySeq bool // %
+}
 
-	// Handle _ wildcards
-	for i := 0; i <= len(str)-len(segment); i++ {
-		match := true
-		for j := 0; j < len(segment); j++ {
-			if segment[j] != '_' && str[i+j] != segment[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return i
+// compileLikePattern splits a LIKE pattern into elements. An escape
+// character at the end of the pattern matches itself.
+func compileLikePattern(pattern string, escape rune) []likeElem {
+	runes := []rune(pattern)
+	elems := make([]likeElem, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escape != 0 && r == escape && i+1 < len(runes):
+			i++
+			elems = append(elems, likeElem{r: runes[i]})
+		case r == '%':
+			elems = append(elems, likeElem{anySeq: true})
+		case r == '_':
+			elems = append(elems, likeElem{anyOne: true})
+		default:
+			elems = append(elems, likeElem{r: r})
 		}
 	}
-
-	return -1
+	return elems
 }
@@ -1,7 +1,11 @@
 package query
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCompare_Numbers(t *testing.T) {
@@ -38,6 +42,22 @@ func TestCompare_Numbers(t *testing.T) {
 		{"int not equal same", int64(30), TokenNotEqual, int64(30), false},
 		{"int less wrong", int64(35), TokenLess, int64(30), false},
 		{"int greater wrong", int64(25), TokenGreater, int64(30), false},
+
+		// NaN is unordered and unequal to everything, including itself
+		{"nan equal nan", math.NaN(), TokenEqual, math.NaN(), false},
+		{"nan not equal nan", math.NaN(), TokenNotEqual, math.NaN(), true},
+		{"nan greater", math.NaN(), TokenGreater, int64(0), false},
+		{"nan less", math.NaN(), TokenLess, int64(0), false},
+		{"nan greater equal", math.NaN(), TokenGreaterEqual, int64(0), false},
+		{"less equal nan", int64(0), TokenLessEqual, math.NaN(), false},
+		{"not equal nan", int64(0), TokenNotEqual, math.NaN(), true},
+
+		// Infinities
+		{"inf equal inf", math.Inf(1), TokenEqual, math.Inf(1), true},
+		{"inf not equal inf", math.Inf(1), TokenNotEqual, math.Inf(1), false},
+		{"inf greater max", math.Inf(1), TokenGreater, math.MaxFloat64, true},
+		{"neg inf less", math.Inf(-1), TokenLess, int64(-1000), true},
+		{"inf not equal neg inf", math.Inf(1), TokenNotEqual, math.Inf(-1), true},
 	}
 
 	for _, tt := range tests {
@@ -124,6 +144,66 @@ func TestCompare_Booleans(t *testing.T) {
 	}
 }
 
+func TestCompare_Times(t *testing.T) {
+	ts := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		left     interface{}
+		operator TokenType
+		right    interface{}
+		want     bool
+	}{
+		{"equal times", ts, TokenEqual, ts, true},
+		{"same instant in another zone", ts, TokenEqual, ts.In(time.FixedZone("UTC+2", 2*3600)), true},
+		{"earlier is less", ts, TokenLess, ts.Add(time.Second), true},
+		{"later is greater", ts.Add(time.Hour), TokenGreater, ts, true},
+		{"less or equal", ts, TokenLessEqual, ts, true},
+		{"greater or equal", ts, TokenGreaterEqual, ts.Add(time.Nanosecond), false},
+		{"not equal", ts, TokenNotEqual, ts.Add(time.Nanosecond), true},
+		{"after date string", ts, TokenGreater, "2024-03-15", true},
+		{"before date string", ts, TokenLess, "2024-03-16", true},
+		{"equals RFC3339 string", ts, TokenEqual, "2024-03-15T10:30:00Z", true},
+		{"date string on the left", "2024-01-01 00:00:00", TokenLess, ts, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compare(tt.left, tt.operator, tt.right)
+			if err != nil {
+				t.Errorf("compare() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("compare(%v, %v, %v) = %v, want %v", tt.left, tt.operator, tt.right, got, tt.want)
+			}
+		})
+	}
+
+	if got := compareValues(ts, ts.Add(time.Minute)); got != -1 {
+		t.Errorf("compareValues(earlier, later) = %d, want -1", got)
+	}
+	if got := compareValues(ts, "2024-01-01"); got != 1 {
+		t.Errorf("compareValues(ts, \"2024-01-01\") = %d, want 1", got)
+	}
+}
+
+func TestCompare_TimeInvalidDate(t *testing.T) {
+	ts := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	for _, literal := range []string{"2024-13-01", "15/03/2024", "yesterday"} {
+		t.Run(literal, func(t *testing.T) {
+			_, err := compare(ts, TokenGreater, literal)
+			if err == nil {
+				t.Fatalf("compare(ts, >, %q) expected error", literal)
+			}
+			for _, want := range []string{"comparing with a timestamp", literal, "2006-01-02T15:04:05Z07:00", "2006-01-02 15:04:05"} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q should contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
 func TestCompare_Nil(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -164,6 +244,8 @@ func TestCompare_TypeMismatch(t *testing.T) {
 		{"number vs boolean", int64(30), true},
 		{"string vs boolean", "true", true},
 		{"boolean vs string", true, "true"},
+		{"time vs number", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), int64(30)},
+		{"time vs non-date string", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "alice"},
 	}
 
 	for _, tt := range tests {
@@ -176,6 +258,62 @@ func TestCompare_TypeMismatch(t *testing.T) {
 	}
 }
 
+func TestTypeErrors(t *testing.T) {
+	// A row whose columns hold unexpected types, as after reading files with divergent schemas
+	rows := []map[string]interface{}{
+		{"id": int64(1), "amount": 10.5, "name": "alice"},
+		{"id": "two", "amount": "n/a", "name": int64(3)},
+	}
+
+	tests := []struct {
+		query        string
+		wantColumn   string
+		wantExpected string
+		wantValue    interface{}
+	}{
+		{"SELECT * FROM t WHERE id > 0", "id", "number", "two"},
+		{"SELECT * FROM t WHERE id BETWEEN 0 AND 5", "id", "number", "two"},
+		{"SELECT * FROM t WHERE id IN (1, 2)", "id", "number", "two"},
+		{"SELECT * FROM t WHERE name LIKE 'a%'", "name", "string", int64(3)},
+		{"SELECT * FROM t WHERE name = amount", "name", "number", "alice"},
+		{"SELECT SUM(amount) AS total FROM t", "amount", "number", "n/a"},
+		{"SELECT name, AVG(amount) AS avg FROM t GROUP BY name", "amount", "number", "n/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ctx := NewExecutionContext(nil)
+			ctx.CTEs["t"] = rows
+			_, err = ctx.executeSelect(q)
+
+			var typeErr *TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("executeSelect() error = %v, want a *TypeError", err)
+			}
+			if typeErr.Column != tt.wantColumn || typeErr.Expected != tt.wantExpected || typeErr.Value != tt.wantValue {
+				t.Errorf("TypeError = %+v, want column %q, expected %q, value %#v", *typeErr, tt.wantColumn, tt.wantExpected, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestTypeError_Error(t *testing.T) {
+	err := &TypeError{Column: "id", Expected: "number", Value: "two"}
+	if got, want := err.Error(), `column "id": expected number, got string value two`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	err = &TypeError{Expected: "string", Value: int64(3)}
+	if got, want := err.Error(), "expected string, got int64 value 3"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
 func TestComparisonExpr_Evaluate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -225,6 +363,54 @@ func TestComparisonExpr_Evaluate(t *testing.T) {
 	}
 }
 
+func TestComparisonExpr_NumericCoercion(t *testing.T) {
+	operators := []TokenType{TokenEqual, TokenNotEqual, TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual}
+
+	// want holds the result for =, !=, <, >, <= and >=, in that order
+	tests := []struct {
+		name    string
+		column  interface{}
+		literal interface{}
+		want    [6]bool
+	}{
+		{name: "int64 vs equal int", column: int64(25), literal: int64(25), want: [6]bool{true, false, false, false, true, true}},
+		{name: "int64 vs equal float", column: int64(25), literal: 25.0, want: [6]bool{true, false, false, false, true, true}},
+		{name: "int64 vs smaller int", column: int64(25), literal: int64(24), want: [6]bool{false, true, false, true, false, true}},
+		{name: "int64 vs smaller whole float", column: int64(25), literal: 24.0, want: [6]bool{false, true, false, true, false, true}},
+		{name: "int64 vs larger whole float", column: int64(25), literal: 26.0, want: [6]bool{false, true, true, false, true, false}},
+		{name: "int64 vs fraction above", column: int64(25), literal: 25.5, want: [6]bool{false, true, true, false, true, false}},
+		{name: "int64 vs fraction below", column: int64(25), literal: 24.5, want: [6]bool{false, true, false, true, false, true}},
+		{name: "int64 vs tiny fraction above", column: int64(25), literal: 25.000000000001, want: [6]bool{false, true, true, false, true, false}},
+		{name: "int32 vs equal float", column: int32(25), literal: 25.0, want: [6]bool{true, false, false, false, true, true}},
+		{name: "int64 vs float32", column: int64(25), literal: float32(25), want: [6]bool{true, false, false, false, true, true}},
+		{name: "uint64 vs int", column: uint64(25), literal: int64(-1), want: [6]bool{false, true, false, true, false, true}},
+		{name: "negative int64 vs float", column: int64(-3), literal: -3.0, want: [6]bool{true, false, false, false, true, true}},
+		// Beyond 2^53, neighbouring integers are the same float64
+		{name: "large int64 vs neighbouring int", column: int64(9007199254740993), literal: int64(9007199254740992), want: [6]bool{false, true, false, true, false, true}},
+		{name: "large int64 vs whole float", column: int64(9007199254740993), literal: 9007199254740992.0, want: [6]bool{false, true, false, true, false, true}},
+		// The float tolerance used to make these equal
+		{name: "int64 ids one apart", column: int64(10000000000), literal: int64(10000000001), want: [6]bool{false, true, true, false, true, false}},
+		{name: "int64 vs float out of range", column: int64(math.MaxInt64), literal: 1e19, want: [6]bool{false, true, true, false, true, false}},
+		{name: "int64 vs infinity", column: int64(25), literal: math.Inf(-1), want: [6]bool{false, true, false, true, false, true}},
+		{name: "int64 vs NaN", column: int64(25), literal: math.NaN(), want: [6]bool{false, true, false, false, false, false}},
+		{name: "floats within tolerance", column: 0.1 + 0.2, literal: 0.3, want: [6]bool{true, false, false, false, true, true}},
+	}
+
+	for _, tt := range tests {
+		for i, operator := range operators {
+			expr := ComparisonExpr{Column: "age", Operator: operator, Value: tt.literal}
+			got, err := expr.Evaluate(map[string]interface{}{"age": tt.column})
+			if err != nil {
+				t.Errorf("%s: operator %d: Evaluate() error = %v", tt.name, operator, err)
+				continue
+			}
+			if got != tt.want[i] {
+				t.Errorf("%s: %v (%T) operator %d %v (%T) = %v, want %v", tt.name, tt.column, tt.column, operator, tt.literal, tt.literal, got, tt.want[i])
+			}
+		}
+	}
+}
+
 func TestBinaryExpr_Evaluate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -427,6 +613,24 @@ func TestInExpr_Evaluate(t *testing.T) {
 			row:  map[string]interface{}{"age": int64(30)},
 			want: true,
 		},
+		{
+			name: "int value in list of floats",
+			expr: &InExpr{Column: "age", Values: []interface{}{25.0, 26.0}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: true,
+		},
+		{
+			name: "int value not in list of fractions",
+			expr: &InExpr{Column: "age", Values: []interface{}{24.5, 25.5}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: false,
+		},
+		{
+			name: "large int value next to a list value",
+			expr: &InExpr{Column: "id", Values: []interface{}{int64(10000000001)}},
+			row:  map[string]interface{}{"id": int64(10000000000)},
+			want: false,
+		},
 		{
 			name: "NOT IN - value in list",
 			expr: &InExpr{Column: "status", Values: []interface{}{"deleted"}, Negate: true},
@@ -439,6 +643,61 @@ func TestInExpr_Evaluate(t *testing.T) {
 			row:  map[string]interface{}{"status": "active"},
 			want: true,
 		},
+		{
+			name: "mixed list - number matches",
+			expr: &InExpr{Column: "code", Values: []interface{}{"n/a", int64(404), 500.0}},
+			row:  map[string]interface{}{"code": int32(404)},
+			want: true,
+		},
+		{
+			name: "mixed list - string matches",
+			expr: &InExpr{Column: "code", Values: []interface{}{"n/a", int64(404)}},
+			row:  map[string]interface{}{"code": "n/a"},
+			want: true,
+		},
+		{
+			name: "mixed list - float matches integer element",
+			expr: &InExpr{Column: "code", Values: []interface{}{"n/a", int64(500)}},
+			row:  map[string]interface{}{"code": 500.0},
+			want: true,
+		},
+		{
+			name: "NOT IN mixed list - value not in list",
+			expr: &InExpr{Column: "code", Values: []interface{}{"n/a", int64(404)}, Negate: true},
+			row:  map[string]interface{}{"code": int64(200)},
+			want: true,
+		},
+		{
+			name:    "no element of a comparable type",
+			expr:    &InExpr{Column: "code", Values: []interface{}{"n/a", "none"}},
+			row:     map[string]interface{}{"code": int64(404)},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "NULL value - IN",
+			expr: &InExpr{Column: "status", Values: []interface{}{"active", nil}},
+			row:  map[string]interface{}{"status": nil},
+			want: false,
+		},
+		{
+			name: "NULL value - NOT IN",
+			expr: &InExpr{Column: "status", Values: []interface{}{"deleted"}, Negate: true},
+			row:  map[string]interface{}{"status": nil},
+			want: false,
+		},
+		{
+			name: "NOT IN - list with NULL",
+			expr: &InExpr{Column: "status", Values: []interface{}{"deleted", nil}, Negate: true},
+			row:  map[string]interface{}{"status": "active"},
+			want: false,
+		},
+		{
+			name: "IN - list with NULL still matches",
+			expr: &InExpr{Column: "status", Values: []interface{}{nil, "active"}},
+			row:  map[string]interface{}{"status": "active"},
+			want: true,
+		},
 		{
 			name:    "column missing",
 			expr:    &InExpr{Column: "status", Values: []interface{}{"active"}},
@@ -518,6 +777,72 @@ func TestLikeExpr_Evaluate(t *testing.T) {
 			row:  map[string]interface{}{"name": "test user"},
 			want: false,
 		},
+		{
+			name: "suffix that also occurs earlier",
+			expr: &LikeExpr{Column: "name", Pattern: "%bc"},
+			row:  map[string]interface{}{"name": "abcbc"},
+			want: true,
+		},
+		{
+			name: "single char wildcard on a multibyte character",
+			expr: &LikeExpr{Column: "name", Pattern: "caf_"},
+			row:  map[string]interface{}{"name": "café"},
+			want: true,
+		},
+		{
+			name: "escaped percent matches literally",
+			expr: &LikeExpr{Column: "rate", Pattern: "100!%", Escape: '!'},
+			row:  map[string]interface{}{"rate": "100%"},
+			want: true,
+		},
+		{
+			name: "escaped percent is not a wildcard",
+			expr: &LikeExpr{Column: "rate", Pattern: "100!%", Escape: '!'},
+			row:  map[string]interface{}{"rate": "1000"},
+			want: false,
+		},
+		{
+			name: "escaped underscore with a wildcard",
+			expr: &LikeExpr{Column: "key", Pattern: "%!_id", Escape: '!'},
+			row:  map[string]interface{}{"key": "user_id"},
+			want: true,
+		},
+		{
+			name: "escaped escape character",
+			expr: &LikeExpr{Column: "path", Pattern: `C:\\%`, Escape: '\\'},
+			row:  map[string]interface{}{"path": `C:\temp`},
+			want: true,
+		},
+		{
+			name: "percent without ESCAPE is still a wildcard",
+			expr: &LikeExpr{Column: "rate", Pattern: "100!%"},
+			row:  map[string]interface{}{"rate": "100!x"},
+			want: true,
+		},
+		{
+			name: "LIKE is case-sensitive",
+			expr: &LikeExpr{Column: "name", Pattern: "alice%"},
+			row:  map[string]interface{}{"name": "Alice Smith"},
+			want: false,
+		},
+		{
+			name: "ILIKE ignores case",
+			expr: &LikeExpr{Column: "name", Pattern: "ALICE%", CaseInsensitive: true},
+			row:  map[string]interface{}{"name": "Alice Smith"},
+			want: true,
+		},
+		{
+			name: "ILIKE with single char wildcard",
+			expr: &LikeExpr{Column: "code", Pattern: "a_c", CaseInsensitive: true},
+			row:  map[string]interface{}{"code": "AbC"},
+			want: true,
+		},
+		{
+			name: "NOT ILIKE",
+			expr: &LikeExpr{Column: "name", Pattern: "%SMITH", Negate: true, CaseInsensitive: true},
+			row:  map[string]interface{}{"name": "Alice Smith"},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -660,6 +985,262 @@ func TestIsNullExpr_Evaluate(t *testing.T) {
 	}
 }
 
+func TestIsBoolExpr_Evaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    *IsBoolExpr
+		row     map[string]interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "IS TRUE - value true",
+			expr: &IsBoolExpr{Column: "active", Value: true},
+			row:  map[string]interface{}{"active": true},
+			want: true,
+		},
+		{
+			name: "IS TRUE - value false",
+			expr: &IsBoolExpr{Column: "active", Value: true},
+			row:  map[string]interface{}{"active": false},
+			want: false,
+		},
+		{
+			name: "IS TRUE - value is nil",
+			expr: &IsBoolExpr{Column: "active", Value: true},
+			row:  map[string]interface{}{"active": nil},
+			want: false,
+		},
+		{
+			name: "IS NOT TRUE - value is nil",
+			expr: &IsBoolExpr{Column: "active", Value: true, Negate: true},
+			row:  map[string]interface{}{"active": nil},
+			want: true,
+		},
+		{
+			name: "IS FALSE - value false",
+			expr: &IsBoolExpr{Column: "active", Value: false},
+			row:  map[string]interface{}{"active": false},
+			want: true,
+		},
+		{
+			name: "IS NOT FALSE - column missing",
+			expr: &IsBoolExpr{Column: "active", Value: false, Negate: true},
+			row:  map[string]interface{}{"name": "alice"},
+			want: true,
+		},
+		{
+			name:    "IS TRUE - non-boolean value",
+			expr:    &IsBoolExpr{Column: "name", Value: true},
+			row:     map[string]interface{}{"name": "alice"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Evaluate(tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsBoolExpr.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsBoolExpr.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistinctExpr_Evaluate(t *testing.T) {
+	a := &ColumnRef{Column: "a"}
+	b := &ColumnRef{Column: "b"}
+
+	tests := []struct {
+		name    string
+		expr    *DistinctExpr
+		row     map[string]interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "NULL <=> NULL",
+			expr: &DistinctExpr{Left: a, Right: b, Negate: true},
+			row:  map[string]interface{}{"a": nil, "b": nil},
+			want: true,
+		},
+		{
+			name: "NULL <=> 1",
+			expr: &DistinctExpr{Left: a, Right: b, Negate: true},
+			row:  map[string]interface{}{"a": nil, "b": int64(1)},
+			want: false,
+		},
+		{
+			name: "equal numbers of different types",
+			expr: &DistinctExpr{Left: a, Right: b, Negate: true},
+			row:  map[string]interface{}{"a": int64(1), "b": 1.0},
+			want: true,
+		},
+		{
+			name: "NULL IS DISTINCT FROM NULL",
+			expr: &DistinctExpr{Left: a, Right: b},
+			row:  map[string]interface{}{"a": nil, "b": nil},
+			want: false,
+		},
+		{
+			name: "1 IS DISTINCT FROM NULL",
+			expr: &DistinctExpr{Left: a, Right: &LiteralExpr{Value: nil}},
+			row:  map[string]interface{}{"a": int64(1)},
+			want: true,
+		},
+		{
+			name: "different strings",
+			expr: &DistinctExpr{Left: a, Right: b},
+			row:  map[string]interface{}{"a": "x", "b": "y"},
+			want: true,
+		},
+		{
+			name:    "missing column",
+			expr:    &DistinctExpr{Left: a, Right: b},
+			row:     map[string]interface{}{"a": nil},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Evaluate(tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DistinctExpr.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DistinctExpr.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotExpr_Evaluate(t *testing.T) {
+	olderThan30 := &ComparisonExpr{Column: "age", Operator: TokenGreater, Value: int64(30)}
+	active := &ComparisonExpr{Column: "active", Operator: TokenEqual, Value: true}
+
+	tests := []struct {
+		name    string
+		expr    Expression
+		row     map[string]interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "negates false",
+			expr: &NotExpr{Expr: olderThan30},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: true,
+		},
+		{
+			name: "negates true",
+			expr: &NotExpr{Expr: olderThan30},
+			row:  map[string]interface{}{"age": int64(35)},
+			want: false,
+		},
+		{
+			name: "NOT of NULL comparison stays unknown",
+			expr: &NotExpr{Expr: olderThan30},
+			row:  map[string]interface{}{"age": nil},
+			want: false,
+		},
+		{
+			name: "NOT of comparison with NULL literal",
+			expr: &NotExpr{Expr: &ComparisonExpr{Column: "age", Operator: TokenEqual, Value: nil}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: true,
+		},
+		{
+			name: "NOT AND with a false side",
+			expr: &NotExpr{Expr: &BinaryExpr{Left: olderThan30, Operator: TokenAnd, Right: active}},
+			row:  map[string]interface{}{"age": nil, "active": false},
+			want: true,
+		},
+		{
+			name: "NOT AND with an unknown side",
+			expr: &NotExpr{Expr: &BinaryExpr{Left: olderThan30, Operator: TokenAnd, Right: active}},
+			row:  map[string]interface{}{"age": nil, "active": true},
+			want: false,
+		},
+		{
+			name: "NOT OR with a true side",
+			expr: &NotExpr{Expr: &BinaryExpr{Left: olderThan30, Operator: TokenOr, Right: active}},
+			row:  map[string]interface{}{"age": nil, "active": true},
+			want: false,
+		},
+		{
+			name: "NOT OR with an unknown side",
+			expr: &NotExpr{Expr: &BinaryExpr{Left: olderThan30, Operator: TokenOr, Right: active}},
+			row:  map[string]interface{}{"age": nil, "active": false},
+			want: false,
+		},
+		{
+			name: "double negation of unknown",
+			expr: &NotExpr{Expr: &NotExpr{Expr: olderThan30}},
+			row:  map[string]interface{}{"age": nil},
+			want: false,
+		},
+		{
+			name: "NOT IS NULL is never unknown",
+			expr: &NotExpr{Expr: &IsNullExpr{Column: "age"}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: true,
+		},
+		{
+			name: "NOT IN with NULL in the list",
+			expr: &NotExpr{Expr: &InExpr{Column: "age", Values: []interface{}{int64(30), nil}}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: false,
+		},
+		{
+			name: "NOT of NOT IN found in a list with NULL",
+			expr: &NotExpr{Expr: &InExpr{Column: "age", Values: []interface{}{int64(25), nil}, Negate: true}},
+			row:  map[string]interface{}{"age": int64(25)},
+			want: true,
+		},
+		{
+			name: "NOT LIKE on NULL",
+			expr: &NotExpr{Expr: &LikeExpr{Column: "name", Pattern: "a%"}},
+			row:  map[string]interface{}{"name": nil},
+			want: false,
+		},
+		{
+			name: "NOT of expression comparison with NULL",
+			expr: &NotExpr{Expr: &ExpressionComparisonExpr{
+				Left:     &ArithmeticExpr{Left: &ColumnRef{Column: "age"}, Operator: TokenPlus, Right: &LiteralExpr{Value: int64(1)}},
+				Operator: TokenGreater,
+				Right:    &LiteralExpr{Value: int64(30)},
+			}},
+			row:  map[string]interface{}{"age": nil},
+			want: false,
+		},
+		{
+			name:    "missing column",
+			expr:    &NotExpr{Expr: olderThan30},
+			row:     map[string]interface{}{"name": "alice"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Evaluate(tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestApplyDistinct(t *testing.T) {
 	tests := []struct {
 		name string
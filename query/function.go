@@ -39,6 +39,13 @@ func (r *FunctionRegistry) Register(f Function) {
 	r.functions[strings.ToUpper(f.Name())] = f
 }
 
+// RegisterAlias registers a function under an additional name
+func (r *FunctionRegistry) RegisterAlias(alias string, f Function) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functions[strings.ToUpper(alias)] = f
+}
+
 // Get retrieves a function by name (case-insensitive)
 func (r *FunctionRegistry) Get(name string) (Function, bool) {
 	r.mu.RLock()
@@ -57,11 +64,17 @@ func init() {
 	globalRegistry.Register(&UpperFunc{})
 	globalRegistry.Register(&LowerFunc{})
 	globalRegistry.Register(&ConcatFunc{})
+	globalRegistry.Register(&ConcatWSFunc{})
 	globalRegistry.Register(&LengthFunc{})
 	globalRegistry.Register(&TrimFunc{})
 	globalRegistry.Register(&LTrimFunc{})
 	globalRegistry.Register(&RTrimFunc{})
 	globalRegistry.Register(&SubstringFunc{})
+	globalRegistry.RegisterAlias("SUBSTR", &SubstringFunc{})
+	globalRegistry.Register(&LeftFunc{})
+	globalRegistry.Register(&RightFunc{})
+	globalRegistry.Register(&LPadFunc{})
+	globalRegistry.Register(&RPadFunc{})
 	globalRegistry.Register(&ReplaceFunc{})
 	globalRegistry.Register(&SplitFunc{})
 	globalRegistry.Register(&ReverseFunc{})
@@ -69,6 +82,10 @@ func init() {
 	globalRegistry.Register(&StartsWithFunc{})
 	globalRegistry.Register(&EndsWithFunc{})
 	globalRegistry.Register(&RepeatFunc{})
+	globalRegistry.Register(&FormatFunc{})
+	globalRegistry.RegisterAlias("PRINTF", &FormatFunc{})
+	globalRegistry.Register(&RegexpMatchFunc{})
+	globalRegistry.Register(&RegexpReplaceFunc{})
 
 	// Register math functions
 	globalRegistry.Register(&AbsFunc{})
@@ -78,6 +95,9 @@ func init() {
 	globalRegistry.Register(&ModFunc{})
 	globalRegistry.Register(&SqrtFunc{})
 	globalRegistry.Register(&PowFunc{})
+	globalRegistry.Register(&ExpFunc{})
+	globalRegistry.Register(&LogFunc{})
+	globalRegistry.Register(&Log10Func{})
 	globalRegistry.Register(&SignFunc{})
 	globalRegistry.Register(&TruncFunc{})
 	globalRegistry.Register(&RandomFunc{})
@@ -113,6 +133,83 @@ func GetGlobalRegistry() *FunctionRegistry {
 	return globalRegistry
 }
 
+// customFunction adapts a function registered with RegisterFunction to the Function interface
+type customFunction struct {
+	name  string
+	arity int
+	fn    func(args []interface{}) (interface{}, error)
+}
+
+func (f *customFunction) Name() string { return f.name }
+
+func (f *customFunction) MinArity() int {
+	if f.arity < 0 {
+		return 0
+	}
+	return f.arity
+}
+
+func (f *customFunction) MaxArity() int { return f.arity }
+
+func (f *customFunction) Evaluate(args []interface{}) (interface{}, error) {
+	return f.fn(args)
+}
+
+// RegisterFunction registers a custom scalar function in the global registry
+// so queries can call it by name (case-insensitive). arity is the exact number
+// of arguments, or -1 to accept any number; calls with a different number of
+// arguments fail before fn is invoked.
+//
+// Built-in, aggregate and window functions cannot be replaced. Registering a
+// custom function again replaces the earlier one.
+//
+// Register functions before executing queries. Registration itself is safe for
+// concurrent use, but a query running concurrently may or may not see the new
+// function. fn may be called concurrently by queries running in parallel.
+func RegisterFunction(name string, arity int, fn func(args []interface{}) (interface{}, error)) error {
+	if fn == nil {
+		return fmt.Errorf("function %s: implementation is nil", name)
+	}
+	if arity < -1 {
+		return fmt.Errorf("function %s: invalid arity %d", name, arity)
+	}
+	if !isValidFunctionName(name) {
+		return fmt.Errorf("invalid function name %q", name)
+	}
+
+	upper := strings.ToUpper(name)
+	if isAggregateFunction(upper) || isWindowFunction(upper) || upper == "EXTRACT" {
+		return fmt.Errorf("cannot register function %s: name is reserved", upper)
+	}
+
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if existing, ok := globalRegistry.functions[upper]; ok {
+		if _, custom := existing.(*customFunction); !custom {
+			return fmt.Errorf("cannot register function %s: a built-in function has that name", upper)
+		}
+	}
+	globalRegistry.functions[upper] = &customFunction{name: upper, arity: arity, fn: fn}
+	return nil
+}
+
+// isValidFunctionName reports whether name lexes as a plain identifier that
+// can be called as a function, i.e. it is not a SQL keyword
+func isValidFunctionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	tokens := Tokenize(strings.ToUpper(name))
+	return len(tokens) == 2 && tokens[0].Type == TokenIdent
+}
+
 // Helper function to convert value to string
 func valueToString(v interface{}) (string, error) {
 	switch val := v.(type) {
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -14,22 +16,15 @@ func (f *CastFunc) Name() string  { return "CAST" }
 func (f *CastFunc) MinArity() int { return 2 }
 func (f *CastFunc) MaxArity() int { return 2 }
 func (f *CastFunc) Evaluate(args []interface{}) (interface{}, error) {
-	value := args[0]
 	typeName, err := valueToString(args[1])
 	if err != nil {
 		return nil, fmt.Errorf("CAST: type: %w", err)
 	}
-
-	switch strings.ToLower(typeName) {
-	case "string":
-		return valueToString(value)
-	case "number":
-		return valueToNumber(value)
-	case "date":
-		return parseDate(value)
-	default:
-		return nil, fmt.Errorf("CAST: unknown type: %s", typeName)
+	result, err := castValue(args[0], typeName)
+	if err != nil {
+		return nil, fmt.Errorf("CAST: %w", err)
 	}
+	return result, nil
 }
 
 // TryCastFunc converts a value to a specific type, returning null on error
@@ -39,34 +34,145 @@ func (f *TryCastFunc) Name() string  { return "TRY_CAST" }
 func (f *TryCastFunc) MinArity() int { return 2 }
 func (f *TryCastFunc) MaxArity() int { return 2 }
 func (f *TryCastFunc) Evaluate(args []interface{}) (interface{}, error) {
-	value := args[0]
 	typeName, err := valueToString(args[1])
 	if err != nil {
 		return nil, nil
 	}
+	result, err := castValue(args[0], typeName)
+	if err != nil {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// isCastType checks if CAST can convert to the named type
+func isCastType(typeName string) bool {
+	switch strings.ToLower(typeName) {
+	case "string", "number", "date", "int", "integer", "float", "double", "bool", "boolean":
+		return true
+	}
+	return false
+}
+
+// castValue converts a value to the named type. NULL stays NULL.
+func castValue(value interface{}, typeName string) (interface{}, error) {
+	if !isCastType(typeName) {
+		return nil, fmt.Errorf("unknown type: %s", typeName)
+	}
+	if value == nil {
+		return nil, nil
+	}
 
 	switch strings.ToLower(typeName) {
 	case "string":
-		result, err := valueToString(value)
-		if err != nil {
-			return nil, nil
+		return valueToString(value)
+	case "number", "float", "double":
+		switch val := value.(type) {
+		case bool:
+			if val {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to %s", val, strings.ToUpper(typeName))
+			}
+			return f, nil
 		}
-		return result, nil
-	case "number":
-		result, err := valueToNumber(value)
-		if err != nil {
-			return nil, nil
+		return valueToNumber(value)
+	case "int", "integer":
+		return valueToInt(value)
+	case "bool", "boolean":
+		return valueToBool(value)
+	default:
+		return parseDate(value)
+	}
+}
+
+// valueToInt converts a value to int64. Floats are rounded to the nearest
+// integer and strings must hold a number.
+func valueToInt(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	case uint:
+		return uintToInt(uint64(val))
+	case uint64:
+		return uintToInt(val)
+	case bool:
+		if val {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case float32:
+		return floatToInt(float64(val))
+	case float64:
+		return floatToInt(val)
+	case string:
+		trimmed := strings.TrimSpace(val)
+		if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return i, nil
 		}
-		return result, nil
-	case "date":
-		result, err := parseDate(value)
-		if err != nil {
-			return nil, nil
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return floatToInt(f)
 		}
-		return result, nil
+		return nil, fmt.Errorf("cannot convert %q to INT", val)
 	default:
-		return nil, nil
+		return nil, fmt.Errorf("cannot convert %T to INT", v)
+	}
+}
+
+// uintToInt converts an unsigned integer to int64
+func uintToInt(u uint64) (interface{}, error) {
+	if u > math.MaxInt64 {
+		return nil, fmt.Errorf("cannot convert %d to INT: out of range", u)
+	}
+	return int64(u), nil
+}
+
+// floatToInt rounds a float to the nearest int64
+func floatToInt(f float64) (interface{}, error) {
+	rounded := math.Round(f)
+	if math.IsNaN(rounded) || rounded >= math.MaxInt64 || rounded < math.MinInt64 {
+		return nil, fmt.Errorf("cannot convert %v to INT: out of range", f)
+	}
+	return int64(rounded), nil
+}
+
+// valueToBool converts a value to bool. Numbers are true when non-zero and
+// strings must spell a boolean (true/false, t/f, yes/no, 1/0).
+func valueToBool(v interface{}) (interface{}, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	if f, ok := toFloat64(v); ok {
+		return f != 0, nil
+	}
+	if s, ok := v.(string); ok {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true", "t", "yes", "y", "1":
+			return true, nil
+		case "false", "f", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("cannot convert %q to BOOL", s)
 	}
+	return nil, fmt.Errorf("cannot convert %T to BOOL", v)
 }
 
 // ToStringFunc converts a value to a string
@@ -2,6 +2,7 @@ package query
 
 import (
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -30,8 +31,38 @@ func TestCastFunc(t *testing.T) {
 		{"datetime to date", []interface{}{"2023-12-25T10:30:00Z", "date"}, nil, false},
 		{"invalid date", []interface{}{"not-a-date", "date"}, nil, true},
 
+		// Integer conversions
+		{"string to int", []interface{}{" 42 ", "int"}, int64(42), false},
+		{"float to int rounds", []interface{}{2.5, "INT"}, int64(3), false},
+		{"negative float to int", []interface{}{-2.4, "integer"}, int64(-2), false},
+		{"numeric string to int", []interface{}{"7.6", "int"}, int64(8), false},
+		{"int32 to int", []interface{}{int32(5), "int"}, int64(5), false},
+		{"bool to int", []interface{}{true, "int"}, int64(1), false},
+		{"invalid int", []interface{}{"abc", "int"}, nil, true},
+		{"NaN to int", []interface{}{math.NaN(), "int"}, nil, true},
+		{"out of range to int", []interface{}{uint64(math.MaxUint64), "int"}, nil, true},
+
+		// Float conversions
+		{"int to float", []interface{}{int64(3), "float"}, 3.0, false},
+		{"string to float", []interface{}{"2.5", "FLOAT"}, 2.5, false},
+		{"bool to float", []interface{}{false, "double"}, 0.0, false},
+		{"invalid float", []interface{}{"abc", "float"}, nil, true},
+
+		// Bool conversions
+		{"string to bool", []interface{}{"TRUE", "bool"}, true, false},
+		{"yes to bool", []interface{}{"yes", "boolean"}, true, false},
+		{"zero string to bool", []interface{}{"0", "bool"}, false, false},
+		{"number to bool", []interface{}{2.5, "bool"}, true, false},
+		{"zero to bool", []interface{}{int64(0), "bool"}, false, false},
+		{"invalid bool", []interface{}{"maybe", "bool"}, nil, true},
+
+		// NULL stays NULL
+		{"null to int", []interface{}{nil, "int"}, nil, false},
+		{"null to string", []interface{}{nil, "string"}, nil, false},
+
 		// Unknown type
 		{"unknown type", []interface{}{"value", "unknown"}, nil, true},
+		{"unknown type with null", []interface{}{nil, "unknown"}, nil, true},
 
 		// Case insensitivity
 		{"uppercase STRING", []interface{}{42, "STRING"}, "42", false},
@@ -53,6 +84,105 @@ func TestCastFunc(t *testing.T) {
 	}
 }
 
+func TestParser_CastAs(t *testing.T) {
+	row := map[string]interface{}{"id": int64(42), "price": 19.99, "zip": "02134", "flag": "yes", "missing": nil}
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "int to string", sql: "select CAST(id AS STRING) from t.parquet", want: "42"},
+		{name: "string to int", sql: "select CAST(zip AS INT) from t.parquet", want: int64(2134)},
+		{name: "int to float", sql: "select CAST(id as float) from t.parquet", want: 42.0},
+		{name: "string to bool", sql: "select cast(flag AS Bool) from t.parquet", want: true},
+		{name: "null", sql: "select CAST(missing AS INT) from t.parquet", want: nil},
+		{name: "round trip int through string", sql: "select CAST(CAST(id AS STRING) AS INT) from t.parquet", want: int64(42)},
+		{name: "round trip float through string", sql: "select CAST(CAST(price AS STRING) AS FLOAT) from t.parquet", want: 19.99},
+		{name: "round trip bool through string", sql: "select CAST(CAST(flag AS BOOL) AS STRING) from t.parquet", want: "true"},
+		{name: "expression argument", sql: "select CAST(price * 2 AS INT) from t.parquet", want: int64(40)},
+		{name: "old two-argument form", sql: "select CAST(id, 'string') from t.parquet", want: "42"},
+		{name: "try cast", sql: "select TRY_CAST('abc' AS INT) from t.parquet", want: nil},
+		{name: "impossible conversion", sql: "select CAST('abc' AS INT) from t.parquet", wantErr: `cannot convert "abc" to INT`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := q.SelectList[0].Expr.EvaluateSelect(row)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateSelect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_CastInWhere(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(30), "zip": "02134"},
+		{"id": int64(42), "zip": "10001"},
+	}
+
+	tests := []struct {
+		where string
+		want  int64
+	}{
+		{where: "id = CAST('30' AS INT)", want: 30},
+		{where: "CAST(zip AS INT) = CAST('10001' AS INT)", want: 42},
+		{where: "zip = CAST(10001 AS STRING)", want: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			q, err := Parse("select id from t.parquet where " + tt.where)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			result, err := ExecuteOnRows(q, rows)
+			if err != nil {
+				t.Fatalf("ExecuteOnRows() error = %v", err)
+			}
+			if len(result) != 1 || result[0]["id"] != tt.want {
+				t.Errorf("got %v, want one row with id %d", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_CastAsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr string
+	}{
+		{name: "unknown type", sql: "select CAST(id AS BLOB) from t.parquet", wantErr: "unknown type BLOB"},
+		{name: "missing type", sql: "select CAST(id AS) from t.parquet", wantErr: "expected type name"},
+		{name: "AS in other function", sql: "select UPPER(name AS STRING) from t.parquet", wantErr: "expected ')'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.sql)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestTryCastFunc(t *testing.T) {
 	fn := &TryCastFunc{}
 	tests := []struct {
@@ -9,37 +9,49 @@ import (
 
 // Date/Time Functions
 
-// Helper to parse date strings
+// dateLayouts are the date and timestamp formats accepted in strings.
+// RFC 3339 also accepts fractional seconds.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// Helper to parse date strings. time.Time values are returned as-is.
 func parseDate(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t != nil {
+			return *t, nil
+		}
+	}
+
 	str, err := valueToString(v)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	layouts := []string{
-		time.RFC3339,
-		"2006-01-02",
-		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05",
-	}
-
-	for _, layout := range layouts {
+	for _, layout := range dateLayouts {
 		if t, err := time.Parse(layout, str); err == nil {
 			return t, nil
 		}
 	}
 
-	return time.Time{}, fmt.Errorf("cannot parse date: %s", str)
+	return time.Time{}, fmt.Errorf("cannot parse date %q (expected %s, %s, %s or %s)", str,
+		dateLayouts[0], dateLayouts[1], dateLayouts[2], dateLayouts[3])
 }
 
-// NowFunc returns the current timestamp
+// NowFunc returns the current timestamp as a time.Time
 type NowFunc struct{}
 
 func (f *NowFunc) Name() string  { return "NOW" }
 func (f *NowFunc) MinArity() int { return 0 }
 func (f *NowFunc) MaxArity() int { return 0 }
 func (f *NowFunc) Evaluate(args []interface{}) (interface{}, error) {
-	return time.Now().Format(time.RFC3339), nil
+	return time.Now(), nil
 }
 
 // CurrentDateFunc returns the current date
@@ -62,7 +74,8 @@ func (f *CurrentTimeFunc) Evaluate(args []interface{}) (interface{}, error) {
 	return time.Now().Format("15:04:05"), nil
 }
 
-// DateTruncFunc truncates a date to the specified unit
+// DateTruncFunc truncates a date to the start of the specified unit,
+// returning a time.Time. Weeks start on Monday, as ISO weeks do.
 type DateTruncFunc struct{}
 
 func (f *DateTruncFunc) Name() string  { return "DATE_TRUNC" }
@@ -74,20 +87,34 @@ func (f *DateTruncFunc) Evaluate(args []interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("DATE_TRUNC: unit: %w", err)
 	}
 
+	if args[1] == nil {
+		return nil, nil
+	}
 	date, err := parseDate(args[1])
 	if err != nil {
 		return nil, fmt.Errorf("DATE_TRUNC: %w", err)
 	}
 
+	year, month, day := date.Date()
+	loc := date.Location()
 	switch strings.ToLower(unit) {
 	case "year":
-		return time.Date(date.Year(), 1, 1, 0, 0, 0, 0, date.Location()).Format(time.RFC3339), nil
+		return time.Date(year, 1, 1, 0, 0, 0, 0, loc), nil
+	case "quarter":
+		return time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, loc), nil
 	case "month":
-		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()).Format(time.RFC3339), nil
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc), nil
+	case "week":
+		daysSinceMonday := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, loc), nil
 	case "day":
-		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).Format(time.RFC3339), nil
+		return time.Date(year, month, day, 0, 0, 0, 0, loc), nil
 	case "hour":
-		return time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), 0, 0, 0, date.Location()).Format(time.RFC3339), nil
+		return time.Date(year, month, day, date.Hour(), 0, 0, 0, loc), nil
+	case "minute":
+		return time.Date(year, month, day, date.Hour(), date.Minute(), 0, 0, loc), nil
+	case "second":
+		return time.Date(year, month, day, date.Hour(), date.Minute(), date.Second(), 0, loc), nil
 	default:
 		return nil, fmt.Errorf("DATE_TRUNC: invalid unit: %s", unit)
 	}
@@ -128,6 +155,54 @@ func (f *DatePartFunc) Evaluate(args []interface{}) (interface{}, error) {
 	}
 }
 
+// extractFields lists the fields supported by EXTRACT(field FROM expr)
+var extractFields = map[string]bool{
+	"YEAR": true, "QUARTER": true, "MONTH": true, "WEEK": true, "DAY": true,
+	"HOUR": true, "MINUTE": true, "SECOND": true, "MILLISECOND": true,
+	"DOW": true, "ISODOW": true, "DOY": true, "EPOCH": true,
+}
+
+// extractField returns a field of t. DOW counts from Sunday (0) to
+// Saturday (6), ISODOW from Monday (1) to Sunday (7), and WEEK is the ISO
+// week number. EPOCH is the number of seconds since 1970-01-01 UTC as a
+// float64; every other field is an int64.
+func extractField(field string, t time.Time) (interface{}, error) {
+	switch strings.ToUpper(field) {
+	case "YEAR":
+		return int64(t.Year()), nil
+	case "QUARTER":
+		return int64((t.Month()-1)/3 + 1), nil
+	case "MONTH":
+		return int64(t.Month()), nil
+	case "WEEK":
+		_, week := t.ISOWeek()
+		return int64(week), nil
+	case "DAY":
+		return int64(t.Day()), nil
+	case "HOUR":
+		return int64(t.Hour()), nil
+	case "MINUTE":
+		return int64(t.Minute()), nil
+	case "SECOND":
+		return int64(t.Second()), nil
+	case "MILLISECOND":
+		return int64(t.Nanosecond() / int(time.Millisecond)), nil
+	case "DOW":
+		return int64(t.Weekday()), nil
+	case "ISODOW":
+		if t.Weekday() == time.Sunday {
+			return int64(7), nil
+		}
+		return int64(t.Weekday()), nil
+	case "DOY":
+		return int64(t.YearDay()), nil
+	case "EPOCH":
+		return float64(t.Unix()) + float64(t.Nanosecond())/float64(time.Second), nil
+	default:
+		return nil, fmt.Errorf("EXTRACT: unsupported field: %s", field)
+	}
+}
+
 // DateAddFunc adds an interval to a date
 type DateAddFunc struct{}
 
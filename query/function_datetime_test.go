@@ -2,6 +2,7 @@ package query
 
 import (
 	"testing"
+	"time"
 )
 
 func TestDateNowFunc(t *testing.T) {
@@ -10,13 +11,12 @@ func TestDateNowFunc(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Just verify it returns a non-empty string in RFC3339 format
-	if got == "" {
-		t.Errorf("NOW() returned empty string")
+	now, ok := got.(time.Time)
+	if !ok {
+		t.Fatalf("NOW() should return time.Time, got %T", got)
 	}
-	// Verify it can be parsed as RFC3339
-	if _, ok := got.(string); !ok {
-		t.Errorf("NOW() should return string, got %T", got)
+	if d := time.Since(now); d < 0 || d > time.Minute {
+		t.Errorf("NOW() = %v, not close to the current time", now)
 	}
 }
 
@@ -77,27 +77,45 @@ func TestDateParseDate(t *testing.T) {
 
 func TestDateTruncFunc(t *testing.T) {
 	fn := &DateTruncFunc{}
+	// Thursday 2024-08-15 10:30:45.5 UTC
+	ts := time.Date(2024, 8, 15, 10, 30, 45, 500000000, time.UTC)
 	tests := []struct {
 		name    string
 		args    []interface{}
+		want    interface{}
 		wantErr bool
 	}{
-		{"year", []interface{}{"year", "2023-12-25T10:30:45Z"}, false},
-		{"month", []interface{}{"month", "2023-12-25T10:30:45Z"}, false},
-		{"day", []interface{}{"day", "2023-12-25T10:30:45Z"}, false},
-		{"hour", []interface{}{"hour", "2023-12-25T10:30:45Z"}, false},
-		{"invalid unit", []interface{}{"week", "2023-12-25T10:30:45Z"}, true},
-		{"invalid date", []interface{}{"day", "invalid"}, true},
+		{"year", []interface{}{"year", ts}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"quarter", []interface{}{"quarter", ts}, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), false},
+		{"month", []interface{}{"month", ts}, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), false},
+		{"week starts on monday", []interface{}{"week", ts}, time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC), false},
+		{"week of a sunday", []interface{}{"week", "2024-09-01"}, time.Date(2024, 8, 26, 0, 0, 0, 0, time.UTC), false},
+		{"week across a year", []interface{}{"week", "2025-01-02"}, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), false},
+		{"day", []interface{}{"day", ts}, time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC), false},
+		{"hour", []interface{}{"hour", ts}, time.Date(2024, 8, 15, 10, 0, 0, 0, time.UTC), false},
+		{"minute", []interface{}{"minute", ts}, time.Date(2024, 8, 15, 10, 30, 0, 0, time.UTC), false},
+		{"second", []interface{}{"second", ts}, time.Date(2024, 8, 15, 10, 30, 45, 0, time.UTC), false},
+		{"uppercase unit", []interface{}{"MONTH", ts}, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), false},
+		{"date string", []interface{}{"month", "2023-12-25T10:30:45Z"}, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"null", []interface{}{"day", nil}, nil, false},
+		{"invalid unit", []interface{}{"fortnight", ts}, nil, true},
+		{"invalid date", []interface{}{"day", "invalid"}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := fn.Evaluate(tt.args)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("DATE_TRUNC() error = %v, wantErr %v", err, tt.wantErr)
+				t.Fatalf("DATE_TRUNC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
 				return
 			}
-			if !tt.wantErr && got == "" {
-				t.Errorf("DATE_TRUNC() returned empty string")
+			if want, ok := tt.want.(time.Time); ok {
+				if gotTime, isTime := got.(time.Time); !isTime || !gotTime.Equal(want) {
+					t.Errorf("DATE_TRUNC() = %v, want %v", got, want)
+				}
+			} else if got != tt.want {
+				t.Errorf("DATE_TRUNC() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -134,6 +152,93 @@ func TestDatePartFunc(t *testing.T) {
 	}
 }
 
+func TestExtractExpr(t *testing.T) {
+	// 2023-12-25 was a Monday
+	ts := time.Date(2023, 12, 25, 10, 30, 45, 250*int(time.Millisecond), time.UTC)
+	sunday := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	row := map[string]interface{}{"ts": ts, "sunday": sunday, "str": "2024-03-01", "missing": nil}
+
+	tests := []struct {
+		name    string
+		field   string
+		column  string
+		want    interface{}
+		wantErr bool
+	}{
+		{"year", "YEAR", "ts", int64(2023), false},
+		{"quarter", "QUARTER", "ts", int64(4), false},
+		{"month", "MONTH", "ts", int64(12), false},
+		{"day", "DAY", "ts", int64(25), false},
+		{"hour", "HOUR", "ts", int64(10), false},
+		{"minute", "MINUTE", "ts", int64(30), false},
+		{"second", "SECOND", "ts", int64(45), false},
+		{"millisecond", "MILLISECOND", "ts", int64(250), false},
+		{"dow monday", "DOW", "ts", int64(1), false},
+		{"dow sunday", "DOW", "sunday", int64(0), false},
+		{"isodow sunday", "ISODOW", "sunday", int64(7), false},
+		{"doy", "DOY", "ts", int64(359), false},
+		{"iso week", "WEEK", "sunday", int64(52), false},
+		{"epoch", "EPOCH", "ts", float64(1703500245.25), false},
+		{"epoch of date", "EPOCH", "sunday", float64(1703980800), false},
+		{"date string", "MONTH", "str", int64(3), false},
+		{"null", "YEAR", "missing", nil, false},
+		{"unsupported field", "CENTURY", "ts", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := &ExtractExpr{Field: tt.field, Expr: &ColumnRef{Column: tt.column}}
+			got, err := expr.EvaluateSelect(row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EXTRACT(%s) error = %v, wantErr %v", tt.field, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("EXTRACT(%s) = %v (%T), want %v (%T)", tt.field, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Extract(t *testing.T) {
+	ts := time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC) // Thursday
+	row := map[string]interface{}{"created_at": ts}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"year", "SELECT EXTRACT(YEAR FROM created_at) FROM t", int64(2024), false},
+		{"lowercase field", "SELECT extract(dow from created_at) FROM t", int64(4), false},
+		{"epoch", "SELECT EXTRACT(EPOCH FROM created_at) FROM t", float64(ts.Unix()), false},
+		{"string literal", "SELECT EXTRACT(DAY FROM '2024-02-29') FROM t", int64(29), false},
+		{"function call form", "SELECT EXTRACT('year', created_at) FROM t", int64(2024), false},
+		{"function call form with identifier", "SELECT EXTRACT(hour, created_at) FROM t", int64(8), false},
+		{"unknown quoted field", "SELECT EXTRACT('fortnight', created_at) FROM t", nil, true},
+		{"unknown field", "SELECT EXTRACT(FORTNIGHT FROM created_at) FROM t", nil, true},
+		{"missing FROM", "SELECT EXTRACT(YEAR created_at) FROM t", nil, true},
+		{"missing paren", "SELECT EXTRACT(YEAR FROM created_at FROM t", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := q.SelectList[0].Expr.EvaluateSelect(row)
+			if err != nil {
+				t.Fatalf("EvaluateSelect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateSelect() = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
 func TestDateAddFunc(t *testing.T) {
 	fn := &DateAddFunc{}
 	tests := []struct {
@@ -143,9 +143,72 @@ func (f *PowFunc) Evaluate(args []interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("POW: exponent: %w", err)
 	}
 
+	if x < 0 && y != math.Trunc(y) {
+		return nil, fmt.Errorf("POW: negative base with fractional exponent")
+	}
+	if x == 0 && y < 0 {
+		return nil, fmt.Errorf("POW: zero base with negative exponent")
+	}
 	return math.Pow(x, y), nil
 }
 
+// ExpFunc returns e raised to the power of x
+type ExpFunc struct{}
+
+func (f *ExpFunc) Name() string  { return "EXP" }
+func (f *ExpFunc) MinArity() int { return 1 }
+func (f *ExpFunc) MaxArity() int { return 1 }
+func (f *ExpFunc) Evaluate(args []interface{}) (interface{}, error) {
+	num, err := valueToNumber(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("EXP: %w", err)
+	}
+	return math.Exp(num), nil
+}
+
+// LogFunc returns the natural logarithm
+type LogFunc struct{}
+
+func (f *LogFunc) Name() string  { return "LOG" }
+func (f *LogFunc) MinArity() int { return 1 }
+func (f *LogFunc) MaxArity() int { return 1 }
+func (f *LogFunc) Evaluate(args []interface{}) (interface{}, error) {
+	num, err := logArgument("LOG", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Log(num), nil
+}
+
+// Log10Func returns the base 10 logarithm
+type Log10Func struct{}
+
+func (f *Log10Func) Name() string  { return "LOG10" }
+func (f *Log10Func) MinArity() int { return 1 }
+func (f *Log10Func) MaxArity() int { return 1 }
+func (f *Log10Func) Evaluate(args []interface{}) (interface{}, error) {
+	num, err := logArgument("LOG10", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Log10(num), nil
+}
+
+// logArgument converts a logarithm's argument, which must be positive
+func logArgument(name string, v interface{}) (float64, error) {
+	num, err := valueToNumber(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if math.IsNaN(num) {
+		return 0, fmt.Errorf("%s: invalid input (NaN)", name)
+	}
+	if num <= 0 {
+		return 0, fmt.Errorf("%s: non-positive number", name)
+	}
+	return num, nil
+}
+
 // SignFunc returns the sign of a number (-1, 0, or 1)
 type SignFunc struct{}
 
@@ -2,6 +2,7 @@ package query
 
 import (
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -170,6 +171,7 @@ func TestSqrtFunc(t *testing.T) {
 		{"positive", []interface{}{int64(16)}, 4.0, false},
 		{"zero", []interface{}{int64(0)}, 0.0, false},
 		{"negative", []interface{}{int64(-1)}, 0, true},
+		{"NaN", []interface{}{math.NaN()}, 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -187,26 +189,97 @@ func TestSqrtFunc(t *testing.T) {
 func TestPowFunc(t *testing.T) {
 	fn := &PowFunc{}
 	tests := []struct {
-		name string
-		args []interface{}
-		want float64
+		name    string
+		args    []interface{}
+		want    float64
+		wantErr bool
 	}{
-		{"square", []interface{}{int64(2), int64(3)}, 8.0},
-		{"cube", []interface{}{int64(3), int64(2)}, 9.0},
+		{"square", []interface{}{int64(2), int64(3)}, 8.0, false},
+		{"cube", []interface{}{int64(3), int64(2)}, 9.0, false},
+		{"negative exponent", []interface{}{int64(2), int64(-1)}, 0.5, false},
+		{"fractional exponent", []interface{}{int64(9), 0.5}, 3.0, false},
+		{"negative base with integer exponent", []interface{}{int64(-2), int64(3)}, -8.0, false},
+		{"negative base with fractional exponent", []interface{}{int64(-8), 0.5}, 0, true},
+		{"zero to a negative power", []interface{}{int64(0), int64(-2)}, 0, true},
+		{"non-numeric base", []interface{}{"x", int64(2)}, 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := fn.Evaluate(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpLogFuncs(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      Function
+		arg     interface{}
+		want    float64
+		wantErr string
+	}{
+		{"EXP of zero", &ExpFunc{}, int64(0), 1.0, ""},
+		{"EXP of one", &ExpFunc{}, int64(1), math.E, ""},
+		{"EXP of negative", &ExpFunc{}, int64(-1), 1 / math.E, ""},
+		{"LOG of e", &LogFunc{}, math.E, 1.0, ""},
+		{"LOG of one", &LogFunc{}, int64(1), 0.0, ""},
+		{"LOG of zero", &LogFunc{}, int64(0), 0, "LOG: non-positive number"},
+		{"LOG of negative", &LogFunc{}, -5.0, 0, "LOG: non-positive number"},
+		{"LOG10 of 1000", &Log10Func{}, int32(1000), 3.0, ""},
+		{"LOG10 of fraction", &Log10Func{}, 0.01, -2.0, ""},
+		{"LOG10 of zero", &Log10Func{}, 0.0, 0, "LOG10: non-positive number"},
+		{"LOG10 of NaN", &Log10Func{}, math.NaN(), 0, "LOG10: invalid input"},
+		{"LOG of string", &LogFunc{}, "abc", 0, "LOG:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn.Evaluate([]interface{}{tt.arg})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if got != tt.want {
+			if math.Abs(got.(float64)-tt.want) > 1e-12 {
 				t.Errorf("got %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestPowArity(t *testing.T) {
+	row := map[string]interface{}{"x": int64(2)}
+	tests := []struct {
+		sql     string
+		wantErr string
+	}{
+		{"select POW(x) from t.parquet", "expected at least 2 arguments, got 1"},
+		{"select POW(x, 2, 3) from t.parquet", "expected at most 2 arguments, got 3"},
+		{"select POW() from t.parquet", "expected at least 2 arguments, got 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = q.SelectList[0].Expr.EvaluateSelect(row)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestSignFunc(t *testing.T) {
 	fn := &SignFunc{}
 	tests := []struct {
@@ -389,6 +462,9 @@ func TestMinMaxArityMathFunctions(t *testing.T) {
 		{"MOD", &ModFunc{}, 2, 2},
 		{"SQRT", &SqrtFunc{}, 1, 1},
 		{"POW", &PowFunc{}, 2, 2},
+		{"EXP", &ExpFunc{}, 1, 1},
+		{"LOG", &LogFunc{}, 1, 1},
+		{"LOG10", &Log10Func{}, 1, 1},
 		{"SIGN", &SignFunc{}, 1, 1},
 		{"TRUNC", &TruncFunc{}, 1, 1},
 		{"RANDOM", &RandomFunc{}, 0, 0},
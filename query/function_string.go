@@ -2,7 +2,10 @@ package query
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 // String Functions
@@ -53,6 +56,36 @@ func (f *ConcatFunc) Evaluate(args []interface{}) (interface{}, error) {
 	return builder.String(), nil
 }
 
+// ConcatWSFunc concatenates strings with a separator between them, skipping nulls.
+// A null separator makes the result null.
+type ConcatWSFunc struct{}
+
+func (f *ConcatWSFunc) Name() string  { return "CONCAT_WS" }
+func (f *ConcatWSFunc) MinArity() int { return 2 }
+func (f *ConcatWSFunc) MaxArity() int { return -1 } // variadic
+func (f *ConcatWSFunc) Evaluate(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	sep, err := valueToString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("CONCAT_WS: separator: %w", err)
+	}
+
+	parts := make([]string, 0, len(args)-1)
+	for i, arg := range args[1:] {
+		if arg == nil {
+			continue
+		}
+		str, err := valueToString(arg)
+		if err != nil {
+			return nil, fmt.Errorf("CONCAT_WS: argument %d: %w", i+2, err)
+		}
+		parts = append(parts, str)
+	}
+	return strings.Join(parts, sep), nil
+}
+
 // LengthFunc returns the length of a string
 type LengthFunc struct{}
 
@@ -67,49 +100,76 @@ func (f *LengthFunc) Evaluate(args []interface{}) (interface{}, error) {
 	return int64(len(str)), nil
 }
 
-// TrimFunc trims whitespace from both ends of a string
+// trimArgs returns the string to trim and the optional set of characters to
+// trim from it. ok is false when no character set was given.
+func trimArgs(name string, args []interface{}) (str, chars string, ok bool, err error) {
+	str, err = valueToString(args[0])
+	if err != nil {
+		return "", "", false, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(args) < 2 {
+		return str, "", false, nil
+	}
+	chars, err = valueToString(args[1])
+	if err != nil {
+		return "", "", false, fmt.Errorf("%s: characters: %w", name, err)
+	}
+	return str, chars, true, nil
+}
+
+// TrimFunc trims whitespace, or any of the given characters, from both ends of a string
 type TrimFunc struct{}
 
 func (f *TrimFunc) Name() string  { return "TRIM" }
 func (f *TrimFunc) MinArity() int { return 1 }
-func (f *TrimFunc) MaxArity() int { return 1 }
+func (f *TrimFunc) MaxArity() int { return 2 }
 func (f *TrimFunc) Evaluate(args []interface{}) (interface{}, error) {
-	str, err := valueToString(args[0])
+	str, chars, ok, err := trimArgs("TRIM", args)
 	if err != nil {
-		return nil, fmt.Errorf("TRIM: %w", err)
+		return nil, err
+	}
+	if ok {
+		return strings.Trim(str, chars), nil
 	}
 	return strings.TrimSpace(str), nil
 }
 
-// LTrimFunc trims whitespace from the left side of a string
+// LTrimFunc trims whitespace, or any of the given characters, from the left side of a string
 type LTrimFunc struct{}
 
 func (f *LTrimFunc) Name() string  { return "LTRIM" }
 func (f *LTrimFunc) MinArity() int { return 1 }
-func (f *LTrimFunc) MaxArity() int { return 1 }
+func (f *LTrimFunc) MaxArity() int { return 2 }
 func (f *LTrimFunc) Evaluate(args []interface{}) (interface{}, error) {
-	str, err := valueToString(args[0])
+	str, chars, ok, err := trimArgs("LTRIM", args)
 	if err != nil {
-		return nil, fmt.Errorf("LTRIM: %w", err)
+		return nil, err
 	}
-	return strings.TrimLeft(str, " \t\n\r"), nil
+	if !ok {
+		chars = " \t\n\r"
+	}
+	return strings.TrimLeft(str, chars), nil
 }
 
-// RTrimFunc trims whitespace from the right side of a string
+// RTrimFunc trims whitespace, or any of the given characters, from the right side of a string
 type RTrimFunc struct{}
 
 func (f *RTrimFunc) Name() string  { return "RTRIM" }
 func (f *RTrimFunc) MinArity() int { return 1 }
-func (f *RTrimFunc) MaxArity() int { return 1 }
+func (f *RTrimFunc) MaxArity() int { return 2 }
 func (f *RTrimFunc) Evaluate(args []interface{}) (interface{}, error) {
-	str, err := valueToString(args[0])
+	str, chars, ok, err := trimArgs("RTRIM", args)
 	if err != nil {
-		return nil, fmt.Errorf("RTRIM: %w", err)
+		return nil, err
 	}
-	return strings.TrimRight(str, " \t\n\r"), nil
+	if !ok {
+		chars = " \t\n\r"
+	}
+	return strings.TrimRight(str, chars), nil
 }
 
-// SubstringFunc extracts a substring (1-indexed, SQL style)
+// SubstringFunc extracts a substring (1-indexed, SQL style).
+// A negative start counts from the end of the string, so SUBSTRING(s, -3) returns the last 3 characters.
 type SubstringFunc struct{}
 
 func (f *SubstringFunc) Name() string  { return "SUBSTRING" }
@@ -128,6 +188,9 @@ func (f *SubstringFunc) Evaluate(args []interface{}) (interface{}, error) {
 	// Convert to runes to handle multibyte UTF-8 characters correctly
 	runes := []rune(str)
 	startIdx := int(start) - 1 // SQL uses 1-based indexing
+	if start < 0 {
+		startIdx = len(runes) + int(start) // Negative start counts from the end
+	}
 
 	if startIdx < 0 {
 		startIdx = 0
@@ -155,6 +218,129 @@ func (f *SubstringFunc) Evaluate(args []interface{}) (interface{}, error) {
 	return string(runes[startIdx:]), nil
 }
 
+// maxPadLength caps the length LPAD and RPAD can pad to
+const maxPadLength = 10 * 1024 * 1024
+
+// nonNegativeInt converts a length or count argument, which must be a
+// non-negative whole number
+func nonNegativeInt(v interface{}) (int, error) {
+	num, err := valueToNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	if num < 0 || num != math.Trunc(num) || num > math.MaxInt32 {
+		return 0, fmt.Errorf("must be a non-negative integer, got %v", v)
+	}
+	return int(num), nil
+}
+
+// LeftFunc returns the first n characters of a string
+type LeftFunc struct{}
+
+func (f *LeftFunc) Name() string  { return "LEFT" }
+func (f *LeftFunc) MinArity() int { return 2 }
+func (f *LeftFunc) MaxArity() int { return 2 }
+func (f *LeftFunc) Evaluate(args []interface{}) (interface{}, error) {
+	str, err := valueToString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("LEFT: %w", err)
+	}
+	n, err := nonNegativeInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("LEFT: length: %w", err)
+	}
+
+	runes := []rune(str)
+	if n > len(runes) {
+		n = len(runes)
+	}
+	return string(runes[:n]), nil
+}
+
+// RightFunc returns the last n characters of a string
+type RightFunc struct{}
+
+func (f *RightFunc) Name() string  { return "RIGHT" }
+func (f *RightFunc) MinArity() int { return 2 }
+func (f *RightFunc) MaxArity() int { return 2 }
+func (f *RightFunc) Evaluate(args []interface{}) (interface{}, error) {
+	str, err := valueToString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("RIGHT: %w", err)
+	}
+	n, err := nonNegativeInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("RIGHT: length: %w", err)
+	}
+
+	runes := []rune(str)
+	if n > len(runes) {
+		n = len(runes)
+	}
+	return string(runes[len(runes)-n:]), nil
+}
+
+// LPadFunc pads a string on the left to a length, truncating longer strings
+type LPadFunc struct{}
+
+func (f *LPadFunc) Name() string  { return "LPAD" }
+func (f *LPadFunc) MinArity() int { return 2 }
+func (f *LPadFunc) MaxArity() int { return 3 }
+func (f *LPadFunc) Evaluate(args []interface{}) (interface{}, error) {
+	return pad("LPAD", args, true)
+}
+
+// RPadFunc pads a string on the right to a length, truncating longer strings
+type RPadFunc struct{}
+
+func (f *RPadFunc) Name() string  { return "RPAD" }
+func (f *RPadFunc) MinArity() int { return 2 }
+func (f *RPadFunc) MaxArity() int { return 3 }
+func (f *RPadFunc) Evaluate(args []interface{}) (interface{}, error) {
+	return pad("RPAD", args, false)
+}
+
+// pad implements LPAD and RPAD. The pad string defaults to a space and is
+// repeated, then cut, to fill the length in characters.
+func pad(name string, args []interface{}, left bool) (interface{}, error) {
+	str, err := valueToString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	length, err := nonNegativeInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s: length: %w", name, err)
+	}
+	if length > maxPadLength {
+		return nil, fmt.Errorf("%s: length %d is too large", name, length)
+	}
+	fill := " "
+	if len(args) == 3 {
+		fill, err = valueToString(args[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: pad: %w", name, err)
+		}
+		if fill == "" {
+			return nil, fmt.Errorf("%s: pad string must not be empty", name)
+		}
+	}
+
+	runes := []rune(str)
+	if len(runes) >= length {
+		return string(runes[:length]), nil
+	}
+
+	fillRunes := []rune(fill)
+	padding := make([]rune, length-len(runes))
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+	if left {
+		return string(padding) + str, nil
+	}
+	return str + string(padding), nil
+}
+
 // ReplaceFunc replaces occurrences of a substring
 type ReplaceFunc struct{}
 
@@ -309,3 +495,242 @@ func (f *RepeatFunc) Evaluate(args []interface{}) (interface{}, error) {
 
 	return strings.Repeat(str, countInt), nil
 }
+
+// maxFormatWidth caps the width and precision of a FORMAT verb, so a format
+// string can't ask for an enormous result
+const maxFormatWidth = 1000
+
+// FormatFunc formats its arguments printf-style. It supports a safe subset of
+// Go's fmt verbs: %s, %v and %q for any value, %d, %x and %X for integers,
+// %f, %e and %g for numbers, %t for booleans and %% for a literal percent.
+// Verbs may have the flags "-", "+", "0" and " ", a width and a precision.
+// Null arguments format as empty strings; a null format string gives null.
+type FormatFunc struct{}
+
+func (f *FormatFunc) Name() string  { return "FORMAT" }
+func (f *FormatFunc) MinArity() int { return 1 }
+func (f *FormatFunc) MaxArity() int { return -1 } // variadic
+func (f *FormatFunc) Evaluate(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	format, err := valueToString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("FORMAT: format: %w", err)
+	}
+
+	var builder strings.Builder
+	next := 1
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			builder.WriteByte(format[i])
+			continue
+		}
+
+		spec, verb, end, err := parseFormatVerb(format, i)
+		if err != nil {
+			return nil, fmt.Errorf("FORMAT: %w", err)
+		}
+		i = end
+		if verb == '%' {
+			builder.WriteByte('%')
+			continue
+		}
+
+		if next >= len(args) {
+			return nil, fmt.Errorf("FORMAT: missing argument for %s", spec)
+		}
+		arg := args[next]
+		next++
+		if arg == nil {
+			continue
+		}
+
+		value, err := formatArgument(verb, arg)
+		if err != nil {
+			return nil, fmt.Errorf("FORMAT: argument %d for %s: %w", next, spec, err)
+		}
+		builder.WriteString(fmt.Sprintf(spec, value))
+	}
+
+	if next < len(args) {
+		return nil, fmt.Errorf("FORMAT: %d arguments given but the format uses %d", len(args)-1, next-1)
+	}
+	return builder.String(), nil
+}
+
+// parseFormatVerb parses the verb starting at format[start], which is '%'.
+// It returns the verb's full text, the verb letter and the index of its last byte.
+func parseFormatVerb(format string, start int) (spec string, verb byte, end int, err error) {
+	i := start + 1
+	for i < len(format) && strings.IndexByte("-+0 ", format[i]) >= 0 {
+		i++
+	}
+
+	number := func(what string) error {
+		n := 0
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			n = n*10 + int(format[i]-'0')
+			if n > maxFormatWidth {
+				return fmt.Errorf("%s in %q is larger than %d", what, format[start:i+1], maxFormatWidth)
+			}
+			i++
+		}
+		return nil
+	}
+	if err := number("width"); err != nil {
+		return "", 0, 0, err
+	}
+	if i < len(format) && format[i] == '.' {
+		i++
+		if err := number("precision"); err != nil {
+			return "", 0, 0, err
+		}
+	}
+
+	if i >= len(format) {
+		return "", 0, 0, fmt.Errorf("incomplete verb %q at end of format", format[start:])
+	}
+	verb = format[i]
+	spec = format[start : i+1]
+	if strings.IndexByte("svqdxXfegt%", verb) < 0 {
+		return "", 0, 0, fmt.Errorf("unsupported verb %q", spec)
+	}
+	if verb == '%' && i != start+1 {
+		return "", 0, 0, fmt.Errorf("unsupported verb %q", spec)
+	}
+	return spec, verb, i, nil
+}
+
+// formatArgument converts a non-null FORMAT argument to the Go type its verb expects
+func formatArgument(verb byte, arg interface{}) (interface{}, error) {
+	switch verb {
+	case 'd', 'x', 'X':
+		switch v := arg.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return v, nil
+		case float32, float64:
+			f, _ := valueToNumber(v)
+			if f != math.Trunc(f) || math.IsInf(f, 0) || f > math.MaxInt64 || f < math.MinInt64 {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int64(f), nil
+		default:
+			return nil, fmt.Errorf("expected an integer, got %T", arg)
+		}
+	case 'f', 'e', 'g':
+		if _, ok := arg.(string); ok {
+			return nil, fmt.Errorf("expected a number, got string")
+		}
+		return valueToNumber(arg)
+	case 't':
+		b, ok := arg.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a boolean, got %T", arg)
+		}
+		return b, nil
+	default:
+		return valueToString(arg)
+	}
+}
+
+// Regular Expression Functions
+
+// maxCachedRegexps bounds the compiled pattern cache. Queries use a handful of
+// patterns, so the cache is simply emptied if something builds many of them.
+const maxCachedRegexps = 1024
+
+// regexpCache holds compiled patterns, so a pattern is compiled once instead
+// of for every row it is matched against
+type regexpCache struct {
+	mu       sync.RWMutex
+	patterns map[string]*regexp.Regexp
+}
+
+// compiledRegexps is shared by the REGEXP_* functions. It can't belong to
+// an ExecutionContext: functions only see their arguments (see
+// Function.Evaluate), and most calls come through EvaluateSelect from
+// filters, aggregates, window functions and ApplySelectList, which have no
+// context, or from library callers that never create one. Sharing it is
+// safe, since it is keyed by the pattern text alone, a compiled Regexp is
+// immutable and safe for concurrent use, and access is locked. Its size is
+// bounded by maxCachedRegexps, so long-running processes don't accumulate
+// patterns.
+var compiledRegexps = &regexpCache{patterns: make(map[string]*regexp.Regexp)}
+
+// compile returns the compiled pattern, compiling and caching it on first use
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.RLock()
+	re, ok := c.patterns[pattern]
+	c.mu.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.patterns) >= maxCachedRegexps {
+		c.patterns = make(map[string]*regexp.Regexp)
+	}
+	c.patterns[pattern] = re
+	return re, nil
+}
+
+// regexpArgs converts the string and pattern arguments of a REGEXP_* function.
+// A NULL string gives ok false, so the function returns NULL.
+func regexpArgs(name string, args []interface{}) (str string, re *regexp.Regexp, ok bool, err error) {
+	if args[0] == nil {
+		return "", nil, false, nil
+	}
+	str, err = valueToString(args[0])
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%s: %w", name, err)
+	}
+	pattern, err := valueToString(args[1])
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%s: pattern: %w", name, err)
+	}
+	re, err = compiledRegexps.compile(pattern)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%s: %w", name, err)
+	}
+	return str, re, true, nil
+}
+
+// RegexpMatchFunc reports whether a string contains a match of a pattern
+type RegexpMatchFunc struct{}
+
+func (f *RegexpMatchFunc) Name() string  { return "REGEXP_MATCH" }
+func (f *RegexpMatchFunc) MinArity() int { return 2 }
+func (f *RegexpMatchFunc) MaxArity() int { return 2 }
+func (f *RegexpMatchFunc) Evaluate(args []interface{}) (interface{}, error) {
+	str, re, ok, err := regexpArgs("REGEXP_MATCH", args)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return re.MatchString(str), nil
+}
+
+// RegexpReplaceFunc replaces every match of a pattern. The replacement may
+// refer to capture groups as $1 or ${name}.
+type RegexpReplaceFunc struct{}
+
+func (f *RegexpReplaceFunc) Name() string  { return "REGEXP_REPLACE" }
+func (f *RegexpReplaceFunc) MinArity() int { return 3 }
+func (f *RegexpReplaceFunc) MaxArity() int { return 3 }
+func (f *RegexpReplaceFunc) Evaluate(args []interface{}) (interface{}, error) {
+	str, re, ok, err := regexpArgs("REGEXP_REPLACE", args)
+	if err != nil || !ok {
+		return nil, err
+	}
+	replacement, err := valueToString(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("REGEXP_REPLACE: replacement: %w", err)
+	}
+	return re.ReplaceAllString(str, replacement), nil
+}
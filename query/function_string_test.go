@@ -1,6 +1,9 @@
 package query
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -94,6 +97,84 @@ func TestConcatFunc(t *testing.T) {
 	}
 }
 
+func TestConcatWSFunc(t *testing.T) {
+	fn := &ConcatWSFunc{}
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"three strings", []interface{}{", ", "a", "b", "c"}, "a, b, c", false},
+		{"nulls are skipped", []interface{}{"-", nil, "a", nil, "b", nil}, "a-b", false},
+		{"only nulls", []interface{}{"-", nil, nil}, "", false},
+		{"single value", []interface{}{"-", "a"}, "a", false},
+		{"numbers", []interface{}{":", int64(1), 2.5, true}, "1:2.5:true", false},
+		{"empty separator", []interface{}{"", "a", "b"}, "ab", false},
+		{"null separator", []interface{}{nil, "a", "b"}, nil, false},
+		{"unsupported type", []interface{}{"-", []interface{}{"a"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fn.Evaluate(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConcatWSFunc.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ConcatWSFunc.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFunc(t *testing.T) {
+	fn := &FormatFunc{}
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"string and integer", []interface{}{"%s is %d", "Alice", int64(30)}, "Alice is 30", false},
+		{"no verbs", []interface{}{"plain"}, "plain", false},
+		{"literal percent", []interface{}{"%d%%", int64(50)}, "50%", false},
+		{"precision", []interface{}{"%.2f", 3.14159}, "3.14", false},
+		{"width and flags", []interface{}{"[%5s|%-5s|%05d|%+d]", "a", "b", int64(42), int64(7)}, "[    a|b    |00042|+7]", false},
+		{"hex", []interface{}{"%x/%X", int64(255), int64(255)}, "ff/FF", false},
+		{"whole float as integer", []interface{}{"%d", 3.0}, "3", false},
+		{"integer as float", []interface{}{"%.1f", int64(2)}, "2.0", false},
+		{"v and q", []interface{}{"%v %q", 1.5, "hi"}, `1.5 "hi"`, false},
+		{"boolean", []interface{}{"%t", true}, "true", false},
+		{"null argument", []interface{}{"[%s|%d]", nil, nil}, "[|]", false},
+		{"null format", []interface{}{nil, "a"}, nil, false},
+		{"missing argument", []interface{}{"%s %s", "a"}, nil, true},
+		{"extra argument", []interface{}{"%s", "a", "b"}, nil, true},
+		{"unsupported verb", []interface{}{"%p", "a"}, nil, true},
+		{"incomplete verb", []interface{}{"100%"}, nil, true},
+		{"fractional integer", []interface{}{"%d", 2.5}, nil, true},
+		{"string as integer", []interface{}{"%d", "12"}, nil, true},
+		{"string as float", []interface{}{"%f", "1.5"}, nil, true},
+		{"width too large", []interface{}{"%100000s", "a"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fn.Evaluate(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatFunc.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FormatFunc.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLengthFunc(t *testing.T) {
 	fn := &LengthFunc{}
 
@@ -138,6 +219,10 @@ func TestTrimFunc(t *testing.T) {
 		{"no spaces", []interface{}{"hello"}, "hello", false},
 		{"tabs and newlines", []interface{}{"\t\nhello\n\t"}, "hello", false},
 		{"only spaces", []interface{}{"   "}, "", false},
+		{"custom characters", []interface{}{"xxhelloyx", "xy"}, "hello", false},
+		{"custom keeps whitespace", []interface{}{" -hello- ", "-"}, " -hello- ", false},
+		{"multibyte characters", []interface{}{"«hello»", "«»"}, "hello", false},
+		{"empty character set", []interface{}{"  hello  ", ""}, "  hello  ", false},
 	}
 
 	for _, tt := range tests {
@@ -164,6 +249,9 @@ func TestLTrimFunc(t *testing.T) {
 		{"leading spaces", []interface{}{"  hello"}, "hello"},
 		{"no spaces", []interface{}{"hello"}, "hello"},
 		{"trailing only", []interface{}{"hello  "}, "hello  "},
+		{"custom characters", []interface{}{"000123", "0"}, "123"},
+		{"custom keeps trailing", []interface{}{"--a--", "-"}, "a--"},
+		{"multibyte characters", []interface{}{"ééclair", "é"}, "clair"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -188,6 +276,9 @@ func TestRTrimFunc(t *testing.T) {
 		{"trailing spaces", []interface{}{"hello  "}, "hello"},
 		{"no spaces", []interface{}{"hello"}, "hello"},
 		{"leading only", []interface{}{"  hello"}, "  hello"},
+		{"custom characters", []interface{}{"1.500", "0"}, "1.5"},
+		{"custom set", []interface{}{"path/to//", "/"}, "path/to"},
+		{"multibyte characters", []interface{}{"done✓✓", "✓"}, "done"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -202,6 +293,49 @@ func TestRTrimFunc(t *testing.T) {
 	}
 }
 
+func TestParser_TrimSyntax(t *testing.T) {
+	row := map[string]interface{}{"code": "xx-A1-xx", "padded": "  hi  ", "leading": "  col  "}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    interface{}
+		wantErr bool
+	}{
+		{"plain", "TRIM(padded)", "hi", false},
+		{"two-arg form", "TRIM(code, 'x-')", "A1", false},
+		{"ltrim two-arg form", "LTRIM(code, 'x')", "-A1-xx", false},
+		{"chars from", "TRIM('x' FROM code)", "-A1-", false},
+		{"both", "TRIM(BOTH 'x-' FROM code)", "A1", false},
+		{"leading", "TRIM(LEADING 'x' FROM code)", "-A1-xx", false},
+		{"trailing", "trim(trailing 'x' from code)", "xx-A1-", false},
+		{"leading whitespace", "TRIM(LEADING FROM padded)", "hi  ", false},
+		{"whitespace from", "TRIM(FROM padded)", "hi", false},
+		{"column named like keyword", "TRIM(leading)", "col", false},
+		{"missing FROM", "TRIM(LEADING 'x' code)", nil, true},
+		{"missing string", "TRIM(BOTH 'x' FROM)", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("SELECT " + tt.expr + " FROM t.parquet")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := q.SelectList[0].Expr.EvaluateSelect(row)
+			if err != nil {
+				t.Fatalf("EvaluateSelect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateSelect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSubstringFunc(t *testing.T) {
 	fn := &SubstringFunc{}
 	tests := []struct {
@@ -213,6 +347,11 @@ func TestSubstringFunc(t *testing.T) {
 		{"from middle", []interface{}{"hello", int64(2), int64(3)}, "ell"},
 		{"no length", []interface{}{"hello", int64(3)}, "llo"},
 		{"past end", []interface{}{"hello", int64(3), int64(10)}, "llo"},
+		{"negative start", []interface{}{"hello", int64(-3)}, "llo"},
+		{"negative start with length", []interface{}{"hello", int64(-3), int64(2)}, "ll"},
+		{"negative start before beginning", []interface{}{"hello", int64(-10)}, "hello"},
+		{"start past end", []interface{}{"hello", int64(10)}, ""},
+		{"negative start multibyte", []interface{}{"héllo wörld", int64(-5), int64(2)}, "wö"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -227,6 +366,123 @@ func TestSubstringFunc(t *testing.T) {
 	}
 }
 
+func TestLeftRightFuncs(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      Function
+		args    []interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"LEFT", &LeftFunc{}, []interface{}{"hello", int64(2)}, "he", ""},
+		{"LEFT zero", &LeftFunc{}, []interface{}{"hello", int64(0)}, "", ""},
+		{"LEFT past end", &LeftFunc{}, []interface{}{"hello", int64(10)}, "hello", ""},
+		{"LEFT multibyte", &LeftFunc{}, []interface{}{"日本語テキスト", int64(3)}, "日本語", ""},
+		{"LEFT float length", &LeftFunc{}, []interface{}{"hello", 2.0}, "he", ""},
+		{"LEFT negative", &LeftFunc{}, []interface{}{"hello", int64(-1)}, nil, "LEFT: length: must be a non-negative integer"},
+		{"LEFT fractional", &LeftFunc{}, []interface{}{"hello", 1.5}, nil, "LEFT: length: must be a non-negative integer"},
+		{"LEFT non-numeric", &LeftFunc{}, []interface{}{"hello", "x"}, nil, "LEFT: length:"},
+		{"RIGHT", &RightFunc{}, []interface{}{"hello", int64(3)}, "llo", ""},
+		{"RIGHT past end", &RightFunc{}, []interface{}{"hello", int64(10)}, "hello", ""},
+		{"RIGHT multibyte", &RightFunc{}, []interface{}{"héllo wörld", int64(4)}, "örld", ""},
+		{"RIGHT number", &RightFunc{}, []interface{}{int64(12345), int64(2)}, "45", ""},
+		{"RIGHT negative", &RightFunc{}, []interface{}{"hello", int64(-2)}, nil, "RIGHT: length: must be a non-negative integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn.Evaluate(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPadFuncs(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      Function
+		args    []interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"LPAD zeros", &LPadFunc{}, []interface{}{"42", int64(5), "0"}, "00042", ""},
+		{"LPAD number", &LPadFunc{}, []interface{}{int64(7), int64(3), "0"}, "007", ""},
+		{"LPAD default space", &LPadFunc{}, []interface{}{"ab", int64(4)}, "  ab", ""},
+		{"LPAD repeats and cuts pad", &LPadFunc{}, []interface{}{"x", int64(6), "ab"}, "ababax", ""},
+		{"LPAD truncates", &LPadFunc{}, []interface{}{"hello", int64(3), "*"}, "hel", ""},
+		{"LPAD exact length", &LPadFunc{}, []interface{}{"abc", int64(3), "*"}, "abc", ""},
+		{"LPAD multibyte string", &LPadFunc{}, []interface{}{"日本", int64(4), "*"}, "**日本", ""},
+		{"LPAD multibyte pad", &LPadFunc{}, []interface{}{"a", int64(4), "éü"}, "éüéa", ""},
+		{"RPAD", &RPadFunc{}, []interface{}{"ab", int64(5), "-"}, "ab---", ""},
+		{"RPAD multibyte", &RPadFunc{}, []interface{}{"wörld", int64(7), "·"}, "wörld··", ""},
+		{"RPAD truncates multibyte", &RPadFunc{}, []interface{}{"héllo", int64(2), "x"}, "hé", ""},
+		{"RPAD zero length", &RPadFunc{}, []interface{}{"abc", int64(0), "x"}, "", ""},
+		{"LPAD empty pad", &LPadFunc{}, []interface{}{"ab", int64(5), ""}, nil, "LPAD: pad string must not be empty"},
+		{"RPAD empty pad", &RPadFunc{}, []interface{}{"ab", int64(5), ""}, nil, "RPAD: pad string must not be empty"},
+		{"LPAD negative length", &LPadFunc{}, []interface{}{"ab", int64(-1), "x"}, nil, "LPAD: length: must be a non-negative integer"},
+		{"RPAD fractional length", &RPadFunc{}, []interface{}{"ab", 2.5, "x"}, nil, "RPAD: length: must be a non-negative integer"},
+		{"LPAD huge length", &LPadFunc{}, []interface{}{"ab", int64(1 << 30), "x"}, nil, "too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn.Evaluate(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_LeftRightFunctions(t *testing.T) {
+	row := map[string]interface{}{"code": "AB-1234", "id": int64(7)}
+
+	q, err := Parse("select LEFT(code, 2), RIGHT (code, 4), LPAD(RIGHT(code, 1), 3, '·') from t.parquet where LEFT(code, 2) = 'AB'")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []interface{}{"AB", "1234", "··4"}
+	for i, item := range q.SelectList {
+		got, err := item.Expr.EvaluateSelect(row)
+		if err != nil {
+			t.Fatalf("EvaluateSelect() error = %v", err)
+		}
+		if got != want[i] {
+			t.Errorf("select item %d: got %q, want %q", i, got, want[i])
+		}
+	}
+	if match, err := q.Filter.Evaluate(row); err != nil || !match {
+		t.Errorf("expected WHERE LEFT(code, 2) = 'AB' to match, got %v, %v", match, err)
+	}
+
+	// LEFT and RIGHT still start joins
+	q, err = Parse("select * from a.parquet a left join b.parquet b on a.id = b.id right join c.parquet c on b.id = c.id")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(q.Joins) != 2 {
+		t.Errorf("expected 2 joins, got %d", len(q.Joins))
+	}
+}
+
 func TestReplaceFunc(t *testing.T) {
 	fn := &ReplaceFunc{}
 	tests := []struct {
@@ -259,6 +515,7 @@ func TestReverseFunc(t *testing.T) {
 	}{
 		{"simple", []interface{}{"hello"}, "olleh"},
 		{"empty", []interface{}{""}, ""},
+		{"multibyte", []interface{}{"héllo 日本"}, "本日 olléh"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -408,6 +665,179 @@ func TestSplitFunc(t *testing.T) {
 	}
 }
 
+func TestRegexpMatchFunc(t *testing.T) {
+	fn := &RegexpMatchFunc{}
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"match", []interface{}{"alice@example.com", `@example\.com$`}, true, ""},
+		{"no match", []interface{}{"bob@example.org", `@example\.com$`}, false, ""},
+		{"partial match", []interface{}{"hello world", "o w"}, true, ""},
+		{"case-insensitive flag", []interface{}{"HELLO", "(?i)^hello$"}, true, ""},
+		{"multibyte", []interface{}{"日本語", "^日.語$"}, true, ""},
+		{"number input", []interface{}{int64(12345), `^\d+$`}, true, ""},
+		{"null input", []interface{}{nil, "x"}, nil, ""},
+		{"invalid pattern", []interface{}{"abc", "(abc"}, nil, `REGEXP_MATCH: invalid pattern "(abc"`},
+		{"null pattern", []interface{}{"abc", nil}, nil, "REGEXP_MATCH: pattern:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fn.Evaluate(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexpReplaceFunc(t *testing.T) {
+	fn := &RegexpReplaceFunc{}
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"replace all", []interface{}{"a1b22c333", `\d+`, "#"}, "a#b#c#", ""},
+		{"capture groups", []interface{}{"2024-03-15", `(\d+)-(\d+)-(\d+)`, "$3/$2/$1"}, "15/03/2024", ""},
+		{"named group", []interface{}{"john smith", `(?P<first>\w+) (?P<last>\w+)`, "${last}, ${first}"}, "smith, john", ""},
+		{"no match", []interface{}{"hello", "x", "y"}, "hello", ""},
+		{"delete", []interface{}{"  a  b ", `\s+`, ""}, "ab", ""},
+		{"null input", []interface{}{nil, "x", "y"}, nil, ""},
+		{"invalid pattern", []interface{}{"abc", "[", "x"}, nil, "REGEXP_REPLACE: invalid pattern"},
+		{"null replacement", []interface{}{"abc", "b", nil}, nil, "REGEXP_REPLACE: replacement:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fn.Evaluate(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexpCache(t *testing.T) {
+	cache := &regexpCache{patterns: make(map[string]*regexp.Regexp)}
+
+	first, err := cache.compile(`^a+$`)
+	if err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+	second, err := cache.compile(`^a+$`)
+	if err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+	if first != second {
+		t.Error("expected the cached pattern to be reused")
+	}
+
+	if _, err := cache.compile("(a"); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if len(cache.patterns) != 1 {
+		t.Errorf("expected invalid patterns not to be cached, got %d entries", len(cache.patterns))
+	}
+
+	for i := 0; i < maxCachedRegexps+10; i++ {
+		if _, err := cache.compile(fmt.Sprintf("p%d", i)); err != nil {
+			t.Fatalf("compile() error = %v", err)
+		}
+	}
+	if len(cache.patterns) > maxCachedRegexps {
+		t.Errorf("cache grew to %d entries, want at most %d", len(cache.patterns), maxCachedRegexps)
+	}
+}
+
+func TestParser_RegexpMatchInWhere(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "Alice", "email": "alice@example.com"},
+		{"name": "Bob", "email": "bob@example.org"},
+		{"name": "Carol", "email": nil},
+		{"name": "Dave", "email": "dave@EXAMPLE.com"},
+	}
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "bare function condition",
+			sql:  `select name from t.parquet where REGEXP_MATCH(email, '@example\\.com$')`,
+			want: []string{"Alice"},
+		},
+		{
+			name: "combined with AND",
+			sql:  `select name from t.parquet where REGEXP_MATCH(email, '(?i)@example\\.com$') and name != 'Alice'`,
+			want: []string{"Dave"},
+		},
+		{
+			name: "negated by comparing with false",
+			sql:  `select name from t.parquet where REGEXP_MATCH(email, '\\.com$') = false`,
+			want: []string{"Bob"},
+		},
+		{
+			name: "replace in comparison",
+			sql:  `select name from t.parquet where REGEXP_REPLACE(name, '[aeiou]', '') = 'Alc'`,
+			want: []string{"Alice"},
+		},
+		{
+			name:    "non-boolean function",
+			sql:     `select name from t.parquet where UPPER(name)`,
+			wantErr: "expected boolean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			filtered, err := ApplyFilter(rows, q.Filter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			var got []string
+			for _, row := range filtered {
+				got = append(got, row["name"].(string))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMinMaxArityStringFunctions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -418,11 +848,16 @@ func TestMinMaxArityStringFunctions(t *testing.T) {
 		{"UPPER", &UpperFunc{}, 1, 1},
 		{"LOWER", &LowerFunc{}, 1, 1},
 		{"CONCAT", &ConcatFunc{}, 1, -1},
+		{"CONCAT_WS", &ConcatWSFunc{}, 2, -1},
 		{"LENGTH", &LengthFunc{}, 1, 1},
-		{"TRIM", &TrimFunc{}, 1, 1},
-		{"LTRIM", &LTrimFunc{}, 1, 1},
-		{"RTRIM", &RTrimFunc{}, 1, 1},
+		{"TRIM", &TrimFunc{}, 1, 2},
+		{"LTRIM", &LTrimFunc{}, 1, 2},
+		{"RTRIM", &RTrimFunc{}, 1, 2},
 		{"SUBSTRING", &SubstringFunc{}, 2, 3},
+		{"LEFT", &LeftFunc{}, 2, 2},
+		{"RIGHT", &RightFunc{}, 2, 2},
+		{"LPAD", &LPadFunc{}, 2, 3},
+		{"RPAD", &RPadFunc{}, 2, 3},
 		{"REPLACE", &ReplaceFunc{}, 3, 3},
 		{"SPLIT", &SplitFunc{}, 2, 2},
 		{"REVERSE", &ReverseFunc{}, 1, 1},
@@ -430,6 +865,9 @@ func TestMinMaxArityStringFunctions(t *testing.T) {
 		{"STARTS_WITH", &StartsWithFunc{}, 2, 2},
 		{"ENDS_WITH", &EndsWithFunc{}, 2, 2},
 		{"REPEAT", &RepeatFunc{}, 2, 2},
+		{"FORMAT", &FormatFunc{}, 1, -1},
+		{"REGEXP_MATCH", &RegexpMatchFunc{}, 2, 2},
+		{"REGEXP_REPLACE", &RegexpReplaceFunc{}, 3, 3},
 	}
 
 	for _, tt := range tests {
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -116,18 +117,32 @@ func TestFunctionCallEvaluateSelect(t *testing.T) {
 	}
 }
 
+func TestGlobalRegistry_Aliases(t *testing.T) {
+	registry := GetGlobalRegistry()
+
+	fn, exists := registry.Get("substr")
+	if !exists {
+		t.Fatalf("expected SUBSTR alias to be registered")
+	}
+	if fn.Name() != "SUBSTRING" {
+		t.Errorf("expected SUBSTR to resolve to SUBSTRING, got %s", fn.Name())
+	}
+}
+
 func TestGlobalRegistry(t *testing.T) {
 	registry := GetGlobalRegistry()
 
 	// Check that all expected functions are registered
 	expectedFunctions := []string{
-		// String functions (15)
-		"UPPER", "LOWER", "CONCAT", "LENGTH", "TRIM",
-		"LTRIM", "RTRIM", "SUBSTRING", "REPLACE", "SPLIT",
-		"REVERSE", "CONTAINS", "STARTS_WITH", "ENDS_WITH", "REPEAT",
-		// Math functions (12)
+		// String functions (21)
+		"UPPER", "LOWER", "CONCAT", "CONCAT_WS", "LENGTH", "TRIM",
+		"LTRIM", "RTRIM", "SUBSTRING", "LEFT", "RIGHT", "LPAD", "RPAD", "REPLACE", "SPLIT",
+		"REVERSE", "CONTAINS", "STARTS_WITH", "ENDS_WITH", "REPEAT", "FORMAT",
+		// Regular expression functions (2)
+		"REGEXP_MATCH", "REGEXP_REPLACE",
+		// Math functions (15)
 		"ABS", "ROUND", "FLOOR", "CEIL", "MOD",
-		"SQRT", "POW", "SIGN", "TRUNC", "RANDOM", "MIN", "MAX",
+		"SQRT", "POW", "EXP", "LOG", "LOG10", "SIGN", "TRUNC", "RANDOM", "MIN", "MAX",
 		// Date/Time functions (10)
 		"NOW", "CURRENT_DATE", "CURRENT_TIME", "DATE_TRUNC", "DATE_PART",
 		"DATE_ADD", "DATE_SUB", "DATE_DIFF", "YEAR", "MONTH",
@@ -149,3 +164,91 @@ func TestGlobalRegistry(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterFunction(t *testing.T) {
+	err := RegisterFunction("test_label", 2, func(args []interface{}) (interface{}, error) {
+		return fmt.Sprintf("%v:%v", args[0], args[1]), nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunction() error = %v", err)
+	}
+
+	q, err := Parse("SELECT Test_Label(name, age) AS label FROM users.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	row := map[string]interface{}{"name": "alice", "age": int64(30)}
+
+	got, err := q.SelectList[0].Expr.EvaluateSelect(row)
+	if err != nil {
+		t.Fatalf("EvaluateSelect() error = %v", err)
+	}
+	if got != "alice:30" {
+		t.Errorf("EvaluateSelect() = %v, want %v", got, "alice:30")
+	}
+
+	got, err = NewExecutionContext(nil).EvaluateSelectExpression(row, q.SelectList[0].Expr)
+	if err != nil {
+		t.Fatalf("EvaluateSelectExpression() error = %v", err)
+	}
+	if got != "alice:30" {
+		t.Errorf("EvaluateSelectExpression() = %v, want %v", got, "alice:30")
+	}
+
+	// Arity is checked before the function is called
+	call := &FunctionCall{Name: "TEST_LABEL", Args: []SelectExpression{&LiteralExpr{Value: "x"}}}
+	if _, err := call.EvaluateSelect(row); err == nil {
+		t.Errorf("expected arity error calling TEST_LABEL with 1 argument")
+	}
+
+	// Registering again replaces the custom function
+	err = RegisterFunction("TEST_LABEL", -1, func(args []interface{}) (interface{}, error) {
+		return int64(len(args)), nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunction() replacing custom function error = %v", err)
+	}
+	call.Args = append(call.Args, &LiteralExpr{Value: "y"}, &LiteralExpr{Value: "z"})
+	got, err = call.EvaluateSelect(row)
+	if err != nil {
+		t.Fatalf("EvaluateSelect() variadic error = %v", err)
+	}
+	if got != int64(3) {
+		t.Errorf("EvaluateSelect() variadic = %v, want 3", got)
+	}
+}
+
+func TestRegisterFunction_Errors(t *testing.T) {
+	noop := func(args []interface{}) (interface{}, error) { return nil, nil }
+
+	tests := []struct {
+		name     string
+		funcName string
+		arity    int
+		fn       func(args []interface{}) (interface{}, error)
+	}{
+		{"built-in", "upper", 1, noop},
+		{"aggregate", "SUM", 1, noop},
+		{"window", "ROW_NUMBER", 0, noop},
+		{"keyword", "Select", 1, noop},
+		{"empty name", "", 1, noop},
+		{"invalid characters", "my-func", 1, noop},
+		{"leading digit", "1func", 1, noop},
+		{"invalid arity", "TEST_BAD_ARITY", -2, noop},
+		{"nil function", "TEST_NIL", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterFunction(tt.funcName, tt.arity, tt.fn); err == nil {
+				t.Errorf("RegisterFunction(%q) expected error, got nil", tt.funcName)
+			}
+		})
+	}
+
+	// A failed registration must not replace the built-in
+	fn, _ := GetGlobalRegistry().Get("UPPER")
+	if _, custom := fn.(*customFunction); custom {
+		t.Errorf("built-in UPPER was replaced by a custom function")
+	}
+}
@@ -0,0 +1,177 @@
+package query
+
+// joinKeyKind is the kind of value a hash join key holds. Keys of
+// different kinds never compare equal, and comparing them can fail, so a
+// hash join only runs when the non-NULL keys of both sides share one kind.
+type joinKeyKind int
+
+const (
+	joinKeyNull joinKeyKind = iota
+	joinKeyInteger
+	joinKeyString
+	joinKeyBool
+)
+
+// joinKey returns the hash key of a join column value and its kind. It
+// returns false for values whose equality isn't plain equality of keys:
+// floats compare with a tolerance and timestamps also match date strings.
+func joinKey(v interface{}) (interface{}, joinKeyKind, bool) {
+	if v == nil {
+		// NULL = NULL holds in this engine (see compare)
+		return nil, joinKeyNull, true
+	}
+	if i, ok := toInt64(v); ok {
+		return i, joinKeyInteger, true
+	}
+	switch val := v.(type) {
+	case string:
+		return val, joinKeyString, true
+	case bool:
+		return val, joinKeyBool, true
+	default:
+		return nil, joinKeyNull, false
+	}
+}
+
+// HashInnerJoin runs an INNER JOIN as a hash join when its condition is an
+// equality of a column of each side, such as ON a.id = b.id. The smaller
+// side is indexed by its key and probed with the other, so the join takes
+// time proportional to its inputs and result rather than to their product.
+//
+// The result, including its order and any error, is that of the nested
+// loop join. When that can't be guaranteed, for example because a key is a
+// float or the sides hold keys of different types, HashInnerJoin returns
+// false without joining and the caller should use a nested loop instead.
+func (ctx *ExecutionContext) HashInnerJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, bool, error) {
+	cond, ok := condition.(*ColumnComparisonExpr)
+	if !ok || cond.Operator != TokenEqual {
+		return nil, false, nil
+	}
+	if len(leftRows) == 0 || len(rightRows) == 0 {
+		return nil, true, nil
+	}
+
+	leftColumn, rightColumn, ok := joinKeyColumns(leftRows, rightRows, cond)
+	if !ok {
+		return nil, false, nil
+	}
+	leftKeys, leftKind, ok := joinKeys(leftRows, leftColumn)
+	if !ok {
+		return nil, false, nil
+	}
+	rightKeys, rightKind, ok := joinKeys(rightRows, rightColumn)
+	if !ok || leftKind != rightKind && leftKind != joinKeyNull && rightKind != joinKeyNull {
+		return nil, false, nil
+	}
+
+	// matches[i] lists, in order, the right rows joining left row i, so the
+	// result is in the order the nested loop produces
+	matches := make([][]int, len(leftRows))
+	if len(rightRows) <= len(leftRows) {
+		index := indexJoinKeys(rightKeys)
+		for i, key := range leftKeys {
+			matches[i] = index[key]
+		}
+	} else {
+		index := indexJoinKeys(leftKeys)
+		for j, key := range rightKeys {
+			for _, i := range index[key] {
+				matches[i] = append(matches[i], j)
+			}
+		}
+	}
+
+	var result []map[string]interface{}
+	for i, leftRow := range leftRows {
+		if err := ctx.Err(); err != nil {
+			return nil, true, err
+		}
+		for _, j := range matches[i] {
+			merged, err := mergeRows(leftRow, rightRows[j])
+			if err != nil {
+				return nil, true, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, true, err
+			}
+			result = append(result, merged)
+		}
+	}
+	return result, true, nil
+}
+
+// joinKeyColumns returns which of the condition's columns belongs to the
+// left rows and which to the right ones. It returns false unless every row
+// has the column of its side, and no row has a column of a row on the other
+// side, so each merged row holds both keys as read from its own side.
+func joinKeyColumns(leftRows, rightRows []map[string]interface{}, cond *ColumnComparisonExpr) (string, string, bool) {
+	leftColumns := rowColumns(leftRows)
+	rightColumns := rowColumns(rightRows)
+	for col := range rightColumns {
+		// Both sides may have _file, which mergeRows renames
+		if leftColumns[col] && col != "_file" {
+			return "", "", false
+		}
+	}
+
+	leftColumn, rightColumn := cond.LeftColumn, cond.RightColumn
+	if !leftColumns[leftColumn] {
+		leftColumn, rightColumn = rightColumn, leftColumn
+	}
+	if leftColumn == "_file" || rightColumn == "_file" || leftColumns[rightColumn] || rightColumns[leftColumn] {
+		return "", "", false
+	}
+	for _, row := range leftRows {
+		if _, ok := row[leftColumn]; !ok {
+			return "", "", false
+		}
+	}
+	for _, row := range rightRows {
+		if _, ok := row[rightColumn]; !ok {
+			return "", "", false
+		}
+	}
+	return leftColumn, rightColumn, true
+}
+
+// rowColumns returns the set of columns found in any of rows
+func rowColumns(rows []map[string]interface{}) map[string]bool {
+	columns := make(map[string]bool)
+	for _, row := range rows {
+		for col := range row {
+			columns[col] = true
+		}
+	}
+	return columns
+}
+
+// joinKeys returns the key of column in each row and the kind of its
+// non-NULL keys, which is joinKeyNull if all of them are NULL. It returns
+// false if a key can't be hashed or the keys are of different kinds.
+func joinKeys(rows []map[string]interface{}, column string) ([]interface{}, joinKeyKind, bool) {
+	keys := make([]interface{}, len(rows))
+	kind := joinKeyNull
+	for i, row := range rows {
+		key, k, ok := joinKey(row[column])
+		if !ok {
+			return nil, joinKeyNull, false
+		}
+		if k != joinKeyNull {
+			if kind != joinKeyNull && kind != k {
+				return nil, joinKeyNull, false
+			}
+			kind = k
+		}
+		keys[i] = key
+	}
+	return keys, kind, true
+}
+
+// indexJoinKeys maps each key to the positions it appears at, in order
+func indexJoinKeys(keys []interface{}) map[interface{}][]int {
+	index := make(map[interface{}][]int, len(keys))
+	for i, key := range keys {
+		index[key] = append(index[key], i)
+	}
+	return index
+}
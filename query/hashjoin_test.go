@@ -0,0 +1,165 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHashInnerJoin(t *testing.T) {
+	idsEqual := &ColumnComparisonExpr{LeftColumn: "a.id", Operator: TokenEqual, RightColumn: "b.id"}
+
+	tests := []struct {
+		name      string
+		left      []map[string]interface{}
+		right     []map[string]interface{}
+		condition Expression
+		wantHash  bool
+	}{
+		{
+			name: "duplicate keys on both sides keep nested loop order",
+			left: []map[string]interface{}{
+				{"a.id": int64(1), "a.n": "x"}, {"a.id": int64(2), "a.n": "y"}, {"a.id": int64(1), "a.n": "z"},
+			},
+			right: []map[string]interface{}{
+				{"b.id": int64(1), "b.n": "p"}, {"b.id": int64(3), "b.n": "q"}, {"b.id": int64(1), "b.n": "r"},
+				{"b.id": int64(2), "b.n": "s"},
+			},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name: "smaller left side is indexed",
+			left: []map[string]interface{}{{"a.id": int64(2)}, {"a.id": int64(1)}},
+			right: []map[string]interface{}{
+				{"b.id": int64(1)}, {"b.id": int64(2)}, {"b.id": int64(1)}, {"b.id": int64(2)}, {"b.id": int64(4)},
+			},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name:      "columns named right side first",
+			left:      []map[string]interface{}{{"a.id": "k1"}, {"a.id": "k2"}},
+			right:     []map[string]interface{}{{"b.id": "k2"}, {"b.id": "k1"}},
+			condition: &ColumnComparisonExpr{LeftColumn: "b.id", Operator: TokenEqual, RightColumn: "a.id"},
+			wantHash:  true,
+		},
+		{
+			name:      "integer widths match",
+			left:      []map[string]interface{}{{"a.id": int32(7)}, {"a.id": uint8(8)}},
+			right:     []map[string]interface{}{{"b.id": int64(8)}, {"b.id": int(7)}},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name:      "NULL keys match NULL keys",
+			left:      []map[string]interface{}{{"a.id": nil}, {"a.id": true}},
+			right:     []map[string]interface{}{{"b.id": nil}, {"b.id": true}, {"b.id": false}},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name:      "no matches",
+			left:      []map[string]interface{}{{"a.id": int64(1)}},
+			right:     []map[string]interface{}{{"b.id": int64(2)}},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name:      "empty side",
+			left:      []map[string]interface{}{},
+			right:     []map[string]interface{}{{"b.id": int64(2)}},
+			condition: idsEqual,
+			wantHash:  true,
+		},
+		{
+			name:      "float keys use the nested loop",
+			left:      []map[string]interface{}{{"a.id": 1.0}},
+			right:     []map[string]interface{}{{"b.id": int64(1)}},
+			condition: idsEqual,
+		},
+		{
+			name:      "mixed key types use the nested loop and fail like it",
+			left:      []map[string]interface{}{{"a.id": int64(1)}},
+			right:     []map[string]interface{}{{"b.id": "1"}},
+			condition: idsEqual,
+		},
+		{
+			name:      "column collision uses the nested loop and fails like it",
+			left:      []map[string]interface{}{{"a.id": int64(1), "name": "x"}},
+			right:     []map[string]interface{}{{"b.id": int64(1), "name": "y"}},
+			condition: idsEqual,
+		},
+		{
+			name:      "missing key column uses the nested loop and fails like it",
+			left:      []map[string]interface{}{{"a.id": int64(1)}, {"a.other": int64(2)}},
+			right:     []map[string]interface{}{{"b.id": int64(1)}},
+			condition: idsEqual,
+		},
+		{
+			name:      "both columns from one side",
+			left:      []map[string]interface{}{{"a.id": int64(1), "b.id": int64(1)}},
+			right:     []map[string]interface{}{{"c.id": int64(1)}},
+			condition: idsEqual,
+		},
+		{
+			name:      "non-equality condition",
+			left:      []map[string]interface{}{{"a.id": int64(1)}},
+			right:     []map[string]interface{}{{"b.id": int64(2)}},
+			condition: &ColumnComparisonExpr{LeftColumn: "a.id", Operator: TokenLess, RightColumn: "b.id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewExecutionContext(nil)
+			want, wantErr := ctx.nestedLoopInnerJoin(tt.left, tt.right, tt.condition)
+
+			got, ok, err := ctx.HashInnerJoin(tt.left, tt.right, tt.condition)
+			if ok != tt.wantHash {
+				t.Fatalf("HashInnerJoin() ok = %v, want %v", ok, tt.wantHash)
+			}
+			if ok {
+				if err != nil || !reflect.DeepEqual(got, want) {
+					t.Errorf("HashInnerJoin() = %v, %v, want %v", got, err, want)
+				}
+			}
+
+			// executeInnerJoin gives the nested loop's result either way
+			got, err = ctx.executeInnerJoin(tt.left, tt.right, tt.condition)
+			if fmt.Sprint(err) != fmt.Sprint(wantErr) || !reflect.DeepEqual(got, want) {
+				t.Errorf("executeInnerJoin() = %v, %v, want %v, %v", got, err, want, wantErr)
+			}
+		})
+	}
+}
+
+// BenchmarkInnerJoin compares the nested loop and hash joins of two 10,000
+// row tables where each row matches one row of the other table
+func BenchmarkInnerJoin(b *testing.B) {
+	const n = 10000
+	left := make([]map[string]interface{}, n)
+	right := make([]map[string]interface{}, n)
+	for i := range left {
+		left[i] = map[string]interface{}{"a.id": int64(i)}
+		right[i] = map[string]interface{}{"b.id": int64(n - 1 - i)}
+	}
+	condition := &ColumnComparisonExpr{LeftColumn: "a.id", Operator: TokenEqual, RightColumn: "b.id"}
+	ctx := NewExecutionContext(nil)
+
+	b.Run("nested loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ctx.nestedLoopInnerJoin(left, right, condition); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("hash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, ok, err := ctx.HashInnerJoin(left, right, condition); !ok || err != nil {
+				b.Fatalf("HashInnerJoin() ok = %v, error = %v", ok, err)
+			}
+		}
+	})
+}
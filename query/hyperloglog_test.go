@@ -0,0 +1,52 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		distinct int
+	}{
+		{"empty", 0},
+		{"one value", 1},
+		{"small", 100},
+		{"linear counting range", 10000},
+		{"large", 200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sketch hyperLogLog
+			// Add every value twice so duplicates are exercised
+			for pass := 0; pass < 2; pass++ {
+				for i := 0; i < tt.distinct; i++ {
+					sketch.add(fmt.Sprintf("user-%d", i))
+				}
+			}
+
+			got := sketch.estimate()
+			// 3% is well over three standard errors (0.81%)
+			tolerance := math.Max(1, 0.03*float64(tt.distinct))
+			if math.Abs(float64(got-int64(tt.distinct))) > tolerance {
+				t.Errorf("estimate() = %d, want %d ± %.0f", got, tt.distinct, tolerance)
+			}
+		})
+	}
+}
+
+func TestHyperLogLog_TypesAreDistinct(t *testing.T) {
+	// Keys match COUNT(DISTINCT): strings, booleans and numbers differ
+	var sketch hyperLogLog
+	sketch.add(int64(1))
+	sketch.add("1")
+	sketch.add(true)
+	sketch.add(int64(1))
+
+	if got := sketch.estimate(); got != 3 {
+		t.Errorf("estimate() = %d, want 3", got)
+	}
+}
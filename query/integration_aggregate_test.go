@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -559,3 +560,525 @@ func TestParquetAggregateWithNulls(t *testing.T) {
 	}
 }
 
+// TestParquetArrayAgg tests ARRAY_AGG collecting values into a list per group
+func TestParquetArrayAgg(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0, Active: true, Score: 85.5},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0, Active: false, Score: 72.3},
+		{ID: 3, Name: "Charlie", Age: 30, Salary: 60000.0, Active: true, Score: 91.2},
+		{ID: 4, Name: "Diana", Age: 25, Salary: 52000.0, Active: true, Score: 78.9},
+		{ID: 5, Name: "Eve", Age: 35, Salary: 48000.0, Active: false, Score: 88.1},
+		{ID: 6, Name: "Frank", Age: 30, Salary: 55000.0, Active: true, Score: 82.0},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantRows int
+		validate func(t *testing.T, rows []map[string]interface{})
+	}{
+		{
+			name:     "array_agg grouped by age preserves input order",
+			queryTpl: "SELECT age, ARRAY_AGG(name) as names FROM '%s' GROUP BY age",
+			wantRows: 3,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				expected := map[int64][]interface{}{
+					25: {"Bob", "Diana"},
+					30: {"Alice", "Charlie", "Frank"},
+					35: {"Eve"},
+				}
+				for _, row := range rows {
+					age := row["age"].(int64)
+					names, ok := row["names"].([]interface{})
+					if !ok {
+						t.Fatalf("Expected names to be []interface{}, got %T", row["names"])
+					}
+					if fmt.Sprint(names) != fmt.Sprint(expected[age]) {
+						t.Errorf("Age %d: expected names %v, got %v", age, expected[age], names)
+					}
+				}
+			},
+		},
+		{
+			name:     "array_agg without group by",
+			queryTpl: "SELECT ARRAY_AGG(id) FROM '%s'",
+			wantRows: 1,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				ids, ok := rows[0]["array_agg"].([]interface{})
+				if !ok {
+					t.Fatalf("Expected array_agg to be []interface{}, got %T", rows[0]["array_agg"])
+				}
+				if len(ids) != 6 {
+					t.Errorf("Expected 6 ids, got %d", len(ids))
+				}
+			},
+		},
+		{
+			name:     "array_agg over empty input is null",
+			queryTpl: "SELECT ARRAY_AGG(name) as names FROM '%s' WHERE age > 100",
+			wantRows: 1,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if rows[0]["names"] != nil {
+					t.Errorf("Expected NULL for empty input, got %v", rows[0]["names"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != tt.wantRows {
+				t.Errorf("Expected %d rows, got %d", tt.wantRows, len(results))
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, results)
+			}
+		})
+	}
+}
+
+// TestParquetOrderedAggregates tests ORDER BY inside STRING_AGG and ARRAY_AGG
+func TestParquetOrderedAggregates(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Frank", Age: 30},
+		{ID: 2, Name: "Diana", Age: 25},
+		{ID: 3, Name: "Alice", Age: 30},
+		{ID: 4, Name: "Bob", Age: 25},
+		{ID: 5, Name: "Charlie", Age: 30},
+		{ID: 6, Name: "Alice", Age: 35},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		want     []map[string]interface{}
+	}{
+		{
+			name:     "string_agg ordered by value",
+			queryTpl: "SELECT age, STRING_AGG(name, ',' ORDER BY name) as names FROM '%s' GROUP BY age ORDER BY age",
+			want: []map[string]interface{}{
+				{"age": int64(25), "names": "Bob,Diana"},
+				{"age": int64(30), "names": "Alice,Charlie,Frank"},
+				{"age": int64(35), "names": "Alice"},
+			},
+		},
+		{
+			name:     "string_agg ordered by another column descending",
+			queryTpl: "SELECT STRING_AGG(name, ' ' ORDER BY id DESC) as names FROM '%s' WHERE age = 30",
+			want: []map[string]interface{}{
+				{"names": "Charlie Alice Frank"},
+			},
+		},
+		{
+			name:     "string_agg distinct ordered",
+			queryTpl: "SELECT STRING_AGG(DISTINCT name, ';' ORDER BY name) as names FROM '%s'",
+			want: []map[string]interface{}{
+				{"names": "Alice;Bob;Charlie;Diana;Frank"},
+			},
+		},
+		{
+			name:     "string_agg over empty input is null",
+			queryTpl: "SELECT STRING_AGG(name, ',') as names FROM '%s' WHERE age > 100",
+			want: []map[string]interface{}{
+				{"names": nil},
+			},
+		},
+		{
+			name:     "array_agg ordered by multiple columns",
+			queryTpl: "SELECT ARRAY_AGG(id ORDER BY age DESC, name) as ids FROM '%s'",
+			want: []map[string]interface{}{
+				{"ids": []interface{}{int64(6), int64(3), int64(5), int64(1), int64(4), int64(2)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("ExecuteQuery() = %v, want %v", results, tt.want)
+			}
+		})
+	}
+}
+
+// TestParquetCountDistinct tests that DISTINCT aggregates are computed per group
+// and can be combined with non-distinct aggregates in one query
+func TestParquetCountDistinct(t *testing.T) {
+	testData := []EmployeeDataRow{
+		{ID: 1, Dept: "eng", Team: "platform", Salary: 100},
+		{ID: 2, Dept: "eng", Team: "platform", Salary: 100},
+		{ID: 3, Dept: "eng", Team: "data", Salary: 120},
+		{ID: 4, Dept: "eng", Team: "platform", Salary: 110},
+		{ID: 5, Dept: "sales", Team: "emea", Salary: 90},
+		{ID: 6, Dept: "sales", Team: "emea", Salary: 90},
+		{ID: 7, Dept: "ops", Team: "platform", Salary: 80},
+	}
+
+	testFile := createEmployeeParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantRows int
+		validate func(t *testing.T, rows []map[string]interface{})
+	}{
+		{
+			name:     "distinct and non-distinct aggregates per group",
+			queryTpl: "SELECT dept, COUNT(DISTINCT team) as teams, COUNT(*) as employees, SUM(DISTINCT salary) as distinct_salaries, SUM(salary) as total FROM '%s' GROUP BY dept",
+			wantRows: 3,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				expected := map[string]struct {
+					teams, employees         int64
+					distinctSalaries, totals float64
+				}{
+					"eng":   {2, 4, 330, 430},
+					"sales": {1, 2, 90, 180},
+					"ops":   {1, 1, 80, 80},
+				}
+				for _, row := range rows {
+					dept := row["dept"].(string)
+					want := expected[dept]
+					if row["teams"] != want.teams {
+						t.Errorf("%s: expected %d distinct teams, got %v", dept, want.teams, row["teams"])
+					}
+					if row["employees"] != want.employees {
+						t.Errorf("%s: expected %d employees, got %v", dept, want.employees, row["employees"])
+					}
+					if row["distinct_salaries"] != want.distinctSalaries {
+						t.Errorf("%s: expected distinct salary sum %v, got %v", dept, want.distinctSalaries, row["distinct_salaries"])
+					}
+					if row["total"] != want.totals {
+						t.Errorf("%s: expected salary total %v, got %v", dept, want.totals, row["total"])
+					}
+				}
+			},
+		},
+		{
+			name:     "distinct count is smaller than row count where teams repeat",
+			queryTpl: "SELECT dept, COUNT(DISTINCT team) as teams, COUNT(*) as employees FROM '%s' WHERE dept = 'eng' GROUP BY dept",
+			wantRows: 1,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if rows[0]["teams"].(int64) >= rows[0]["employees"].(int64) {
+					t.Errorf("Expected fewer distinct teams than employees, got %v teams and %v employees", rows[0]["teams"], rows[0]["employees"])
+				}
+			},
+		},
+		{
+			name:     "distinct without group by is global",
+			queryTpl: "SELECT COUNT(DISTINCT team) as teams, COUNT(DISTINCT dept) as depts FROM '%s'",
+			wantRows: 1,
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if rows[0]["teams"] != int64(3) {
+					t.Errorf("Expected 3 distinct teams, got %v", rows[0]["teams"])
+				}
+				if rows[0]["depts"] != int64(3) {
+					t.Errorf("Expected 3 distinct depts, got %v", rows[0]["depts"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != tt.wantRows {
+				t.Fatalf("Expected %d rows, got %d", tt.wantRows, len(results))
+			}
+
+			tt.validate(t, results)
+		})
+	}
+}
+
+func TestParquetApproxCountDistinct(t *testing.T) {
+	// 20000 rows with 5000 distinct ages and 3000 distinct names
+	testData := make([]BasicDataRow, 20000)
+	for i := range testData {
+		testData[i] = BasicDataRow{
+			ID:     int64(i),
+			Name:   fmt.Sprintf("user-%d", i%3000),
+			Age:    int64(i % 5000),
+			Active: i%2 == 0,
+		}
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	q, err := Parse(fmt.Sprintf("SELECT active, APPROX_COUNT_DISTINCT(age) as ages, APPROX_COUNT_DISTINCT(name) as names, COUNT(DISTINCT name) as exact FROM '%s' GROUP BY active", testFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	r, err := reader.NewReader(testFile)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	results, err := ExecuteQuery(q, r)
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(results))
+	}
+
+	// Each group holds every other row: 2500 distinct ages and 1500 distinct names
+	within := func(got interface{}, want int64) bool {
+		estimate, ok := got.(int64)
+		if !ok {
+			return false
+		}
+		diff := estimate - want
+		if diff < 0 {
+			diff = -diff
+		}
+		return float64(diff) <= 0.03*float64(want)
+	}
+	for _, row := range results {
+		if !within(row["ages"], 2500) {
+			t.Errorf("active=%v: expected about 2500 distinct ages, got %v", row["active"], row["ages"])
+		}
+		if !within(row["names"], 1500) {
+			t.Errorf("active=%v: expected about 1500 distinct names, got %v", row["active"], row["names"])
+		}
+		if row["exact"] != int64(1500) {
+			t.Errorf("active=%v: expected exactly 1500 distinct names, got %v", row["active"], row["exact"])
+		}
+	}
+}
+
+func TestParquetGroupByExpression(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+		{ID: 3, Name: "Charlie", Age: 30},
+		{ID: 4, Name: "Diana", Age: 25},
+		{ID: 5, Name: "Eve", Age: 30},
+		{ID: 6, Name: "Frank", Age: 35},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		want     []map[string]interface{}
+	}{
+		{
+			name:     "select alias",
+			queryTpl: "SELECT age - age %% 10 AS decade, COUNT(*) AS n FROM '%s' GROUP BY decade ORDER BY decade",
+			want: []map[string]interface{}{
+				{"decade": float64(20), "n": int64(2)},
+				{"decade": float64(30), "n": int64(4)},
+			},
+		},
+		{
+			name:     "arithmetic expression",
+			queryTpl: "SELECT age - age %% 10 AS decade, COUNT(*) AS n FROM '%s' GROUP BY age - age %% 10 ORDER BY decade",
+			want: []map[string]interface{}{
+				{"decade": float64(20), "n": int64(2)},
+				{"decade": float64(30), "n": int64(4)},
+			},
+		},
+		{
+			name:     "unselected expression",
+			queryTpl: "SELECT COUNT(*) AS n FROM '%s' GROUP BY id %% 4 ORDER BY n",
+			want: []map[string]interface{}{
+				{"n": int64(1)}, {"n": int64(1)}, {"n": int64(2)}, {"n": int64(2)},
+			},
+		},
+		{
+			name:     "unnamed expression with HAVING",
+			queryTpl: "SELECT age %% 10, SUM(id) AS total FROM '%s' GROUP BY age %% 10 HAVING total > 10",
+			want: []map[string]interface{}{
+				{"col_0": int64(5), "total": float64(12)},
+			},
+		},
+		{
+			name:     "alias of a column",
+			queryTpl: "SELECT age AS years, COUNT(*) AS n FROM '%s' GROUP BY years ORDER BY years",
+			want: []map[string]interface{}{
+				{"years": int64(25), "n": int64(2)},
+				{"years": int64(30), "n": int64(3)},
+				{"years": int64(35), "n": int64(1)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("ExecuteQuery() = %v, want %v", results, tt.want)
+			}
+		})
+	}
+}
+
+func TestParquetGroupByRollup(t *testing.T) {
+	testData := []EmployeeDataRow{
+		{ID: 1, Dept: "eng", Team: "data", Salary: 100},
+		{ID: 2, Dept: "eng", Team: "data", Salary: 120},
+		{ID: 3, Dept: "eng", Team: "platform", Salary: 150},
+		{ID: 4, Dept: "sales", Team: "east", Salary: 80},
+		{ID: 5, Dept: "sales", Team: "west", Salary: 90},
+	}
+
+	testFile := createEmployeeParquetFile(t, testData)
+
+	q, err := Parse(fmt.Sprintf("SELECT dept, team, COUNT(*) AS n, SUM(salary) AS total FROM '%s' GROUP BY ROLLUP(dept, team) ORDER BY dept, team", testFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	r, err := reader.NewReader(testFile)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	results, err := ExecuteQuery(q, r)
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+
+	// Four groups, a subtotal per dept and a grand total, with NULLs sorting last
+	want := []map[string]interface{}{
+		{"dept": "eng", "team": "data", "n": int64(2), "total": float64(220)},
+		{"dept": "eng", "team": "platform", "n": int64(1), "total": float64(150)},
+		{"dept": "eng", "team": nil, "n": int64(3), "total": float64(370)},
+		{"dept": "sales", "team": "east", "n": int64(1), "total": float64(80)},
+		{"dept": "sales", "team": "west", "n": int64(1), "total": float64(90)},
+		{"dept": "sales", "team": nil, "n": int64(2), "total": float64(170)},
+		{"dept": nil, "team": nil, "n": int64(5), "total": float64(540)},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("ExecuteQuery() = %v, want %v", results, want)
+	}
+}
+
+func TestParquetHavingWithoutGroupBy(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+		{ID: 3, Name: "Charlie", Age: 35},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		want     []map[string]interface{}
+	}{
+		{
+			name:     "condition holds",
+			queryTpl: "SELECT COUNT(*) AS n, MAX(age) AS oldest FROM '%s' HAVING COUNT(*) > 2",
+			want:     []map[string]interface{}{{"n": int64(3), "oldest": float64(35)}},
+		},
+		{
+			name:     "condition fails",
+			queryTpl: "SELECT COUNT(*) AS n FROM '%s' HAVING COUNT(*) > 100",
+			want:     nil,
+		},
+		{
+			name:     "unselected aggregate",
+			queryTpl: "SELECT COUNT(*) AS n FROM '%s' HAVING MIN(age) < 30",
+			want:     []map[string]interface{}{{"n": int64(3)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != len(tt.want) || (len(tt.want) > 0 && !reflect.DeepEqual(results, tt.want)) {
+				t.Errorf("ExecuteQuery() = %v, want %v", results, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,12 @@ package query
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vegasq/parcat/reader"
 )
@@ -334,3 +339,912 @@ func TestParquetDistinct(t *testing.T) {
 		})
 	}
 }
+
+// TestParquetTableSample tests TABLESAMPLE on parquet files
+func TestParquetTableSample(t *testing.T) {
+	testData := make([]BasicDataRow, 1000)
+	for i := range testData {
+		testData[i] = BasicDataRow{ID: int64(i + 1), Name: fmt.Sprintf("user%d", i+1), Age: int64(20 + i%40)}
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		validate func(t *testing.T, rows []map[string]interface{})
+	}{
+		{
+			name:     "rows sample stops after n rows",
+			queryTpl: "SELECT id FROM '%s' TABLESAMPLE (10 ROWS)",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if len(rows) != 10 {
+					t.Errorf("Expected 10 rows, got %d", len(rows))
+				}
+			},
+		},
+		{
+			name:     "percent sample is approximate",
+			queryTpl: "SELECT id FROM '%s' TABLESAMPLE (50 PERCENT)",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				// Bernoulli sampling: expect roughly 500 rows
+				if len(rows) < 350 || len(rows) > 650 {
+					t.Errorf("Expected roughly 500 rows, got %d", len(rows))
+				}
+			},
+		},
+		{
+			name:     "100 percent reads every row",
+			queryTpl: "SELECT COUNT(*) as total FROM '%s' TABLESAMPLE (100 PERCENT)",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if rows[0]["total"] != int64(1000) {
+					t.Errorf("Expected total 1000, got %v", rows[0]["total"])
+				}
+			},
+		},
+		{
+			name:     "0 percent reads nothing",
+			queryTpl: "SELECT id FROM '%s' TABLESAMPLE (0 PERCENT)",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if len(rows) != 0 {
+					t.Errorf("Expected 0 rows, got %d", len(rows))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			tt.validate(t, results)
+		})
+	}
+
+	t.Run("seeded sample is reproducible", func(t *testing.T) {
+		sample := &TableSample{Percent: 10}
+
+		first, err := ReadTable(testFile, reader.ReadOptions{Rand: rand.New(rand.NewSource(42))}, sample)
+		if err != nil {
+			t.Fatalf("ReadTable() error = %v", err)
+		}
+		second, err := ReadTable(testFile, reader.ReadOptions{Rand: rand.New(rand.NewSource(42))}, sample)
+		if err != nil {
+			t.Fatalf("ReadTable() error = %v", err)
+		}
+
+		if len(first) != len(second) {
+			t.Fatalf("Expected same sample size with the same seed, got %d and %d", len(first), len(second))
+		}
+		for i := range first {
+			if first[i]["id"] != second[i]["id"] {
+				t.Fatalf("Row %d differs between seeded samples: %v vs %v", i, first[i]["id"], second[i]["id"])
+			}
+		}
+	})
+}
+
+// TestParquetUUIDFilter tests filtering on UUID columns using their canonical string form
+func TestParquetUUIDFilter(t *testing.T) {
+	testData := []UUIDDataRow{
+		{
+			ID:       [16]byte{0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4, 0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00},
+			Name:     "Alice",
+			Checksum: [4]byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			ID:       [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+			Name:     "Bob",
+			Checksum: [4]byte{0x00, 0x00, 0x00, 0x01},
+		},
+	}
+
+	testFile := createUUIDParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		validate func(t *testing.T, rows []map[string]interface{})
+	}{
+		{
+			name:     "filter by canonical uuid string",
+			queryTpl: "SELECT name, id FROM '%s' WHERE id = '550e8400-e29b-41d4-a716-446655440000'",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if len(rows) != 1 {
+					t.Fatalf("Expected 1 row, got %d", len(rows))
+				}
+				if rows[0]["name"] != "Alice" {
+					t.Errorf("Expected Alice, got %v", rows[0]["name"])
+				}
+				if rows[0]["id"] != "550e8400-e29b-41d4-a716-446655440000" {
+					t.Errorf("Expected canonical uuid, got %v", rows[0]["id"])
+				}
+			},
+		},
+		{
+			name:     "fixed byte array compares as hex",
+			queryTpl: "SELECT name FROM '%s' WHERE checksum = '00000001'",
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if len(rows) != 1 || rows[0]["name"] != "Bob" {
+					t.Errorf("Expected only Bob, got %v", rows)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			tt.validate(t, results)
+		})
+	}
+}
+
+// TestParquetNegativeNumberFilter tests filters with negative and
+// exponent-notation thresholds
+func TestParquetNegativeNumberFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Salary: -1500.0, Score: -0.25},
+		{ID: 2, Name: "Bob", Salary: -1200.0, Score: 0.5},
+		{ID: 3, Name: "Carol", Salary: 300.0, Score: -0.75},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "negative exponent threshold", queryTpl: "SELECT id FROM '%s' WHERE salary < -1.2e3", wantIDs: []int64{1}},
+		{name: "leading dot", queryTpl: "SELECT id FROM '%s' WHERE score > -.5", wantIDs: []int64{1, 2}},
+		{name: "minus separated from its number", queryTpl: "SELECT id FROM '%s' WHERE score < - 0.5", wantIDs: []int64{3}},
+		{name: "negative BETWEEN bounds", queryTpl: "SELECT id FROM '%s' WHERE salary BETWEEN -1.3e3 AND 1E3", wantIDs: []int64{2, 3}},
+		{name: "negated column", queryTpl: "SELECT id FROM '%s' WHERE -salary > 1000 AND -score > 0", wantIDs: []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetArithmeticOnRight tests comparisons of a column with an
+// expression on the right side
+func TestParquetArithmeticOnRight(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0},
+		{ID: 3, Name: "Charlie", Age: 35, Salary: 60000.0},
+		{ID: 4, Name: "EVE", Age: 28, Salary: 40000.0},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name    string
+		where   string
+		wantIDs []int64
+	}{
+		{name: "product", where: "salary > 1000 * 49", wantIDs: []int64{1, 3}},
+		{name: "negative sum", where: "id = -1 + 2", wantIDs: []int64{1}},
+		{name: "columns", where: "age > id * 10 + 5", wantIDs: []int64{1}},
+		{name: "function", where: "name = UPPER(name)", wantIDs: []int64{4}},
+		{name: "parenthesized", where: "age <= (salary / 1000) - 20", wantIDs: []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetParenthesizedFilter tests that parentheses override AND binding
+// tighter than OR, comparing each grouped condition with the same condition
+// without parentheses
+func TestParquetParenthesizedFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0, Active: true, Score: 85.5},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0, Active: false, Score: 72.3},
+		{ID: 3, Name: "Charlie", Age: 35, Salary: 60000.0, Active: true, Score: 91.2},
+		{ID: 4, Name: "Diana", Age: 28, Salary: 52000.0, Active: true, Score: 78.9},
+		{ID: 5, Name: "Eve", Age: 25, Salary: 48000.0, Active: false, Score: 88.1},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name    string
+		where   string
+		wantIDs []int64
+	}{
+		{name: "OR then AND", where: "active = false OR age > 29 AND salary > 55000.0", wantIDs: []int64{2, 3, 5}},
+		{name: "grouped OR then AND", where: "(active = false OR age > 29) AND salary > 55000.0", wantIDs: []int64{3}},
+		{name: "AND then OR", where: "age < 26 AND active OR score > 85", wantIDs: []int64{1, 3, 5}},
+		{name: "AND then grouped OR", where: "age < 26 AND (active OR score > 85)", wantIDs: []int64{5}},
+		{name: "redundant parentheses", where: "((age < 26)) AND ((active) OR (score > 85))", wantIDs: []int64{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetILikeFilter tests case-insensitive pattern matching on
+// mixed-case data
+func TestParquetILikeFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "ALICIA"},
+		{ID: 3, Name: "malice"},
+		{ID: 4, Name: "Bob"},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "LIKE is case-sensitive", queryTpl: "SELECT id FROM '%s' WHERE name LIKE 'ali%%'", wantIDs: nil},
+		{name: "ILIKE prefix", queryTpl: "SELECT id FROM '%s' WHERE name ILIKE 'ali%%'", wantIDs: []int64{1, 2}},
+		{name: "ILIKE contains", queryTpl: "SELECT id FROM '%s' WHERE name ilike '%%LIC%%'", wantIDs: []int64{1, 2, 3}},
+		{name: "ILIKE single char wildcard", queryTpl: "SELECT id FROM '%s' WHERE name ILIKE 'b_B'", wantIDs: []int64{4}},
+		{name: "NOT ILIKE", queryTpl: "SELECT id FROM '%s' WHERE name NOT ILIKE '%%ice'", wantIDs: []int64{2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetLikeEscapeFilter tests matching literal % and _ with ESCAPE
+func TestParquetLikeEscapeFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "100% cotton"},
+		{ID: 2, Name: "1000 cotton"},
+		{ID: 3, Name: "user_id"},
+		{ID: 4, Name: "userid2"},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "percent is a wildcard without ESCAPE", queryTpl: "SELECT id FROM '%s' WHERE name LIKE '100%%'", wantIDs: []int64{1, 2}},
+		{name: "escaped percent", queryTpl: "SELECT id FROM '%s' WHERE name LIKE '100!%% %%' ESCAPE '!'", wantIDs: []int64{1}},
+		{name: "escaped underscore", queryTpl: "SELECT id FROM '%s' WHERE name LIKE 'user#_%%' ESCAPE '#'", wantIDs: []int64{3}},
+		{name: "backslash escape", queryTpl: `SELECT id FROM '%s' WHERE name LIKE '%%\\%%%%' ESCAPE '\\'`, wantIDs: []int64{1}},
+		{name: "NOT ILIKE with ESCAPE", queryTpl: "SELECT id FROM '%s' WHERE name NOT ILIKE 'USER!_%%' ESCAPE '!'", wantIDs: []int64{1, 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetNumericCoercionFilter tests int64 columns against integer and
+// float literals
+func TestParquetNumericCoercionFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Age: 25},
+		{ID: 2, Age: 26},
+		{ID: 10000000000, Age: 30},
+		{ID: 10000000001, Age: 30},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "equal int", queryTpl: "SELECT id FROM '%s' WHERE age = 25", wantIDs: []int64{1}},
+		{name: "equal float", queryTpl: "SELECT id FROM '%s' WHERE age = 25.0", wantIDs: []int64{1}},
+		{name: "fraction matches nothing", queryTpl: "SELECT id FROM '%s' WHERE age = 25.5", wantIDs: nil},
+		{name: "IN ints", queryTpl: "SELECT id FROM '%s' WHERE age IN (25, 26)", wantIDs: []int64{1, 2}},
+		{name: "IN floats", queryTpl: "SELECT id FROM '%s' WHERE age IN (25.0, 26.5)", wantIDs: []int64{1}},
+		{name: "greater than fraction", queryTpl: "SELECT id FROM '%s' WHERE age > 25.5 AND age <= 26.0", wantIDs: []int64{2}},
+		{name: "BETWEEN floats", queryTpl: "SELECT id FROM '%s' WHERE age BETWEEN 24.5 AND 25.0", wantIDs: []int64{1}},
+		{name: "large ids are exact", queryTpl: "SELECT id FROM '%s' WHERE id = 10000000001", wantIDs: []int64{10000000001}},
+		{name: "large ids against a float", queryTpl: "SELECT id FROM '%s' WHERE id >= 10000000000.5", wantIDs: []int64{10000000001}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetNotFilter tests NOT combined with parenthesized AND/OR
+func TestParquetNotFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 35, Active: true},
+		{ID: 2, Name: "Bob", Age: 35, Active: false},
+		{ID: 3, Name: "Carol", Age: 25, Active: true},
+		{ID: 4, Name: "Dave", Age: 25, Active: false},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "NOT of AND", queryTpl: "SELECT id FROM '%s' WHERE NOT (age > 30 AND active = true)", wantIDs: []int64{2, 3, 4}},
+		{name: "NOT of OR", queryTpl: "SELECT id FROM '%s' WHERE NOT (age > 30 OR active)", wantIDs: []int64{4}},
+		{name: "NOT inside OR", queryTpl: "SELECT id FROM '%s' WHERE NOT active OR (age < 30 AND id > 3)", wantIDs: []int64{2, 4}},
+		{name: "grouped OR with AND", queryTpl: "SELECT id FROM '%s' WHERE (id = 1 OR id = 4) AND NOT (name LIKE 'D%%')", wantIDs: []int64{1}},
+		{name: "nested groups", queryTpl: "SELECT id FROM '%s' WHERE NOT ((age > 30 AND active) OR (age < 30 AND NOT active))", wantIDs: []int64{2, 3}},
+		{name: "NOT with an expression", queryTpl: "SELECT id FROM '%s' WHERE NOT (age * 2) > 60", wantIDs: []int64{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetEscapedQuoteFilter tests string literals with embedded quotes
+func TestParquetEscapedQuoteFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "O'Brien"},
+		{ID: 2, Name: "it's"},
+		{ID: 3, Name: "Obrien"},
+		{ID: 4, Name: "'"},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		wantIDs  []int64
+	}{
+		{name: "doubled quote", queryTpl: "SELECT id FROM '%s' WHERE name = 'O''Brien'", wantIDs: []int64{1}},
+		{name: "backslash escape", queryTpl: `SELECT id FROM '%s' WHERE name = 'it\'s'`, wantIDs: []int64{2}},
+		{name: "quote alone", queryTpl: "SELECT id FROM '%s' WHERE name = ''''", wantIDs: []int64{4}},
+		{name: "LIKE pattern", queryTpl: "SELECT id FROM '%s' WHERE name LIKE '%%''%%' ORDER BY id", wantIDs: []int64{1, 2, 4}},
+		{name: "IN list", queryTpl: "SELECT id FROM '%s' WHERE name IN ('O''Brien', 'Obrien') ORDER BY id", wantIDs: []int64{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParquetQuotedIdentifiers tests columns whose names have spaces, are
+// keywords or contain quotes
+func TestParquetQuotedIdentifiers(t *testing.T) {
+	testData := []QuotedNameDataRow{
+		{ID: 1, FirstName: "Alice", Order: 3, Quoted: "x"},
+		{ID: 2, FirstName: "Bob", Order: 1, Quoted: "y"},
+		{ID: 3, FirstName: "Carol", Order: 2, Quoted: "z"},
+	}
+
+	testFile := createQuotedNameParquetFile(t, testData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		want     []map[string]interface{}
+	}{
+		{
+			name:     "column with a space",
+			queryTpl: `SELECT "first name" FROM "%s" WHERE id = 2`,
+			want:     []map[string]interface{}{{"first name": "Bob"}},
+		},
+		{
+			name:     "keyword column in every clause",
+			queryTpl: `SELECT "first name", "order" FROM "%s" WHERE "order" > 1 ORDER BY "order"`,
+			want: []map[string]interface{}{
+				{"first name": "Carol", "order": int64(2)},
+				{"first name": "Alice", "order": int64(3)},
+			},
+		},
+		{
+			name:     "doubled quotes",
+			queryTpl: `SELECT "say ""hi""" AS greeting FROM "%s" WHERE "first name" = 'Alice'`,
+			want:     []map[string]interface{}{{"greeting": "x"}},
+		},
+		{
+			name:     "quoted alias",
+			queryTpl: `SELECT UPPER("first name") AS "Upper Name" FROM "%s" WHERE id = 3`,
+			want:     []map[string]interface{}{{"Upper Name": "CAROL"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(tt.queryTpl, testFile)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("ExecuteQuery() = %v, want %v", results, tt.want)
+			}
+		})
+	}
+}
+
+// TestParquetNaNFilter tests that NaN floats never satisfy comparisons
+func TestParquetNaNFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Score: 85.5},
+		{ID: 2, Name: "Bob", Score: math.NaN()},
+		{ID: 3, Name: "Charlie", Score: math.Inf(1)},
+	}
+
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name      string
+		queryTpl  string
+		wantNames []string
+	}{
+		{"greater than", "SELECT name FROM '%s' WHERE score > 50", []string{"Alice", "Charlie"}},
+		{"less than", "SELECT name FROM '%s' WHERE score < 1000", []string{"Alice"}},
+		{"equal", "SELECT name FROM '%s' WHERE score = 85.5", []string{"Alice"}},
+		{"not equal", "SELECT name FROM '%s' WHERE score != 85.5", []string{"Bob", "Charlie"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != len(tt.wantNames) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.wantNames), len(results), results)
+			}
+			for i, want := range tt.wantNames {
+				if results[i]["name"] != want {
+					t.Errorf("Row %d: expected %s, got %v", i, want, results[i]["name"])
+				}
+			}
+		})
+	}
+}
+
+// TestParquetInferTypes tests that numbers stored as strings can be compared
+// numerically when type inference is enabled
+func TestParquetInferTypes(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "50"},
+		{ID: 2, Name: "150"},
+		{ID: 3, Name: "1000"},
+	}
+	testFile := createBasicParquetFile(t, testData)
+
+	q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE name > 100 ORDER BY name", testFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Without inference the string column can't be compared with a number
+	if _, err := NewExecutionContext(nil).executeSelect(q); err == nil {
+		t.Fatal("expected a type error comparing a string column with a number")
+	}
+
+	ctx := NewExecutionContext(nil)
+	ctx.ReadOptions = reader.ReadOptions{InferTypes: true}
+	results, err := ctx.executeSelect(q)
+	if err != nil {
+		t.Fatalf("executeSelect() error = %v", err)
+	}
+
+	if len(results) != 2 || results[0]["id"] != int64(2) || results[1]["id"] != int64(3) {
+		t.Errorf("expected ids 2 and 3 in numeric order, got %v", results)
+	}
+}
+
+// TestParquetTupleIn tests row constructor IN lists on a composite key
+func TestParquetTupleIn(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Active: true},
+		{ID: 2, Name: "Bob", Age: 25, Active: false},
+		{ID: 3, Name: "Charlie", Age: 30, Active: false},
+		{ID: 4, Name: "Diana", Age: 25, Active: true},
+		{ID: 5, Name: "Eve", Age: 35, Active: true},
+	}
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name    string
+		where   string
+		wantIDs []int64
+	}{
+		{name: "tuple in", where: "(age, active) IN ((30, true), (25, false))", wantIDs: []int64{1, 2}},
+		{name: "tuple not in", where: "(age, active) NOT IN ((30, true), (25, false))", wantIDs: []int64{3, 4, 5}},
+		{name: "tuple in combined with and", where: "(age, active) IN ((30, false), (35, true)) AND id > 3", wantIDs: []int64{5}},
+		{name: "no matching tuple", where: "(age, active) IN ((40, true))", wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s ORDER BY id", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+// TestParquetRegexpFilter tests filtering and transforming rows with regular expressions
+func TestParquetRegexpFilter(t *testing.T) {
+	testData := []BasicDataRow{
+		{ID: 1, Name: "alice@example.com"},
+		{ID: 2, Name: "bob@example.org"},
+		{ID: 3, Name: "carol@EXAMPLE.com"},
+		{ID: 4, Name: "dave@sub.example.com"},
+	}
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		name    string
+		where   string
+		wantIDs []int64
+	}{
+		{name: "anchored match", where: `REGEXP_MATCH(name, '@example\\.com$')`, wantIDs: []int64{1}},
+		{name: "case-insensitive", where: `REGEXP_MATCH(name, '(?i)[@.]example\\.com$')`, wantIDs: []int64{1, 3, 4}},
+		{name: "negated", where: `REGEXP_MATCH(name, '\\.com$') = false`, wantIDs: []int64{2}},
+		{name: "replace in comparison", where: `REGEXP_REPLACE(name, '@.*', '') = 'dave'`, wantIDs: []int64{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s ORDER BY id", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+// TestParquetTimestampFilter tests time-range filters on a TIMESTAMP column
+func TestParquetTimestampFilter(t *testing.T) {
+	testData := []ComplexDataRow{
+		{ID: 1, Name: "Alpha", Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{ID: 2, Name: "Beta", Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, Name: "Gamma", Timestamp: time.Date(2024, 2, 20, 14, 45, 0, 0, time.UTC)},
+		{ID: 4, Name: "Delta", Timestamp: time.Date(2024, 3, 10, 9, 15, 0, 0, time.UTC)},
+	}
+	testFile := createComplexParquetFile(t, testData)
+
+	tests := []struct {
+		name    string
+		where   string
+		wantIDs []int64
+		wantErr string
+	}{
+		{name: "after a date", where: "timestamp > '2024-02-01'", wantIDs: []int64{3, 4}},
+		{name: "on or after a date", where: "timestamp >= '2024-02-01'", wantIDs: []int64{2, 3, 4}},
+		{name: "before a timestamp", where: "timestamp < '2024-02-20 14:45:00'", wantIDs: []int64{1, 2}},
+		{name: "equal to an RFC 3339 timestamp", where: "timestamp = '2024-01-15T10:30:00Z'", wantIDs: []int64{1}},
+		{name: "RFC 3339 with an offset", where: "timestamp = '2024-01-15T12:30:00+02:00'", wantIDs: []int64{1}},
+		{name: "range", where: "timestamp >= '2024-02-01' AND timestamp < '2024-03-01'", wantIDs: []int64{2, 3}},
+		{name: "between", where: "timestamp BETWEEN '2024-01-01' AND '2024-02-01'", wantIDs: []int64{1, 2}},
+		{name: "in", where: "timestamp IN ('2024-02-01', '2024-03-10T09:15:00Z')", wantIDs: []int64{2, 4}},
+		{name: "invalid date", where: "timestamp > '2024-13-01'", wantErr: `cannot parse date "2024-13-01"`},
+		{name: "not a date", where: "timestamp > 'last week'", wantErr: "expected 2006-01-02T15:04:05Z07:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s ORDER BY id", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(testFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExecuteQuery() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
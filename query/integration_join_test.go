@@ -1,6 +1,9 @@
 package query
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/vegasq/parcat/reader"
@@ -217,6 +220,262 @@ func TestParquetLeftJoin(t *testing.T) {
 	}
 }
 
+// TestParquetOuterJoinEmptyTable tests that outer joins against a parquet
+// file with no rows still add its columns as NULLs
+func TestParquetOuterJoinEmptyTable(t *testing.T) {
+	tmpDir := t.TempDir()
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+	})
+	emptyFile := createEmptyParquetFile(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string // non-NULL column of each row
+		nullCols  []string // columns every row must have as NULL
+	}{
+		{
+			name:      "left join",
+			query:     fmt.Sprintf("SELECT * FROM '%s' u LEFT JOIN '%s' o ON u.id = o.age", usersFile, emptyFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"o.id", "o.name", "o.age", "o.salary", "o.active", "o.score"},
+		},
+		{
+			name:      "right join",
+			query:     fmt.Sprintf("SELECT * FROM '%s' o RIGHT JOIN '%s' u ON u.id = o.age", emptyFile, usersFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"o.id", "o.name", "o.salary"},
+		},
+		{
+			name:      "full join",
+			query:     fmt.Sprintf("SELECT * FROM '%s' u FULL JOIN '%s' o ON u.id = o.age", usersFile, emptyFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"o.id", "o.salary"},
+		},
+		{
+			name:      "projected column of the empty table",
+			query:     fmt.Sprintf("SELECT u.name, o.salary FROM '%s' u LEFT JOIN '%s' o ON u.id = o.age", usersFile, emptyFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"o.salary"},
+		},
+		{
+			name:      "empty result of a previous join",
+			query:     fmt.Sprintf("SELECT * FROM '%s' a JOIN '%s' b ON a.id = b.id RIGHT JOIN '%s' u ON u.id = a.id", emptyFile, emptyFile, usersFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"a.name", "b.name"},
+		},
+		{
+			name:      "left join subquery with no rows",
+			query:     fmt.Sprintf("SELECT * FROM '%s' u LEFT JOIN (SELECT id, name AS label FROM '%s' WHERE id < 0) b ON u.id = b.id", usersFile, usersFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"b.id", "b.label"},
+		},
+		{
+			name:      "left join star subquery with no rows",
+			query:     fmt.Sprintf("SELECT * FROM '%s' u LEFT JOIN (SELECT * FROM '%s' WHERE id < 0) b ON u.id = b.id", usersFile, usersFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"b.id", "b.name", "b.age"},
+		},
+		{
+			name:      "right join FROM subquery with no rows",
+			query:     fmt.Sprintf("SELECT * FROM (SELECT name, age FROM '%s') o RIGHT JOIN '%s' u ON u.id = o.age", emptyFile, usersFile),
+			wantNames: []string{"Alice", "Bob"},
+			nullCols:  []string{"o.name", "o.age"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			results, err := NewExecutionContext(nil).executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			if len(results) != len(tt.wantNames) {
+				t.Fatalf("expected %d rows, got %d: %v", len(tt.wantNames), len(results), results)
+			}
+			for i, row := range results {
+				if row["u.name"] != tt.wantNames[i] {
+					t.Errorf("row %d: u.name = %v, want %s", i, row["u.name"], tt.wantNames[i])
+				}
+				for _, col := range tt.nullCols {
+					if value, ok := row[col]; !ok || value != nil {
+						t.Errorf("row %d: %s = %v (present %v), want a NULL column", i, col, value, ok)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParquetJoinColumnCollisions(t *testing.T) {
+	tmpDir := t.TempDir()
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+	})
+	ordersFile := createNamedBasicParquetFile(t, tmpDir, "orders.parquet", []BasicDataRow{
+		{ID: 1, Name: "Book", Age: 3},
+		{ID: 3, Name: "Lamp", Age: 5},
+	})
+
+	tests := []struct {
+		name    string
+		query   string
+		strict  bool
+		want    map[string]interface{} // columns of the single result row
+		wantErr string
+	}{
+		{
+			name:  "file names qualify shared columns",
+			query: fmt.Sprintf("SELECT * FROM '%s' JOIN '%s' ON users.id = orders.id", usersFile, ordersFile),
+			want:  map[string]interface{}{"users.id": int64(1), "orders.id": int64(1), "users.name": "Alice", "orders.name": "Book"},
+		},
+		{
+			name:  "unnamed subquery gets suffixes",
+			query: fmt.Sprintf("SELECT * FROM '%s' JOIN (SELECT id, name FROM '%s') ON users.id = id_1", usersFile, ordersFile),
+			want:  map[string]interface{}{"users.id": int64(1), "id_1": int64(1), "users.name": "Alice", "name_1": "Book"},
+		},
+		{
+			name:  "left join keeps renamed NULL columns",
+			query: fmt.Sprintf("SELECT users.name, orders.name FROM '%s' LEFT JOIN '%s' ON users.id = orders.id WHERE users.id = 2", usersFile, ordersFile),
+			want:  map[string]interface{}{"users.name": "Bob", "orders.name": nil},
+		},
+		{
+			name:    "strict mode fails",
+			query:   fmt.Sprintf("SELECT * FROM '%s' JOIN '%s' ON users.id = orders.id", usersFile, ordersFile),
+			strict:  true,
+			wantErr: "column name collision",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			ctx := NewExecutionContext(nil)
+			ctx.StrictJoins = tt.strict
+			results, err := ctx.executeSelect(q)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeSelect() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			if len(results) != 1 {
+				t.Fatalf("expected 1 row, got %d: %v", len(results), results)
+			}
+			for col, want := range tt.want {
+				if got, ok := results[0][col]; !ok || got != want {
+					t.Errorf("%s = %v (present %v), want %v", col, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParquetJoinUsing(t *testing.T) {
+	tmpDir := t.TempDir()
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+	})
+	ordersFile := createNamedBasicParquetFile(t, tmpDir, "orders.parquet", []BasicDataRow{
+		{ID: 1, Name: "Book", Age: 3},
+		{ID: 3, Name: "Lamp", Age: 5},
+	})
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []map[string]interface{} // columns of each result row
+		wantErr string
+	}{
+		{
+			name:  "inner join",
+			query: fmt.Sprintf("SELECT * FROM '%s' JOIN '%s' USING (id)", usersFile, ordersFile),
+			want:  []map[string]interface{}{{"id": int64(1), "users.name": "Alice", "orders.name": "Book"}},
+		},
+		{
+			name:  "left join keeps unmatched rows",
+			query: fmt.Sprintf("SELECT * FROM '%s' LEFT JOIN '%s' USING (id) ORDER BY id", usersFile, ordersFile),
+			want: []map[string]interface{}{
+				{"id": int64(1), "users.name": "Alice", "orders.name": "Book"},
+				{"id": int64(2), "users.name": "Bob", "orders.name": nil},
+			},
+		},
+		{
+			name:  "right join takes the key from the right",
+			query: fmt.Sprintf("SELECT * FROM '%s' RIGHT JOIN '%s' USING (id) ORDER BY id", usersFile, ordersFile),
+			want: []map[string]interface{}{
+				{"id": int64(1), "users.name": "Alice"},
+				{"id": int64(3), "users.name": nil, "orders.name": "Lamp"},
+			},
+		},
+		{
+			name:  "aliases and several columns",
+			query: fmt.Sprintf("SELECT * FROM '%s' u JOIN '%s' o USING (id, age)", usersFile, usersFile),
+			want: []map[string]interface{}{
+				{"id": int64(1), "age": int64(30), "u.name": "Alice", "o.name": "Alice"},
+				{"id": int64(2), "age": int64(25), "u.name": "Bob", "o.name": "Bob"},
+			},
+		},
+		{
+			name:    "unknown column",
+			query:   fmt.Sprintf("SELECT * FROM '%s' JOIN '%s' USING (nope)", usersFile, ordersFile),
+			wantErr: "column nope not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			results, err := NewExecutionContext(nil).executeSelect(q)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeSelect() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("expected %d rows, got %d: %v", len(tt.want), len(results), results)
+			}
+			for i, want := range tt.want {
+				for col, wantVal := range want {
+					if got, ok := results[i][col]; !ok || got != wantVal {
+						t.Errorf("row %d: %s = %v (present %v), want %v", i, col, got, ok, wantVal)
+					}
+				}
+				// The USING column appears once, under its own name
+				for col := range results[i] {
+					if strings.HasSuffix(col, ".id") || strings.HasPrefix(col, "id_") {
+						t.Errorf("row %d has duplicate USING column %s", i, col)
+					}
+				}
+			}
+		})
+	}
+}
+
 // TestParquetRightJoin tests RIGHT JOIN with real parquet files
 func TestParquetRightJoin(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -608,3 +867,311 @@ func TestParquetMultipleJoins(t *testing.T) {
 	}
 }
 
+// TestParquetParenthesizedJoin tests that a parenthesized join is evaluated
+// as its own row set before the outer join
+func TestParquetParenthesizedJoin(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	usersData := []BasicDataRow{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", usersData)
+
+	// Orders (Age = user_id); Carol's order has no product
+	ordersData := []BasicDataRow{
+		{ID: 101, Name: "Order-A", Age: 1},
+		{ID: 102, Name: "Order-B", Age: 2},
+		{ID: 103, Name: "Order-C", Age: 3},
+	}
+	ordersFile := createNamedBasicParquetFile(t, tmpDir, "orders.parquet", ordersData)
+
+	// Products (Age = order_id)
+	productsData := []BasicDataRow{
+		{ID: 1001, Name: "Product-X", Age: 101},
+		{ID: 1002, Name: "Product-Y", Age: 102},
+	}
+	productsFile := createNamedBasicParquetFile(t, tmpDir, "products.parquet", productsData)
+
+	tests := []struct {
+		name      string
+		queryTpl  string
+		wantUsers []string
+		validate  func(t *testing.T, rows []map[string]interface{})
+	}{
+		{
+			// The inner join after the left join drops Carol, who has no product
+			name:      "flat left-to-right chain",
+			queryTpl:  "SELECT u.name, p.name FROM '%s' u LEFT JOIN '%s' o ON u.id = o.age JOIN '%s' p ON o.id = p.age ORDER BY u.name",
+			wantUsers: []string{"Alice", "Bob"},
+		},
+		{
+			// The grouped inner join runs first, so the outer left join keeps Carol
+			name:      "parenthesized group",
+			queryTpl:  "SELECT u.name, p.name FROM '%s' u LEFT JOIN ('%s' o JOIN '%s' p ON o.id = p.age) ON u.id = o.age ORDER BY u.name",
+			wantUsers: []string{"Alice", "Bob", "Carol"},
+			validate: func(t *testing.T, rows []map[string]interface{}) {
+				if rows[0]["p.name"] != "Product-X" || rows[1]["p.name"] != "Product-Y" {
+					t.Errorf("Expected products X and Y for Alice and Bob, got %v", rows)
+				}
+				if rows[2]["p.name"] != nil {
+					t.Errorf("Expected NULL product for Carol, got %v", rows[2]["p.name"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, usersFile, ordersFile, productsFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(usersFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != len(tt.wantUsers) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.wantUsers), len(results), results)
+			}
+			for i, want := range tt.wantUsers {
+				if results[i]["u.name"] != want {
+					t.Errorf("Row %d: expected %s, got %v", i, want, results[i]["u.name"])
+				}
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, results)
+			}
+		})
+	}
+}
+
+func TestParquetJoinNullSafeEquality(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	leftData := []ComplexDataRow{
+		{ID: 1, Name: "Alice", Age: int64Ptr(30)},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol", Age: int64Ptr(40)},
+	}
+	leftFile := createNamedComplexParquetFile(t, tmpDir, "left.parquet", leftData)
+
+	rightData := []ComplexDataRow{
+		{ID: 10, Name: "x", Age: int64Ptr(30)},
+		{ID: 20, Name: "y"},
+		{ID: 30, Name: "z", Age: int64Ptr(50)},
+	}
+	rightFile := createNamedComplexParquetFile(t, tmpDir, "right.parquet", rightData)
+
+	tests := []struct {
+		name      string
+		condition string
+		want      [][2]int64
+	}{
+		{name: "null-safe equality", condition: "l.age <=> r.age", want: [][2]int64{{1, 10}, {2, 20}}},
+		{name: "IS NOT DISTINCT FROM", condition: "l.age IS NOT DISTINCT FROM r.age", want: [][2]int64{{1, 10}, {2, 20}}},
+		{
+			name:      "IS DISTINCT FROM",
+			condition: "l.age IS DISTINCT FROM r.age AND r.id = 20",
+			want:      [][2]int64{{1, 20}, {3, 20}},
+		},
+		// Comparing NULL is unknown, so the negated comparison drops the NULL pairs
+		{
+			name:      "negated equality",
+			condition: "NOT (l.age = r.age) AND r.id = 20",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf("SELECT l.id, r.id FROM '%s' l JOIN '%s' r ON %s ORDER BY l.id, r.id", leftFile, rightFile, tt.condition)
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(leftFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var got [][2]int64
+			for _, row := range results {
+				got = append(got, [2]int64{row["l.id"].(int64), row["r.id"].(int64)})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pairs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParquetMixedIntegerWidths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	usersData := []BasicDataRow{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", usersData)
+
+	// INT32 user_id and INT(16) count against INT64 id and limit_value
+	ordersData := []WidthDataRow{
+		{ID: 101, UserID: 1, Limit: 5, Count: 5},
+		{ID: 102, UserID: 1, Limit: 5, Count: 2},
+		{ID: 103, UserID: 3, Limit: 1, Count: 4},
+	}
+	ordersFile := createNamedWidthParquetFile(t, tmpDir, "orders.parquet", ordersData)
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		want     []map[string]interface{}
+	}{
+		{
+			name:     "join int64 column to int32 column",
+			queryTpl: "SELECT u.name, o.id FROM '%[1]s' u JOIN '%[2]s' o ON u.id = o.user_id ORDER BY o.id",
+			want: []map[string]interface{}{
+				{"u.name": "Alice", "o.id": int64(101)},
+				{"u.name": "Alice", "o.id": int64(102)},
+				{"u.name": "Carol", "o.id": int64(103)},
+			},
+		},
+		{
+			name:     "compare int16 column to int64 column",
+			queryTpl: "SELECT id, count FROM '%[2]s' WHERE count >= limit_value ORDER BY id",
+			want: []map[string]interface{}{
+				{"id": int64(101), "count": int64(5)},
+				{"id": int64(103), "count": int64(4)},
+			},
+		},
+		{
+			name:     "IN list against int32 column",
+			queryTpl: "SELECT user_id FROM '%[2]s' WHERE user_id IN (SELECT id FROM '%[1]s' WHERE name = 'Carol')",
+			want: []map[string]interface{}{
+				{"user_id": int64(3)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, usersFile, ordersFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			r, err := reader.NewReader(usersFile)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			results, err := ExecuteQuery(q, r)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.want), len(results), results)
+			}
+			for i, want := range tt.want {
+				for col, value := range want {
+					// Compare with types so narrower integers must have been widened
+					if results[i][col] != value {
+						t.Errorf("Row %d column %s: expected %#v, got %#v", i, col, value, results[i][col])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParquetJoinQualifiedWildcard(t *testing.T) {
+	tmpDir := t.TempDir()
+	usersFile := createNamedBasicParquetFile(t, tmpDir, "users.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30, Salary: 50000.0, Active: true, Score: 85.5},
+		{ID: 2, Name: "Bob", Age: 25, Salary: 45000.0, Active: false, Score: 72.3},
+	})
+	ordersFile := createNamedBasicParquetFile(t, tmpDir, "orders.parquet", []BasicDataRow{
+		{ID: 101, Name: "Book", Age: 1, Salary: 250.0},
+	})
+	from := fmt.Sprintf("FROM '%s' u LEFT JOIN '%s' o ON u.id = o.age", usersFile, ordersFile)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "one table's columns and another column",
+			query: "SELECT u.*, o.salary AS amount " + from + " ORDER BY u.id",
+			want: []map[string]interface{}{
+				{"u.id": int64(1), "u.name": "Alice", "u.age": int64(30), "u.salary": 50000.0, "u.active": true, "u.score": 85.5, "amount": 250.0},
+				{"u.id": int64(2), "u.name": "Bob", "u.age": int64(25), "u.salary": 45000.0, "u.active": false, "u.score": 72.3, "amount": nil},
+			},
+		},
+		{
+			name:  "columns of the outer joined side",
+			query: "SELECT u.name, o.* " + from + " WHERE u.id = 2",
+			want: []map[string]interface{}{
+				{"u.name": "Bob", "o.id": nil, "o.name": nil, "o.age": nil, "o.salary": nil, "o.active": nil, "o.score": nil},
+			},
+		},
+		{
+			name:  "same column from both tables",
+			query: "SELECT o.*, u.* " + from + " WHERE u.id = 1",
+			want: []map[string]interface{}{
+				{
+					"o.id": int64(101), "o.name": "Book", "o.age": int64(1), "o.salary": 250.0, "o.active": false, "o.score": 0.0,
+					"u.id": int64(1), "u.name": "Alice", "u.age": int64(30), "u.salary": 50000.0, "u.active": true, "u.score": 85.5,
+				},
+			},
+		},
+		{
+			name:    "unknown table",
+			query:   "SELECT x.* " + from,
+			wantErr: "x.* matches no columns",
+		},
+		{
+			name:    "alias",
+			query:   "SELECT u.* AS all_columns " + from,
+			wantErr: "u.* cannot have an alias",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err == nil {
+				var results []map[string]interface{}
+				results, err = ExecuteQuery(q, nil)
+				if err == nil && !reflect.DeepEqual(results, tt.want) {
+					t.Errorf("ExecuteQuery() = %v, want %v", results, tt.want)
+				}
+			}
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/vegasq/parcat/reader"
@@ -229,3 +230,153 @@ func TestParquetOrderBy(t *testing.T) {
 		})
 	}
 }
+
+// TestParquetOrderByAliasPositionExpression tests ORDER BY on SELECT aliases,
+// SELECT positions and expressions
+func TestParquetOrderByAliasPositionExpression(t *testing.T) {
+	testFile := createBasicParquetFile(t, []BasicDataRow{
+		{ID: 1, Name: "Charlie", Age: 30, Salary: 50000.0},
+		{ID: 2, Name: "Alice", Age: 25, Salary: 45000.0},
+		{ID: 3, Name: "Bob", Age: 35, Salary: 60000.0},
+		{ID: 4, Name: "Diana", Age: 25, Salary: 52000.0},
+		{ID: 5, Name: "Eve", Age: 30, Salary: 48000.0},
+	})
+
+	tests := []struct {
+		name     string
+		queryTpl string
+		column   string        // column whose values are checked
+		want     []interface{} // values of column, in result order
+		columns  []string      // columns of every result row
+	}{
+		{
+			name:     "alias",
+			queryTpl: "SELECT name, salary * 2 AS bonus FROM '%s' ORDER BY bonus",
+			column:   "name",
+			want:     []interface{}{"Alice", "Eve", "Charlie", "Diana", "Bob"},
+			columns:  []string{"bonus", "name"},
+		},
+		{
+			name:     "position of an aliased item, descending",
+			queryTpl: "SELECT name, salary * 2 AS bonus FROM '%s' ORDER BY 2 DESC",
+			column:   "name",
+			want:     []interface{}{"Bob", "Diana", "Charlie", "Eve", "Alice"},
+			columns:  []string{"bonus", "name"},
+		},
+		{
+			name:     "position of an unnamed item",
+			queryTpl: "SELECT name, salary * 2 FROM '%s' ORDER BY 2",
+			column:   "name",
+			want:     []interface{}{"Alice", "Eve", "Charlie", "Diana", "Bob"},
+			columns:  []string{"col_1", "name"},
+		},
+		{
+			name:     "several positions",
+			queryTpl: "SELECT name, age FROM '%s' ORDER BY 2 DESC, 1",
+			column:   "name",
+			want:     []interface{}{"Bob", "Charlie", "Eve", "Alice", "Diana"},
+			columns:  []string{"age", "name"},
+		},
+		{
+			name:     "expression not in the SELECT list",
+			queryTpl: "SELECT name FROM '%s' ORDER BY salary * 2 DESC",
+			column:   "name",
+			want:     []interface{}{"Bob", "Diana", "Charlie", "Eve", "Alice"},
+			columns:  []string{"name"},
+		},
+		{
+			name:     "expression matching an aliased item",
+			queryTpl: "SELECT name, salary * 2 AS bonus FROM '%s' ORDER BY salary * 2 DESC",
+			column:   "name",
+			want:     []interface{}{"Bob", "Diana", "Charlie", "Eve", "Alice"},
+			columns:  []string{"bonus", "name"},
+		},
+		{
+			name:     "aggregate position",
+			queryTpl: "SELECT age, COUNT(*) AS n FROM '%s' GROUP BY age ORDER BY 2 DESC, 1",
+			column:   "age",
+			want:     []interface{}{int64(25), int64(30), int64(35)},
+			columns:  []string{"age", "n"},
+		},
+		{
+			name:     "aggregate not in the SELECT list",
+			queryTpl: "SELECT age FROM '%s' GROUP BY age ORDER BY SUM(salary) DESC",
+			column:   "age",
+			want:     []interface{}{int64(30), int64(25), int64(35)},
+			columns:  []string{"age"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.queryTpl, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			results, err := NewExecutionContext(nil).executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			var got []interface{}
+			for _, row := range results {
+				got = append(got, row[tt.column])
+				// Expressions sorted by but not selected are not returned
+				if cols := sortedColumns(row); !reflect.DeepEqual(cols, tt.columns) {
+					t.Errorf("columns = %v, want %v", cols, tt.columns)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParquetOrderByNulls tests where NULLs of a nullable column sort
+func TestParquetOrderByNulls(t *testing.T) {
+	testFile := createComplexParquetFile(t, []ComplexDataRow{
+		{ID: 1, Name: "Alice", Salary: float64Ptr(50000)},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Charlie", Salary: float64Ptr(40000)},
+		{ID: 4, Name: "Diana"},
+		{ID: 5, Name: "Eve", Salary: float64Ptr(60000)},
+	})
+
+	tests := []struct {
+		name     string
+		orderBy  string
+		wantName []interface{}
+	}{
+		{"ASC defaults to NULLS LAST", "salary", []interface{}{"Charlie", "Alice", "Eve", "Bob", "Diana"}},
+		{"DESC defaults to NULLS FIRST", "salary DESC", []interface{}{"Bob", "Diana", "Eve", "Alice", "Charlie"}},
+		{"ASC NULLS FIRST", "salary ASC NULLS FIRST", []interface{}{"Bob", "Diana", "Charlie", "Alice", "Eve"}},
+		{"DESC NULLS LAST", "salary DESC NULLS LAST", []interface{}{"Eve", "Alice", "Charlie", "Bob", "Diana"}},
+		{"without direction", "salary nulls first, id", []interface{}{"Bob", "Diana", "Charlie", "Alice", "Eve"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT name, salary FROM '%s' ORDER BY %s", testFile, tt.orderBy))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			results, err := NewExecutionContext(nil).executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			var got []interface{}
+			for _, row := range results {
+				got = append(got, row["name"])
+			}
+			if !reflect.DeepEqual(got, tt.wantName) {
+				t.Errorf("names = %v, want %v", got, tt.wantName)
+			}
+		})
+	}
+
+	if _, err := Parse(fmt.Sprintf("SELECT name FROM '%s' ORDER BY salary NULLS", testFile)); err == nil {
+		t.Error("Parse() with NULLS but no FIRST or LAST succeeded, want error")
+	}
+}
@@ -0,0 +1,720 @@
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// executeJoin executes a JOIN operation
+//
+// leftColumns are the columns of the left side, needed for the NULL columns
+// of outer joins when it has no rows, and may be nil if unknown. The columns
+// of the result are returned when it has no rows, for use by the next join.
+func (ctx *ExecutionContext) executeJoin(leftRows []map[string]interface{}, leftColumns []string, leftName string, join Join) ([]map[string]interface{}, []string, error) {
+	if join.Unnest != nil {
+		return ctx.UnnestJoin(leftRows, leftColumns, join)
+	}
+
+	// Get right-side data
+	var rightRows []map[string]interface{}
+	var rightColumns []string
+	var err error
+
+	if join.Subquery != nil {
+		// JOIN with subquery - use child context if subquery has CTEs to prevent scope leaking
+		var subqueryCtx *ExecutionContext
+		if len(join.Subquery.CTEs) > 0 {
+			subqueryCtx = ctx.NewChildContext()
+			if err := subqueryCtx.materializeCTEs(join.Subquery.CTEs); err != nil {
+				return nil, nil, fmt.Errorf("failed to materialize CTEs in JOIN subquery: %w", err)
+			}
+		} else {
+			subqueryCtx = ctx
+		}
+		rightRows, err = subqueryCtx.executeSelect(join.Subquery)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to execute JOIN subquery: %w", err)
+		}
+		if len(rightRows) == 0 {
+			rightColumns = aliasColumns(subqueryCtx.resultColumns(join.Subquery), join.Alias)
+		}
+	} else if join.Group != nil {
+		rightRows, err = ctx.ExecuteJoinGroup(join.Group)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if join.TableName != "" {
+		rightRows, err = ctx.readJoinTable(join.TableName)
+		if err != nil {
+			return nil, nil, err
+		}
+		rightColumns = ctx.emptyTableColumns(rightRows, join.TableName, join.Alias)
+	} else {
+		return nil, nil, fmt.Errorf("JOIN requires table name or subquery")
+	}
+
+	// Apply alias to right table rows if specified
+	if join.Alias != "" {
+		rightRows = applyTableAlias(rightRows, join.Alias)
+	}
+
+	// USING joins on its columns, renaming the right copy if it clashes
+	var using *UsingJoin
+	if len(join.UsingColumns) > 0 {
+		using, err = ResolveUsing(join.UsingColumns,
+			JoinInput{Rows: leftRows, Columns: leftColumns},
+			JoinInput{Rows: rightRows, Columns: rightColumns},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		rightRows, rightColumns = using.Right.Rows, using.Right.Columns
+		join.Condition = using.Condition
+	}
+
+	// Rename the columns both sides have rather than failing on them
+	if !ctx.StrictJoins {
+		var rightName string
+		if join.Subquery == nil && join.Group == nil {
+			rightName = JoinName(join.TableName, join.Alias)
+		}
+		left, right := DisambiguateJoinColumns(
+			JoinInput{Rows: leftRows, Columns: leftColumns, Name: leftName},
+			JoinInput{Rows: rightRows, Columns: rightColumns, Name: rightName},
+		)
+		leftRows, leftColumns = left.Rows, left.Columns
+		rightRows, rightColumns = right.Rows, right.Columns
+	}
+
+	// Execute the appropriate join algorithm
+	var rows []map[string]interface{}
+	switch join.Type {
+	case JoinInner:
+		rows, err = ctx.executeInnerJoin(leftRows, rightRows, join.Condition)
+	case JoinLeft:
+		rows, err = ctx.executeLeftJoin(leftRows, rightRows, rightColumns, join.Condition)
+	case JoinRight:
+		rows, err = ctx.executeRightJoin(leftRows, rightRows, leftColumns, join.Condition)
+	case JoinFull:
+		rows, err = ctx.executeFullJoin(leftRows, rightRows, leftColumns, rightColumns, join.Condition)
+	case JoinCross:
+		rows, err = ctx.executeCrossJoin(leftRows, rightRows)
+	default:
+		return nil, nil, fmt.Errorf("unsupported join type: %v", join.Type)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// An empty result still has the columns of both sides
+	var columns []string
+	if len(rows) == 0 {
+		columns = append(columnNames(leftRows, leftColumns), columnNames(rightRows, rightColumns)...)
+	}
+	if using != nil {
+		columns = using.Coalesce(rows, columns)
+	}
+	return rows, columns, nil
+}
+
+// readJoinTable reads the rows of a joined table, which may be a CTE reference
+func (ctx *ExecutionContext) readJoinTable(tableName string) ([]map[string]interface{}, error) {
+	// Check if it's a CTE reference
+	if cteRows, exists := ctx.CTEs[tableName]; exists {
+		return cteRows, nil
+	}
+	if ctx.AllCTENames[tableName] {
+		// This is a forward CTE reference (CTE defined but not yet materialized)
+		return nil, fmt.Errorf("forward CTE reference in JOIN: %s is defined but not yet materialized (CTEs must be referenced in order)", tableName)
+	}
+
+	// Read from parquet file
+	rows, err := ctx.ReadTable(tableName, ctx.readOptions(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JOIN table %s: %w", tableName, err)
+	}
+	return rows, nil
+}
+
+// ExecuteJoinGroup evaluates a parenthesized join expression into a single
+// row set, which is then used as the right-hand side of the enclosing JOIN
+func (ctx *ExecutionContext) ExecuteJoinGroup(group *JoinGroup) ([]map[string]interface{}, error) {
+	rows, err := ctx.readJoinTable(group.TableName)
+	if err != nil {
+		return nil, err
+	}
+	columns := ctx.emptyTableColumns(rows, group.TableName, group.Alias)
+	rows = applyTableAlias(rows, group.Alias)
+
+	leftName := JoinName(group.TableName, group.Alias)
+	for _, join := range group.Joins {
+		rows, columns, err = ctx.executeJoin(rows, columns, leftName, join)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute parenthesized JOIN: %w", err)
+		}
+		leftName = ""
+	}
+	return rows, nil
+}
+
+// applyTableAlias prefixes all column names with table alias
+func applyTableAlias(rows []map[string]interface{}, alias string) []map[string]interface{} {
+	if alias == "" {
+		return rows
+	}
+
+	aliasedRows := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		aliasedRow := make(map[string]interface{})
+		for col, val := range row {
+			// Don't alias the special _file column
+			if col == "_file" {
+				aliasedRow[col] = val
+			} else {
+				aliasedRow[alias+"."+col] = val
+			}
+		}
+		aliasedRows[i] = aliasedRow
+	}
+	return aliasedRows
+}
+
+// executeInnerJoin performs an INNER JOIN, as a hash join when the
+// condition allows it and with a nested loop otherwise
+func (ctx *ExecutionContext) executeInnerJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
+	if rows, ok, err := ctx.HashInnerJoin(leftRows, rightRows, condition); ok {
+		return rows, err
+	}
+	return ctx.nestedLoopInnerJoin(leftRows, rightRows, condition)
+}
+
+// nestedLoopInnerJoin performs an INNER JOIN by evaluating the condition on
+// every pair of rows
+func (ctx *ExecutionContext) nestedLoopInnerJoin(leftRows, rightRows []map[string]interface{}, condition Expression) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	for _, leftRow := range leftRows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, rightRow := range rightRows {
+			// Merge rows
+			merged, err := mergeRows(leftRow, rightRow)
+			if err != nil {
+				return nil, err
+			}
+
+			// Evaluate join condition
+			match, err := condition.Evaluate(merged)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
+			}
+
+			if match {
+				if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+					return nil, err
+				}
+				result = append(result, merged)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// executeLeftJoin performs a LEFT OUTER JOIN. rightColumns name the NULL
+// columns added when the right side has no rows, and may be nil.
+func (ctx *ExecutionContext) executeLeftJoin(leftRows, rightRows []map[string]interface{}, rightColumns []string, condition Expression) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	for _, leftRow := range leftRows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matched := false
+
+		for _, rightRow := range rightRows {
+			// Merge rows
+			merged, err := mergeRows(leftRow, rightRow)
+			if err != nil {
+				return nil, err
+			}
+
+			// Evaluate join condition
+			match, err := condition.Evaluate(merged)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
+			}
+
+			if match {
+				if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+					return nil, err
+				}
+				result = append(result, merged)
+				matched = true
+			}
+		}
+
+		// If no match, include left row with NULL values for right columns
+		if !matched {
+			merged, err := mergeRows(leftRow, createNullRow(rightRows, rightColumns))
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, err
+			}
+			result = append(result, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// executeRightJoin performs a RIGHT OUTER JOIN. leftColumns name the NULL
+// columns added when the left side has no rows, and may be nil.
+func (ctx *ExecutionContext) executeRightJoin(leftRows, rightRows []map[string]interface{}, leftColumns []string, condition Expression) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	for _, rightRow := range rightRows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matched := false
+
+		for _, leftRow := range leftRows {
+			// Merge rows
+			merged, err := mergeRows(leftRow, rightRow)
+			if err != nil {
+				return nil, err
+			}
+
+			// Evaluate join condition
+			match, err := condition.Evaluate(merged)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
+			}
+
+			if match {
+				if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+					return nil, err
+				}
+				result = append(result, merged)
+				matched = true
+			}
+		}
+
+		// If no match, include right row with NULL values for left columns
+		if !matched {
+			merged, err := mergeRows(createNullRow(leftRows, leftColumns), rightRow)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, err
+			}
+			result = append(result, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// executeFullJoin performs a FULL OUTER JOIN. leftColumns and rightColumns
+// name the NULL columns added when a side has no rows, and may be nil.
+func (ctx *ExecutionContext) executeFullJoin(leftRows, rightRows []map[string]interface{}, leftColumns, rightColumns []string, condition Expression) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	// Track which right rows have been matched
+	rightMatched := make([]bool, len(rightRows))
+
+	// Process left rows
+	for _, leftRow := range leftRows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matched := false
+
+		for i, rightRow := range rightRows {
+			// Merge rows
+			merged, err := mergeRows(leftRow, rightRow)
+			if err != nil {
+				return nil, err
+			}
+
+			// Evaluate join condition
+			match, err := condition.Evaluate(merged)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate JOIN condition: %w", err)
+			}
+
+			if match {
+				if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+					return nil, err
+				}
+				result = append(result, merged)
+				matched = true
+				rightMatched[i] = true
+			}
+		}
+
+		// If no match, include left row with NULL values for right columns
+		if !matched {
+			merged, err := mergeRows(leftRow, createNullRow(rightRows, rightColumns))
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, err
+			}
+			result = append(result, merged)
+		}
+	}
+
+	// Add unmatched right rows with NULL values for left columns
+	for i, rightRow := range rightRows {
+		if !rightMatched[i] {
+			merged, err := mergeRows(createNullRow(leftRows, leftColumns), rightRow)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, err
+			}
+			result = append(result, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// executeCrossJoin performs a CROSS JOIN (Cartesian product)
+func (ctx *ExecutionContext) executeCrossJoin(leftRows, rightRows []map[string]interface{}) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+
+	for _, leftRow := range leftRows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, rightRow := range rightRows {
+			merged, err := mergeRows(leftRow, rightRow)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.checkRowLimit(len(result)+1, "JOIN"); err != nil {
+				return nil, err
+			}
+			result = append(result, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// mergeRows combines two rows into one
+// If both left and right have the same column name, returns an error;
+// executeJoin renames such columns first unless StrictJoins is set
+func mergeRows(left, right map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+
+	// Copy left row
+	for k, v := range left {
+		merged[k] = v
+	}
+
+	// Copy right row - check for collisions (except _file which is allowed to be duplicated)
+	for k, v := range right {
+		if _, exists := merged[k]; exists {
+			// Allow _file column to be duplicated - it's added by glob reads
+			// When both sides have _file, we keep both but suffix them with the table position
+			if k == "_file" {
+				// Keep left as _file_left and right as _file_right
+				if leftFile, ok := merged["_file"]; ok {
+					delete(merged, "_file")
+					merged["_file_left"] = leftFile
+					merged["_file_right"] = v
+				}
+				continue
+			}
+			return nil, fmt.Errorf("column name collision in JOIN: %q exists in both tables. Use table aliases to disambiguate (e.g., SELECT t1.%s, t2.%s FROM ...)", k, k, k)
+		}
+		merged[k] = v
+	}
+
+	return merged, nil
+}
+
+// JoinInput is one side of a JOIN: its rows, its columns for when it has no
+// rows (may be nil), and the name qualifying its columns (may be empty)
+type JoinInput struct {
+	Rows    []map[string]interface{}
+	Columns []string
+	Name    string
+}
+
+// JoinName returns the name that qualifies the columns of a joined table: a
+// CTE's name, or a parquet file's name without directory or extension
+// (users for data/users.parquet). Returns "" for an aliased table, whose
+// columns are already qualified, and for a glob pattern.
+func JoinName(tableName, alias string) string {
+	if alias != "" || tableName == "" {
+		return ""
+	}
+	name := strings.TrimSuffix(filepath.Base(tableName), filepath.Ext(tableName))
+	if strings.ContainsAny(name, "*?[") {
+		return ""
+	}
+	return name
+}
+
+// DisambiguateJoinColumns renames the columns both sides of a JOIN have, so
+// the joined rows keep both values. A side with a name gets name.column, so
+// joining users.parquet with orders.parquet gives users.id and orders.id.
+// Without a name the left column keeps its name and the right one gets the
+// first free numeric suffix (id_1, id_2, ...). The _file column is left to
+// mergeRows. Renamed rows are copies; the input rows are not modified.
+func DisambiguateJoinColumns(left, right JoinInput) (JoinInput, JoinInput) {
+	leftCols := columnNames(left.Rows, left.Columns)
+	rightCols := columnNames(right.Rows, right.Columns)
+
+	taken := make(map[string]bool, len(leftCols)+len(rightCols))
+	for _, col := range leftCols {
+		taken[col] = true
+	}
+	var shared []string
+	for _, col := range rightCols {
+		if taken[col] && col != "_file" {
+			shared = append(shared, col)
+		}
+		taken[col] = true
+	}
+	if len(shared) == 0 {
+		return left, right
+	}
+	// Sorted so suffixes don't depend on map order
+	slices.Sort(shared)
+
+	leftNames := make(map[string]string)
+	rightNames := make(map[string]string)
+	for _, col := range shared {
+		if left.Name != "" {
+			leftNames[col] = freeColumnName(left.Name+"."+col, taken)
+		}
+		if right.Name != "" {
+			rightNames[col] = freeColumnName(right.Name+"."+col, taken)
+		} else {
+			rightNames[col] = freeColumnName(col, taken)
+		}
+	}
+
+	return renameColumns(left, leftNames), renameColumns(right, rightNames)
+}
+
+// freeColumnName returns name, or name with the first numeric suffix not in
+// taken, and marks the result as taken
+func freeColumnName(name string, taken map[string]bool) string {
+	candidate := name
+	for i := 1; taken[candidate]; i++ {
+		candidate = name + "_" + strconv.Itoa(i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// renameColumns returns a copy of in with its columns renamed by names
+func renameColumns(in JoinInput, names map[string]string) JoinInput {
+	if len(names) == 0 {
+		return in
+	}
+
+	out := JoinInput{Name: in.Name}
+	if in.Columns != nil {
+		out.Columns = make([]string, len(in.Columns))
+		for i, col := range in.Columns {
+			if name, ok := names[col]; ok {
+				col = name
+			}
+			out.Columns[i] = col
+		}
+	}
+	if in.Rows != nil {
+		out.Rows = make([]map[string]interface{}, len(in.Rows))
+		for i, row := range in.Rows {
+			renamed := make(map[string]interface{}, len(row))
+			for col, val := range row {
+				if name, ok := names[col]; ok {
+					col = name
+				}
+				renamed[col] = val
+			}
+			out.Rows[i] = renamed
+		}
+	}
+	return out
+}
+
+// UsingJoin is a JOIN ... USING clause resolved against the columns of both
+// sides. Run the join on Right with Condition, then pass the result to
+// Coalesce so each USING column appears once.
+type UsingJoin struct {
+	Condition Expression // Equality of the USING columns of both sides
+	Right     JoinInput  // Right side with USING columns renamed where they clash with the left
+
+	columns   []string // USING columns, as written
+	leftKeys  []string // Row key of each USING column on the left side
+	rightKeys []string // Row key of each USING column on the right side
+}
+
+// ResolveUsing finds the USING columns on both sides of a join. A column
+// matches a key of the same name, or a unique key qualified with a table
+// name or alias, such as u.id for id. Returns an error if a column is
+// missing from a side with known columns or matches more than one key.
+func ResolveUsing(columns []string, left, right JoinInput) (*UsingJoin, error) {
+	leftCols := columnNames(left.Rows, left.Columns)
+	rightCols := columnNames(right.Rows, right.Columns)
+
+	taken := make(map[string]bool, len(leftCols)+len(rightCols))
+	for _, col := range append(slices.Clone(leftCols), rightCols...) {
+		taken[col] = true
+	}
+
+	u := &UsingJoin{columns: columns, Right: right}
+	renames := make(map[string]string)
+	for _, col := range columns {
+		leftKey, err := usingKey(leftCols, col)
+		if err != nil {
+			return nil, fmt.Errorf("left side of USING: %w", err)
+		}
+		rightKey, err := usingKey(rightCols, col)
+		if err != nil {
+			return nil, fmt.Errorf("right side of USING: %w", err)
+		}
+		// Both sides use the same key, so the right one needs a name of its own
+		if rightKey == leftKey {
+			renames[rightKey] = freeColumnName(rightKey, taken)
+			rightKey = renames[rightKey]
+		}
+		u.leftKeys = append(u.leftKeys, leftKey)
+		u.rightKeys = append(u.rightKeys, rightKey)
+
+		var eq Expression = &ColumnComparisonExpr{LeftColumn: leftKey, Operator: TokenEqual, RightColumn: rightKey}
+		if u.Condition != nil {
+			eq = &BinaryExpr{Left: u.Condition, Operator: TokenAnd, Right: eq}
+		}
+		u.Condition = eq
+	}
+	u.Right = renameColumns(right, renames)
+	return u, nil
+}
+
+// usingKey returns the key of USING column col among columns. A side with
+// no known columns is assumed to use col itself.
+func usingKey(columns []string, col string) (string, error) {
+	if len(columns) == 0 || slices.Contains(columns, col) {
+		return col, nil
+	}
+
+	var key string
+	for _, c := range columns {
+		if strings.HasSuffix(c, "."+col) {
+			if key != "" {
+				return "", fmt.Errorf("column %s is ambiguous: %s and %s", col, key, c)
+			}
+			key = c
+		}
+	}
+	if key == "" {
+		return "", fmt.Errorf("column %s not found", col)
+	}
+	return key, nil
+}
+
+// Coalesce replaces the two keys of each USING column in rows with a single
+// column of the USING name, holding the left value or the right one when
+// the left is NULL, as for rows an outer join added. Rows are modified in
+// place. columns, the column list of an empty result, is returned with the
+// same change.
+func (u *UsingJoin) Coalesce(rows []map[string]interface{}, columns []string) []string {
+	for _, row := range rows {
+		for i, col := range u.columns {
+			val := row[u.leftKeys[i]]
+			if val == nil {
+				val = row[u.rightKeys[i]]
+			}
+			delete(row, u.leftKeys[i])
+			delete(row, u.rightKeys[i])
+			row[col] = val
+		}
+	}
+
+	if columns == nil {
+		return nil
+	}
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		if !slices.Contains(u.leftKeys, c) && !slices.Contains(u.rightKeys, c) {
+			out = append(out, c)
+		}
+	}
+	return append(out, u.columns...)
+}
+
+// createNullRow creates a row with NULL values for all columns from a sample
+// row set, or for the given columns if there are no rows
+func createNullRow(rows []map[string]interface{}, columns []string) map[string]interface{} {
+	nullRow := make(map[string]interface{})
+	for _, col := range columnNames(rows, columns) {
+		nullRow[col] = nil
+	}
+
+	return nullRow
+}
+
+// columnNames returns the columns of a row set, taken from its first row, or
+// columns if it has no rows
+func columnNames(rows []map[string]interface{}, columns []string) []string {
+	if len(rows) == 0 {
+		return columns
+	}
+
+	names := make([]string, 0, len(rows[0]))
+	for col := range rows[0] {
+		names = append(names, col)
+	}
+	return names
+}
+
+// emptyTableColumns returns the columns of a table read with no rows, with
+// its alias applied, so outer joins can fill them with NULLs. Returns nil if
+// the table has rows or is not a parquet file.
+func (ctx *ExecutionContext) emptyTableColumns(rows []map[string]interface{}, tableName, alias string) []string {
+	if len(rows) > 0 || tableName == "" || ctx.AllCTENames[tableName] {
+		return nil
+	}
+
+	columns, err := TableColumns(tableName)
+	if err != nil {
+		return nil
+	}
+	return aliasColumns(columns, alias)
+}
+
+// aliasColumns returns columns prefixed with a table alias, as
+// applyTableAlias renames them
+func aliasColumns(columns []string, alias string) []string {
+	if alias == "" || columns == nil {
+		return columns
+	}
+	aliased := make([]string, len(columns))
+	for i, col := range columns {
+		// Don't alias the special _file column
+		if col != "_file" {
+			col = alias + "." + col
+		}
+		aliased[i] = col
+	}
+	return aliased
+}
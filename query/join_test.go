@@ -202,3 +202,92 @@ func TestJoinWithSubquery(t *testing.T) {
 		t.Errorf("Parse() JOIN subquery table = %q, want %q", q.Joins[0].Subquery.TableName, "orders.parquet")
 	}
 }
+
+func TestJoinWithParenthesizedGroup(t *testing.T) {
+	query := "SELECT * FROM users.parquet u LEFT JOIN (orders.parquet o JOIN products.parquet p ON o.product_id = p.id) ON u.id = o.user_id"
+
+	q, err := Parse(query)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(q.Joins) != 1 {
+		t.Fatalf("Parse() expected 1 outer JOIN, got %d", len(q.Joins))
+	}
+
+	join := q.Joins[0]
+	if join.Type != JoinLeft {
+		t.Errorf("Parse() outer join type = %v, want %v", join.Type, JoinLeft)
+	}
+	if join.Condition == nil {
+		t.Errorf("Parse() outer join should have an ON condition")
+	}
+
+	group := join.Group
+	if group == nil {
+		t.Fatalf("Parse() expected JOIN with parenthesized group, got nil")
+	}
+	if group.TableName != "orders.parquet" || group.Alias != "o" {
+		t.Errorf("Parse() group source = %q AS %q, want %q AS %q", group.TableName, group.Alias, "orders.parquet", "o")
+	}
+	if len(group.Joins) != 1 || group.Joins[0].TableName != "products.parquet" || group.Joins[0].Type != JoinInner {
+		t.Errorf("Parse() group joins = %+v, want one INNER JOIN with products.parquet", group.Joins)
+	}
+}
+
+func TestJoinWithParenthesizedGroup_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"group without join", "SELECT * FROM users.parquet u JOIN (orders.parquet o) ON u.id = o.user_id"},
+		{"unclosed group", "SELECT * FROM users.parquet u JOIN (orders.parquet o JOIN products.parquet p ON o.product_id = p.id ON u.id = o.user_id"},
+		{"missing outer ON", "SELECT * FROM users.parquet u JOIN (orders.parquet o JOIN products.parquet p ON o.product_id = p.id)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.query); err == nil {
+				t.Errorf("Parse(%q) expected error, got nil", tt.query)
+			}
+		})
+	}
+}
+
+func TestJoinUsing(t *testing.T) {
+	q, err := Parse("SELECT * FROM users.parquet u LEFT JOIN orders.parquet o USING (id, region)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	join := q.Joins[0]
+	if join.Alias != "o" {
+		t.Errorf("Alias = %q, want o", join.Alias)
+	}
+	if len(join.UsingColumns) != 2 || join.UsingColumns[0] != "id" || join.UsingColumns[1] != "region" {
+		t.Errorf("UsingColumns = %v, want [id region]", join.UsingColumns)
+	}
+	if join.Condition != nil {
+		t.Errorf("Condition = %v, want nil for USING", join.Condition)
+	}
+}
+
+func TestJoinUsing_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing parenthesis", "SELECT * FROM users.parquet JOIN orders.parquet USING id"},
+		{"empty list", "SELECT * FROM users.parquet JOIN orders.parquet USING ()"},
+		{"unclosed list", "SELECT * FROM users.parquet JOIN orders.parquet USING (id"},
+		{"repeated column", "SELECT * FROM users.parquet JOIN orders.parquet USING (id, id)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.query); err == nil {
+				t.Errorf("Parse(%q) expected error, got nil", tt.query)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package query
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Lexer tokenizes SQL query strings
@@ -10,6 +12,7 @@ type Lexer struct {
 	input string
 	pos   int
 	ch    rune
+	prev  Token // last token returned, for telling minus from a sign
 }
 
 // NewLexer creates a new lexer
@@ -37,20 +40,65 @@ func (l *Lexer) peekChar() rune {
 	return rune(l.input[l.pos])
 }
 
-// skipWhitespace skips whitespace characters
-func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		l.readChar()
+// skipWhitespace skips whitespace characters and comments: -- to the end
+// of the line and /* ... */, which may span lines. It reports false if the
+// input ends inside a /* comment.
+func (l *Lexer) skipWhitespace() bool {
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '-' && l.peekChar() == '-':
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+		case l.ch == '/' && l.peekChar() == '*':
+			l.readChar()
+			l.readChar()
+			for !(l.ch == '*' && l.peekChar() == '/') {
+				if l.ch == 0 {
+					return false
+				}
+				l.readChar()
+			}
+			l.readChar()
+			l.readChar()
+		default:
+			return true
+		}
 	}
 }
 
-// readString reads a quoted string
-func (l *Lexer) readString(quote rune) string {
+// startsComment reports whether a comment starts at the current character
+func (l *Lexer) startsComment() bool {
+	return (l.ch == '-' && l.peekChar() == '-') || (l.ch == '/' && l.peekChar() == '*')
+}
+
+// nextNonSpace returns the current or next character that isn't whitespace,
+// without advancing
+func (l *Lexer) nextNonSpace() rune {
+	ch := l.ch
+	for n := 1; ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'; n++ {
+		ch = l.peekCharAt(n)
+	}
+	return ch
+}
+
+// readString reads a quoted string. A quote inside it is escaped by
+// doubling it, as SQL does, or with a backslash. It reports false if the
+// input ends before the closing quote.
+func (l *Lexer) readString(quote rune) (string, bool) {
 	var result strings.Builder
 	l.readChar() // skip opening quote
 
-	for l.ch != quote && l.ch != 0 {
-		if l.ch == '\\' {
+	for l.ch != 0 {
+		if l.ch == quote {
+			if l.peekChar() != quote {
+				break
+			}
+			l.readChar()
+			result.WriteRune(quote)
+		} else if l.ch == '\\' {
 			l.readChar()
 			switch l.ch {
 			case 'n':
@@ -62,22 +110,67 @@ func (l *Lexer) readString(quote rune) string {
 			case quote:
 				result.WriteRune(quote)
 			default:
-				result.WriteRune(l.ch)
+				result.WriteByte(byte(l.ch))
 			}
 		} else {
-			result.WriteRune(l.ch)
+			// The lexer reads bytes, so copy them as is to keep UTF-8 intact
+			result.WriteByte(byte(l.ch))
 		}
 		l.readChar()
 	}
 
-	if l.ch == quote {
-		l.readChar() // skip closing quote
+	if l.ch != quote {
+		return "", false
 	}
+	l.readChar() // skip closing quote
+	return result.String(), true
+}
 
-	return result.String()
+// readQuotedIdentifier reads a double-quoted identifier, such as a column
+// name with spaces. Two double quotes stand for one; backslashes are kept
+// as written. It reports false if the input ends before the closing quote.
+func (l *Lexer) readQuotedIdentifier() (string, bool) {
+	var result strings.Builder
+	l.readChar() // skip opening quote
+
+	for l.ch != 0 {
+		if l.ch == '"' {
+			if l.peekChar() != '"' {
+				break
+			}
+			l.readChar()
+		}
+		// The lexer reads bytes, so copy them as is to keep UTF-8 intact
+		result.WriteByte(byte(l.ch))
+		l.readChar()
+	}
+
+	if l.ch != '"' {
+		return "", false
+	}
+	l.readChar() // skip closing quote
+	return result.String(), true
 }
 
-// readNumber reads a number
+// QuoteIdentifier returns name as it can be written in a query: as is if
+// it lexes as a single identifier, otherwise double-quoted
+func QuoteIdentifier(name string) string {
+	if tokens := Tokenize(name); len(tokens) == 2 && tokens[0].Type == TokenIdent && tokens[0].Value == name && name != "" && name[0] != '"' {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// peekCharAt looks n characters past the current one without advancing
+func (l *Lexer) peekCharAt(n int) rune {
+	if l.pos+n-1 >= len(l.input) {
+		return 0
+	}
+	return rune(l.input[l.pos+n-1])
+}
+
+// readNumber reads a number, including exponents (1.5e-3) and digit-group
+// underscores (1_000_000). Underscores are dropped from the token value.
 func (l *Lexer) readNumber() string {
 	var result strings.Builder
 
@@ -88,17 +181,51 @@ func (l *Lexer) readNumber() string {
 	}
 
 	// Read digits and decimal point (but not additional minus signs)
-	for unicode.IsDigit(l.ch) || l.ch == '.' {
-		result.WriteRune(l.ch)
-		l.readChar()
+	l.readDigits(&result, true)
+
+	// Read exponent only when digits follow, so "1e" stays a number followed by an identifier
+	if l.ch == 'e' || l.ch == 'E' {
+		next := l.peekChar()
+		if unicode.IsDigit(next) || ((next == '+' || next == '-') && unicode.IsDigit(l.peekCharAt(2))) {
+			result.WriteRune(l.ch)
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				result.WriteRune(l.ch)
+				l.readChar()
+			}
+			l.readDigits(&result, false)
+		}
 	}
+
 	return result.String()
 }
 
+// readDigits reads digits, skipping underscores between digits, and
+// optionally decimal points
+func (l *Lexer) readDigits(result *strings.Builder, allowDot bool) {
+	prevDigit := false
+	for {
+		switch {
+		case unicode.IsDigit(l.ch):
+			result.WriteRune(l.ch)
+			prevDigit = true
+		case l.ch == '_' && prevDigit && unicode.IsDigit(l.peekChar()):
+			// Digit-group separator, dropped from the value
+			prevDigit = false
+		case l.ch == '.' && allowDot:
+			result.WriteRune(l.ch)
+			prevDigit = false
+		default:
+			return
+		}
+		l.readChar()
+	}
+}
+
 // readIdentifier reads an identifier or keyword (including file paths)
 func (l *Lexer) readIdentifier() string {
 	var result strings.Builder
-	for unicode.IsLetter(l.ch) || unicode.IsDigit(l.ch) || l.ch == '_' || l.ch == '.' || l.ch == '/' || l.ch == '-' {
+	for (unicode.IsLetter(l.ch) || unicode.IsDigit(l.ch) || l.ch == '_' || l.ch == '.' || l.ch == '/' || l.ch == '-') && !l.startsComment() {
 		result.WriteRune(l.ch)
 		l.readChar()
 	}
@@ -107,7 +234,10 @@ func (l *Lexer) readIdentifier() string {
 
 // NextToken returns the next token
 func (l *Lexer) NextToken() Token {
-	l.skipWhitespace()
+	if !l.skipWhitespace() {
+		l.prev = Token{Type: TokenError, Value: "unterminated /* comment"}
+		return l.prev
+	}
 
 	var tok Token
 
@@ -129,7 +259,12 @@ func (l *Lexer) NextToken() Token {
 	case '<':
 		if l.peekChar() == '=' {
 			l.readChar()
-			tok = Token{Type: TokenLessEqual, Value: "<="}
+			if l.peekChar() == '>' {
+				l.readChar()
+				tok = Token{Type: TokenNullSafeEqual, Value: "<=>"}
+			} else {
+				tok = Token{Type: TokenLessEqual, Value: "<="}
+			}
 			l.readChar()
 		} else {
 			tok = Token{Type: TokenLess, Value: "<"}
@@ -144,12 +279,40 @@ func (l *Lexer) NextToken() Token {
 			tok = Token{Type: TokenGreater, Value: ">"}
 			l.readChar()
 		}
-	case '\'', '"':
-		quote := l.ch
-		tok = Token{Type: TokenString, Value: l.readString(quote)}
+	case '\'':
+		if value, ok := l.readString('\''); ok {
+			tok = Token{Type: TokenString, Value: value}
+		} else {
+			tok = Token{Type: TokenError, Value: "unterminated string literal"}
+		}
+	case '"':
+		// A quoted identifier is never a keyword
+		if value, ok := l.readQuotedIdentifier(); ok {
+			tok = Token{Type: TokenIdent, Value: value}
+		} else {
+			tok = Token{Type: TokenError, Value: "unterminated quoted identifier"}
+		}
 	case '*':
 		tok = Token{Type: TokenIdent, Value: "*"}
 		l.readChar()
+	case '+':
+		tok = Token{Type: TokenPlus, Value: "+"}
+		l.readChar()
+	case '/':
+		tok = Token{Type: TokenSlash, Value: "/"}
+		l.readChar()
+	case '%':
+		tok = Token{Type: TokenPercent, Value: "%"}
+		l.readChar()
+	case '|':
+		if l.peekChar() == '|' {
+			l.readChar()
+			tok = Token{Type: TokenConcat, Value: "||"}
+			l.readChar()
+		} else {
+			tok = Token{Type: TokenError, Value: "|"}
+			l.readChar()
+		}
 	case ',':
 		tok = Token{Type: TokenComma, Value: ","}
 		l.readChar()
@@ -159,27 +322,59 @@ func (l *Lexer) NextToken() Token {
 	case ')':
 		tok = Token{Type: TokenRightParen, Value: ")"}
 		l.readChar()
+	case '[':
+		tok = Token{Type: TokenLeftBracket, Value: "["}
+		l.readChar()
+	case ']':
+		tok = Token{Type: TokenRightBracket, Value: "]"}
+		l.readChar()
+	case ';':
+		tok = Token{Type: TokenSemicolon, Value: ";"}
+		l.readChar()
 	default:
-		if unicode.IsDigit(l.ch) || l.ch == '-' {
+		if l.ch == '-' && l.afterOperand() {
+			// Subtraction, as in "a - 1" or "a -1"
+			tok = Token{Type: TokenMinus, Value: "-"}
+			l.readChar()
+		} else if unicode.IsDigit(l.ch) || l.ch == '-' || (l.ch == '.' && unicode.IsDigit(l.peekChar())) {
 			value := l.readNumber()
-			// Validate that a standalone minus sign is not treated as a number
+			// A minus sign not followed by a number is an operator
 			if value == "-" {
-				tok = Token{Type: TokenError, Value: "-"}
+				tok = Token{Type: TokenMinus, Value: "-"}
 			} else {
 				tok = Token{Type: TokenNumber, Value: value}
 			}
 		} else if unicode.IsLetter(l.ch) || l.ch == '_' {
 			value := l.readIdentifier()
 			tok = Token{Type: identifierType(value), Value: value}
+			// LEFT and RIGHT start joins, but LEFT(...) and RIGHT(...) are functions
+			if (tok.Type == TokenLeft || tok.Type == TokenRight) && l.nextNonSpace() == '(' {
+				tok.Type = TokenIdent
+			}
 		} else {
 			tok = Token{Type: TokenError, Value: string(l.ch)}
 			l.readChar()
 		}
 	}
 
+	l.prev = tok
 	return tok
 }
 
+// afterOperand reports whether the last token ends an operand, so a
+// following minus sign subtracts rather than starting a negative number
+func (l *Lexer) afterOperand() bool {
+	switch l.prev.Type {
+	case TokenIdent:
+		// * is lexed as an identifier but is an operator
+		return l.prev.Value != "*"
+	case TokenNumber, TokenString, TokenBool, TokenNull, TokenRightParen, TokenRightBracket, TokenEnd:
+		return true
+	default:
+		return false
+	}
+}
+
 // identifierType determines if an identifier is a keyword
 func identifierType(ident string) TokenType {
 	keywords := map[string]TokenType{
@@ -215,6 +410,8 @@ func identifierType(ident string) TokenType {
 		"IN":        TokenIn,
 		"like":      TokenLike,
 		"LIKE":      TokenLike,
+		"ilike":     TokenILike,
+		"ILIKE":     TokenILike,
 		"between":   TokenBetween,
 		"BETWEEN":   TokenBetween,
 		"is":        TokenIs,
@@ -269,6 +466,18 @@ func identifierType(ident string) TokenType {
 		"TRUE":      TokenBool,
 		"false":     TokenBool,
 		"FALSE":     TokenBool,
+
+		// Table sampling
+		"tablesample": TokenTablesample,
+		"TABLESAMPLE": TokenTablesample,
+		"qualify":     TokenQualify,
+		"QUALIFY":     TokenQualify,
+		"union":       TokenUnion,
+		"UNION":       TokenUnion,
+		"all":         TokenAll,
+		"ALL":         TokenAll,
+		"using":       TokenUsing,
+		"USING":       TokenUsing,
 	}
 
 	if tokType, ok := keywords[ident]; ok {
@@ -277,6 +486,21 @@ func identifierType(ident string) TokenType {
 	return TokenIdent
 }
 
+// tokenError returns the error that stopped tokenizing the input to a
+// parser (what), which is the last token, or nil. The token holds either
+// the invalid character or, for an unterminated literal or comment, a
+// description of the problem.
+func tokenError(tokens []Token, what string) error {
+	last := tokens[len(tokens)-1]
+	if last.Type != TokenError {
+		return nil
+	}
+	if utf8.RuneCountInString(last.Value) == 1 {
+		return fmt.Errorf("invalid character in %s: %s", what, last.Value)
+	}
+	return fmt.Errorf("%s in %s", last.Value, what)
+}
+
 // Tokenize returns all tokens from the input
 func Tokenize(input string) []Token {
 	lexer := NewLexer(input)
@@ -18,6 +18,23 @@ func TestLexer_Keywords(t *testing.T) {
 				{Type: TokenEOF, Value: ""},
 			},
 		},
+		{
+			name:  "LEFT and RIGHT are functions before a parenthesis",
+			input: "LEFT(a) right (b) LEFT JOIN",
+			expected: []Token{
+				{Type: TokenIdent, Value: "LEFT"},
+				{Type: TokenLeftParen, Value: "("},
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenRightParen, Value: ")"},
+				{Type: TokenIdent, Value: "right"},
+				{Type: TokenLeftParen, Value: "("},
+				{Type: TokenIdent, Value: "b"},
+				{Type: TokenRightParen, Value: ")"},
+				{Type: TokenLeft, Value: "LEFT"},
+				{Type: TokenJoin, Value: "JOIN"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
 		{
 			name:  "case insensitive keywords",
 			input: "select FROM where",
@@ -78,6 +95,85 @@ func TestLexer_Operators(t *testing.T) {
 				{Type: TokenEOF, Value: ""},
 			},
 		},
+		{
+			name:  "null-safe equality",
+			input: "a<=>b <= c",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenNullSafeEqual, Value: "<=>"},
+				{Type: TokenIdent, Value: "b"},
+				{Type: TokenLessEqual, Value: "<="},
+				{Type: TokenIdent, Value: "c"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
+		{
+			name:  "arithmetic operators",
+			input: "a + b - c * d / e % f",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenPlus, Value: "+"},
+				{Type: TokenIdent, Value: "b"},
+				{Type: TokenMinus, Value: "-"},
+				{Type: TokenIdent, Value: "c"},
+				{Type: TokenIdent, Value: "*"},
+				{Type: TokenIdent, Value: "d"},
+				{Type: TokenSlash, Value: "/"},
+				{Type: TokenIdent, Value: "e"},
+				{Type: TokenPercent, Value: "%"},
+				{Type: TokenIdent, Value: "f"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
+		{
+			name:  "concatenation operator",
+			input: "a||'-' || -1 OR b",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenConcat, Value: "||"},
+				{Type: TokenString, Value: "-"},
+				{Type: TokenConcat, Value: "||"},
+				{Type: TokenNumber, Value: "-1"},
+				{Type: TokenOr, Value: "OR"},
+				{Type: TokenIdent, Value: "b"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
+		{
+			name:  "single pipe is an error",
+			input: "a | b",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenError, Value: "|"},
+			},
+		},
+		{
+			name:  "minus after operand subtracts",
+			input: "a -1 (b) -2",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenMinus, Value: "-"},
+				{Type: TokenNumber, Value: "1"},
+				{Type: TokenLeftParen, Value: "("},
+				{Type: TokenIdent, Value: "b"},
+				{Type: TokenRightParen, Value: ")"},
+				{Type: TokenMinus, Value: "-"},
+				{Type: TokenNumber, Value: "2"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
+		{
+			name:  "minus after operator is a sign",
+			input: "a > -1 * -2",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenGreater, Value: ">"},
+				{Type: TokenNumber, Value: "-1"},
+				{Type: TokenIdent, Value: "*"},
+				{Type: TokenNumber, Value: "-2"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
 		{
 			name:  "operators with whitespace",
 			input: "  =   !=  ",
@@ -115,11 +211,6 @@ func TestLexer_Strings(t *testing.T) {
 			input:    "'hello world'",
 			expected: Token{Type: TokenString, Value: "hello world"},
 		},
-		{
-			name:     "double quoted string",
-			input:    `"hello world"`,
-			expected: Token{Type: TokenString, Value: "hello world"},
-		},
 		{
 			name:     "string with escape sequences",
 			input:    `'hello\nworld\ttab'`,
@@ -130,11 +221,36 @@ func TestLexer_Strings(t *testing.T) {
 			input:    `'alice\'s data'`,
 			expected: Token{Type: TokenString, Value: "alice's data"},
 		},
+		{
+			name:     "string with doubled quotes",
+			input:    `'O''Brien'`,
+			expected: Token{Type: TokenString, Value: "O'Brien"},
+		},
+		{
+			name:     "string that is only a doubled quote",
+			input:    `''''`,
+			expected: Token{Type: TokenString, Value: "'"},
+		},
 		{
 			name:     "empty string",
 			input:    "''",
 			expected: Token{Type: TokenString, Value: ""},
 		},
+		{
+			name:     "unterminated string",
+			input:    "'O''Brien",
+			expected: Token{Type: TokenError, Value: "unterminated string literal"},
+		},
+		{
+			name:     "unterminated quoted identifier",
+			input:    `"first name`,
+			expected: Token{Type: TokenError, Value: "unterminated quoted identifier"},
+		},
+		{
+			name:     "multibyte string",
+			input:    "'héllo 日本'",
+			expected: Token{Type: TokenString, Value: "héllo 日本"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -177,6 +293,71 @@ func TestLexer_Numbers(t *testing.T) {
 			input:    "-3.14",
 			expected: Token{Type: TokenNumber, Value: "-3.14"},
 		},
+		{
+			name:     "exponent",
+			input:    "1e5",
+			expected: Token{Type: TokenNumber, Value: "1e5"},
+		},
+		{
+			name:     "uppercase exponent",
+			input:    "2E10",
+			expected: Token{Type: TokenNumber, Value: "2E10"},
+		},
+		{
+			name:     "float with negative exponent",
+			input:    "1.5e-3",
+			expected: Token{Type: TokenNumber, Value: "1.5e-3"},
+		},
+		{
+			name:     "explicit positive exponent",
+			input:    "-2.5e+2",
+			expected: Token{Type: TokenNumber, Value: "-2.5e+2"},
+		},
+		{
+			name:     "digit group underscores",
+			input:    "1_000_000",
+			expected: Token{Type: TokenNumber, Value: "1000000"},
+		},
+		{
+			name:     "underscores with decimals",
+			input:    "1_234.567_8",
+			expected: Token{Type: TokenNumber, Value: "1234.5678"},
+		},
+		{
+			name:     "exponent without digits is not consumed",
+			input:    "1e",
+			expected: Token{Type: TokenNumber, Value: "1"},
+		},
+		{
+			name:     "trailing underscore is not consumed",
+			input:    "1_",
+			expected: Token{Type: TokenNumber, Value: "1"},
+		},
+		{
+			name:     "leading dot",
+			input:    ".5",
+			expected: Token{Type: TokenNumber, Value: ".5"},
+		},
+		{
+			name:     "negative leading dot with exponent",
+			input:    "-.5e2",
+			expected: Token{Type: TokenNumber, Value: "-.5e2"},
+		},
+		{
+			name:     "negative exponent notation",
+			input:    "-1.2e3",
+			expected: Token{Type: TokenNumber, Value: "-1.2e3"},
+		},
+		{
+			name:     "minus before a column is an operator",
+			input:    "-age",
+			expected: Token{Type: TokenMinus, Value: "-"},
+		},
+		{
+			name:     "dot without digits is not a number",
+			input:    ".x",
+			expected: Token{Type: TokenError, Value: "."},
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,6 +405,26 @@ func TestLexer_Identifiers(t *testing.T) {
 			input:    "*",
 			expected: Token{Type: TokenIdent, Value: "*"},
 		},
+		{
+			name:     "quoted identifier with a space",
+			input:    `"first name"`,
+			expected: Token{Type: TokenIdent, Value: "first name"},
+		},
+		{
+			name:     "quoted keyword",
+			input:    `"order"`,
+			expected: Token{Type: TokenIdent, Value: "order"},
+		},
+		{
+			name:     "quoted identifier with doubled quotes",
+			input:    `"say ""hi"""`,
+			expected: Token{Type: TokenIdent, Value: `say "hi"`},
+		},
+		{
+			name:     "quoted identifier keeps backslashes",
+			input:    `"C:\data\x.parquet"`,
+			expected: Token{Type: TokenIdent, Value: `C:\data\x.parquet`},
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,6 +441,27 @@ func TestLexer_Identifiers(t *testing.T) {
 	}
 }
 
+func TestQuoteIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"age":          "age",
+		"data.parquet": "data.parquet",
+		"first name":   `"first name"`,
+		"order":        `"order"`,
+		`say "hi"`:     `"say ""hi"""`,
+		"":             `""`,
+	}
+
+	for name, want := range tests {
+		if got := QuoteIdentifier(name); got != want {
+			t.Errorf("QuoteIdentifier(%q) = %s, want %s", name, got, want)
+		}
+		// The quoted form lexes back to the name
+		if tokens := Tokenize(QuoteIdentifier(name)); tokens[0].Type != TokenIdent || tokens[0].Value != name {
+			t.Errorf("Tokenize(QuoteIdentifier(%q)) = %v", name, tokens)
+		}
+	}
+}
+
 func TestLexer_Booleans(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -305,6 +527,15 @@ func TestLexer_Delimiters(t *testing.T) {
 				{Type: TokenEOF, Value: ""},
 			},
 		},
+		{
+			name:  "semicolon",
+			input: "a;",
+			expected: []Token{
+				{Type: TokenIdent, Value: "a"},
+				{Type: TokenSemicolon, Value: ";"},
+				{Type: TokenEOF, Value: ""},
+			},
+		},
 		{
 			name:  "multiple commas",
 			input: "col1, col2, col3",
@@ -399,6 +630,69 @@ func TestLexer_CompleteQuery(t *testing.T) {
 	}
 }
 
+func TestLexer_Comments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{
+			name:     "line comment",
+			input:    "age -- the age\n> 30",
+			expected: []Token{{TokenIdent, "age"}, {TokenGreater, ">"}, {TokenNumber, "30"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "line comment at end of input",
+			input:    "age -- no newline",
+			expected: []Token{{TokenIdent, "age"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "block comment spanning lines",
+			input:    "age /* first\nsecond */ > 30",
+			expected: []Token{{TokenIdent, "age"}, {TokenGreater, ">"}, {TokenNumber, "30"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "comment right after an identifier",
+			input:    "age--comment\nname/**/",
+			expected: []Token{{TokenIdent, "age"}, {TokenIdent, "name"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "comment markers in a string",
+			input:    "'a -- b /* c */'",
+			expected: []Token{{TokenString, "a -- b /* c */"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "division is not a comment",
+			input:    "a / b",
+			expected: []Token{{TokenIdent, "a"}, {TokenSlash, "/"}, {TokenIdent, "b"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "subtracting a negative number",
+			input:    "a - -1",
+			expected: []Token{{TokenIdent, "a"}, {TokenMinus, "-"}, {TokenNumber, "-1"}, {TokenEOF, ""}},
+		},
+		{
+			name:     "unterminated block comment",
+			input:    "age /* never closed",
+			expected: []Token{{TokenIdent, "age"}, {TokenError, "unterminated /* comment"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := Tokenize(tt.input)
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("expected %d tokens, got %d: %v", len(tt.expected), len(tokens), tokens)
+			}
+			for i, tok := range tokens {
+				if tok != tt.expected[i] {
+					t.Errorf("token %d: expected %v, got %v", i, tt.expected[i], tok)
+				}
+			}
+		})
+	}
+}
+
 func TestLexer_ColumnProjection(t *testing.T) {
 	input := "select name, age, UPPER(status) as status_upper from data.parquet"
 
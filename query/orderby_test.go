@@ -113,29 +113,32 @@ func TestApplyOrderBy_NullValues(t *testing.T) {
 		{"name": "alice", "age": int64(30)},
 		{"name": "bob", "age": nil},
 		{"name": "charlie", "age": int64(25)},
+		{"name": "dave"}, // A missing column sorts as NULL
 	}
 
-	orderBy := []OrderByItem{
-		{Column: "age", Desc: false},
-	}
-
-	sorted, err := ApplyOrderBy(rows, orderBy)
-	if err != nil {
-		t.Fatalf("ApplyOrderBy() error = %v", err)
-	}
-
-	// NULL should sort first in ASC order
-	firstName := sorted[0]["name"].(string)
-	if firstName != "bob" {
-		t.Errorf("First row (NULL) name = %s, want bob", firstName)
+	tests := []struct {
+		name string
+		item OrderByItem
+		want []string
+	}{
+		{"ASC puts NULLs last", OrderByItem{Column: "age"}, []string{"charlie", "alice", "bob", "dave"}},
+		{"DESC puts NULLs first", OrderByItem{Column: "age", Desc: true}, []string{"bob", "dave", "alice", "charlie"}},
+		{"ASC NULLS FIRST", OrderByItem{Column: "age", Nulls: NullsFirst}, []string{"bob", "dave", "charlie", "alice"}},
+		{"DESC NULLS LAST", OrderByItem{Column: "age", Desc: true, Nulls: NullsLast}, []string{"alice", "charlie", "bob", "dave"}},
 	}
 
-	// Then 25, then 30
-	if sorted[1]["name"].(string) != "charlie" {
-		t.Errorf("Second row name = %s, want charlie", sorted[1]["name"].(string))
-	}
-	if sorted[2]["name"].(string) != "alice" {
-		t.Errorf("Third row name = %s, want alice", sorted[2]["name"].(string))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := ApplyOrderBy(rows, []OrderByItem{tt.item})
+			if err != nil {
+				t.Fatalf("ApplyOrderBy() error = %v", err)
+			}
+			for i, want := range tt.want {
+				if got := sorted[i]["name"]; got != want {
+					t.Errorf("Row %d name = %v, want %s", i, got, want)
+				}
+			}
+		})
 	}
 }
 
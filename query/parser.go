@@ -2,7 +2,9 @@ package query
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // Parser parses SQL queries into AST
@@ -10,6 +12,10 @@ type Parser struct {
 	tokens       []Token
 	pos          int
 	depthCounter *ExpressionDepthCounter
+
+	// havingAggregates collects the aggregates of the HAVING clause being
+	// parsed; nil outside HAVING, where aggregates are not allowed
+	havingAggregates *[]SelectItem
 }
 
 // NewParser creates a new parser
@@ -37,6 +43,14 @@ func (p *Parser) peek() Token {
 	return p.tokens[p.pos+1]
 }
 
+// peekAt returns the token n positions after the current one
+func (p *Parser) peekAt(n int) Token {
+	if p.pos+n >= len(p.tokens) {
+		return Token{Type: TokenEOF, Value: ""}
+	}
+	return p.tokens[p.pos+n]
+}
+
 // advance moves to the next token
 func (p *Parser) advance() {
 	p.pos++
@@ -64,6 +78,9 @@ func Parse(query string) (*Query, error) {
 	if err := ValidateTokens(tokens); err != nil {
 		return nil, err
 	}
+	if err := tokenError(tokens, "query"); err != nil {
+		return nil, err
+	}
 
 	parser := NewParser(tokens)
 	q, err := parser.parseQuery()
@@ -71,9 +88,16 @@ func Parse(query string) (*Query, error) {
 		return nil, err
 	}
 
+	// A statement may end with semicolons
+	terminated := false
+	for parser.current().Type == TokenSemicolon {
+		parser.advance()
+		terminated = true
+	}
+
 	// Validate that we consumed all tokens (should be at EOF)
-	if parser.current().Type == TokenError {
-		return nil, fmt.Errorf("invalid character in query: %s", parser.current().Value)
+	if terminated && parser.current().Type != TokenEOF {
+		return nil, fmt.Errorf("query contains multiple statements, use ParseMulti to parse a script")
 	}
 	if parser.current().Type != TokenEOF {
 		return nil, fmt.Errorf("unexpected trailing tokens after query: %s", parser.current().Value)
@@ -83,7 +107,13 @@ func Parse(query string) (*Query, error) {
 }
 
 // parseQuery parses: [WITH cte AS (...)] SELECT col1, col2, ... FROM table WHERE expr
+// [UNION [ALL] SELECT ...] [ORDER BY ...] [LIMIT n] [OFFSET n]
 func (p *Parser) parseQuery() (*Query, error) {
+	// A subquery inside HAVING has its own clauses
+	outerHaving := p.havingAggregates
+	p.havingAggregates = nil
+	defer func() { p.havingAggregates = outerHaving }()
+
 	var ctes []CTE
 
 	// Parse WITH clause (optional)
@@ -95,6 +125,54 @@ func (p *Parser) parseQuery() (*Query, error) {
 		}
 	}
 
+	q, err := p.parseSelect(ctes)
+	if err != nil {
+		return nil, err
+	}
+	q.CTEs = ctes
+
+	// Parse UNION [ALL] arms (optional)
+	for p.current().Type == TokenUnion {
+		op, err := p.parseSetOperation(q, ctes)
+		if err != nil {
+			return nil, err
+		}
+		q.SetOperations = append(q.SetOperations, op)
+	}
+
+	// Parse ORDER BY clause (optional)
+	if p.current().Type == TokenOrder {
+		orderBy, err := p.parseQueryOrderBy(q)
+		if err != nil {
+			return nil, err
+		}
+		q.OrderBy = orderBy
+	}
+
+	// Parse LIMIT clause (optional)
+	if p.current().Type == TokenLimit {
+		limit, err := p.parseLimit()
+		if err != nil {
+			return nil, err
+		}
+		q.Limit = limit
+	}
+
+	// Parse OFFSET clause (optional)
+	if p.current().Type == TokenOffset {
+		offset, err := p.parseOffset()
+		if err != nil {
+			return nil, err
+		}
+		q.Offset = offset
+	}
+
+	return q, nil
+}
+
+// parseSelect parses a SELECT statement up to its QUALIFY clause. ctes are
+// the CTEs in scope, which FROM and JOIN may reference.
+func (p *Parser) parseSelect(ctes []CTE) (*Query, error) {
 	// Parse SELECT
 	if err := p.expect(TokenSelect); err != nil {
 		return nil, fmt.Errorf("query must start with SELECT (or WITH): %w", err)
@@ -120,7 +198,6 @@ func (p *Parser) parseQuery() (*Query, error) {
 
 	// Initialize query
 	q := &Query{
-		CTEs:       ctes,
 		SelectList: selectList,
 		Distinct:   distinct,
 	}
@@ -179,19 +256,23 @@ func (p *Parser) parseQuery() (*Query, error) {
 			q.TableAlias = p.current().Value
 			p.advance()
 		}
+
+		// Parse optional TABLESAMPLE clause
+		if p.current().Type == TokenTablesample {
+			sample, err := p.parseTableSample()
+			if err != nil {
+				return nil, err
+			}
+			q.Sample = sample
+		}
 	}
 
 	// Parse JOIN clauses (optional, can be multiple)
-	for p.current().Type == TokenJoin || p.current().Type == TokenInner ||
-		p.current().Type == TokenLeft || p.current().Type == TokenRight ||
-		p.current().Type == TokenFull || p.current().Type == TokenCross {
-
-		join, err := p.parseJoin(ctes)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse JOIN: %w", err)
-		}
-		q.Joins = append(q.Joins, *join)
+	joins, err := p.parseJoins(ctes)
+	if err != nil {
+		return nil, err
 	}
+	q.Joins = joins
 
 	// Parse WHERE clause (optional)
 	if p.current().Type == TokenWhere {
@@ -205,54 +286,159 @@ func (p *Parser) parseQuery() (*Query, error) {
 
 	// Parse GROUP BY clause (optional)
 	if p.current().Type == TokenGroup {
-		groupBy, err := p.parseGroupBy()
+		groupBy, err := p.parseGroupBy(q)
 		if err != nil {
 			return nil, err
 		}
 		q.GroupBy = groupBy
 	}
 
-	// Parse HAVING clause (optional, only valid with GROUP BY)
+	// Parse HAVING clause (optional, only valid with GROUP BY or an
+	// aggregate SELECT list, which forms a single implicit group)
 	if p.current().Type == TokenHaving {
-		if len(q.GroupBy) == 0 {
-			return nil, fmt.Errorf("HAVING clause requires GROUP BY")
+		if len(q.GroupBy) == 0 && !HasAggregateFunction(q.SelectList) {
+			return nil, fmt.Errorf("HAVING clause requires GROUP BY or aggregates in the SELECT list")
 		}
 		p.advance()
+		var aggregates []SelectItem
+		p.havingAggregates = &aggregates
 		expr, err := p.parseOr()
+		p.havingAggregates = nil
 		if err != nil {
 			return nil, err
 		}
 		q.Having = expr
+		q.HavingAggregates = aggregates
 	}
 
-	// Parse ORDER BY clause (optional)
-	if p.current().Type == TokenOrder {
-		orderBy, err := p.parseOrderBy()
+	// Parse QUALIFY clause (optional, filters on window function results)
+	if p.current().Type == TokenQualify {
+		if !HasWindowFunction(q.SelectList) {
+			return nil, fmt.Errorf("QUALIFY clause requires a window function in the SELECT list")
+		}
+		p.advance()
+		expr, err := p.parseOr()
 		if err != nil {
 			return nil, err
 		}
-		q.OrderBy = orderBy
+		q.Qualify = expr
 	}
 
-	// Parse LIMIT clause (optional)
-	if p.current().Type == TokenLimit {
-		limit, err := p.parseLimit()
-		if err != nil {
-			return nil, err
+	return q, nil
+}
+
+// parseSetOperation parses UNION [ALL] followed by a SELECT, checking that
+// it selects as many columns as first where both lists are known
+func (p *Parser) parseSetOperation(first *Query, ctes []CTE) (SetOperation, error) {
+	p.advance() // skip UNION
+	op := SetOperation{Operator: SetUnion}
+	if p.current().Type == TokenAll {
+		op.Operator = SetUnionAll
+		p.advance()
+	}
+
+	arm, err := p.parseSelect(ctes)
+	if err != nil {
+		return op, fmt.Errorf("failed to parse %s query: %w", op.Operator, err)
+	}
+
+	// SELECT * has as many columns as its table, known only once it is read
+	if !selectsAll(first.SelectList) && !selectsAll(arm.SelectList) && len(first.SelectList) != len(arm.SelectList) {
+		return op, fmt.Errorf("%s queries must select the same number of columns, got %d and %d",
+			op.Operator, len(first.SelectList), len(arm.SelectList))
+	}
+
+	op.Query = arm
+	return op, nil
+}
+
+// selectsAll reports whether a SELECT list contains * or u.*
+func selectsAll(selectList []SelectItem) bool {
+	for _, item := range selectList {
+		if colRef, ok := item.Expr.(*ColumnRef); ok && colRef.isWildcard() {
+			return true
 		}
-		q.Limit = limit
 	}
+	return false
+}
 
-	// Parse OFFSET clause (optional)
-	if p.current().Type == TokenOffset {
-		offset, err := p.parseOffset()
+// isJoinStart reports whether the current token begins a JOIN clause
+func (p *Parser) isJoinStart() bool {
+	switch p.current().Type {
+	case TokenJoin, TokenInner, TokenLeft, TokenRight, TokenFull, TokenCross:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseJoins parses a sequence of JOIN clauses
+func (p *Parser) parseJoins(ctes []CTE) ([]Join, error) {
+	var joins []Join
+	for p.isJoinStart() {
+		join, err := p.parseJoin(ctes)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to parse JOIN: %w", err)
 		}
-		q.Offset = offset
+		joins = append(joins, *join)
 	}
+	return joins, nil
+}
 
-	return q, nil
+// parseTableName parses a table name or CTE reference, validating it unless it names a CTE
+func (p *Parser) parseTableName(ctes []CTE, context string) (string, error) {
+	tableName := p.current().Value
+	if p.current().Type != TokenIdent && p.current().Type != TokenString {
+		return "", fmt.Errorf("expected table name or subquery after %s", context)
+	}
+	p.advance()
+
+	for _, cte := range ctes {
+		if cte.Name == tableName {
+			return tableName, nil
+		}
+	}
+	if err := ValidateTableName(tableName); err != nil {
+		return "", err
+	}
+	return tableName, nil
+}
+
+// parseTableAlias parses an optional [AS] alias
+func (p *Parser) parseTableAlias() string {
+	if p.current().Type == TokenAs {
+		p.advance()
+	}
+	if p.current().Type == TokenIdent {
+		alias := p.current().Value
+		p.advance()
+		return alias
+	}
+	return ""
+}
+
+// parseJoinGroup parses the inside of a parenthesized join expression,
+// e.g. "c JOIN d ON c.id = d.c_id" in "LEFT JOIN (c JOIN d ON c.id = d.c_id) ON ..."
+func (p *Parser) parseJoinGroup(ctes []CTE) (*JoinGroup, error) {
+	tableName, err := p.parseTableName(ctes, "(")
+	if err != nil {
+		return nil, err
+	}
+	group := &JoinGroup{TableName: tableName, Alias: p.parseTableAlias()}
+
+	joins, err := p.parseJoins(ctes)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, fmt.Errorf("parenthesized JOIN source must contain a JOIN")
+	}
+	group.Joins = joins
+
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ) after parenthesized JOIN: %w", err)
+	}
+	return group, nil
 }
 
 // parseJoin parses a JOIN clause
@@ -311,8 +497,27 @@ func (p *Parser) parseJoin(ctes []CTE) (*Join, error) {
 		return nil, fmt.Errorf("expected JOIN keyword")
 	}
 
-	// Parse joined table or subquery
-	if p.current().Type == TokenLeftParen {
+	// Parse joined table, subquery, parenthesized join or UNNEST(expr)
+	if p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "UNNEST") && p.peek().Type == TokenLeftParen {
+		p.advance() // consume UNNEST
+		p.advance() // consume (
+		expr, err := p.parseSelectExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse UNNEST: %w", err)
+		}
+		if err := p.expect(TokenRightParen); err != nil {
+			return nil, fmt.Errorf("expected ) after UNNEST array: %w", err)
+		}
+		join.Unnest = expr
+		join.Alias = p.parseTableAlias()
+	} else if p.current().Type == TokenLeftParen && p.peek().Type != TokenSelect && p.peek().Type != TokenWith {
+		p.advance() // consume (
+		group, err := p.parseJoinGroup(ctes)
+		if err != nil {
+			return nil, err
+		}
+		join.Group = group
+	} else if p.current().Type == TokenLeftParen {
 		// Subquery
 		p.advance() // consume (
 		subquery, err := p.parseQuery()
@@ -334,40 +539,23 @@ func (p *Parser) parseJoin(ctes []CTE) (*Join, error) {
 		}
 	} else {
 		// Table name or CTE reference
-		tableName := p.current().Value
-		if p.current().Type != TokenIdent && p.current().Type != TokenString {
-			return nil, fmt.Errorf("expected table name or subquery after JOIN")
-		}
-		p.advance()
-
-		// Validate table name (unless it's a CTE reference)
-		isCTE := false
-		for _, cte := range ctes {
-			if cte.Name == tableName {
-				isCTE = true
-				break
-			}
-		}
-		if !isCTE {
-			if err := ValidateTableName(tableName); err != nil {
-				return nil, err
-			}
+		tableName, err := p.parseTableName(ctes, "JOIN")
+		if err != nil {
+			return nil, err
 		}
-
 		join.TableName = tableName
-
-		// Parse optional alias for table
-		if p.current().Type == TokenAs {
-			p.advance()
-		}
-		if p.current().Type == TokenIdent {
-			join.Alias = p.current().Value
-			p.advance()
-		}
+		join.Alias = p.parseTableAlias()
 	}
 
-	// Parse ON clause (required for all join types except CROSS JOIN)
-	if join.Type != JoinCross {
+	// Parse ON or USING clause (required for all join types except CROSS JOIN)
+	if join.Type != JoinCross && p.current().Type == TokenUsing {
+		columns, err := p.parseUsing()
+		if err != nil {
+			return nil, err
+		}
+		join.UsingColumns = columns
+	} else if join.Type != JoinCross && (join.Unnest == nil || p.current().Type == TokenOn) {
+		// UNNEST joins every element without an ON clause
 		if err := p.expect(TokenOn); err != nil {
 			return nil, fmt.Errorf("expected ON clause after JOIN table: %w", err)
 		}
@@ -381,6 +569,36 @@ func (p *Parser) parseJoin(ctes []CTE) (*Join, error) {
 	return join, nil
 }
 
+// parseUsing parses the column list of a USING (col, ...) clause
+func (p *Parser) parseUsing() ([]string, error) {
+	p.advance() // consume USING
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, fmt.Errorf("expected ( after USING: %w", err)
+	}
+
+	var columns []string
+	for {
+		if p.current().Type != TokenIdent {
+			return nil, fmt.Errorf("expected column name in USING")
+		}
+		if slices.Contains(columns, p.current().Value) {
+			return nil, fmt.Errorf("column %s listed twice in USING", p.current().Value)
+		}
+		columns = append(columns, p.current().Value)
+		p.advance()
+
+		if p.current().Type != TokenComma {
+			break
+		}
+		p.advance()
+	}
+
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ) after USING columns: %w", err)
+	}
+	return columns, nil
+}
+
 // parseSelectList parses the SELECT list (columns, expressions, aliases)
 func (p *Parser) parseSelectList() ([]SelectItem, error) {
 	var items []SelectItem
@@ -409,6 +627,18 @@ func (p *Parser) parseSelectList() ([]SelectItem, error) {
 func (p *Parser) parseSelectItem() (SelectItem, error) {
 	var item SelectItem
 
+	// u.* is lexed as "u." followed by *
+	if table := p.current().Value; p.current().Type == TokenIdent && len(table) > 1 && strings.HasSuffix(table, ".") &&
+		p.peek().Type == TokenIdent && p.peek().Value == "*" {
+		p.advance()
+		p.advance()
+		item.Expr = &ColumnRef{Column: table + "*"}
+		if p.current().Type == TokenAs {
+			return item, fmt.Errorf("%s* cannot have an alias", table)
+		}
+		return item, nil
+	}
+
 	// Parse the expression (column or function call)
 	expr, err := p.parseSelectExpression()
 	if err != nil {
@@ -416,12 +646,15 @@ func (p *Parser) parseSelectItem() (SelectItem, error) {
 	}
 	item.Expr = expr
 
-	// Check for AS alias
+	// Check for AS alias; a quoted alias may contain spaces ("Total Count")
 	if p.current().Type == TokenAs {
 		p.advance()
-		if p.current().Type != TokenIdent {
+		if p.current().Type != TokenIdent && p.current().Type != TokenString {
 			return item, fmt.Errorf("expected alias name after AS")
 		}
+		if p.current().Value == "" {
+			return item, fmt.Errorf("alias after AS cannot be empty")
+		}
 		item.Alias = p.current().Value
 		p.advance()
 	} else if p.current().Type == TokenIdent && p.current().Value != "*" {
@@ -455,6 +688,7 @@ func isKeyword(s string) bool {
 		"offset": true, "OFFSET": true,
 		"in": true, "IN": true,
 		"like": true, "LIKE": true,
+		"ilike": true, "ILIKE": true,
 		"between": true, "BETWEEN": true,
 		"is": true, "IS": true,
 		"not": true, "NOT": true,
@@ -464,119 +698,15 @@ func isKeyword(s string) bool {
 		"partition": true, "PARTITION": true,
 		"rows": true, "ROWS": true,
 		"range": true, "RANGE": true,
+		"tablesample": true, "TABLESAMPLE": true,
+		"qualify": true, "QUALIFY": true,
+		"union": true, "UNION": true,
+		"all": true, "ALL": true,
+		"using": true, "USING": true,
 	}
 	return keywords[s]
 }
 
-// parseGroupBy parses the GROUP BY clause
-func (p *Parser) parseGroupBy() ([]string, error) {
-	// Expect GROUP
-	if err := p.expect(TokenGroup); err != nil {
-		return nil, err
-	}
-
-	// Expect BY
-	if err := p.expect(TokenBy); err != nil {
-		return nil, fmt.Errorf("expected BY after GROUP: %w", err)
-	}
-
-	var columns []string
-
-	// Parse column list
-	for {
-		if p.current().Type != TokenIdent {
-			return nil, fmt.Errorf("expected column name in GROUP BY, got %v", p.current().Type)
-		}
-
-		column := p.current().Value
-		if err := ValidateColumnName(column); err != nil {
-			return nil, err
-		}
-
-		columns = append(columns, column)
-		p.advance()
-
-		// Check for comma (more columns)
-		if p.current().Type == TokenComma {
-			p.advance()
-			continue
-		}
-
-		// No comma, we're done
-		break
-	}
-
-	if len(columns) == 0 {
-		return nil, fmt.Errorf("GROUP BY requires at least one column")
-	}
-
-	return columns, nil
-}
-
-// parseOrderBy parses the ORDER BY clause
-func (p *Parser) parseOrderBy() ([]OrderByItem, error) {
-	// Expect ORDER
-	if err := p.expect(TokenOrder); err != nil {
-		return nil, err
-	}
-
-	// Expect BY
-	if err := p.expect(TokenBy); err != nil {
-		return nil, fmt.Errorf("expected BY after ORDER: %w", err)
-	}
-
-	return p.parseOrderByList()
-}
-
-// parseOrderByList parses the ORDER BY column list (without ORDER BY keywords)
-func (p *Parser) parseOrderByList() ([]OrderByItem, error) {
-	var items []OrderByItem
-
-	// Parse column list
-	for {
-		if p.current().Type != TokenIdent {
-			return nil, fmt.Errorf("expected column name in ORDER BY, got %v", p.current().Type)
-		}
-
-		column := p.current().Value
-		if err := ValidateColumnName(column); err != nil {
-			return nil, err
-		}
-
-		item := OrderByItem{
-			Column: column,
-			Desc:   false, // Default to ASC
-		}
-		p.advance()
-
-		// Check for ASC/DESC modifier
-		if p.current().Type == TokenAsc {
-			item.Desc = false
-			p.advance()
-		} else if p.current().Type == TokenDesc {
-			item.Desc = true
-			p.advance()
-		}
-
-		items = append(items, item)
-
-		// Check for comma (more columns)
-		if p.current().Type == TokenComma {
-			p.advance()
-			continue
-		}
-
-		// No comma, we're done
-		break
-	}
-
-	if len(items) == 0 {
-		return nil, fmt.Errorf("ORDER BY requires at least one column")
-	}
-
-	return items, nil
-}
-
 // parseLimit parses the LIMIT clause
 func (p *Parser) parseLimit() (*int64, error) {
 	// Expect LIMIT
@@ -603,6 +733,51 @@ func (p *Parser) parseLimit() (*int64, error) {
 	return &limit, nil
 }
 
+// parseTableSample parses TABLESAMPLE (n PERCENT) or TABLESAMPLE (n ROWS)
+func (p *Parser) parseTableSample() (*TableSample, error) {
+	// Expect TABLESAMPLE
+	if err := p.expect(TokenTablesample); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, fmt.Errorf("expected ( after TABLESAMPLE: %w", err)
+	}
+
+	// Expect a number
+	if p.current().Type != TokenNumber {
+		return nil, fmt.Errorf("expected number in TABLESAMPLE, got %v", p.current().Type)
+	}
+	numStr := p.current().Value
+	p.advance()
+
+	sample := &TableSample{}
+	switch {
+	case p.current().Type == TokenRows:
+		rows, err := strconv.ParseInt(numStr, 10, 64)
+		if err != nil || rows < 0 {
+			return nil, fmt.Errorf("TABLESAMPLE ROWS must be a non-negative integer, got %s", numStr)
+		}
+		sample.Rows = rows
+		sample.ByRows = true
+	case p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "PERCENT"):
+		percent, err := strconv.ParseFloat(numStr, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("TABLESAMPLE PERCENT must be between 0 and 100, got %s", numStr)
+		}
+		sample.Percent = percent
+	default:
+		return nil, fmt.Errorf("expected PERCENT or ROWS in TABLESAMPLE, got %q", p.current().Value)
+	}
+	p.advance()
+
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ) after TABLESAMPLE: %w", err)
+	}
+
+	return sample, nil
+}
+
 // parseOffset parses the OFFSET clause
 func (p *Parser) parseOffset() (*int64, error) {
 	// Expect OFFSET
@@ -636,9 +811,11 @@ func (p *Parser) parseWithClause() ([]CTE, error) {
 		return nil, err
 	}
 
-	// Check for RECURSIVE (not supported yet)
+	// RECURSIVE lets each CTE of the clause refer to itself
+	recursive := false
 	if p.current().Type == TokenRecursive {
-		return nil, fmt.Errorf("RECURSIVE CTEs are not supported yet")
+		recursive = true
+		p.advance()
 	}
 
 	var ctes []CTE
@@ -674,8 +851,9 @@ func (p *Parser) parseWithClause() ([]CTE, error) {
 
 		// Add CTE
 		ctes = append(ctes, CTE{
-			Name:  cteName,
-			Query: subquery,
+			Name:      cteName,
+			Query:     subquery,
+			Recursive: recursive,
 		})
 
 		// Check for comma (more CTEs)
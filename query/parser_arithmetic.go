@@ -0,0 +1,256 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseCaseExpression parses a CASE expression
+func (p *Parser) parseCaseExpression() (SelectExpression, error) {
+	// Expect CASE
+	if err := p.expect(TokenCase); err != nil {
+		return nil, err
+	}
+
+	var whenClauses []WhenClause
+
+	// Parse WHEN clauses
+	for p.current().Type == TokenWhen {
+		p.advance() // skip WHEN
+
+		// Parse the condition (a WHERE-like expression with AND/OR support)
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CASE WHEN condition: %w", err)
+		}
+
+		// Expect THEN
+		if err := p.expect(TokenThen); err != nil {
+			return nil, fmt.Errorf("expected THEN after WHEN condition: %w", err)
+		}
+
+		// Parse the result expression
+		result, err := p.parseSelectExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CASE THEN result: %w", err)
+		}
+
+		whenClauses = append(whenClauses, WhenClause{
+			Condition: condition,
+			Result:    result,
+		})
+	}
+
+	// Check for at least one WHEN clause
+	if len(whenClauses) == 0 {
+		return nil, fmt.Errorf("CASE expression must have at least one WHEN clause")
+	}
+
+	// Parse optional ELSE clause
+	var elseExpr SelectExpression
+	if p.current().Type == TokenElse {
+		p.advance() // skip ELSE
+
+		var err error
+		elseExpr, err = p.parseSelectExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CASE ELSE result: %w", err)
+		}
+	}
+
+	// Expect END
+	if err := p.expect(TokenEnd); err != nil {
+		return nil, fmt.Errorf("expected END after CASE expression: %w", err)
+	}
+
+	return &CaseExpr{
+		WhenClauses: whenClauses,
+		ElseExpr:    elseExpr,
+	}, nil
+}
+
+// parseSelectExpression parses a select expression: operands combined with
+// the arithmetic operators + - * / % and the || concatenation operator.
+// * / % bind tighter than + and -, which bind tighter than ||.
+func (p *Parser) parseSelectExpression() (SelectExpression, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.current().Type == TokenConcat {
+		p.advance()
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		left = &ConcatExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseSum parses terms combined with + and -
+func (p *Parser) parseSum() (SelectExpression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.current().Type == TokenPlus || p.current().Type == TokenMinus {
+		operator := p.current().Type
+		p.advance()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &ArithmeticExpr{Left: left, Operator: operator, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseTerm parses operands combined with *, / and %
+func (p *Parser) parseTerm() (SelectExpression, error) {
+	left, err := p.parseSelectOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isMultiplyOperator() || p.current().Type == TokenSlash || p.current().Type == TokenPercent {
+		operator := p.current().Type
+		if p.isMultiplyOperator() {
+			operator = TokenStar
+		}
+		p.advance()
+		right, err := p.parseSelectOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = &ArithmeticExpr{Left: left, Operator: operator, Right: right}
+	}
+
+	return left, nil
+}
+
+// isMultiplyOperator reports whether the current token is *, which the lexer
+// returns as an identifier so that SELECT * and COUNT(*) parse as columns
+func (p *Parser) isMultiplyOperator() bool {
+	return p.current().Type == TokenIdent && p.current().Value == "*"
+}
+
+// parseSelectOperand parses a single operand (column reference, function call,
+// literal, CASE, subquery or parenthesized expression)
+func (p *Parser) parseSelectOperand() (SelectExpression, error) {
+	// Check for CASE expression
+	if p.current().Type == TokenCase {
+		return p.parseCaseExpression()
+	}
+
+	// Check for scalar subquery (starts with opening paren)
+	if p.current().Type == TokenLeftParen {
+		// Look ahead to see if it's a subquery (SELECT or WITH)
+		nextPos := p.pos + 1
+		if nextPos < len(p.tokens) && (p.tokens[nextPos].Type == TokenSelect || p.tokens[nextPos].Type == TokenWith) {
+			return p.parseScalarSubquery()
+		}
+		return p.parseParenExpression()
+	}
+
+	// Check for aggregate or regular function call (identifier followed by left paren)
+	if p.current().Type == TokenIdent && p.peek().Type == TokenLeftParen {
+		funcName := strings.ToUpper(p.current().Value)
+		// EXTRACT uses FROM inside its parentheses, so it isn't a regular function call
+		if funcName == "EXTRACT" {
+			return p.parseExtractExpression()
+		}
+		// TRIM accepts the standard TRIM([LEADING|TRAILING|BOTH] [chars] FROM str) form
+		if funcName == "TRIM" {
+			return p.parseTrimExpression()
+		}
+		// Check if it's an aggregate function
+		if isAggregateFunction(funcName) {
+			return p.parseAggregateFunction()
+		}
+		// Check if it's a window function
+		if isWindowFunction(funcName) {
+			return p.parseWindowFunction()
+		}
+		funcCall, err := p.parseFunctionCall()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseSubscript(funcCall)
+	}
+
+	// A minus sign the lexer didn't join to a number negates the operand
+	// after it, computed as 0 - operand
+	if p.current().Type == TokenMinus {
+		p.advance()
+		operand, err := p.parseSelectOperand()
+		if err != nil {
+			return nil, err
+		}
+		if literal, ok := operand.(*LiteralExpr); ok {
+			switch v := literal.Value.(type) {
+			case int64:
+				return &LiteralExpr{Value: -v}, nil
+			case float64:
+				return &LiteralExpr{Value: -v}, nil
+			}
+		}
+		return &ArithmeticExpr{Left: &LiteralExpr{Value: int64(0)}, Operator: TokenMinus, Right: operand}, nil
+	}
+
+	// Check for literals (numbers, strings, bools)
+	switch p.current().Type {
+	case TokenNumber:
+		numStr := p.current().Value
+		p.advance()
+		// Try to parse as int first, then float
+		if intVal, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+			return &LiteralExpr{Value: intVal}, nil
+		} else if floatVal, err := strconv.ParseFloat(numStr, 64); err == nil {
+			return &LiteralExpr{Value: floatVal}, nil
+		} else {
+			return nil, fmt.Errorf("invalid number: %s", numStr)
+		}
+	case TokenString:
+		str := p.current().Value
+		p.advance()
+		return &LiteralExpr{Value: str}, nil
+	case TokenBool:
+		b := strings.ToLower(p.current().Value) == "true"
+		p.advance()
+		return &LiteralExpr{Value: b}, nil
+	}
+
+	// Otherwise, it's a column reference
+	if p.current().Type != TokenIdent {
+		return nil, fmt.Errorf("expected column name, literal, or function call, got %v", p.current().Type)
+	}
+
+	column := p.current().Value
+	p.advance()
+
+	return p.parseSubscript(&ColumnRef{Column: column})
+}
+
+// parseParenExpression parses a parenthesized select expression such as (a + b)
+func (p *Parser) parseParenExpression() (SelectExpression, error) {
+	if err := p.depthCounter.Enter(); err != nil {
+		return nil, err
+	}
+	defer p.depthCounter.Exit()
+
+	p.advance() // skip (
+	expr, err := p.parseSelectExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after expression: %w", err)
+	}
+	return expr, nil
+}
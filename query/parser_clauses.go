@@ -0,0 +1,308 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// parseGroupBy parses the GROUP BY clause
+func (p *Parser) parseGroupBy(q *Query) ([]string, error) {
+	// Expect GROUP
+	if err := p.expect(TokenGroup); err != nil {
+		return nil, err
+	}
+
+	// Expect BY
+	if err := p.expect(TokenBy); err != nil {
+		return nil, fmt.Errorf("expected BY after GROUP: %w", err)
+	}
+
+	var columns []string
+
+	// ROLLUP(...) wraps the whole column list
+	rollup := p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "ROLLUP") && p.peek().Type == TokenLeftParen
+	if rollup {
+		p.advance() // skip ROLLUP
+		p.advance() // skip (
+		q.GroupByRollup = true
+	}
+
+	// Parse column list
+	for {
+		expr, err := p.parseSelectExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse GROUP BY: %w", err)
+		}
+		column, err := groupByColumn(q, expr)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+
+		// Check for comma (more columns)
+		if p.current().Type == TokenComma {
+			p.advance()
+			continue
+		}
+
+		// No comma, we're done
+		break
+	}
+
+	if rollup {
+		if err := p.expect(TokenRightParen); err != nil {
+			return nil, fmt.Errorf("expected ) after ROLLUP columns: %w", err)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("GROUP BY requires at least one column")
+	}
+
+	return columns, nil
+}
+
+// groupByColumn returns the column of the input rows that a GROUP BY item
+// groups on. A column name is used as is unless it is the alias of a
+// computed SELECT item, which like any other expression is computed into
+// an entry of q.GroupKeys. SELECT items computing a key read its column.
+func groupByColumn(q *Query, expr SelectExpression) (string, error) {
+	switch e := expr.(type) {
+	case *ColumnRef:
+		if err := ValidateColumnName(e.Column); err != nil {
+			return "", err
+		}
+		var aliased SelectExpression
+		for _, item := range q.SelectList {
+			if item.Alias == e.Column {
+				if ref, ok := item.Expr.(*ColumnRef); !ok || ref.Column != e.Column {
+					aliased = item.Expr
+				}
+				break
+			}
+		}
+		if aliased == nil {
+			return e.Column, nil
+		}
+		expr = aliased
+	case *LiteralExpr:
+		return "", fmt.Errorf("GROUP BY %v is a constant, expected a column, SELECT alias or expression", e.Value)
+	}
+	if HasAggregateFunction([]SelectItem{{Expr: expr}}) || HasWindowFunction([]SelectItem{{Expr: expr}}) {
+		return "", fmt.Errorf("GROUP BY cannot contain aggregate or window functions")
+	}
+
+	for _, key := range q.GroupKeys {
+		if reflect.DeepEqual(key.Expr, expr) {
+			return key.Alias, nil
+		}
+	}
+
+	// The key is named after a SELECT item computing it, if one has an alias
+	column := fmt.Sprintf("GROUP BY #%d", len(q.GroupKeys)+1)
+	for _, item := range q.SelectList {
+		if item.Alias != "" && reflect.DeepEqual(item.Expr, expr) {
+			column = item.Alias
+			break
+		}
+	}
+	for i, item := range q.SelectList {
+		if !reflect.DeepEqual(item.Expr, expr) {
+			continue
+		}
+		// Keep the name the expression would have had
+		if item.Alias == "" {
+			item.Alias = fmt.Sprintf("col_%d", i)
+		}
+		q.SelectList[i] = SelectItem{Expr: &ColumnRef{Column: column}, Alias: item.Alias}
+	}
+	q.GroupKeys = append(q.GroupKeys, SelectItem{Expr: expr, Alias: column})
+	return column, nil
+}
+
+// parseOrderBy parses an ORDER BY clause of column names, as used inside
+// ordered aggregates
+func (p *Parser) parseOrderBy() ([]OrderByItem, error) {
+	if err := p.expectOrderBy(); err != nil {
+		return nil, err
+	}
+	return p.parseOrderByList()
+}
+
+// expectOrderBy consumes the ORDER BY keywords
+func (p *Parser) expectOrderBy() error {
+	// Expect ORDER
+	if err := p.expect(TokenOrder); err != nil {
+		return err
+	}
+
+	// Expect BY
+	if err := p.expect(TokenBy); err != nil {
+		return fmt.Errorf("expected BY after ORDER: %w", err)
+	}
+	return nil
+}
+
+// parseQueryOrderBy parses the ORDER BY clause of q, whose items may also be
+// SELECT positions or expressions
+func (p *Parser) parseQueryOrderBy(q *Query) ([]OrderByItem, error) {
+	if err := p.expectOrderBy(); err != nil {
+		return nil, err
+	}
+
+	var items []OrderByItem
+	for {
+		expr, err := p.parseSelectExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ORDER BY: %w", err)
+		}
+		column, err := orderByColumn(q, expr)
+		if err != nil {
+			return nil, err
+		}
+
+		item := OrderByItem{Column: column}
+		if err := p.parseOrderDirection(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if p.current().Type != TokenComma {
+			break
+		}
+		p.advance()
+	}
+
+	return items, nil
+}
+
+// orderByColumn returns the result column of q that an ORDER BY item sorts
+// by. A column name or SELECT alias is used as is, and a number picks a
+// SELECT item by position. Other expressions, and positions of unnamed
+// SELECT items, sort by the SELECT item computing the same expression under
+// an alias or else by a new entry of q.OrderKeys.
+func orderByColumn(q *Query, expr SelectExpression) (string, error) {
+	switch e := expr.(type) {
+	case *ColumnRef:
+		if e.Column == "*" {
+			return "", fmt.Errorf("cannot ORDER BY *")
+		}
+		if err := ValidateColumnName(e.Column); err != nil {
+			return "", err
+		}
+		return e.Column, nil
+	case *LiteralExpr:
+		position, ok := e.Value.(int64)
+		if !ok {
+			return "", fmt.Errorf("ORDER BY %v is a constant, expected a column, expression or SELECT position", e.Value)
+		}
+		if position < 1 || position > int64(len(q.SelectList)) {
+			return "", fmt.Errorf("ORDER BY position %d is not in the SELECT list of %d items", position, len(q.SelectList))
+		}
+		item := q.SelectList[position-1]
+		if item.Alias != "" {
+			return item.Alias, nil
+		}
+		if ref, ok := item.Expr.(*ColumnRef); ok {
+			if ref.isWildcard() {
+				return "", fmt.Errorf("ORDER BY position %d refers to %s, which has no single column", position, ref.Column)
+			}
+			return ref.Column, nil
+		}
+		expr = item.Expr
+	}
+
+	for _, item := range q.SelectList {
+		if item.Alias != "" && reflect.DeepEqual(item.Expr, expr) {
+			return item.Alias, nil
+		}
+	}
+
+	// The key is an extra column, which would change the rows DISTINCT and
+	// UNION compare
+	if q.Distinct || len(q.SetOperations) > 0 {
+		return "", fmt.Errorf("ORDER BY expression must be a SELECT item with an alias when using DISTINCT or UNION")
+	}
+	for _, key := range q.OrderKeys {
+		if reflect.DeepEqual(key.Expr, expr) {
+			return key.Alias, nil
+		}
+	}
+	column := fmt.Sprintf("ORDER BY #%d", len(q.OrderKeys)+1)
+	q.OrderKeys = append(q.OrderKeys, SelectItem{Expr: expr, Alias: column})
+	return column, nil
+}
+
+// parseOrderByList parses the ORDER BY column list (without ORDER BY keywords)
+func (p *Parser) parseOrderByList() ([]OrderByItem, error) {
+	var items []OrderByItem
+
+	// Parse column list
+	for {
+		if p.current().Type != TokenIdent {
+			return nil, fmt.Errorf("expected column name in ORDER BY, got %v", p.current().Type)
+		}
+
+		column := p.current().Value
+		if err := ValidateColumnName(column); err != nil {
+			return nil, err
+		}
+
+		item := OrderByItem{
+			Column: column,
+			Desc:   false, // Default to ASC
+		}
+		p.advance()
+
+		if err := p.parseOrderDirection(&item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+
+		// Check for comma (more columns)
+		if p.current().Type == TokenComma {
+			p.advance()
+			continue
+		}
+
+		// No comma, we're done
+		break
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("ORDER BY requires at least one column")
+	}
+
+	return items, nil
+}
+
+// parseOrderDirection parses the optional ASC/DESC and NULLS FIRST/LAST
+// modifiers of an ORDER BY item
+func (p *Parser) parseOrderDirection(item *OrderByItem) error {
+	if p.current().Type == TokenAsc {
+		item.Desc = false
+		p.advance()
+	} else if p.current().Type == TokenDesc {
+		item.Desc = true
+		p.advance()
+	}
+
+	// NULLS, FIRST and LAST are not reserved, so they lex as identifiers
+	if p.current().Type != TokenIdent || !strings.EqualFold(p.current().Value, "NULLS") {
+		return nil
+	}
+	p.advance()
+	switch {
+	case p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "FIRST"):
+		item.Nulls = NullsFirst
+	case p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "LAST"):
+		item.Nulls = NullsLast
+	default:
+		return fmt.Errorf("expected FIRST or LAST after NULLS in ORDER BY, got %s", p.current().Value)
+	}
+	p.advance()
+	return nil
+}
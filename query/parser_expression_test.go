@@ -1,6 +1,7 @@
 package query
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -90,6 +91,193 @@ func TestParser_OperatorPrecedence(t *testing.T) {
 	}
 }
 
+func TestParser_ParenthesizedPrecedence(t *testing.T) {
+	// (a OR b) AND c should keep the OR inside the AND
+	q, err := Parse("select * from data.parquet where (a = 1 OR b = 2) AND c = 3")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	binExpr, ok := q.Filter.(*BinaryExpr)
+	if !ok {
+		t.Fatalf("expected BinaryExpr, got %T", q.Filter)
+	}
+	if binExpr.Operator != TokenAnd {
+		t.Errorf("expected root operator to be AND, got %v", binExpr.Operator)
+	}
+
+	leftBin, ok := binExpr.Left.(*BinaryExpr)
+	if !ok {
+		t.Fatalf("expected left side to be BinaryExpr, got %T", binExpr.Left)
+	}
+	if leftBin.Operator != TokenOr {
+		t.Errorf("expected left operator to be OR, got %v", leftBin.Operator)
+	}
+}
+
+func TestParser_DistinctOperator(t *testing.T) {
+	tests := []struct {
+		name    string
+		where   string
+		want    Expression
+		wantErr string
+	}{
+		{
+			name:  "IS DISTINCT FROM a value",
+			where: "a IS DISTINCT FROM 1",
+			want:  &DistinctExpr{Left: &ColumnRef{Column: "a"}, Right: &LiteralExpr{Value: int64(1)}},
+		},
+		{
+			name:  "IS NOT DISTINCT FROM a column",
+			where: "a is not distinct from b",
+			want:  &DistinctExpr{Left: &ColumnRef{Column: "a"}, Right: &ColumnRef{Column: "b"}, Negate: true},
+		},
+		{
+			name:  "null-safe equality with NULL",
+			where: "a <=> NULL",
+			want:  &DistinctExpr{Left: &ColumnRef{Column: "a"}, Right: &LiteralExpr{Value: nil}, Negate: true},
+		},
+		{
+			name:  "expression on the left",
+			where: "LENGTH(a) <=> b",
+			want: &DistinctExpr{
+				Left:   &FunctionCall{Name: "LENGTH", Args: []SelectExpression{&ColumnRef{Column: "a"}}},
+				Right:  &ColumnRef{Column: "b"},
+				Negate: true,
+			},
+		},
+		{
+			name:  "combined with AND",
+			where: "a <=> b AND c = 1",
+			want: &BinaryExpr{
+				Left:     &DistinctExpr{Left: &ColumnRef{Column: "a"}, Right: &ColumnRef{Column: "b"}, Negate: true},
+				Operator: TokenAnd,
+				Right:    &ComparisonExpr{Column: "c", Operator: TokenEqual, Value: int64(1)},
+			},
+		},
+		{
+			name:    "DISTINCT without FROM",
+			where:   "a IS DISTINCT 1",
+			wantErr: "expected FROM after IS [NOT] DISTINCT",
+		},
+		{
+			name:    "IS NULL after an expression",
+			where:   "a + 1 IS NULL",
+			wantErr: "expected DISTINCT FROM after IS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("select * from data.parquet where " + tt.where)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(q.Filter, tt.want) {
+				t.Errorf("Filter = %#v, want %#v", q.Filter, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_NotOperator(t *testing.T) {
+	tests := []struct {
+		name    string
+		where   string
+		want    Expression
+		wantErr string
+	}{
+		{
+			name:  "NOT of a parenthesized AND",
+			where: "NOT (age > 30 AND active = true)",
+			want: &NotExpr{Expr: &BinaryExpr{
+				Left:     &ComparisonExpr{Column: "age", Operator: TokenGreater, Value: int64(30)},
+				Operator: TokenAnd,
+				Right:    &ComparisonExpr{Column: "active", Operator: TokenEqual, Value: true},
+			}},
+		},
+		{
+			name:  "NOT binds tighter than AND",
+			where: "NOT a = 1 AND b = 2",
+			want: &BinaryExpr{
+				Left:     &NotExpr{Expr: &ComparisonExpr{Column: "a", Operator: TokenEqual, Value: int64(1)}},
+				Operator: TokenAnd,
+				Right:    &ComparisonExpr{Column: "b", Operator: TokenEqual, Value: int64(2)},
+			},
+		},
+		{
+			name:  "parenthesized OR inside AND",
+			where: "(a = 1 OR b = 2) AND NOT c = 3",
+			want: &BinaryExpr{
+				Left: &BinaryExpr{
+					Left:     &ComparisonExpr{Column: "a", Operator: TokenEqual, Value: int64(1)},
+					Operator: TokenOr,
+					Right:    &ComparisonExpr{Column: "b", Operator: TokenEqual, Value: int64(2)},
+				},
+				Operator: TokenAnd,
+				Right:    &NotExpr{Expr: &ComparisonExpr{Column: "c", Operator: TokenEqual, Value: int64(3)}},
+			},
+		},
+		{
+			name:  "double negation",
+			where: "NOT NOT (a = 1)",
+			want:  &NotExpr{Expr: &NotExpr{Expr: &ComparisonExpr{Column: "a", Operator: TokenEqual, Value: int64(1)}}},
+		},
+		{
+			name:  "parentheses starting an expression",
+			where: "NOT (a + 1) > 2",
+			want: &NotExpr{Expr: &ExpressionComparisonExpr{
+				Left:     &ArithmeticExpr{Left: &ColumnRef{Column: "a"}, Operator: TokenPlus, Right: &LiteralExpr{Value: int64(1)}},
+				Operator: TokenGreater,
+				Right:    &LiteralExpr{Value: int64(2)},
+			}},
+		},
+		{
+			name:  "NOT EXISTS is unchanged",
+			where: "NOT EXISTS (SELECT id FROM other)",
+		},
+		{
+			name:    "error inside the parentheses",
+			where:   "NOT (a = 1 OR b >)",
+			wantErr: "expected column name, literal",
+		},
+		{
+			name:    "unclosed parenthesis",
+			where:   "NOT (a = 1 OR b = 2",
+			wantErr: "expected ')' after condition",
+		},
+		{
+			name:    "NOT without a condition",
+			where:   "NOT",
+			wantErr: "expected column name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("select * from data.parquet where " + tt.where)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(q.Filter, tt.want) {
+				t.Errorf("Filter = %#v, want %#v", q.Filter, tt.want)
+			}
+		})
+	}
+}
+
 func TestComparisonExpr_String(t *testing.T) {
 	query := "select * from data.parquet where name = 'alice'"
 	q, err := Parse(query)
@@ -134,6 +322,21 @@ func TestComparisonExpr_Number(t *testing.T) {
 			query:     "select * from data.parquet where temp = -10",
 			wantValue: int64(-10),
 		},
+		{
+			name:      "scientific notation",
+			query:     "select * from data.parquet where salary > 1e5",
+			wantValue: float64(100000),
+		},
+		{
+			name:      "negative exponent",
+			query:     "select * from data.parquet where rate < 1.5e-3",
+			wantValue: float64(0.0015),
+		},
+		{
+			name:      "underscore digit groups",
+			query:     "select * from data.parquet where n = 1_000_000",
+			wantValue: int64(1000000),
+		},
 	}
 
 	for _, tt := range tests {
@@ -176,6 +379,36 @@ func TestParser_InOperator(t *testing.T) {
 			query:   "select * from data.parquet where status NOT IN ('deleted', 'archived')",
 			wantErr: false,
 		},
+		{
+			name:    "IN with mixed numbers, strings and NULL",
+			query:   "select * from data.parquet where code IN (404, 'n/a', -1.5, NULL)",
+			wantErr: false,
+		},
+		{
+			name:    "tuple IN",
+			query:   "select * from data.parquet where (age, status) IN ((30, 'active'), (25, 'pending')) AND id > 1",
+			wantErr: false,
+		},
+		{
+			name:    "tuple NOT IN",
+			query:   "select * from data.parquet where (age, active) NOT IN ((30, true))",
+			wantErr: false,
+		},
+		{
+			name:    "tuple with wrong number of values",
+			query:   "select * from data.parquet where (age, status) IN ((30, 'active'), (25))",
+			wantErr: true,
+		},
+		{
+			name:    "tuple list without inner parentheses",
+			query:   "select * from data.parquet where (age, status) IN (30, 'active')",
+			wantErr: true,
+		},
+		{
+			name:    "tuple with expression instead of column",
+			query:   "select * from data.parquet where (age, 1) IN ((30, 1))",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -192,6 +425,41 @@ func TestParser_InOperator(t *testing.T) {
 	}
 }
 
+func TestInExpr_Tuples(t *testing.T) {
+	expr := &InExpr{
+		Columns: []string{"age", "name"},
+		Tuples:  [][]interface{}{{int64(30), "alice"}, {int64(25), "bob"}},
+	}
+
+	tests := []struct {
+		name    string
+		row     map[string]interface{}
+		negate  bool
+		want    bool
+		wantErr bool
+	}{
+		{name: "matches first tuple", row: map[string]interface{}{"age": int64(30), "name": "alice"}, want: true},
+		{name: "matches second tuple", row: map[string]interface{}{"age": 25.0, "name": "bob"}, want: true},
+		{name: "values from different tuples", row: map[string]interface{}{"age": int64(30), "name": "bob"}, want: false},
+		{name: "not in", row: map[string]interface{}{"age": int64(30), "name": "bob"}, negate: true, want: true},
+		{name: "missing column", row: map[string]interface{}{"age": int64(30)}, wantErr: true},
+		{name: "type mismatch", row: map[string]interface{}{"age": "thirty", "name": "alice"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr.Negate = tt.negate
+			got, err := expr.Evaluate(tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParser_LikeOperator(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -218,6 +486,46 @@ func TestParser_LikeOperator(t *testing.T) {
 			query:   "select * from data.parquet where name NOT LIKE 'test%'",
 			wantErr: false,
 		},
+		{
+			name:    "ILIKE",
+			query:   "select * from data.parquet where name ILIKE 'Alice%'",
+			wantErr: false,
+		},
+		{
+			name:    "NOT ILIKE",
+			query:   "select * from data.parquet where name not ilike '%SMITH'",
+			wantErr: false,
+		},
+		{
+			name:    "LIKE with ESCAPE",
+			query:   "select * from data.parquet where rate LIKE '100!%' ESCAPE '!'",
+			wantErr: false,
+		},
+		{
+			name:    "NOT ILIKE with ESCAPE",
+			query:   "select * from data.parquet where key NOT ILIKE '%!_ID' escape '!' AND escape = 1",
+			wantErr: false,
+		},
+		{
+			name:    "ESCAPE with more than one character",
+			query:   "select * from data.parquet where rate LIKE '100!%' ESCAPE '!!'",
+			wantErr: true,
+		},
+		{
+			name:    "ESCAPE without a string",
+			query:   "select * from data.parquet where rate LIKE '100!%' ESCAPE",
+			wantErr: true,
+		},
+		{
+			name:    "pattern ending with the escape character",
+			query:   "select * from data.parquet where rate LIKE '100%!' ESCAPE '!'",
+			wantErr: true,
+		},
+		{
+			name:    "ILIKE without a pattern",
+			query:   "select * from data.parquet where name ILIKE 5",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -287,6 +595,21 @@ func TestParser_IsNullOperator(t *testing.T) {
 			query:   "select * from data.parquet where email IS NOT NULL",
 			wantErr: false,
 		},
+		{
+			name:    "IS TRUE",
+			query:   "select * from data.parquet where active IS TRUE",
+			wantErr: false,
+		},
+		{
+			name:    "IS NOT FALSE",
+			query:   "select * from data.parquet where active IS NOT FALSE",
+			wantErr: false,
+		},
+		{
+			name:    "IS followed by a value",
+			query:   "select * from data.parquet where active IS 1",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -488,3 +811,21 @@ func TestParseFrameBound_Direct(t *testing.T) {
 func containsSubstring(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestParser_NumericLiteralsInSelect(t *testing.T) {
+	q, err := Parse("select 2E3 as a, 1_500 as b from data.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []interface{}{float64(2000), int64(1500)}
+	for i, w := range want {
+		lit, ok := q.SelectList[i].Expr.(*LiteralExpr)
+		if !ok {
+			t.Fatalf("select item %d: expected LiteralExpr, got %T", i, q.SelectList[i].Expr)
+		}
+		if lit.Value != w {
+			t.Errorf("select item %d: expected %v (%T), got %v (%T)", i, w, w, lit.Value, lit.Value)
+		}
+	}
+}
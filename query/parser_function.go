@@ -9,11 +9,15 @@ import (
 // isAggregateFunction checks if a function name is an aggregate function
 func isAggregateFunction(name string) bool {
 	aggregates := map[string]bool{
-		"COUNT": true,
-		"SUM":   true,
-		"AVG":   true,
-		"MIN":   true,
-		"MAX":   true,
+		"COUNT":     true,
+		"SUM":       true,
+		"AVG":       true,
+		"MIN":       true,
+		"MAX":       true,
+		"ARRAY_AGG": true,
+
+		"STRING_AGG":            true,
+		"APPROX_COUNT_DISTINCT": true,
 	}
 	return aggregates[strings.ToUpper(name)]
 }
@@ -59,6 +63,21 @@ func (p *Parser) parseFunctionCall() (SelectExpression, error) {
 		}
 		args = append(args, arg)
 
+		// CAST(expr AS type) passes the type name as a second argument
+		if len(args) == 1 && p.current().Type == TokenAs && isCastFunction(funcName) {
+			p.advance()
+			typeName := p.current()
+			if typeName.Type != TokenIdent || typeName.Value == "*" {
+				return nil, fmt.Errorf("expected type name after AS in %s, got %q", strings.ToUpper(funcName), typeName.Value)
+			}
+			if !isCastType(typeName.Value) {
+				return nil, fmt.Errorf("%s: unknown type %s (expected INT, FLOAT, STRING, BOOL or DATE)", strings.ToUpper(funcName), typeName.Value)
+			}
+			p.advance()
+			args = append(args, &LiteralExpr{Value: strings.ToUpper(typeName.Value)})
+			break
+		}
+
 		if p.current().Type == TokenComma {
 			p.advance()
 			continue
@@ -74,6 +93,12 @@ func (p *Parser) parseFunctionCall() (SelectExpression, error) {
 	return &FunctionCall{Name: funcName, Args: args}, nil
 }
 
+// isCastFunction checks if a function takes the CAST(expr AS type) form
+func isCastFunction(name string) bool {
+	name = strings.ToUpper(name)
+	return name == "CAST" || name == "TRY_CAST"
+}
+
 // parseAggregateFunction parses an aggregate function call
 func (p *Parser) parseAggregateFunction() (SelectExpression, error) {
 	funcName := strings.ToUpper(p.current().Value)
@@ -85,8 +110,22 @@ func (p *Parser) parseAggregateFunction() (SelectExpression, error) {
 
 	var arg SelectExpression
 
+	// Check for DISTINCT modifier (e.g. COUNT(DISTINCT team))
+	distinct := false
+	if p.current().Type == TokenDistinct {
+		distinct = true
+		p.advance()
+	}
+
+	if distinct && funcName == "APPROX_COUNT_DISTINCT" {
+		return nil, fmt.Errorf("APPROX_COUNT_DISTINCT does not take DISTINCT, it already counts distinct values")
+	}
+
 	// Check for COUNT(*)
 	if funcName == "COUNT" && p.current().Type == TokenIdent && p.current().Value == "*" {
+		if distinct {
+			return nil, fmt.Errorf("COUNT(DISTINCT *) is not supported, use COUNT(DISTINCT column)")
+		}
 		p.advance()
 		arg = nil // COUNT(*) has no argument
 	} else {
@@ -99,7 +138,7 @@ func (p *Parser) parseAggregateFunction() (SelectExpression, error) {
 	}
 
 	// Check if MIN/MAX has multiple arguments (scalar function form)
-	if (funcName == "MIN" || funcName == "MAX") && p.current().Type == TokenComma {
+	if (funcName == "MIN" || funcName == "MAX") && p.current().Type == TokenComma && !distinct {
 		// Parse as scalar function with multiple arguments
 		args := []SelectExpression{arg}
 		for p.current().Type == TokenComma {
@@ -118,15 +157,61 @@ func (p *Parser) parseAggregateFunction() (SelectExpression, error) {
 		return &FunctionCall{Name: funcName, Args: args}, nil
 	}
 
+	aggExpr := &AggregateExpr{
+		Function: funcName,
+		Arg:      arg,
+		Distinct: distinct,
+	}
+
+	// STRING_AGG takes the separator as a second, literal argument
+	if funcName == "STRING_AGG" {
+		if err := p.expect(TokenComma); err != nil {
+			return nil, fmt.Errorf("STRING_AGG requires a separator argument: %w", err)
+		}
+		if p.current().Type != TokenString {
+			return nil, fmt.Errorf("STRING_AGG separator must be a string literal, got %s", p.current().Value)
+		}
+		aggExpr.Separator = p.current().Value
+		p.advance()
+	}
+
+	// Ordered aggregates accept ORDER BY before the closing parenthesis
+	if p.current().Type == TokenOrder {
+		if funcName != "ARRAY_AGG" && funcName != "STRING_AGG" {
+			return nil, fmt.Errorf("ORDER BY is only supported in ARRAY_AGG and STRING_AGG, not %s", funcName)
+		}
+		orderBy, err := p.parseOrderBy()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ORDER BY in %s: %w", funcName, err)
+		}
+		aggExpr.OrderBy = orderBy
+	}
+
 	if err := p.expect(TokenRightParen); err != nil {
 		return nil, fmt.Errorf("expected ')' after aggregate function argument: %w", err)
 	}
 
-	return &AggregateExpr{
-		Function: funcName,
-		Arg:      arg,
-		Distinct: false,
-	}, nil
+	// An aggregate followed by OVER is computed per row over its window frame
+	if p.current().Type == TokenOver {
+		p.advance()
+		windowSpec, err := p.parseWindowSpec()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse window specification: %w", err)
+		}
+
+		var args []SelectExpression
+		if arg != nil {
+			args = []SelectExpression{arg}
+		}
+		return &WindowExpr{
+			Function:  funcName,
+			Args:      args,
+			Window:    windowSpec,
+			Aggregate: aggExpr,
+		}, nil
+	}
+
+	return aggExpr, nil
 }
 
 // parseWindowFunction parses a window function call
@@ -0,0 +1,805 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseComparison parses comparison expressions (including IN, LIKE, BETWEEN, IS NULL)
+func (p *Parser) parseComparison() (Expression, error) {
+	// Check for EXISTS (doesn't start with column)
+	if p.current().Type == TokenExists || (p.current().Type == TokenNot && p.peek().Type == TokenExists) {
+		return p.parseExistsExpr()
+	}
+
+	// Check for scalar subquery (comparison with subquery)
+	// This could be a subquery, but it's not common syntax, so we'll skip for now
+	// Most scalar subqueries appear on the right side of comparison
+
+	// Row constructor: (col1, col2) IN ((val1, val2), ...)
+	if p.current().Type == TokenLeftParen && p.isRowConstructor() {
+		return p.parseTupleInExpr()
+	}
+
+	// A parenthesized condition, (a > 1 OR b < 2), or an expression that
+	// starts with a parenthesis, (salary / age) > 1000
+	if p.current().Type == TokenLeftParen {
+		return p.parseParenthesized()
+	}
+
+	// An expression compared with a value: salary / 12 > 1000,
+	// LENGTH(name) > 5 or CASE WHEN ... END = 'x'
+	if p.startsExpressionComparison() {
+		return p.parseExpressionComparison()
+	}
+
+	// Parse column name, or an aggregate in HAVING, which is compared through
+	// the column it is computed into
+	var column string
+	if p.current().Type == TokenIdent && p.peek().Type == TokenLeftParen && isAggregateFunction(p.current().Value) {
+		var err error
+		column, err = p.parseHavingAggregate()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if p.current().Type != TokenIdent {
+			return nil, fmt.Errorf("expected column name, got %v", p.current().Type)
+		}
+		column = p.current().Value
+
+		// Validate column name length
+		if err := ValidateColumnName(column); err != nil {
+			return nil, err
+		}
+
+		p.advance()
+	}
+
+	// Check for special operators first
+	switch p.current().Type {
+	case TokenEqual, TokenNotEqual, TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual:
+		// Standard comparison, parsed below
+	case TokenIn:
+		return p.parseInExpr(column)
+	case TokenNot:
+		// Could be "NOT IN", "NOT LIKE", "NOT ILIKE", "NOT BETWEEN"
+		p.advance()
+		switch p.current().Type {
+		case TokenIn:
+			expr, err := p.parseInExpr(column)
+			if err != nil {
+				return nil, err
+			}
+			// Handle both InExpr and InSubqueryExpr
+			switch e := expr.(type) {
+			case *InExpr:
+				e.Negate = true
+			case *InSubqueryExpr:
+				e.Negate = true
+			}
+			return expr, nil
+		case TokenLike, TokenILike:
+			expr, err := p.parseLikeExpr(column)
+			if err != nil {
+				return nil, err
+			}
+			if likeExpr, ok := expr.(*LikeExpr); ok {
+				likeExpr.Negate = true
+			}
+			return expr, nil
+		case TokenBetween:
+			expr, err := p.parseBetweenExpr(column)
+			if err != nil {
+				return nil, err
+			}
+			if betweenExpr, ok := expr.(*BetweenExpr); ok {
+				betweenExpr.Negate = true
+			}
+			return expr, nil
+		default:
+			return nil, fmt.Errorf("expected IN, LIKE, ILIKE, or BETWEEN after NOT, got %v", p.current().Type)
+		}
+	case TokenLike, TokenILike:
+		return p.parseLikeExpr(column)
+	case TokenBetween:
+		return p.parseBetweenExpr(column)
+	case TokenIs:
+		return p.parseIsNullExpr(column)
+	case TokenNullSafeEqual:
+		return p.parseDistinctFrom(&ColumnRef{Column: column}, false)
+	default:
+		// A bare boolean column, as in WHERE active or CASE WHEN active THEN ...
+		return &ComparisonExpr{
+			Column:   column,
+			Operator: TokenEqual,
+			Value:    true,
+		}, nil
+	}
+
+	// Parse standard comparison operator
+	operator := p.current().Type
+	p.advance()
+
+	// Comparison with the values of a subquery: column > ALL (SELECT ...)
+	if p.startsQuantifiedSubquery() {
+		return p.parseQuantifiedSubquery(column, operator)
+	}
+
+	// Parse the right side: a value, a column or an expression such as
+	// salary > 1000 * 12, name = UPPER(name) or salary > (SELECT ...)
+	right, err := p.parseSelectExpression()
+	if err != nil {
+		return nil, err
+	}
+	switch r := right.(type) {
+	case *LiteralExpr:
+		return &ComparisonExpr{Column: column, Operator: operator, Value: r.Value}, nil
+	case *ColumnRef:
+		// Column-to-column comparison (for JOINs)
+		return &ColumnComparisonExpr{LeftColumn: column, Operator: operator, RightColumn: r.Column}, nil
+	}
+	return &ExpressionComparisonExpr{
+		Left:     &ColumnRef{Column: column},
+		Operator: operator,
+		Right:    right,
+	}, nil
+}
+
+// isRowConstructor reports whether the parenthesis at the current token opens
+// a row constructor, (col1, col2) or (col) followed by [NOT] IN, rather than a
+// parenthesized expression
+func (p *Parser) isRowConstructor() bool {
+	if p.peek().Type != TokenIdent || p.peek().Value == "*" {
+		return false
+	}
+	switch p.peekAt(2).Type {
+	case TokenComma:
+		return true
+	case TokenRightParen:
+		next := p.peekAt(3).Type
+		return next == TokenIn || next == TokenNot
+	default:
+		return false
+	}
+}
+
+// parseParenthesized parses a condition starting with a parenthesis. The
+// parentheses are first tried as a condition group; if that fails, they are
+// parsed as the start of an expression comparison. When both fail, the error
+// of the attempt that got further is returned.
+func (p *Parser) parseParenthesized() (Expression, error) {
+	start := p.pos
+	var aggregates int
+	if p.havingAggregates != nil {
+		aggregates = len(*p.havingAggregates)
+	}
+
+	group, groupErr := p.parseConditionGroup()
+	if groupErr == nil {
+		return group, nil
+	}
+	groupEnd := p.pos
+
+	// Forget aggregates recorded by the failed attempt
+	p.pos = start
+	if p.havingAggregates != nil {
+		*p.havingAggregates = (*p.havingAggregates)[:aggregates]
+	}
+
+	expr, err := p.parseExpressionComparison()
+	if err != nil && groupEnd > p.pos {
+		return nil, groupErr
+	}
+	return expr, err
+}
+
+// parseConditionGroup parses a condition in parentheses. It fails if the
+// closing parenthesis is followed by an operator, as the parentheses are
+// then part of an expression.
+func (p *Parser) parseConditionGroup() (Expression, error) {
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after condition: %w", err)
+	}
+
+	switch next := p.current(); next.Type {
+	case TokenEqual, TokenNotEqual, TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual, TokenNullSafeEqual, TokenIs,
+		TokenPlus, TokenMinus, TokenSlash, TokenPercent, TokenConcat, TokenLeftBracket:
+		return nil, fmt.Errorf("unexpected %v after condition", next.Type)
+	case TokenIdent:
+		if next.Value == "*" {
+			return nil, fmt.Errorf("unexpected * after condition")
+		}
+	}
+	return expr, nil
+}
+
+// startsExpressionComparison reports whether the condition at the current
+// token compares an expression rather than a plain column
+func (p *Parser) startsExpressionComparison() bool {
+	switch p.current().Type {
+	case TokenCase, TokenLeftParen, TokenMinus:
+		return true
+	case TokenIdent:
+		switch next := p.peek(); next.Type {
+		case TokenLeftParen:
+			// Aggregates in HAVING are compared through their result column
+			return !isAggregateFunction(p.current().Value)
+		case TokenLeftBracket, TokenPlus, TokenMinus, TokenSlash, TokenPercent, TokenConcat:
+			return true
+		case TokenIdent:
+			return next.Value == "*"
+		}
+	}
+	return false
+}
+
+// parseExpressionComparison parses an expression compared with a value,
+// column or another expression
+func (p *Parser) parseExpressionComparison() (Expression, error) {
+	left, err := p.parseSelectExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	operator := p.current().Type
+	switch operator {
+	case TokenEqual, TokenNotEqual, TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual:
+		p.advance()
+	case TokenNullSafeEqual:
+		return p.parseDistinctFrom(left, false)
+	case TokenIs:
+		// Expressions only support IS [NOT] DISTINCT FROM
+		p.advance()
+		negate := p.current().Type == TokenNot
+		if negate {
+			p.advance()
+		}
+		if p.current().Type != TokenDistinct {
+			return nil, fmt.Errorf("expected DISTINCT FROM after IS in an expression comparison, got %v", p.current().Type)
+		}
+		return p.parseDistinctFrom(left, negate)
+	default:
+		// A function call on its own is a boolean condition, as in
+		// WHERE REGEXP_MATCH(email, '@example\.com$')
+		if _, ok := left.(*FunctionCall); ok {
+			return &ExpressionComparisonExpr{Left: left, Operator: TokenEqual, Right: &LiteralExpr{Value: true}}, nil
+		}
+		return nil, fmt.Errorf("expected comparison operator after expression, got %v", operator)
+	}
+
+	right, err := p.parseSelectExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExpressionComparisonExpr{
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}, nil
+}
+
+// parseHavingAggregate parses an aggregate call in a HAVING condition and
+// records it in the clause's aggregates, returning the column it is computed
+// into. The same aggregate used twice is computed once.
+func (p *Parser) parseHavingAggregate() (string, error) {
+	name := strings.ToUpper(p.current().Value)
+	if p.havingAggregates == nil {
+		return "", fmt.Errorf("aggregate function %s is not allowed here, only in the SELECT list and HAVING", name)
+	}
+
+	expr, err := p.parseAggregateFunction()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := expr.(*WindowExpr); ok {
+		return "", fmt.Errorf("window function %s cannot be used in HAVING", name)
+	}
+	aggExpr, ok := expr.(*AggregateExpr)
+	if !ok {
+		return "", fmt.Errorf("%s with multiple arguments is not an aggregate and cannot be used in HAVING", name)
+	}
+
+	aggregates := p.havingAggregates
+	for _, item := range *aggregates {
+		if reflect.DeepEqual(item.Expr, aggExpr) {
+			return item.Alias, nil
+		}
+	}
+
+	// The column is named after the aggregate so errors read naturally
+	column := aggregateText(aggExpr)
+	for _, item := range *aggregates {
+		if item.Alias == column {
+			column = fmt.Sprintf("%s #%d", column, len(*aggregates)+1)
+			break
+		}
+	}
+
+	*aggregates = append(*aggregates, SelectItem{Expr: aggExpr, Alias: column})
+	return column, nil
+}
+
+// aggregateText renders an aggregate call for use as a column name, such as
+// SUM(amount) or COUNT(*). Arguments other than columns are elided.
+func aggregateText(aggExpr *AggregateExpr) string {
+	arg := "..."
+	switch a := aggExpr.Arg.(type) {
+	case nil:
+		arg = "*"
+	case *ColumnRef:
+		arg = a.Column
+	}
+	if aggExpr.Distinct {
+		arg = "DISTINCT " + arg
+	}
+	return fmt.Sprintf("%s(%s)", aggExpr.Function, arg)
+}
+
+// parseInExpr parses an IN expression: column IN (val1, val2, ...) or column IN (subquery)
+func (p *Parser) parseInExpr(column string) (Expression, error) {
+	// Expect IN keyword
+	if err := p.expect(TokenIn); err != nil {
+		return nil, err
+	}
+
+	// Expect opening parenthesis
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, fmt.Errorf("expected '(' after IN: %w", err)
+	}
+
+	// Check if it's a subquery (starts with SELECT or WITH)
+	if p.current().Type == TokenSelect || p.current().Type == TokenWith {
+		// Parse subquery
+		subquery, err := p.parseQuery()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IN subquery: %w", err)
+		}
+
+		if err := validateSingleColumnSubquery(subquery, "IN"); err != nil {
+			return nil, err
+		}
+
+		// Expect closing parenthesis
+		if err := p.expect(TokenRightParen); err != nil {
+			return nil, fmt.Errorf("expected ')' after IN subquery: %w", err)
+		}
+
+		return &InSubqueryExpr{
+			Column:   column,
+			Subquery: subquery,
+			Negate:   false,
+		}, nil
+	}
+
+	// Parse value list
+	values, err := p.parseInValues()
+	if err != nil {
+		return nil, err
+	}
+
+	return &InExpr{
+		Column: column,
+		Values: values,
+		Negate: false,
+	}, nil
+}
+
+// validateSingleColumnSubquery checks that a subquery used with the given
+// operator (IN, ANY, ALL or scalar) selects exactly one column
+func validateSingleColumnSubquery(subquery *Query, operator string) error {
+	if len(subquery.SelectList) == 0 {
+		return fmt.Errorf("%s subquery must select at least one column", operator)
+	}
+	// Check for SELECT * which would select multiple columns
+	if len(subquery.SelectList) == 1 {
+		if colRef, ok := subquery.SelectList[0].Expr.(*ColumnRef); ok && colRef.Column == "*" {
+			return fmt.Errorf("%s subquery cannot use SELECT *, must select exactly one column", operator)
+		}
+	} else if len(subquery.SelectList) > 1 {
+		return fmt.Errorf("%s subquery must select exactly one column, got %d columns", operator, len(subquery.SelectList))
+	}
+	return nil
+}
+
+// startsQuantifiedSubquery reports whether the current token is ANY, SOME or
+// ALL followed by a parenthesis. ANY and SOME aren't keywords, so columns can
+// still have those names.
+func (p *Parser) startsQuantifiedSubquery() bool {
+	if p.peek().Type != TokenLeftParen {
+		return false
+	}
+	switch tok := p.current(); tok.Type {
+	case TokenAll:
+		return true
+	case TokenIdent:
+		name := strings.ToUpper(tok.Value)
+		return name == "ANY" || name == "SOME"
+	}
+	return false
+}
+
+// parseQuantifiedSubquery parses the ANY, SOME or ALL (subquery) after a
+// comparison operator
+func (p *Parser) parseQuantifiedSubquery(column string, operator TokenType) (Expression, error) {
+	all := p.current().Type == TokenAll
+	quantifier := "ANY"
+	if all {
+		quantifier = "ALL"
+	}
+	p.advance()
+
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, err
+	}
+	if p.current().Type != TokenSelect && p.current().Type != TokenWith {
+		return nil, fmt.Errorf("expected subquery after %s (, got %v", quantifier, p.current().Type)
+	}
+	subquery, err := p.parseQuery()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s subquery: %w", quantifier, err)
+	}
+	if err := validateSingleColumnSubquery(subquery, quantifier); err != nil {
+		return nil, err
+	}
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after %s subquery: %w", quantifier, err)
+	}
+
+	return &QuantifiedSubqueryExpr{
+		Column:   column,
+		Operator: operator,
+		All:      all,
+		Subquery: subquery,
+	}, nil
+}
+
+// parseInValues parses a comma-separated list of literals up to and including
+// the closing parenthesis
+func (p *Parser) parseInValues() ([]interface{}, error) {
+	var values []interface{}
+	for {
+		var value interface{}
+		switch p.current().Type {
+		case TokenString:
+			value = p.current().Value
+			p.advance()
+		case TokenNumber:
+			numStr := p.current().Value
+			if intVal, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+				value = intVal
+			} else if floatVal, err := strconv.ParseFloat(numStr, 64); err == nil {
+				value = floatVal
+			} else {
+				return nil, fmt.Errorf("invalid number in IN list: %s", numStr)
+			}
+			p.advance()
+		case TokenBool:
+			value = strings.ToLower(p.current().Value) == "true"
+			p.advance()
+		case TokenNull:
+			p.advance()
+		default:
+			return nil, fmt.Errorf("expected value in IN list, got %v", p.current().Type)
+		}
+		values = append(values, value)
+
+		// Check for comma (more values) or closing parenthesis
+		if p.current().Type == TokenComma {
+			p.advance()
+			continue
+		}
+		if p.current().Type == TokenRightParen {
+			break
+		}
+		return nil, fmt.Errorf("expected ',' or ')' in IN list, got %v", p.current().Type)
+	}
+
+	// Expect closing parenthesis
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after IN list: %w", err)
+	}
+
+	return values, nil
+}
+
+// parseTupleInExpr parses a row constructor IN expression:
+// (col1, col2, ...) [NOT] IN ((val1, val2, ...), ...)
+func (p *Parser) parseTupleInExpr() (Expression, error) {
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for {
+		if p.current().Type != TokenIdent {
+			return nil, fmt.Errorf("expected column name in row constructor, got %v", p.current().Type)
+		}
+		column := p.current().Value
+		if err := ValidateColumnName(column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		p.advance()
+
+		if p.current().Type == TokenComma {
+			p.advance()
+			continue
+		}
+		break
+	}
+
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after row constructor columns: %w", err)
+	}
+
+	negate := false
+	if p.current().Type == TokenNot {
+		negate = true
+		p.advance()
+	}
+
+	if err := p.expect(TokenIn); err != nil {
+		return nil, fmt.Errorf("expected IN after row constructor: %w", err)
+	}
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, fmt.Errorf("expected '(' after IN: %w", err)
+	}
+
+	var tuples [][]interface{}
+	for {
+		if err := p.expect(TokenLeftParen); err != nil {
+			return nil, fmt.Errorf("expected '(' to start a tuple in IN list: %w", err)
+		}
+		tuple, err := p.parseInValues()
+		if err != nil {
+			return nil, err
+		}
+		if len(tuple) != len(columns) {
+			return nil, fmt.Errorf("IN tuple has %d values, expected %d to match (%s)", len(tuple), len(columns), strings.Join(columns, ", "))
+		}
+		tuples = append(tuples, tuple)
+
+		if p.current().Type == TokenComma {
+			p.advance()
+			continue
+		}
+		break
+	}
+
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after IN list: %w", err)
+	}
+
+	return &InExpr{
+		Columns: columns,
+		Tuples:  tuples,
+		Negate:  negate,
+	}, nil
+}
+
+// parseLikeExpr parses a LIKE or ILIKE expression: column LIKE 'pattern',
+// optionally followed by ESCAPE 'c'
+func (p *Parser) parseLikeExpr(column string) (Expression, error) {
+	// Expect LIKE or ILIKE keyword
+	keyword := p.current()
+	if keyword.Type != TokenLike && keyword.Type != TokenILike {
+		return nil, fmt.Errorf("expected LIKE or ILIKE, got %v", keyword.Type)
+	}
+	p.advance()
+
+	// Expect string pattern
+	if p.current().Type != TokenString {
+		return nil, fmt.Errorf("expected string pattern after %s, got %v", strings.ToUpper(keyword.Value), p.current().Type)
+	}
+	pattern := p.current().Value
+	p.advance()
+
+	// ESCAPE is not a keyword, so columns can still be named escape
+	var escape rune
+	if p.current().Type == TokenIdent && strings.EqualFold(p.current().Value, "ESCAPE") {
+		p.advance()
+		if p.current().Type != TokenString || utf8.RuneCountInString(p.current().Value) != 1 {
+			return nil, fmt.Errorf("expected a single character string after ESCAPE, got %q", p.current().Value)
+		}
+		escape, _ = utf8.DecodeRuneInString(p.current().Value)
+		p.advance()
+
+		// Escape characters pair up, so one left over at the end has nothing to escape
+		if strings.HasSuffix(strings.ReplaceAll(pattern, string(escape)+string(escape), ""), string(escape)) {
+			return nil, fmt.Errorf("%s pattern %q ends with the escape character %q", strings.ToUpper(keyword.Value), pattern, escape)
+		}
+	}
+
+	return &LikeExpr{
+		Column:          column,
+		Pattern:         pattern,
+		Negate:          false,
+		CaseInsensitive: keyword.Type == TokenILike,
+		Escape:          escape,
+	}, nil
+}
+
+// parseBetweenExpr parses a BETWEEN expression: column BETWEEN lower AND upper
+func (p *Parser) parseBetweenExpr(column string) (Expression, error) {
+	// Expect BETWEEN keyword
+	if err := p.expect(TokenBetween); err != nil {
+		return nil, err
+	}
+
+	// Parse lower bound
+	var lower interface{}
+	switch p.current().Type {
+	case TokenString:
+		lower = p.current().Value
+		p.advance()
+	case TokenNumber:
+		numStr := p.current().Value
+		if intVal, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+			lower = intVal
+		} else if floatVal, err := strconv.ParseFloat(numStr, 64); err == nil {
+			lower = floatVal
+		} else {
+			return nil, fmt.Errorf("invalid lower bound: %s", numStr)
+		}
+		p.advance()
+	default:
+		return nil, fmt.Errorf("expected value for BETWEEN lower bound, got %v", p.current().Type)
+	}
+
+	// Expect AND
+	if err := p.expect(TokenAnd); err != nil {
+		return nil, fmt.Errorf("expected AND in BETWEEN expression: %w", err)
+	}
+
+	// Parse upper bound
+	var upper interface{}
+	switch p.current().Type {
+	case TokenString:
+		upper = p.current().Value
+		p.advance()
+	case TokenNumber:
+		numStr := p.current().Value
+		if intVal, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+			upper = intVal
+		} else if floatVal, err := strconv.ParseFloat(numStr, 64); err == nil {
+			upper = floatVal
+		} else {
+			return nil, fmt.Errorf("invalid upper bound: %s", numStr)
+		}
+		p.advance()
+	default:
+		return nil, fmt.Errorf("expected value for BETWEEN upper bound, got %v", p.current().Type)
+	}
+
+	return &BetweenExpr{
+		Column: column,
+		Lower:  lower,
+		Upper:  upper,
+		Negate: false,
+	}, nil
+}
+
+// parseIsNullExpr parses an IS NULL or IS TRUE/FALSE expression: column IS [NOT] NULL|TRUE|FALSE
+func (p *Parser) parseIsNullExpr(column string) (Expression, error) {
+	// Expect IS keyword
+	if err := p.expect(TokenIs); err != nil {
+		return nil, err
+	}
+
+	// Check for NOT
+	negate := false
+	if p.current().Type == TokenNot {
+		negate = true
+		p.advance()
+	}
+
+	// IS [NOT] DISTINCT FROM
+	if p.current().Type == TokenDistinct {
+		return p.parseDistinctFrom(&ColumnRef{Column: column}, negate)
+	}
+
+	// IS [NOT] TRUE / IS [NOT] FALSE
+	if p.current().Type == TokenBool {
+		value := strings.ToLower(p.current().Value) == "true"
+		p.advance()
+		return &IsBoolExpr{
+			Column: column,
+			Value:  value,
+			Negate: negate,
+		}, nil
+	}
+
+	// Expect NULL
+	if err := p.expect(TokenNull); err != nil {
+		return nil, fmt.Errorf("expected NULL, TRUE or FALSE after IS [NOT]: %w", err)
+	}
+
+	return &IsNullExpr{
+		Column: column,
+		Negate: negate,
+	}, nil
+}
+
+// parseDistinctFrom parses the value compared with left by IS [NOT]
+// DISTINCT FROM or <=>, starting at DISTINCT or <=>. negate is set for
+// IS NOT DISTINCT FROM.
+func (p *Parser) parseDistinctFrom(left SelectExpression, negate bool) (Expression, error) {
+	if p.current().Type == TokenNullSafeEqual {
+		// a <=> b is the same as a IS NOT DISTINCT FROM b
+		negate = true
+		p.advance()
+	} else {
+		if err := p.expect(TokenDistinct); err != nil {
+			return nil, err
+		}
+		if err := p.expect(TokenFrom); err != nil {
+			return nil, fmt.Errorf("expected FROM after IS [NOT] DISTINCT: %w", err)
+		}
+	}
+
+	var right SelectExpression = &LiteralExpr{Value: nil}
+	if p.current().Type == TokenNull {
+		p.advance()
+	} else {
+		var err error
+		right, err = p.parseSelectExpression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &DistinctExpr{
+		Left:   left,
+		Right:  right,
+		Negate: negate,
+	}, nil
+}
+
+// parseExistsExpr parses an EXISTS expression: EXISTS (subquery) or NOT EXISTS (subquery)
+func (p *Parser) parseExistsExpr() (Expression, error) {
+	negate := false
+
+	// Check for NOT EXISTS
+	if p.current().Type == TokenNot {
+		negate = true
+		p.advance()
+	}
+
+	// Expect EXISTS keyword
+	if err := p.expect(TokenExists); err != nil {
+		return nil, err
+	}
+
+	// Expect opening parenthesis
+	if err := p.expect(TokenLeftParen); err != nil {
+		return nil, fmt.Errorf("expected '(' after EXISTS: %w", err)
+	}
+
+	// Parse subquery
+	subquery, err := p.parseQuery()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EXISTS subquery: %w", err)
+	}
+
+	// Expect closing parenthesis
+	if err := p.expect(TokenRightParen); err != nil {
+		return nil, fmt.Errorf("expected ')' after EXISTS subquery: %w", err)
+	}
+
+	return &ExistsExpr{
+		Subquery: subquery,
+		Negate:   negate,
+	}, nil
+}
@@ -1,6 +1,8 @@
 package query
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -90,6 +92,160 @@ func TestParser_Errors(t *testing.T) {
 	}
 }
 
+func TestParser_QuotedIdentifiers(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantColumns []string
+		wantTable   string
+	}{
+		{
+			name:        "spaces and keywords",
+			query:       `SELECT "first name", "order" FROM x`,
+			wantColumns: []string{"first name", "order"},
+			wantTable:   "x",
+		},
+		{
+			name:        "doubled quotes",
+			query:       `SELECT "say ""hi""" FROM x`,
+			wantColumns: []string{`say "hi"`},
+			wantTable:   "x",
+		},
+		{
+			name:        "quoted table with a space",
+			query:       `SELECT "select" FROM "my data.parquet"`,
+			wantColumns: []string{"select"},
+			wantTable:   "my data.parquet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if q.TableName != tt.wantTable {
+				t.Errorf("Parse() table = %q, want %q", q.TableName, tt.wantTable)
+			}
+			var columns []string
+			for _, item := range q.SelectList {
+				ref, ok := item.Expr.(*ColumnRef)
+				if !ok {
+					t.Fatalf("select item %T is not a column reference", item.Expr)
+				}
+				columns = append(columns, ref.Column)
+			}
+			if !reflect.DeepEqual(columns, tt.wantColumns) {
+				t.Errorf("Parse() columns = %q, want %q", columns, tt.wantColumns)
+			}
+		})
+	}
+}
+
+func TestParser_Comments(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		plain     string
+		wantError string
+	}{
+		{
+			name: "line comments",
+			query: `-- Adults by city
+select name, city -- only what the report shows
+from data.parquet
+where age >= 18 -- inclusive`,
+			plain: "select name, city from data.parquet where age >= 18",
+		},
+		{
+			name:  "block comments",
+			query: "select /* every column */ * from data.parquet /* filter:\n adults only */ where age >= 18",
+			plain: "select * from data.parquet where age >= 18",
+		},
+		{
+			name:  "comment markers in a string",
+			query: "select * from data.parquet where note = '-- not /* a */ comment'",
+			plain: "select * from data.parquet where note='-- not /* a */ comment'",
+		},
+		{
+			name:  "division next to a comment",
+			query: "select total / count /* per item */ as avg from data.parquet",
+			plain: "select total / count as avg from data.parquet",
+		},
+		{
+			name:  "comment before a trailing semicolon",
+			query: "select * from data.parquet /* done */;",
+			plain: "select * from data.parquet",
+		},
+		{
+			name:      "unterminated block comment",
+			query:     "select * from data.parquet /* where age > 30",
+			wantError: "unterminated /* comment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.query)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			want, err := Parse(tt.plain)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.plain, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Parse() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParser_LexErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "unterminated string",
+			query:   "select * from data.parquet where name = 'O''Brien",
+			wantErr: "unterminated string literal in query",
+		},
+		{
+			name:    "unterminated string in the middle",
+			query:   "select * from data.parquet where name = 'alice and age > 30",
+			wantErr: "unterminated string literal in query",
+		},
+		{
+			name:    "unterminated quoted identifier",
+			query:   `select "first name from data.parquet`,
+			wantErr: "unterminated quoted identifier in query",
+		},
+		{
+			name:    "invalid character",
+			query:   "select * from data.parquet where age > 30 ?",
+			wantErr: "invalid character in query: ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestParser_OrderBy(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -141,6 +297,56 @@ func TestParser_OrderBy(t *testing.T) {
 			wantErr:   false,
 			wantCount: 1,
 		},
+		{
+			name:      "position of a column",
+			query:     "select name, age from data.parquet order by 2 desc",
+			wantCount: 1,
+			wantFirst: "age",
+			wantDesc:  true,
+		},
+		{
+			name:      "position of an alias",
+			query:     "select age * 2 as dbl from data.parquet order by 1",
+			wantCount: 1,
+			wantFirst: "dbl",
+		},
+		{
+			name:      "expression",
+			query:     "select name from data.parquet order by age * 2 desc",
+			wantCount: 1,
+			wantFirst: "ORDER BY #1",
+			wantDesc:  true,
+		},
+		{
+			name:    "position zero",
+			query:   "select name from data.parquet order by 0",
+			wantErr: true,
+		},
+		{
+			name:    "position past the SELECT list",
+			query:   "select name from data.parquet order by 2",
+			wantErr: true,
+		},
+		{
+			name:    "position of star",
+			query:   "select * from data.parquet order by 1",
+			wantErr: true,
+		},
+		{
+			name:    "string constant",
+			query:   "select name from data.parquet order by 'name'",
+			wantErr: true,
+		},
+		{
+			name:    "expression with DISTINCT",
+			query:   "select distinct name from data.parquet order by age * 2",
+			wantErr: true,
+		},
+		{
+			name:    "expression with UNION",
+			query:   "select name from a.parquet union select name from b.parquet order by length(name)",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -167,6 +373,27 @@ func TestParser_OrderBy(t *testing.T) {
 	}
 }
 
+func TestParser_OrderKeys(t *testing.T) {
+	q, err := Parse("select name, age + 1 from data.parquet order by age * 2, 2, age * 2 desc")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// The same expression is computed once, and the unnamed item is recomputed
+	if len(q.OrderKeys) != 2 {
+		t.Fatalf("OrderKeys = %v, want 2 keys", q.OrderKeys)
+	}
+	want := []string{"ORDER BY #1", "ORDER BY #2", "ORDER BY #1"}
+	for i, item := range q.OrderBy {
+		if item.Column != want[i] {
+			t.Errorf("OrderBy[%d].Column = %q, want %q", i, item.Column, want[i])
+		}
+	}
+	if len(q.SelectList) != 2 {
+		t.Errorf("SelectList has %d items, want the 2 selected", len(q.SelectList))
+	}
+}
+
 func TestParser_Limit(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -225,6 +452,80 @@ func TestParser_Limit(t *testing.T) {
 	}
 }
 
+func TestParser_TableSample(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantErr    bool
+		wantSample *TableSample
+		wantAlias  string
+	}{
+		{
+			name:       "percent sample",
+			query:      "select * from big.parquet TABLESAMPLE (5 PERCENT)",
+			wantSample: &TableSample{Percent: 5},
+		},
+		{
+			name:       "fractional percent lowercase",
+			query:      "select * from big.parquet tablesample (0.5 percent)",
+			wantSample: &TableSample{Percent: 0.5},
+		},
+		{
+			name:       "rows sample",
+			query:      "select * from big.parquet TABLESAMPLE (100 ROWS) where age > 30",
+			wantSample: &TableSample{Rows: 100, ByRows: true},
+		},
+		{
+			name:       "sample after alias",
+			query:      "select b.id from big.parquet b TABLESAMPLE (10 PERCENT)",
+			wantSample: &TableSample{Percent: 10},
+			wantAlias:  "b",
+		},
+		{
+			name:    "percent above 100",
+			query:   "select * from big.parquet TABLESAMPLE (150 PERCENT)",
+			wantErr: true,
+		},
+		{
+			name:    "fractional rows",
+			query:   "select * from big.parquet TABLESAMPLE (1.5 ROWS)",
+			wantErr: true,
+		},
+		{
+			name:    "missing unit",
+			query:   "select * from big.parquet TABLESAMPLE (5)",
+			wantErr: true,
+		},
+		{
+			name:    "missing parentheses",
+			query:   "select * from big.parquet TABLESAMPLE 5 PERCENT",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if q.Sample == nil {
+				t.Fatalf("Sample = nil, want %+v", *tt.wantSample)
+			}
+			if *q.Sample != *tt.wantSample {
+				t.Errorf("Sample = %+v, want %+v", *q.Sample, *tt.wantSample)
+			}
+			if q.TableAlias != tt.wantAlias {
+				t.Errorf("TableAlias = %q, want %q", q.TableAlias, tt.wantAlias)
+			}
+		})
+	}
+}
+
 func TestParser_Offset(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -200,3 +200,80 @@ func TestApplySelectListPreservesOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestApplySelectListDuplicateNames(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "alice", "age": 30, "salary": 50000.0},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  map[string]interface{}
+	}{
+		{
+			name:  "duplicate aliases are suffixed",
+			query: "SELECT age AS x, salary AS x FROM data.parquet",
+			want:  map[string]interface{}{"x": 30, "x_1": 50000.0},
+		},
+		{
+			name:  "three duplicates",
+			query: "SELECT age AS x, salary AS x, name AS x FROM data.parquet",
+			want:  map[string]interface{}{"x": 30, "x_1": 50000.0, "x_2": "alice"},
+		},
+		{
+			name:  "same function name twice",
+			query: "SELECT UPPER(name), LOWER(name) AS UPPER FROM data.parquet",
+			want:  map[string]interface{}{"UPPER": "ALICE", "UPPER_1": "alice"},
+		},
+		{
+			name:  "column repeated after star",
+			query: "SELECT *, name FROM data.parquet",
+			want:  map[string]interface{}{"name": "alice", "age": 30, "salary": 50000.0, "name_1": "alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			result, err := ApplySelectList(rows, q.SelectList)
+			if err != nil {
+				t.Fatalf("ApplySelectList() error = %v", err)
+			}
+
+			if len(result[0]) != len(tt.want) {
+				t.Fatalf("got columns %v, want %v", result[0], tt.want)
+			}
+			for col, want := range tt.want {
+				if got, exists := result[0][col]; !exists || got != want {
+					t.Errorf("column %q = %v, want %v", col, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyGroupByDuplicateNames(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"age": int64(30), "salary": 50000.0},
+		{"age": int64(30), "salary": 60000.0},
+	}
+
+	q, err := Parse("SELECT age AS x, MAX(salary) AS x FROM data.parquet GROUP BY age")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	result, err := ApplyGroupByAndAggregate(rows, q.GroupBy, q.SelectList)
+	if err != nil {
+		t.Fatalf("ApplyGroupByAndAggregate() error = %v", err)
+	}
+
+	if result[0]["x"] != int64(30) || result[0]["x_1"] != 60000.0 {
+		t.Errorf("expected x = 30 and x_1 = 60000, got %v", result[0])
+	}
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"github.com/vegasq/parcat/reader"
+)
+
+// PushdownRange returns a page filter for reading the FROM table of q,
+// derived from the range comparisons (=, <, <=, >, >= and BETWEEN against a
+// number or string) on one column among the AND-ed conditions of its WHERE
+// clause. Comparisons on other columns are left to the WHERE filter.
+//
+// Returns nil if there is no such comparison, or if the rows read are not
+// filtered directly by WHERE: with a table alias, JOINs or TABLESAMPLE.
+func PushdownRange(q *Query) *reader.RangeFilter {
+	if q == nil || q.Filter == nil || q.TableAlias != "" || len(q.Joins) > 0 || q.Sample != nil {
+		return nil
+	}
+
+	var filter *reader.RangeFilter
+	for _, expr := range conjuncts(q.Filter) {
+		column, lower, upper, ok := rangeBounds(expr)
+		if !ok {
+			continue
+		}
+		if filter == nil {
+			filter = &reader.RangeFilter{Column: column}
+		} else if filter.Column != column {
+			continue
+		}
+
+		// Keep the tightest bounds
+		if lower != nil && (filter.Min == nil || compareValues(lower, filter.Min) > 0) {
+			filter.Min = lower
+		}
+		if upper != nil && (filter.Max == nil || compareValues(upper, filter.Max) < 0) {
+			filter.Max = upper
+		}
+	}
+	return filter
+}
+
+// PushdownLimit returns how many rows of q's FROM table are enough to
+// answer its LIMIT, counting rows skipped by OFFSET, or 0 if the whole table
+// must be read: when q has no LIMIT or StreamsRows reports false.
+func PushdownLimit(q *Query) int64 {
+	if q == nil || q.Limit == nil || !StreamsRows(q) {
+		return 0
+	}
+
+	rows := *q.Limit
+	if q.Offset != nil {
+		rows += *q.Offset
+	}
+	return rows
+}
+
+// StreamsRows reports whether every row read from q's FROM table produces
+// exactly one result row, in the order read, so reading may stop once enough
+// rows are produced. That is a plain projection of a single table: no
+// filtering, joins, UNION, grouping, aggregation, window functions,
+// DISTINCT, ORDER BY or percentage sampling, and no CTEs or FROM subquery.
+func StreamsRows(q *Query) bool {
+	if len(q.CTEs) > 0 || len(q.SetOperations) > 0 || q.Subquery != nil || len(q.Joins) > 0 || q.Filter != nil ||
+		len(q.GroupBy) > 0 || q.Having != nil || q.Qualify != nil || len(q.OrderBy) > 0 || q.Distinct {
+		return false
+	}
+	if q.Sample != nil && !q.Sample.ByRows {
+		return false
+	}
+	return !HasAggregateFunction(q.SelectList) && !HasWindowFunction(q.SelectList) && !HasSubqueryInSELECT(q.SelectList)
+}
+
+// conjuncts splits an expression into the conditions AND-ed together at its top level
+func conjuncts(expr Expression) []Expression {
+	if binary, ok := expr.(*BinaryExpr); ok && binary.Operator == TokenAnd {
+		return append(conjuncts(binary.Left), conjuncts(binary.Right)...)
+	}
+	return []Expression{expr}
+}
+
+// rangeBounds returns the column and inclusive bounds a condition restricts
+// it to, with nil for an open bound. ok is false if the condition is not a
+// range comparison against a number or string.
+func rangeBounds(expr Expression) (column string, lower, upper interface{}, ok bool) {
+	switch e := expr.(type) {
+	case *ComparisonExpr:
+		if !isRangeBound(e.Value) {
+			return "", nil, nil, false
+		}
+		switch e.Operator {
+		case TokenEqual:
+			return e.Column, e.Value, e.Value, true
+		case TokenGreater, TokenGreaterEqual:
+			return e.Column, e.Value, nil, true
+		case TokenLess, TokenLessEqual:
+			return e.Column, nil, e.Value, true
+		}
+	case *BetweenExpr:
+		if !e.Negate && isRangeBound(e.Lower) && isRangeBound(e.Upper) {
+			return e.Column, e.Lower, e.Upper, true
+		}
+	}
+	return "", nil, nil, false
+}
+
+// isRangeBound reports whether a literal can bound a page filter
+func isRangeBound(value interface{}) bool {
+	switch value.(type) {
+	case int64, float64, string:
+		return true
+	}
+	return false
+}
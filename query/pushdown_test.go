@@ -0,0 +1,213 @@
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/vegasq/parcat/reader"
+)
+
+func TestPushdownRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  *reader.RangeFilter
+	}{
+		{
+			name:  "greater than",
+			query: "SELECT * FROM data.parquet WHERE age > 30",
+			want:  &reader.RangeFilter{Column: "age", Min: int64(30)},
+		},
+		{
+			name:  "equality on a string",
+			query: "SELECT * FROM data.parquet WHERE name = 'bob'",
+			want:  &reader.RangeFilter{Column: "name", Min: "bob", Max: "bob"},
+		},
+		{
+			name:  "between",
+			query: "SELECT * FROM data.parquet WHERE score BETWEEN 1.5 AND 9",
+			want:  &reader.RangeFilter{Column: "score", Min: 1.5, Max: int64(9)},
+		},
+		{
+			name:  "and-ed bounds are tightened",
+			query: "SELECT * FROM data.parquet WHERE age >= 20 AND name = 'x' AND age < 40 AND age > 25 AND age <= 50",
+			want:  &reader.RangeFilter{Column: "age", Min: int64(25), Max: int64(40)},
+		},
+		{
+			name:  "or is not pushed down",
+			query: "SELECT * FROM data.parquet WHERE age > 30 OR age < 10",
+		},
+		{
+			name:  "not between is not pushed down",
+			query: "SELECT * FROM data.parquet WHERE age NOT BETWEEN 10 AND 20",
+		},
+		{
+			name:  "not equal is not pushed down",
+			query: "SELECT * FROM data.parquet WHERE age != 30",
+		},
+		{
+			name:  "boolean is not pushed down",
+			query: "SELECT * FROM data.parquet WHERE active = true",
+		},
+		{
+			name:  "table alias",
+			query: "SELECT * FROM data.parquet d WHERE d.age > 30",
+		},
+		{
+			name:  "tablesample",
+			query: "SELECT * FROM data.parquet TABLESAMPLE (10 PERCENT) WHERE age > 30",
+		},
+		{
+			name:  "no where clause",
+			query: "SELECT * FROM data.parquet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := PushdownRange(q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PushdownRange() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParquetPushdownRange checks that page skipping doesn't change query results
+func TestParquetPushdownRange(t *testing.T) {
+	testData := make([]BasicDataRow, 5000)
+	for i := range testData {
+		testData[i] = BasicDataRow{ID: int64(i), Name: fmt.Sprintf("user%04d", i), Age: int64(20 + i/100)}
+	}
+	testFile := createBasicParquetFile(t, testData)
+
+	tests := []struct {
+		where   string
+		wantIDs []int64
+	}{
+		{where: "id BETWEEN 2500 AND 2502", wantIDs: []int64{2500, 2501, 2502}},
+		{where: "id > 4997", wantIDs: []int64{4998, 4999}},
+		{where: "name = 'user0042'", wantIDs: []int64{42}},
+		{where: "age = 20 AND id >= 98", wantIDs: []int64{98, 99}},
+		{where: "id < 0", wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf("SELECT id FROM '%s' WHERE %s", testFile, tt.where))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			results, err := NewExecutionContext(nil).executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestPushdownLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int64
+	}{
+		{"limit", "SELECT * FROM data.parquet LIMIT 5", 5},
+		{"limit and offset", "SELECT id, UPPER(name) AS n FROM data.parquet LIMIT 5 OFFSET 10", 15},
+		{"rows sample", "SELECT * FROM data.parquet TABLESAMPLE (100 ROWS) LIMIT 5", 5},
+		{"no limit", "SELECT * FROM data.parquet", 0},
+		{"where", "SELECT * FROM data.parquet WHERE age > 30 LIMIT 5", 0},
+		{"order by", "SELECT * FROM data.parquet ORDER BY age LIMIT 5", 0},
+		{"group by", "SELECT name, COUNT(*) FROM data.parquet GROUP BY name LIMIT 5", 0},
+		{"aggregate", "SELECT MAX(age) FROM data.parquet LIMIT 5", 0},
+		{"distinct", "SELECT DISTINCT name FROM data.parquet LIMIT 5", 0},
+		{"window", "SELECT ROW_NUMBER() OVER (ORDER BY age) AS rn FROM data.parquet LIMIT 5", 0},
+		{"join", "SELECT * FROM a.parquet a JOIN b.parquet b ON a.id = b.id LIMIT 5", 0},
+		{"percent sample", "SELECT * FROM data.parquet TABLESAMPLE (10 PERCENT) LIMIT 5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := PushdownLimit(q); got != tt.want {
+				t.Errorf("PushdownLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParquetPushdownLimit checks that a LIMIT stops reading a large file
+// early, counting the rows read with a Transform
+func TestParquetPushdownLimit(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+	rows := make([]Row, 1_000_000)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i)}
+	}
+	testFile := filepath.Join(t.TempDir(), "big.parquet")
+	if err := parquet.WriteFile(testFile, rows); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		query   string
+		wantIDs []int64
+		maxRead int64
+	}{
+		{query: "SELECT * FROM '%s' LIMIT 5", wantIDs: []int64{0, 1, 2, 3, 4}, maxRead: 5},
+		{query: "SELECT id FROM '%s' LIMIT 2 OFFSET 3", wantIDs: []int64{3, 4}, maxRead: 5},
+		// A filter may drop any number of rows, so the whole file is read
+		{query: "SELECT id FROM '%s' WHERE id >= 999998 LIMIT 5", wantIDs: []int64{999998, 999999}, maxRead: 1_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(fmt.Sprintf(tt.query, testFile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var read int64
+			ctx := NewExecutionContext(nil)
+			ctx.ReadOptions.Transform = func(row map[string]interface{}) (map[string]interface{}, error) {
+				read++
+				return row, nil
+			}
+			results, err := ctx.executeSelect(q)
+			if err != nil {
+				t.Fatalf("executeSelect() error = %v", err)
+			}
+
+			var ids []int64
+			for _, row := range results {
+				ids = append(ids, row["id"].(int64))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+			if read > tt.maxRead {
+				t.Errorf("read %d rows, want at most %d", read, tt.maxRead)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitStatements splits a script into statements on top-level semicolons.
+// Semicolons inside string literals, comments or parentheses don't end a
+// statement. Statements are returned trimmed, and empty ones (such as after
+// a trailing semicolon, or holding only comments) are dropped.
+func SplitStatements(script string) []string {
+	statements, rest, pending := splitScript(script)
+	if pending {
+		statements = appendStatement(statements, script[rest:])
+	}
+	return statements
+}
+
+// StatementComplete reports whether script ends with a top-level semicolon,
+// followed by nothing but whitespace and comments, so that every statement
+// in it is terminated. An interactive prompt uses it to keep reading lines
+// until a statement is finished.
+func StatementComplete(script string) bool {
+	_, rest, pending := splitScript(script)
+	return rest > 0 && !pending
+}
+
+// splitScript returns the statements of script that end with a top-level
+// semicolon and the offset of the text after the last one, and reports
+// whether that text holds more than whitespace and comments
+func splitScript(script string) (statements []string, rest int, pending bool) {
+	var quote byte
+	depth := 0
+
+	for i := 0; i < len(script); i++ {
+		ch := script[i]
+		if quote != 0 {
+			// Mirror the lexer: a backslash escapes the next character of a
+			// string, while identifiers double their quotes, which this
+			// loop sees as closing and reopening the identifier
+			if ch == '\\' && quote == '\'' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		// Skip comments, which may contain quotes and semicolons
+		if strings.HasPrefix(script[i:], "--") {
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			continue
+		}
+		if strings.HasPrefix(script[i:], "/*") {
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				// The statement goes on until the comment is closed
+				return statements, rest, true
+			}
+			i += end + 3
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '\'', '"':
+			quote = ch
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ';':
+			if depth == 0 {
+				if pending {
+					statements = appendStatement(statements, script[rest:i])
+				}
+				rest = i + 1
+				pending = false
+				continue
+			}
+		}
+		pending = true
+	}
+
+	return statements, rest, pending
+}
+
+// appendStatement appends a trimmed statement unless it is empty
+func appendStatement(statements []string, statement string) []string {
+	if statement = strings.TrimSpace(statement); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// ParseMulti parses a script of one or more statements separated by
+// semicolons, such as "SELECT ...; SELECT ...;". A trailing semicolon is
+// optional. Errors name the statement they occurred in.
+func ParseMulti(script string) ([]*Query, error) {
+	if err := ValidateQuery(script); err != nil {
+		return nil, err
+	}
+
+	statements := SplitStatements(script)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no statements to parse")
+	}
+
+	queries := make([]*Query, 0, len(statements))
+	for i, statement := range statements {
+		q, err := Parse(statement)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
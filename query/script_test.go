@@ -0,0 +1,137 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"single statement", "SELECT * FROM a.parquet", []string{"SELECT * FROM a.parquet"}},
+		{"trailing semicolon", "SELECT * FROM a.parquet;", []string{"SELECT * FROM a.parquet"}},
+		{"two statements", "SELECT * FROM a.parquet; SELECT * FROM b.parquet;", []string{"SELECT * FROM a.parquet", "SELECT * FROM b.parquet"}},
+		{"empty statements dropped", " ;SELECT 1 FROM a.parquet;; \n;", []string{"SELECT 1 FROM a.parquet"}},
+		{"semicolon in string", "SELECT * FROM a.parquet WHERE s = 'x;y'; SELECT * FROM b.parquet", []string{"SELECT * FROM a.parquet WHERE s = 'x;y'", "SELECT * FROM b.parquet"}},
+		{"escaped quote in string", `SELECT * FROM a.parquet WHERE s = 'say \';\''; SELECT 2`, []string{`SELECT * FROM a.parquet WHERE s = 'say \';\''`, "SELECT 2"}},
+		{"quoted identifier", `SELECT "a;""b" FROM "C:\x;.parquet"; SELECT 2`, []string{`SELECT "a;""b" FROM "C:\x;.parquet"`, "SELECT 2"}},
+		{"semicolon in parentheses", "SELECT * FROM (SELECT * FROM a.parquet;) t; SELECT 2", []string{"SELECT * FROM (SELECT * FROM a.parquet;) t", "SELECT 2"}},
+		{"semicolon in comments", "SELECT 1 -- not; here\nFROM a.parquet /* nor; here */; SELECT 2", []string{"SELECT 1 -- not; here\nFROM a.parquet /* nor; here */", "SELECT 2"}},
+		{"quote in comment", "-- don't split\nSELECT 1; SELECT 2", []string{"-- don't split\nSELECT 1", "SELECT 2"}},
+		{"comment-only statements dropped", "SELECT 1; -- done\n/* end */", []string{"SELECT 1"}},
+		{"empty script", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"terminated", "SELECT * FROM a.parquet;", true},
+		{"trailing whitespace", "SELECT *\nFROM a.parquet ;\n", true},
+		{"unterminated", "SELECT * FROM a.parquet", false},
+		{"text after semicolon", "SELECT 1; SELECT 2", false},
+		{"comment after semicolon", "SELECT 1; -- done", true},
+		{"semicolon in comment", "SELECT 1 -- stop;", false},
+		{"unterminated block comment", "SELECT 1; /* still going;", false},
+		{"semicolon in string", "SELECT * FROM a.parquet WHERE s = 'x;", false},
+		{"semicolon in parentheses", "SELECT * FROM (SELECT * FROM a.parquet;", false},
+		{"empty script", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatementComplete(tt.script); got != tt.want {
+				t.Errorf("StatementComplete(%q) = %v, want %v", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMulti(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		wantTables []string
+		wantErr    string
+	}{
+		{
+			name:       "trailing semicolon",
+			script:     "SELECT * FROM a.parquet; SELECT name FROM b.parquet WHERE name = 'x;y';",
+			wantTables: []string{"a.parquet", "b.parquet"},
+		},
+		{
+			name:       "no trailing semicolon",
+			script:     "SELECT * FROM a.parquet;\nSELECT * FROM b.parquet",
+			wantTables: []string{"a.parquet", "b.parquet"},
+		},
+		{
+			name:       "single statement",
+			script:     "SELECT * FROM a.parquet",
+			wantTables: []string{"a.parquet"},
+		},
+		{
+			name:    "error names statement",
+			script:  "SELECT * FROM a.parquet; SELECT * FROM",
+			wantErr: "statement 2:",
+		},
+		{
+			name:    "semicolon inside parentheses",
+			script:  "SELECT * FROM (SELECT * FROM a.parquet;) t",
+			wantErr: "statement 1:",
+		},
+		{
+			name:    "empty script",
+			script:  ";;",
+			wantErr: "no statements",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries, err := ParseMulti(tt.script)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseMulti() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMulti() error = %v", err)
+			}
+
+			var tables []string
+			for _, q := range queries {
+				tables = append(tables, q.TableName)
+			}
+			if !reflect.DeepEqual(tables, tt.wantTables) {
+				t.Errorf("ParseMulti() tables = %v, want %v", tables, tt.wantTables)
+			}
+		})
+	}
+}
+
+func TestParse_Semicolons(t *testing.T) {
+	if _, err := Parse("SELECT * FROM a.parquet;"); err != nil {
+		t.Errorf("Parse() with trailing semicolon error = %v", err)
+	}
+
+	_, err := Parse("SELECT * FROM a.parquet; SELECT * FROM b.parquet")
+	if err == nil || !strings.Contains(err.Error(), "ParseMulti") {
+		t.Errorf("Parse() of two statements error = %v, want a hint to use ParseMulti", err)
+	}
+}
@@ -129,6 +129,21 @@ func TestParser_SelectList_WithAlias(t *testing.T) {
 			wantAlias: "user_name",
 			wantErr:   false,
 		},
+		{
+			name:      "double-quoted alias with spaces",
+			query:     `select COUNT(*) AS "Total Count" from data.parquet`,
+			wantAlias: "Total Count",
+		},
+		{
+			name:      "single-quoted alias",
+			query:     "select name AS 'User Name' from data.parquet",
+			wantAlias: "User Name",
+		},
+		{
+			name:    "empty quoted alias",
+			query:   `select name AS "" from data.parquet`,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,6 +300,14 @@ func TestParser_SelectList_Errors(t *testing.T) {
 			name:  "empty select list",
 			query: "select from data.parquet",
 		},
+		{
+			name:  "unclosed array subscript",
+			query: "select tags[1 from data.parquet",
+		},
+		{
+			name:  "empty array subscript",
+			query: "select tags[] from data.parquet",
+		},
 	}
 
 	for _, tt := range tests {
@@ -355,3 +378,82 @@ func TestColumnRef_EvaluateSelect(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_SelectList_ArraySubscript(t *testing.T) {
+	q, err := Parse("select tags[0], tags[-1] as last, SPLIT(name, ' ')[1] from data.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(q.SelectList) != 3 {
+		t.Fatalf("expected 3 select items, got %d", len(q.SelectList))
+	}
+
+	first, ok := q.SelectList[0].Expr.(*IndexExpr)
+	if !ok {
+		t.Fatalf("expected IndexExpr, got %T", q.SelectList[0].Expr)
+	}
+	if colRef, ok := first.Expr.(*ColumnRef); !ok || colRef.Column != "tags" {
+		t.Errorf("expected subscript of column tags, got %#v", first.Expr)
+	}
+
+	last, ok := q.SelectList[1].Expr.(*IndexExpr)
+	if !ok {
+		t.Fatalf("expected IndexExpr, got %T", q.SelectList[1].Expr)
+	}
+	if lit, ok := last.Index.(*LiteralExpr); !ok || lit.Value != int64(-1) {
+		t.Errorf("expected index -1, got %#v", last.Index)
+	}
+	if q.SelectList[1].Alias != "last" {
+		t.Errorf("expected alias last, got %q", q.SelectList[1].Alias)
+	}
+
+	split, ok := q.SelectList[2].Expr.(*IndexExpr)
+	if !ok {
+		t.Fatalf("expected IndexExpr, got %T", q.SelectList[2].Expr)
+	}
+	if _, ok := split.Expr.(*FunctionCall); !ok {
+		t.Errorf("expected subscript of function call, got %T", split.Expr)
+	}
+}
+
+func TestIndexExpr_EvaluateSelect(t *testing.T) {
+	row := map[string]interface{}{
+		"tags":  []interface{}{"a", "b", "c"},
+		"words": []string{"x", "y"},
+		"empty": []interface{}{},
+		"none":  nil,
+		"name":  "alice",
+	}
+
+	tests := []struct {
+		name    string
+		column  string
+		index   int64
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "first element", column: "tags", index: 0, want: "a"},
+		{name: "last element", column: "tags", index: 2, want: "c"},
+		{name: "negative index", column: "tags", index: -1, want: "c"},
+		{name: "negative index from start", column: "tags", index: -3, want: "a"},
+		{name: "typed slice", column: "words", index: -2, want: "x"},
+		{name: "past end", column: "tags", index: 3, want: nil},
+		{name: "negative past start", column: "tags", index: -4, want: nil},
+		{name: "empty array", column: "empty", index: 0, want: nil},
+		{name: "null array", column: "none", index: 0, want: nil},
+		{name: "non-array value", column: "name", index: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := &IndexExpr{Expr: &ColumnRef{Column: tt.column}, Index: &LiteralExpr{Value: tt.index}}
+			got, err := expr.EvaluateSelect(row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateSelect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("EvaluateSelect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
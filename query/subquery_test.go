@@ -1,6 +1,7 @@
 package query
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -182,6 +183,35 @@ func TestParseScalarSubquery(t *testing.T) {
 	}
 }
 
+func TestParseScalarSubqueryComparison(t *testing.T) {
+	q, err := Parse("SELECT name FROM users.parquet WHERE salary > (SELECT AVG(salary) FROM users.parquet)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expr, ok := q.Filter.(*ExpressionComparisonExpr)
+	if !ok {
+		t.Fatalf("Expected filter to be ExpressionComparisonExpr, got %T", q.Filter)
+	}
+	if col, ok := expr.Left.(*ColumnRef); !ok || col.Column != "salary" {
+		t.Errorf("Expected left side to be column salary, got %#v", expr.Left)
+	}
+	if _, ok := expr.Right.(*ScalarSubqueryExpr); !ok {
+		t.Errorf("Expected right side to be ScalarSubqueryExpr, got %T", expr.Right)
+	}
+
+	// A parenthesis that doesn't open a subquery is a parenthesized expression
+	q, err = Parse("SELECT name FROM users.parquet WHERE salary > (1000 * 12)")
+	if err != nil {
+		t.Fatalf("Parse() error = %v for a parenthesized expression", err)
+	}
+	if expr, ok := q.Filter.(*ExpressionComparisonExpr); !ok {
+		t.Errorf("Expected filter to be ExpressionComparisonExpr, got %T", q.Filter)
+	} else if _, ok := expr.Right.(*ArithmeticExpr); !ok {
+		t.Errorf("Expected right side to be ArithmeticExpr, got %T", expr.Right)
+	}
+}
+
 func TestINSubqueryStructure(t *testing.T) {
 	query := "SELECT * FROM users.parquet WHERE department IN (SELECT dept FROM large_depts.parquet)"
 	q, err := Parse(query)
@@ -216,6 +246,80 @@ func TestINSubqueryStructure(t *testing.T) {
 	}
 }
 
+func TestParseQuantifiedSubquery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		operator TokenType
+		all      bool
+		wantErr  string
+	}{
+		{
+			name:     "greater than ALL",
+			query:    "SELECT * FROM users.parquet WHERE salary > ALL (SELECT salary FROM interns.parquet)",
+			operator: TokenGreater,
+			all:      true,
+		},
+		{
+			name:     "equal to ANY",
+			query:    "SELECT * FROM users.parquet WHERE dept = ANY (SELECT dept FROM large_depts.parquet)",
+			operator: TokenEqual,
+		},
+		{
+			name:     "lowercase some",
+			query:    "SELECT * FROM users.parquet WHERE age <= some (SELECT age FROM users.parquet)",
+			operator: TokenLessEqual,
+		},
+		{
+			name:    "SELECT * rejected",
+			query:   "SELECT * FROM users.parquet WHERE id > ALL (SELECT * FROM users.parquet)",
+			wantErr: "ALL subquery cannot use SELECT *",
+		},
+		{
+			name:    "two columns rejected",
+			query:   "SELECT * FROM users.parquet WHERE id = ANY (SELECT id, age FROM users.parquet)",
+			wantErr: "ANY subquery must select exactly one column, got 2 columns",
+		},
+		{
+			name:    "value list rejected",
+			query:   "SELECT * FROM users.parquet WHERE id = ANY (1, 2)",
+			wantErr: "expected subquery after ANY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			expr, ok := q.Filter.(*QuantifiedSubqueryExpr)
+			if !ok {
+				t.Fatalf("Expected filter to be QuantifiedSubqueryExpr, got %T", q.Filter)
+			}
+			if expr.Operator != tt.operator || expr.All != tt.all || expr.Subquery == nil {
+				t.Errorf("got operator %v, all %v, subquery %v; want operator %v, all %v", expr.Operator, expr.All, expr.Subquery, tt.operator, tt.all)
+			}
+		})
+	}
+
+	// ANY and SOME are not keywords, so they still work as column names
+	q, err := Parse("SELECT any FROM users.parquet WHERE some = 1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := q.Filter.(*ComparisonExpr); !ok {
+		t.Errorf("Expected filter to be ComparisonExpr, got %T", q.Filter)
+	}
+}
+
 func TestEXISTSSubqueryStructure(t *testing.T) {
 	query := "SELECT * FROM users.parquet WHERE EXISTS (SELECT 1 FROM orders.parquet WHERE status = 'active')"
 	q, err := Parse(query)
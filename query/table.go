@@ -0,0 +1,103 @@
+package query
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/vegasq/parcat/reader"
+)
+
+// tableKey identifies the rows a ReadTable call returns: those of a file or
+// glob pattern read with a page filter and row limit
+type tableKey struct {
+	pattern  string
+	pages    reader.RangeFilter
+	hasPages bool
+	maxRows  int64
+}
+
+// ReadTable reads all rows of a parquet file or glob pattern with the given
+// read options, applying a TABLESAMPLE clause if sample is not nil.
+//
+// PERCENT sampling includes each row independently with the given
+// probability, so the number of rows returned (and any aggregate computed
+// over them) is approximate. ROWS sampling returns the first n rows read.
+func ReadTable(pattern string, opts reader.ReadOptions, sample *TableSample) ([]map[string]interface{}, error) {
+	if sample != nil {
+		if sample.ByRows {
+			if sample.Rows == 0 {
+				return []map[string]interface{}{}, nil
+			}
+			if opts.MaxRows <= 0 || sample.Rows < opts.MaxRows {
+				opts.MaxRows = sample.Rows
+			}
+		} else {
+			if sample.Percent == 0 {
+				return []map[string]interface{}{}, nil
+			}
+			opts.SampleFraction = sample.Percent / 100
+		}
+	}
+
+	return reader.ReadMultipleFilesWithOptions(pattern, opts)
+}
+
+// ReadTable reads a table like the ReadTable function, but once per
+// context: a later read of the same pattern with the same page filter and
+// row limit returns the rows of the first one, so a self-join or a table
+// referenced by several CTEs isn't read again. The returned rows are shared
+// and must not be modified. Sampled reads are not cached, since each one
+// draws a new sample. The other options are expected to be the same for
+// every read, as they are when taken from the context's ReadOptions.
+func (ctx *ExecutionContext) ReadTable(pattern string, opts reader.ReadOptions, sample *TableSample) ([]map[string]interface{}, error) {
+	if ctx.tables == nil || sample != nil || opts.SampleFraction > 0 {
+		return ReadTable(pattern, opts, sample)
+	}
+
+	key := tableKey{pattern: filepath.Clean(pattern), maxRows: opts.MaxRows}
+	if opts.Range != nil {
+		key.pages, key.hasPages = *opts.Range, true
+	}
+	if rows, ok := ctx.tables[key]; ok {
+		return rows, nil
+	}
+	rows, err := ReadTable(pattern, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx.tables[key] = rows
+	return rows, nil
+}
+
+// ClearTableCache forgets the tables read so far, so that the next
+// ReadTable of each reads it again. A context reused across queries, such
+// as that of an interactive session, calls it before each one to see
+// changes to the files.
+func (ctx *ExecutionContext) ClearTableCache() {
+	ctx.tables = make(map[tableKey][]map[string]interface{})
+}
+
+// TableColumns returns the names of the columns ReadTable returns for a
+// parquet file or glob pattern, taken from the schema of its first file, so
+// they are known even when the table has no rows. Globs include _file.
+func TableColumns(pattern string) ([]string, error) {
+	files, err := reader.ExpandPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := reader.NewReader(files[0])
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	var columns []string
+	for _, field := range r.Schema().Fields() {
+		columns = append(columns, field.Name())
+	}
+	if strings.ContainsAny(pattern, "*?[]{}") {
+		columns = append(columns, "_file")
+	}
+	return columns, nil
+}
@@ -31,6 +31,37 @@ type ComplexDataRow struct {
 	Score     *float64   `parquet:"score,optional"`
 }
 
+// UUIDDataRow defines a test data structure with a UUID column and a plain fixed-length byte array
+type UUIDDataRow struct {
+	ID       [16]byte `parquet:"id,uuid"`
+	Name     string   `parquet:"name"`
+	Checksum [4]byte  `parquet:"checksum"`
+}
+
+// EmployeeDataRow defines a test data structure for grouping, where departments contain repeated teams
+type EmployeeDataRow struct {
+	ID     int64   `parquet:"id"`
+	Dept   string  `parquet:"dept"`
+	Team   string  `parquet:"team"`
+	Salary float64 `parquet:"salary"`
+}
+
+// WidthDataRow defines a test data structure whose integer columns use narrower physical types than BasicDataRow
+type WidthDataRow struct {
+	ID     int32 `parquet:"id"`
+	UserID int32 `parquet:"user_id"`
+	Limit  int64 `parquet:"limit_value"`
+	Count  int32 `parquet:"count,int(16)"`
+}
+
+// QuotedNameDataRow defines a test data structure whose column names can only be written as quoted identifiers
+type QuotedNameDataRow struct {
+	ID        int64  `parquet:"id"`
+	FirstName string `parquet:"first name"`
+	Order     int64  `parquet:"order"`
+	Quoted    string `parquet:"say \"hi\""`
+}
+
 // createBasicParquetFile creates a temporary parquet file with BasicDataRow structure
 // Returns the path to the created file
 func createBasicParquetFile(t *testing.T, rows []BasicDataRow) string {
@@ -104,6 +135,78 @@ func createEmptyParquetFile(t *testing.T) string {
 	return testFile
 }
 
+// createUUIDParquetFile creates a temporary parquet file with UUIDDataRow structure
+// Returns the path to the created file
+func createUUIDParquetFile(t *testing.T, rows []UUIDDataRow) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test_uuid.parquet")
+
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	writer := parquet.NewGenericWriter[UUIDDataRow](f)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	return testFile
+}
+
+// createQuotedNameParquetFile creates a temporary parquet file with QuotedNameDataRow structure
+// Returns the path to the created file
+func createQuotedNameParquetFile(t *testing.T, rows []QuotedNameDataRow) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test quoted names.parquet")
+
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	writer := parquet.NewGenericWriter[QuotedNameDataRow](f)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	return testFile
+}
+
+// createEmployeeParquetFile creates a temporary parquet file with EmployeeDataRow structure
+// Returns the path to the created file
+func createEmployeeParquetFile(t *testing.T, rows []EmployeeDataRow) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test_employee.parquet")
+
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	writer := parquet.NewGenericWriter[EmployeeDataRow](f)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	return testFile
+}
+
 // createNamedBasicParquetFile creates a parquet file with a specific name in a temp directory
 // Useful for tests that need specific file names (e.g., join tests with multiple files)
 func createNamedBasicParquetFile(t *testing.T, dir, filename string, rows []BasicDataRow) string {
@@ -127,6 +230,28 @@ func createNamedBasicParquetFile(t *testing.T, dir, filename string, rows []Basi
 	return testFile
 }
 
+// createNamedWidthParquetFile creates a parquet file with WidthDataRow structure and a specific name
+func createNamedWidthParquetFile(t *testing.T, dir, filename string, rows []WidthDataRow) string {
+	t.Helper()
+	testFile := filepath.Join(dir, filename)
+
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatalf("failed to create test file %s: %v", filename, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	writer := parquet.NewGenericWriter[WidthDataRow](f)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write test data to %s: %v", filename, err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer for %s: %v", filename, err)
+	}
+
+	return testFile
+}
+
 // createNamedComplexParquetFile creates a complex parquet file with a specific name
 func createNamedComplexParquetFile(t *testing.T, dir, filename string, rows []ComplexDataRow) string {
 	t.Helper()
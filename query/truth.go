@@ -0,0 +1,149 @@
+package query
+
+import "fmt"
+
+// truthValue is the result of a condition in three-valued logic, where a
+// comparison with NULL is neither true nor false but unknown
+type truthValue int
+
+const (
+	truthFalse truthValue = iota
+	truthTrue
+	truthUnknown
+)
+
+// truthOf converts a match into a truth value
+func truthOf(match bool) truthValue {
+	if match {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// not negates a truth value. NOT unknown is unknown.
+func (t truthValue) not() truthValue {
+	switch t {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	default:
+		return truthUnknown
+	}
+}
+
+// evaluateTruth evaluates a condition in three-valued logic. Evaluate only
+// reports whether a row matches, which is false for unknown too, so NOT
+// needs this to keep unknown conditions from matching once negated.
+// Conditions that can't be unknown, or that contain subqueries, are
+// evaluated with evaluate.
+func evaluateTruth(row map[string]interface{}, expr Expression, evaluate func(Expression, map[string]interface{}) (bool, error)) (truthValue, error) {
+	switch e := expr.(type) {
+	case *NotExpr:
+		truth, err := evaluateTruth(row, e.Expr, evaluate)
+		if err != nil {
+			return truthFalse, err
+		}
+		return truth.not(), nil
+	case *BinaryExpr:
+		left, err := evaluateTruth(row, e.Left, evaluate)
+		if err != nil {
+			return truthFalse, err
+		}
+		right, err := evaluateTruth(row, e.Right, evaluate)
+		if err != nil {
+			return truthFalse, err
+		}
+		switch e.Operator {
+		case TokenAnd:
+			if left == truthFalse || right == truthFalse {
+				return truthFalse, nil
+			}
+		case TokenOr:
+			if left == truthTrue || right == truthTrue {
+				return truthTrue, nil
+			}
+		default:
+			return truthFalse, fmt.Errorf("unsupported binary operator: %v", e.Operator)
+		}
+		if left == truthUnknown || right == truthUnknown {
+			return truthUnknown, nil
+		}
+		return left, nil
+	case *ComparisonExpr:
+		// Comparing with a NULL literal is the same as IS NULL
+		if value, exists := row[e.Column]; exists && value == nil && e.Value != nil {
+			return truthUnknown, nil
+		}
+	case *ColumnComparisonExpr:
+		left, leftExists := row[e.LeftColumn]
+		right, rightExists := row[e.RightColumn]
+		if leftExists && rightExists && (left == nil || right == nil) {
+			return truthUnknown, nil
+		}
+	case *ExpressionComparisonExpr:
+		if !hasScalarSubquery(e.Left) && !hasScalarSubquery(e.Right) {
+			left, err := e.Left.EvaluateSelect(row)
+			if err != nil {
+				return truthFalse, err
+			}
+			right, err := e.Right.EvaluateSelect(row)
+			if err != nil {
+				return truthFalse, err
+			}
+			if (left == nil || right == nil) && !isNullLiteral(e.Left) && !isNullLiteral(e.Right) {
+				return truthUnknown, nil
+			}
+			match, err := compare(left, e.Operator, right)
+			return truthOf(match), err
+		}
+	case *LikeExpr:
+		if value, exists := row[e.Column]; exists && value == nil {
+			return truthUnknown, nil
+		}
+	case *BetweenExpr:
+		if value, exists := row[e.Column]; exists && value == nil {
+			return truthUnknown, nil
+		}
+	case *InExpr:
+		if len(e.Columns) == 0 {
+			return e.truth(row)
+		}
+	}
+
+	match, err := evaluate(expr, row)
+	return truthOf(match), err
+}
+
+// truth evaluates a single column IN list in three-valued logic. The
+// result is unknown for a NULL value, and for a value that isn't found in
+// a list holding NULL.
+func (i *InExpr) truth(row map[string]interface{}) (truthValue, error) {
+	if value, exists := row[i.Column]; exists && value == nil {
+		return truthUnknown, nil
+	}
+
+	in := *i
+	in.Negate = false
+	found, err := in.Evaluate(row)
+	if err != nil {
+		return truthFalse, err
+	}
+	if !found {
+		for _, value := range i.Values {
+			if value == nil {
+				return truthUnknown, nil
+			}
+		}
+	}
+	if i.Negate {
+		return truthOf(!found), nil
+	}
+	return truthOf(found), nil
+}
+
+// isNullLiteral reports whether an expression is the literal NULL
+func isNullLiteral(expr SelectExpression) bool {
+	literal, ok := expr.(*LiteralExpr)
+	return ok && literal.Value == nil
+}
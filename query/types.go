@@ -14,7 +14,11 @@
 //	filtered, err := ApplyFilter(rows, query.Filter)
 package query
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
 
 // TokenType represents the type of a token
 type TokenType int
@@ -62,14 +66,27 @@ const (
 	TokenOuter
 	TokenCross
 	TokenOn
+	TokenTablesample
+	TokenQualify
+	TokenUnion
+	TokenAll
+	TokenUsing
+	TokenILike
 
 	// Operators
-	TokenEqual        // =
-	TokenNotEqual     // !=
-	TokenLess         // <
-	TokenGreater      // >
-	TokenLessEqual    // <=
-	TokenGreaterEqual // >=
+	TokenEqual         // =
+	TokenNotEqual      // !=
+	TokenLess          // <
+	TokenGreater       // >
+	TokenLessEqual     // <=
+	TokenGreaterEqual  // >=
+	TokenNullSafeEqual // <=>
+	TokenPlus          // +
+	TokenMinus         // -
+	TokenStar          // * (lexed as an identifier, used for multiplication)
+	TokenSlash         // /
+	TokenPercent       // %
+	TokenConcat        // ||
 
 	// Literals
 	TokenString
@@ -78,9 +95,12 @@ const (
 	TokenBool
 
 	// Delimiters
-	TokenComma      // ,
-	TokenLeftParen  // (
-	TokenRightParen // )
+	TokenComma        // ,
+	TokenLeftParen    // (
+	TokenRightParen   // )
+	TokenLeftBracket  // [
+	TokenRightBracket // ]
+	TokenSemicolon    // ;
 
 	// Special
 	TokenEOF
@@ -95,53 +115,97 @@ type Token struct {
 
 // Query represents a parsed SQL query
 type Query struct {
-	CTEs       []CTE  // WITH clause CTEs
-	TableName  string // Single file path or glob pattern
-	Subquery   *Query // Subquery in FROM clause (alternative to TableName)
-	TableAlias string // Optional alias for table/subquery
-	Joins      []Join // JOIN clauses
+	CTEs       []CTE        // WITH clause CTEs
+	TableName  string       // Single file path or glob pattern
+	Subquery   *Query       // Subquery in FROM clause (alternative to TableName)
+	TableAlias string       // Optional alias for table/subquery
+	Sample     *TableSample // Optional TABLESAMPLE clause on the FROM table
+	Joins      []Join       // JOIN clauses
 	SelectList []SelectItem
 	Filter     Expression
 	GroupBy    []string      // Column names to group by
 	Having     Expression    // Post-aggregation filter
+	Qualify    Expression    // Post-window-function filter
 	OrderBy    []OrderByItem // Sort specification
 	Limit      *int64        // Row limit
 	Offset     *int64        // Row offset
 	Distinct   bool          // DISTINCT modifier
+
+	// SetOperations are the UNION [ALL] queries combined with this one. When
+	// present, OrderBy, Limit and Offset apply to the combined result.
+	SetOperations []SetOperation
+
+	// HavingAggregates are aggregates used in HAVING, computed per group
+	// alongside the SELECT list and dropped after HAVING is applied. Each is
+	// aliased to the column its HAVING comparison reads.
+	HavingAggregates []SelectItem
+
+	// GroupByRollup is set by GROUP BY ROLLUP(...), which adds subtotal
+	// rows for each shorter prefix of GroupBy and a grand total
+	GroupByRollup bool
+
+	// GroupKeys are GROUP BY expressions, computed into a column of each
+	// input row before grouping. Each is aliased to its column in GroupBy.
+	GroupKeys []SelectItem
+
+	// OrderKeys are ORDER BY expressions the SELECT list doesn't name,
+	// computed alongside it and dropped after sorting. Each is aliased to
+	// the column its ORDER BY item sorts by.
+	OrderKeys []SelectItem
 }
 
-// JoinType represents the type of join operation
-type JoinType int
+// SetOperator is the kind of a set operation between queries
+type SetOperator int
 
 const (
-	JoinInner JoinType = iota // INNER JOIN (default)
-	JoinLeft                  // LEFT JOIN / LEFT OUTER JOIN
-	JoinRight                 // RIGHT JOIN / RIGHT OUTER JOIN
-	JoinFull                  // FULL JOIN / FULL OUTER JOIN
-	JoinCross                 // CROSS JOIN
+	SetUnion    SetOperator = iota // UNION, which removes duplicate rows
+	SetUnionAll                    // UNION ALL, which keeps them
 )
 
-// Join represents a JOIN clause
-type Join struct {
-	Type      JoinType   // Type of join (INNER, LEFT, RIGHT, FULL, CROSS)
-	TableName string     // Table/file to join
-	Subquery  *Query     // Subquery to join (alternative to TableName)
-	Alias     string     // Optional alias for joined table/subquery
-	Condition Expression // ON clause condition (nil for CROSS JOIN)
+// String returns the SQL spelling of the operator
+func (op SetOperator) String() string {
+	if op == SetUnionAll {
+		return "UNION ALL"
+	}
+	return "UNION"
+}
+
+// SetOperation combines the result of another query with a query's result
+type SetOperation struct {
+	Operator SetOperator
+	Query    *Query // A SELECT without CTEs, ORDER BY, LIMIT or OFFSET
+}
+
+// TableSample represents a TABLESAMPLE clause: TABLESAMPLE (n PERCENT) or TABLESAMPLE (n ROWS)
+type TableSample struct {
+	Percent float64 // Percentage of rows to include (0-100), used when ByRows is false
+	Rows    int64   // Number of rows to read, used when ByRows is true
+	ByRows  bool    // ROWS sampling instead of PERCENT sampling
 }
 
 // CTE represents a Common Table Expression (WITH clause)
 type CTE struct {
-	Name  string // CTE name
-	Query *Query // Subquery defining the CTE
+	Name      string // CTE name
+	Query     *Query // Subquery defining the CTE
+	Recursive bool   // Defined in a WITH RECURSIVE clause, so it may refer to itself
 }
 
 // OrderByItem represents a column to sort by
 type OrderByItem struct {
-	Column string // Column name or alias
-	Desc   bool   // DESC vs ASC (default)
+	Column string    // Column name or alias, or the alias of an OrderKeys item
+	Desc   bool      // DESC vs ASC (default)
+	Nulls  NullOrder // Where NULLs sort
 }
 
+// NullOrder is where an ORDER BY item sorts NULLs
+type NullOrder int
+
+const (
+	NullsDefault NullOrder = iota // Last for ASC and first for DESC, as in PostgreSQL
+	NullsFirst                    // NULLS FIRST
+	NullsLast                     // NULLS LAST
+)
+
 // SelectItem represents a column or expression in the SELECT list
 type SelectItem struct {
 	Expr  SelectExpression // Column, function, or expression
@@ -153,9 +217,22 @@ type SelectExpression interface {
 	EvaluateSelect(row map[string]interface{}) (interface{}, error)
 }
 
-// ColumnRef references a column (or * for all columns)
+// ColumnRef references a column, or all columns (*) or those of one table
+// (u.*) of a JOIN
 type ColumnRef struct {
-	Column string // Column name or "*"
+	Column string // Column name, "*" or "table.*"
+}
+
+// wildcardTable returns the table of a qualified wildcard such as u.*
+func (c *ColumnRef) wildcardTable() (string, bool) {
+	table, ok := strings.CutSuffix(c.Column, ".*")
+	return table, ok && table != ""
+}
+
+// isWildcard reports whether c is * or a qualified wildcard
+func (c *ColumnRef) isWildcard() bool {
+	_, ok := c.wildcardTable()
+	return ok || c.Column == "*"
 }
 
 // FunctionCall represents a function invocation
@@ -164,16 +241,24 @@ type FunctionCall struct {
 	Args []SelectExpression
 }
 
+// ExtractExpr represents EXTRACT(field FROM expr), such as EXTRACT(YEAR FROM created_at)
+type ExtractExpr struct {
+	Field string           // Upper-cased field name (YEAR, MONTH, DOW, EPOCH, ...)
+	Expr  SelectExpression // Date or timestamp expression
+}
+
 // LiteralExpr represents a literal value (number, string, bool)
 type LiteralExpr struct {
 	Value interface{}
 }
 
-// AggregateExpr represents an aggregate function (COUNT, SUM, AVG, MIN, MAX)
+// AggregateExpr represents an aggregate function (COUNT, SUM, AVG, MIN, MAX, ARRAY_AGG, STRING_AGG, APPROX_COUNT_DISTINCT)
 type AggregateExpr struct {
-	Function string           // COUNT, SUM, AVG, MIN, MAX
-	Arg      SelectExpression // Argument expression (nil for COUNT(*))
-	Distinct bool             // DISTINCT modifier (not implemented yet)
+	Function  string           // COUNT, SUM, AVG, MIN, MAX, ARRAY_AGG, STRING_AGG, APPROX_COUNT_DISTINCT
+	Arg       SelectExpression // Argument expression (nil for COUNT(*))
+	Distinct  bool             // DISTINCT modifier: aggregate each distinct argument value once
+	Separator string           // STRING_AGG separator
+	OrderBy   []OrderByItem    // ORDER BY inside the call: order values are collected in (ARRAY_AGG, STRING_AGG)
 }
 
 // CaseExpr represents a CASE expression
@@ -190,9 +275,10 @@ type WhenClause struct {
 
 // WindowExpr represents a window function call
 type WindowExpr struct {
-	Function string             // Window function name (ROW_NUMBER, RANK, etc.)
-	Args     []SelectExpression // Function arguments
-	Window   *WindowSpec        // Window specification
+	Function  string             // Window function name (ROW_NUMBER, RANK, etc.)
+	Args      []SelectExpression // Function arguments
+	Window    *WindowSpec        // Window specification
+	Aggregate *AggregateExpr     // Aggregate computed over each row's frame (SUM, AVG, ...), or nil
 }
 
 // WindowSpec specifies the window behavior
@@ -246,6 +332,11 @@ type BinaryExpr struct {
 	Right    Expression
 }
 
+// NotExpr negates a condition: NOT (age > 30 AND active = true)
+type NotExpr struct {
+	Expr Expression
+}
+
 // ComparisonExpr represents a comparison expression (column op literal)
 type ComparisonExpr struct {
 	Column   string
@@ -260,18 +351,32 @@ type ColumnComparisonExpr struct {
 	RightColumn string
 }
 
-// InExpr represents an IN expression (col IN (val1, val2, ...))
+// ExpressionComparisonExpr compares the values of two select expressions,
+// such as salary / 12 > 1000 or CASE ... END = 'x'
+type ExpressionComparisonExpr struct {
+	Left     SelectExpression
+	Operator TokenType
+	Right    SelectExpression
+}
+
+// InExpr represents an IN expression (col IN (val1, val2, ...)) or a row
+// constructor IN list ((col1, col2) IN ((val1, val2), ...))
 type InExpr struct {
-	Column string
-	Values []interface{}
-	Negate bool // NOT IN
+	Column  string
+	Values  []interface{}
+	Columns []string        // Row constructor columns (Column and Values are unused)
+	Tuples  [][]interface{} // Row constructor values, each with one value per column
+	Negate  bool            // NOT IN
 }
 
-// LikeExpr represents a LIKE expression (col LIKE 'pattern')
+// LikeExpr represents a LIKE expression (col LIKE 'pattern') or, with
+// CaseInsensitive set, an ILIKE expression
 type LikeExpr struct {
-	Column  string
-	Pattern string
-	Negate  bool // NOT LIKE
+	Column          string
+	Pattern         string
+	Negate          bool // NOT LIKE
+	CaseInsensitive bool // ILIKE
+	Escape          rune // ESCAPE character, or 0 for none
 }
 
 // BetweenExpr represents a BETWEEN expression (col BETWEEN lower AND upper)
@@ -288,6 +393,21 @@ type IsNullExpr struct {
 	Negate bool // IS NOT NULL
 }
 
+// IsBoolExpr represents an IS TRUE / IS FALSE expression (col IS [NOT] TRUE|FALSE)
+type IsBoolExpr struct {
+	Column string
+	Value  bool // TRUE or FALSE
+	Negate bool // IS NOT TRUE / IS NOT FALSE
+}
+
+// DistinctExpr compares two values treating NULL like any other value:
+// a IS [NOT] DISTINCT FROM b, where a <=> b is IS NOT DISTINCT FROM
+type DistinctExpr struct {
+	Left   SelectExpression
+	Right  SelectExpression
+	Negate bool // IS NOT DISTINCT FROM
+}
+
 // SubqueryExpr represents a subquery in WHERE clause (for IN, EXISTS, or scalar)
 type SubqueryExpr struct {
 	Query *Query
@@ -307,6 +427,16 @@ type InSubqueryExpr struct {
 	Negate   bool // NOT IN
 }
 
+// QuantifiedSubqueryExpr represents a comparison with the values of a
+// subquery: column > ALL (subquery) or column = ANY (subquery). SOME is the
+// same as ANY.
+type QuantifiedSubqueryExpr struct {
+	Column   string
+	Operator TokenType
+	All      bool // ALL rather than ANY/SOME
+	Subquery *Query
+}
+
 // SubqueryType represents the type of subquery
 type SubqueryType int
 
@@ -350,7 +480,8 @@ func (c *ComparisonExpr) Evaluate(row map[string]interface{}) (bool, error) {
 		return false, fmt.Errorf("column %q not found", c.Column)
 	}
 
-	return compare(value, c.Operator, c.Value)
+	match, err := compare(value, c.Operator, c.Value)
+	return match, withColumn(err, c.Column)
 }
 
 // Evaluate evaluates a column-to-column comparison expression
@@ -366,22 +497,95 @@ func (c *ColumnComparisonExpr) Evaluate(row map[string]interface{}) (bool, error
 		return false, fmt.Errorf("column %q not found", c.RightColumn)
 	}
 
+	match, err := compare(leftValue, c.Operator, rightValue)
+	return match, withColumn(err, c.LeftColumn)
+}
+
+// Evaluate evaluates a comparison between two select expressions
+func (c *ExpressionComparisonExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	leftValue, err := c.Left.EvaluateSelect(row)
+	if err != nil {
+		return false, err
+	}
+	rightValue, err := c.Right.EvaluateSelect(row)
+	if err != nil {
+		return false, err
+	}
 	return compare(leftValue, c.Operator, rightValue)
 }
 
 // Evaluate evaluates an IN expression
 func (i *InExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	if len(i.Columns) > 0 {
+		return i.evaluateTuples(row)
+	}
+
 	value, exists := row[i.Column]
 	if !exists {
 		return false, fmt.Errorf("column %q not found", i.Column)
 	}
 
-	// Check if value is in the list
-	found := false
+	// NULL IN (...) is unknown, so neither IN nor NOT IN matches
+	if value == nil {
+		return false, nil
+	}
+
+	// Check if value is in the list. Elements of another type than the value
+	// are skipped so mixed lists work; it's only an error if none compares.
+	var found, hasNull, compared bool
+	var typeErr error
 	for _, listValue := range i.Values {
+		if listValue == nil {
+			hasNull = true
+			continue
+		}
 		match, err := compare(value, TokenEqual, listValue)
 		if err != nil {
-			return false, err
+			typeErr = err
+			continue
+		}
+		compared = true
+		if match {
+			found = true
+			break
+		}
+	}
+	if !compared && typeErr != nil {
+		return false, withColumn(typeErr, i.Column)
+	}
+
+	// Apply negation if needed. A NULL in the list makes NOT IN unknown for
+	// values that aren't found.
+	if i.Negate {
+		return !found && !hasNull, nil
+	}
+	return found, nil
+}
+
+// evaluateTuples evaluates a row constructor IN list. A tuple matches when
+// every column equals the corresponding value.
+func (i *InExpr) evaluateTuples(row map[string]interface{}) (bool, error) {
+	values := make([]interface{}, len(i.Columns))
+	for j, column := range i.Columns {
+		value, exists := row[column]
+		if !exists {
+			return false, fmt.Errorf("column %q not found", column)
+		}
+		values[j] = value
+	}
+
+	found := false
+	for _, tuple := range i.Tuples {
+		match := true
+		for j, value := range values {
+			equal, err := compare(value, TokenEqual, tuple[j])
+			if err != nil {
+				return false, withColumn(err, i.Columns[j])
+			}
+			if !equal {
+				match = false
+				break
+			}
 		}
 		if match {
 			found = true
@@ -406,11 +610,17 @@ func (l *LikeExpr) Evaluate(row map[string]interface{}) (bool, error) {
 	// Convert value to string
 	str, ok := value.(string)
 	if !ok {
-		return false, fmt.Errorf("LIKE requires string column, got %T", value)
+		return false, fmt.Errorf("LIKE: %w", &TypeError{Column: l.Column, Expected: "string", Value: value})
 	}
 
-	// Match the LIKE pattern
-	match := matchLikePattern(str, l.Pattern)
+	// Match the LIKE pattern, ignoring case for ILIKE
+	pattern, escape := l.Pattern, l.Escape
+	if l.CaseInsensitive {
+		str = strings.ToLower(str)
+		pattern = strings.ToLower(pattern)
+		escape = unicode.ToLower(escape)
+	}
+	match := matchLikePattern(str, pattern, escape)
 
 	// Apply negation if needed
 	if l.Negate {
@@ -429,13 +639,13 @@ func (b *BetweenExpr) Evaluate(row map[string]interface{}) (bool, error) {
 	// Check if value >= lower
 	lowerMatch, err := compare(value, TokenGreaterEqual, b.Lower)
 	if err != nil {
-		return false, err
+		return false, withColumn(err, b.Column)
 	}
 
 	// Check if value <= upper
 	upperMatch, err := compare(value, TokenLessEqual, b.Upper)
 	if err != nil {
-		return false, err
+		return false, withColumn(err, b.Column)
 	}
 
 	// Value is between if it satisfies both conditions
@@ -462,6 +672,68 @@ func (i *IsNullExpr) Evaluate(row map[string]interface{}) (bool, error) {
 	return isNull, nil
 }
 
+// Evaluate evaluates an IS [NOT] TRUE/FALSE expression.
+// Unlike "= true", it never yields unknown: NULL IS TRUE is false and NULL IS NOT TRUE is true.
+func (i *IsBoolExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	value, exists := row[i.Column]
+
+	matches := false
+	if exists && value != nil {
+		boolVal, ok := value.(bool)
+		if !ok {
+			return false, fmt.Errorf("IS TRUE/FALSE requires a boolean column, %q has type %T", i.Column, value)
+		}
+		matches = boolVal == i.Value
+	}
+
+	// Apply negation if needed (IS NOT TRUE / IS NOT FALSE)
+	if i.Negate {
+		return !matches, nil
+	}
+	return matches, nil
+}
+
+// Evaluate evaluates an IS [NOT] DISTINCT FROM expression
+func (d *DistinctExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	left, err := d.Left.EvaluateSelect(row)
+	if err != nil {
+		return false, err
+	}
+	right, err := d.Right.EvaluateSelect(row)
+	if err != nil {
+		return false, err
+	}
+	return d.compare(left, right)
+}
+
+// compare reports whether two values satisfy the expression. Two NULLs are
+// not distinct, and NULL is distinct from every other value.
+func (d *DistinctExpr) compare(left, right interface{}) (bool, error) {
+	var distinct bool
+	if left == nil || right == nil {
+		distinct = left != nil || right != nil
+	} else {
+		equal, err := compare(left, TokenEqual, right)
+		if err != nil {
+			return false, err
+		}
+		distinct = !equal
+	}
+
+	// Apply negation if needed (IS NOT DISTINCT FROM)
+	if d.Negate {
+		return !distinct, nil
+	}
+	return distinct, nil
+}
+
+// Evaluate evaluates a NOT expression. A condition that is unknown because
+// of a NULL stays unknown when negated, so the row doesn't match either way.
+func (n *NotExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	truth, err := evaluateTruth(row, n, Expression.Evaluate)
+	return truth == truthTrue, err
+}
+
 // EvaluateSelect evaluates a column reference
 func (c *ColumnRef) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
 	// Special case: * means all columns
@@ -511,6 +783,27 @@ func (f *FunctionCall) EvaluateSelect(row map[string]interface{}) (interface{},
 	return fn.Evaluate(args)
 }
 
+// EvaluateSelect extracts a field from a date or timestamp, returning nil for NULL input
+func (e *ExtractExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
+	value, err := e.Expr.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	return extractValue(e.Field, value)
+}
+
+// extractValue extracts a field from a date or timestamp value
+func extractValue(field string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := parseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("EXTRACT: %w", err)
+	}
+	return extractField(field, t)
+}
+
 // EvaluateSelect evaluates a literal expression
 func (l *LiteralExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
 	return l.Value, nil
@@ -572,6 +865,12 @@ func (i *InSubqueryExpr) Evaluate(row map[string]interface{}) (bool, error) {
 	return false, fmt.Errorf("IN subquery evaluation requires executor context")
 }
 
+// Evaluate evaluates an ANY or ALL subquery comparison
+// Note: This requires access to subquery execution context, which is handled in the executor
+func (q *QuantifiedSubqueryExpr) Evaluate(row map[string]interface{}) (bool, error) {
+	return false, fmt.Errorf("ANY/ALL subquery evaluation requires executor context")
+}
+
 // EvaluateSelect evaluates a scalar subquery
 // Note: This requires access to subquery execution context, which is handled in the executor
 func (s *ScalarSubqueryExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
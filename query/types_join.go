@@ -0,0 +1,36 @@
+package query
+
+// JoinType represents the type of join operation
+type JoinType int
+
+const (
+	JoinInner JoinType = iota // INNER JOIN (default)
+	JoinLeft                  // LEFT JOIN / LEFT OUTER JOIN
+	JoinRight                 // RIGHT JOIN / RIGHT OUTER JOIN
+	JoinFull                  // FULL JOIN / FULL OUTER JOIN
+	JoinCross                 // CROSS JOIN
+)
+
+// Join represents a JOIN clause
+type Join struct {
+	Type      JoinType         // Type of join (INNER, LEFT, RIGHT, FULL, CROSS)
+	TableName string           // Table/file to join
+	Subquery  *Query           // Subquery to join (alternative to TableName)
+	Group     *JoinGroup       // Parenthesized join expression to join (alternative to TableName)
+	Unnest    SelectExpression // Array expression of UNNEST(...) to join (alternative to TableName)
+	Alias     string           // Optional alias for joined table/subquery, or the UNNEST column
+	Condition Expression       // ON clause condition (nil for CROSS JOIN and USING)
+
+	// UsingColumns lists the columns of a USING (...) clause, which joins on
+	// equality of the like-named columns and keeps one copy of each
+	UsingColumns []string
+}
+
+// JoinGroup represents a parenthesized join expression used as the right-hand
+// side of a JOIN, such as (c JOIN d ON c.id = d.c_id). The group is joined
+// into a single row set before the outer join is applied.
+type JoinGroup struct {
+	TableName string // Leftmost table/file of the group
+	Alias     string // Optional alias for the leftmost table
+	Joins     []Join // JOIN clauses inside the parentheses
+}
@@ -0,0 +1,199 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ArithmeticExpr represents a binary arithmetic operation (left + right,
+// left - right, left * right, left / right or left % right)
+type ArithmeticExpr struct {
+	Left     SelectExpression
+	Operator TokenType // TokenPlus, TokenMinus, TokenStar, TokenSlash or TokenPercent
+	Right    SelectExpression
+}
+
+// ConcatExpr represents string concatenation with the || operator
+type ConcatExpr struct {
+	Left  SelectExpression
+	Right SelectExpression
+}
+
+// IndexExpr represents an array subscript such as tags[0].
+// Indexes are 0-based; negative indexes count from the end (tags[-1] is the last element).
+type IndexExpr struct {
+	Expr  SelectExpression // Array expression
+	Index SelectExpression // Index expression
+}
+
+// EvaluateSelect evaluates an arithmetic expression
+func (a *ArithmeticExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
+	left, err := a.Left.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.Right.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	return arithmetic(left, a.Operator, right)
+}
+
+// EvaluateSelect evaluates a || concatenation
+func (c *ConcatExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
+	left, err := c.Left.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.Right.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	return concat(left, right)
+}
+
+// concat joins the text of two values, as the || operator does. NULL on
+// either side gives NULL.
+func concat(left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	leftStr, err := valueToString(left)
+	if err != nil {
+		return nil, fmt.Errorf("||: %w", err)
+	}
+	rightStr, err := valueToString(right)
+	if err != nil {
+		return nil, fmt.Errorf("||: %w", err)
+	}
+	return leftStr + rightStr, nil
+}
+
+// arithmetic applies an arithmetic operator to two values. Numbers are
+// computed as float64, except that % of two integers is an int64. NULL on
+// either side gives NULL.
+func arithmetic(left interface{}, op TokenType, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	if op == TokenPercent {
+		if l, ok := integerValue(left); ok {
+			if r, ok := integerValue(right); ok {
+				if r == 0 {
+					return nil, fmt.Errorf("modulo by zero")
+				}
+				return l % r, nil
+			}
+		}
+	}
+
+	l, ok := toFloat64(left)
+	if !ok {
+		return nil, fmt.Errorf("arithmetic on non-numeric value %v (%T)", left, left)
+	}
+	r, ok := toFloat64(right)
+	if !ok {
+		return nil, fmt.Errorf("arithmetic on non-numeric value %v (%T)", right, right)
+	}
+
+	switch op {
+	case TokenPlus:
+		return l + r, nil
+	case TokenMinus:
+		return l - r, nil
+	case TokenStar:
+		return l * r, nil
+	case TokenSlash:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case TokenPercent:
+		if r == 0 {
+			return nil, fmt.Errorf("modulo by zero")
+		}
+		return math.Mod(l, r), nil
+	default:
+		return nil, fmt.Errorf("unsupported arithmetic operator: %v", op)
+	}
+}
+
+// integerValue returns v as an int64 if it is an integer that fits
+func integerValue(v interface{}) (int64, bool) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), true
+	case int8:
+		return int64(val), true
+	case int16:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case uint8:
+		return int64(val), true
+	case uint16:
+		return int64(val), true
+	case uint32:
+		return int64(val), true
+	case uint:
+		return int64(val), uint64(val) <= math.MaxInt64
+	case uint64:
+		return int64(val), val <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// EvaluateSelect evaluates an array subscript, returning nil for NULL arrays
+// and out-of-range indexes
+func (i *IndexExpr) EvaluateSelect(row map[string]interface{}) (interface{}, error) {
+	array, err := i.Expr.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.Index.EvaluateSelect(row)
+	if err != nil {
+		return nil, err
+	}
+	return indexArray(array, index)
+}
+
+// indexArray returns the element of array at a 0-based or negative index
+func indexArray(array, index interface{}) (interface{}, error) {
+	if array == nil || index == nil {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(array)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot index non-array value of type %T", array)
+	}
+
+	n, err := valueToNumber(index)
+	if err != nil {
+		return nil, fmt.Errorf("array index: %w", err)
+	}
+
+	pos, ok := resolveIndex(int(n), rv.Len())
+	if !ok {
+		return nil, nil
+	}
+	return rv.Index(pos).Interface(), nil
+}
+
+// resolveIndex converts a 0-based index, or a negative index counting from
+// the end (-1 is the last element), into a position. Returns false if the
+// index is out of range.
+func resolveIndex(index, length int) (int, bool) {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return 0, false
+	}
+	return index, true
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"fmt"
+)
+
+// ExecuteSetOperations executes a query with UNION [ALL] arms, running the
+// query and each arm with executeFn and concatenating their rows. UNION
+// removes duplicates from the rows combined so far, as DISTINCT does, while
+// UNION ALL keeps them. The query's ORDER BY, LIMIT and OFFSET are applied
+// to the combined rows. The query's CTEs must already be materialized in ctx.
+//
+// Arms are matched by position: the columns of each arm are renamed to
+// those of the first, and a different number of columns is an error. An arm
+// whose column order isn't known, such as SELECT * from a CTE, is matched by
+// column name instead (see setColumns).
+func (ctx *ExecutionContext) ExecuteSetOperations(q *Query, executeFn func(*Query, *ExecutionContext) ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	first := *q
+	first.CTEs = nil
+	first.SetOperations = nil
+	first.OrderBy = nil
+	first.Limit = nil
+	first.Offset = nil
+
+	rows, err := executeFn(&first, ctx)
+	if err != nil {
+		return nil, err
+	}
+	columns, ordered := ctx.setColumns(&first, rows)
+
+	for i, op := range q.SetOperations {
+		armRows, err := executeFn(op.Query, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %s query %d: %w", op.Operator, i+2, err)
+		}
+
+		armColumns, armOrdered := ctx.setColumns(op.Query, armRows)
+		if columns == nil {
+			columns, ordered = armColumns, armOrdered
+		} else if armColumns != nil {
+			if err := checkSetColumns(columns, armColumns, ordered && armOrdered, op.Operator, i+2); err != nil {
+				return nil, err
+			}
+			if ordered && armOrdered {
+				armRows = renameSetColumns(armRows, armColumns, columns)
+			}
+		}
+
+		rows = append(rows, armRows...)
+		if op.Operator == SetUnion {
+			rows, err = ApplyDistinct(rows)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply %s: %w", op.Operator, err)
+			}
+		}
+	}
+
+	if len(q.OrderBy) > 0 {
+		rows, err = ApplyOrderBy(rows, q.OrderBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply ORDER BY: %w", err)
+		}
+	}
+	if q.Limit != nil || q.Offset != nil {
+		rows, err = ApplyLimitOffset(rows, q.Limit, q.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply LIMIT/OFFSET: %w", err)
+		}
+	}
+	return rows, nil
+}
+
+// setColumns returns the columns of a set operation arm and whether they
+// are in SELECT list order, so that arms can be matched by position. The
+// order is known for an explicit SELECT list and for SELECT * from a file or
+// subquery (see resultColumns). Otherwise, as for SELECT * from a CTE or a
+// join, the columns of rows are returned unordered, or nil if there are
+// none.
+func (ctx *ExecutionContext) setColumns(q *Query, rows []map[string]interface{}) ([]string, bool) {
+	if columns := SelectColumns(q.SelectList); columns != nil {
+		return columns, true
+	}
+	if columns := ctx.resultColumns(q); columns != nil && sameColumns(columns, GetColumnNames(rows)) {
+		return columns, true
+	}
+	return GetColumnNames(rows), false
+}
+
+// resultColumns returns the columns of the result of q in SELECT list
+// order, for when it has no rows to take them from. They are known for an
+// explicit SELECT list and for SELECT * from a file, in the field order of
+// its schema, or from a subquery whose columns are known. nil is returned
+// otherwise, as for SELECT * from a CTE or a join.
+func (ctx *ExecutionContext) resultColumns(q *Query) []string {
+	if columns := SelectColumns(q.SelectList); columns != nil {
+		return columns
+	}
+	star := false
+	if len(q.SelectList) == 1 {
+		colRef, ok := q.SelectList[0].Expr.(*ColumnRef)
+		star = ok && colRef.Column == "*"
+	}
+	if !star || len(q.Joins) > 0 {
+		return nil
+	}
+	if q.Subquery != nil {
+		return aliasColumns(ctx.resultColumns(q.Subquery), q.TableAlias)
+	}
+	if _, isCTE := ctx.CTEs[q.TableName]; isCTE || ctx.AllCTENames[q.TableName] {
+		return nil
+	}
+	columns, err := TableColumns(q.TableName)
+	if err != nil {
+		return nil
+	}
+	return aliasColumns(columns, q.TableAlias)
+}
+
+// sameColumns reports whether rowColumns, the columns of some rows, are
+// columns in any order. Rows with no columns match anything.
+func sameColumns(columns, rowColumns []string) bool {
+	if rowColumns == nil {
+		return true
+	}
+	if len(columns) != len(rowColumns) {
+		return false
+	}
+	expected := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		expected[col] = true
+	}
+	for _, col := range rowColumns {
+		if !expected[col] {
+			return false
+		}
+	}
+	return true
+}
+
+// renameSetColumns renames the columns of the rows of a set operation arm
+// to those of the first query by position
+func renameSetColumns(rows []map[string]interface{}, armColumns, columns []string) []map[string]interface{} {
+	renamed := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		newRow := make(map[string]interface{}, len(columns))
+		for j, col := range armColumns {
+			newRow[columns[j]] = row[col]
+		}
+		renamed[i] = newRow
+	}
+	return renamed
+}
+
+// checkSetColumns reports an error unless a set operation arm, the arm-th
+// query of the statement, returns as many columns as the first query. Arms
+// whose columns are ordered are matched by position; otherwise they must
+// also have the same column names.
+func checkSetColumns(columns, armColumns []string, ordered bool, op SetOperator, arm int) error {
+	if len(armColumns) != len(columns) {
+		return fmt.Errorf("%s query %d returns %d columns but the first query returns %d",
+			op, arm, len(armColumns), len(columns))
+	}
+	if ordered {
+		return nil
+	}
+	expected := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		expected[col] = true
+	}
+	for _, col := range armColumns {
+		if !expected[col] {
+			return fmt.Errorf("%s query %d returns column %q, which the first query does not; list the columns of SELECT * from a CTE or join so they can be matched by position",
+				op, arm, col)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_Union(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantOps []SetOperator
+		wantErr string
+	}{
+		{
+			name:    "union",
+			sql:     "select id from a.parquet union select id from b.parquet",
+			wantOps: []SetOperator{SetUnion},
+		},
+		{
+			name:    "union all",
+			sql:     "SELECT id FROM a.parquet UNION ALL SELECT id FROM b.parquet",
+			wantOps: []SetOperator{SetUnionAll},
+		},
+		{
+			name:    "chained",
+			sql:     "select id from a.parquet union all select id from b.parquet union select id from c.parquet",
+			wantOps: []SetOperator{SetUnionAll, SetUnion},
+		},
+		{
+			name:    "star arms are checked when executed",
+			sql:     "select * from a.parquet union select id, name from b.parquet",
+			wantOps: []SetOperator{SetUnion},
+		},
+		{
+			name:    "column count mismatch",
+			sql:     "select id from a.parquet union select id, name from b.parquet",
+			wantErr: "same number of columns",
+		},
+		{
+			name:    "missing select",
+			sql:     "select id from a.parquet union all from b.parquet",
+			wantErr: "UNION ALL",
+		},
+		{
+			name:    "order by before union",
+			sql:     "select id from a.parquet order by id union select id from b.parquet",
+			wantErr: "trailing tokens",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.sql)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(q.SetOperations) != len(tt.wantOps) {
+				t.Fatalf("got %d set operations, want %d", len(q.SetOperations), len(tt.wantOps))
+			}
+			for i, op := range q.SetOperations {
+				if op.Operator != tt.wantOps[i] {
+					t.Errorf("operation %d: got %s, want %s", i, op.Operator, tt.wantOps[i])
+				}
+				if op.Query == nil || op.Query.OrderBy != nil || op.Query.Limit != nil {
+					t.Errorf("operation %d: arm should be a plain SELECT, got %+v", i, op.Query)
+				}
+			}
+		})
+	}
+}
+
+func TestParser_UnionOrderByAppliesToResult(t *testing.T) {
+	q, err := Parse("select id from a.parquet union all select id from b.parquet order by id desc limit 3 offset 1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(q.OrderBy) != 1 || q.Limit == nil || *q.Limit != 3 || q.Offset == nil || *q.Offset != 1 {
+		t.Errorf("expected ORDER BY, LIMIT and OFFSET on the outer query, got %+v", q)
+	}
+	arm := q.SetOperations[0].Query
+	if len(arm.OrderBy) != 0 || arm.Limit != nil || arm.Offset != nil {
+		t.Errorf("expected no ORDER BY, LIMIT or OFFSET on the arm, got %+v", arm)
+	}
+}
+
+func TestExecuteQuery_Union(t *testing.T) {
+	dir := t.TempDir()
+	fileA := createNamedBasicParquetFile(t, dir, "a.parquet", []BasicDataRow{
+		{ID: 1, Name: "Alice", Age: 30},
+		{ID: 2, Name: "Bob", Age: 25},
+	})
+	fileB := createNamedBasicParquetFile(t, dir, "b.parquet", []BasicDataRow{
+		{ID: 2, Name: "Bob", Age: 25},
+		{ID: 3, Name: "Charlie", Age: 35},
+		{ID: 3, Name: "Charlie", Age: 35},
+	})
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "union all keeps duplicates",
+			sql:  "select name from 'A' union all select name from 'B'",
+			want: []string{"Alice", "Bob", "Bob", "Charlie", "Charlie"},
+		},
+		{
+			name: "union removes duplicates",
+			sql:  "select name from 'A' union select name from 'B'",
+			want: []string{"Alice", "Bob", "Charlie"},
+		},
+		{
+			name: "union removes duplicates within one arm",
+			sql:  "select name from 'B' union select name from 'B' where age > 30",
+			want: []string{"Bob", "Charlie"},
+		},
+		{
+			name: "union dedupes everything before it",
+			sql:  "select name from 'A' union all select name from 'B' union select name from 'A' where id = 1",
+			want: []string{"Alice", "Bob", "Charlie"},
+		},
+		{
+			name: "union all after union keeps new duplicates",
+			sql:  "select name from 'A' union select name from 'B' union all select name from 'A' where id = 1",
+			want: []string{"Alice", "Bob", "Charlie", "Alice"},
+		},
+		{
+			name: "order by and limit apply to the combined rows",
+			sql:  "select name, age from 'A' union all select name, age from 'B' order by age desc limit 3",
+			want: []string{"Charlie", "Charlie", "Alice"},
+		},
+		{
+			name: "arms with their own filters and aliases",
+			sql:  "select UPPER(name) as n from 'A' where id = 1 union all select LOWER(name) as n from 'B' where id = 3 limit 2",
+			want: []string{"ALICE", "charlie"},
+		},
+		{
+			name: "empty first arm",
+			sql:  "select name from 'A' where id > 10 union select name from 'B'",
+			want: []string{"Bob", "Charlie"},
+		},
+		{
+			name: "union in FROM subquery",
+			sql:  "select name from (select name, age from 'A' union select name, age from 'B') where age >= 30",
+			want: []string{"Alice", "Charlie"},
+		},
+		{
+			name: "union in IN subquery",
+			sql:  "select name from 'A' where id in (select id from 'B' where id = 2 union select id from 'B' where id = 3)",
+			want: []string{"Bob"},
+		},
+		{
+			name: "union in CTE",
+			sql:  "with everyone as (select name from 'A' union select name from 'B') select name from everyone order by name desc",
+			want: []string{"Charlie", "Bob", "Alice"},
+		},
+		{
+			name: "columns are matched by position, not name",
+			sql:  "select name from 'A' where id = 1 union all select cast(id as string) from 'B' where id = 2",
+			want: []string{"Alice", "2"},
+		},
+		{
+			name: "arms selecting columns in a different order",
+			sql:  "select id, name from 'A' where id = 1 union all select age, name from 'B' where id = 3 limit 2",
+			want: []string{"Alice", "Charlie"},
+		},
+		{
+			name: "star arm over a file uses schema order",
+			sql:  "select id, name, age, salary, active, score from 'A' where id = 1 union all select * from 'B' where id = 2",
+			want: []string{"Alice", "Bob"},
+		},
+		{
+			name:    "different column counts with star",
+			sql:     "select * from 'A' union all select name from 'B'",
+			wantErr: "UNION ALL query 2 returns 1 columns but the first query returns 6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := strings.NewReplacer("'A'", "'"+fileA+"'", "'B'", "'"+fileB+"'").Replace(tt.sql)
+			q, err := Parse(sql)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			rows, err := ExecuteQuery(q, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+
+			var got []string
+			for _, row := range rows {
+				name, ok := row["name"].(string)
+				if !ok {
+					name, _ = row["n"].(string)
+				}
+				got = append(got, name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteOnRows_Union(t *testing.T) {
+	q, err := Parse("select id from a.parquet union select id from b.parquet")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := ExecuteOnRows(q, []map[string]interface{}{{"id": int64(1)}}); err == nil {
+		t.Error("expected an error for a UNION query")
+	}
+}
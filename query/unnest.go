@@ -0,0 +1,97 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// unnestColumn names the element column of an UNNEST without an alias
+const unnestColumn = "unnest"
+
+// UnnestJoin runs a JOIN of UNNEST(expr), as in
+//
+//	SELECT tag, COUNT(*) FROM t CROSS JOIN UNNEST(tags) AS tag GROUP BY tag
+//
+// joining each left row with the elements of the array expr evaluates to on
+// that row, one row per element. The element is held in a column named by
+// the join's alias, or "unnest" without one. A NULL or empty array gives no
+// rows, and a LEFT JOIN keeps the left row with a NULL element instead.
+// The ON clause of an INNER or LEFT JOIN is optional; without one, every
+// element is joined.
+// Like the other joins, it returns the columns of an empty result.
+func (ctx *ExecutionContext) UnnestJoin(leftRows []map[string]interface{}, leftColumns []string, join Join) ([]map[string]interface{}, []string, error) {
+	column := join.Alias
+	if column == "" {
+		column = unnestColumn
+	}
+	if join.Type != JoinCross && join.Type != JoinInner && join.Type != JoinLeft {
+		return nil, nil, fmt.Errorf("UNNEST can only be CROSS, INNER or LEFT joined")
+	}
+	if join.UsingColumns != nil {
+		return nil, nil, fmt.Errorf("UNNEST cannot be joined with USING")
+	}
+
+	var result []map[string]interface{}
+	for _, leftRow := range leftRows {
+		if _, ok := leftRow[column]; ok {
+			return nil, nil, fmt.Errorf("UNNEST column %s is already a column of the joined rows; give UNNEST another alias", column)
+		}
+
+		value, err := ctx.EvaluateSelectExpression(leftRow, join.Unnest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("evaluating UNNEST: %w", err)
+		}
+		elements, err := unnestElements(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		rightRows := make([]map[string]interface{}, len(elements))
+		for i, element := range elements {
+			rightRows[i] = map[string]interface{}{column: element}
+		}
+
+		// The right side depends on the left row, so it is joined one row
+		// at a time
+		left := []map[string]interface{}{leftRow}
+		var rows []map[string]interface{}
+		switch {
+		case join.Condition == nil && join.Type == JoinLeft && len(rightRows) == 0:
+			rows, err = ctx.executeLeftJoin(left, nil, []string{column}, nil)
+		case join.Condition == nil:
+			rows, err = ctx.executeCrossJoin(left, rightRows)
+		case join.Type == JoinInner:
+			rows, err = ctx.executeInnerJoin(left, rightRows, join.Condition)
+		default:
+			rows, err = ctx.executeLeftJoin(left, rightRows, []string{column}, join.Condition)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ctx.checkRowLimit(len(result)+len(rows), "UNNEST"); err != nil {
+			return nil, nil, err
+		}
+		result = append(result, rows...)
+	}
+
+	var columns []string
+	if len(result) == 0 {
+		columns = append(columnNames(leftRows, leftColumns), column)
+	}
+	return result, columns, nil
+}
+
+// unnestElements returns the elements of an array value, or none for NULL
+func unnestElements(value interface{}) ([]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("UNNEST needs an array, got %v (%T)", value, value)
+	}
+	elements := make([]interface{}, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, nil
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParquetUnnest(t *testing.T) {
+	testFile := createComplexParquetFile(t, []ComplexDataRow{
+		{ID: 1, Name: "Alice", Timestamp: time.Now(), Tags: []string{"engineer", "senior"}},
+		{ID: 2, Name: "Bob", Timestamp: time.Now(), Tags: []string{"engineer"}},
+		{ID: 3, Name: "Charlie", Timestamp: time.Now(), Tags: []string{"manager", "senior", "remote"}},
+		{ID: 4, Name: "Diana", Timestamp: time.Now(), Tags: []string{}},
+	})
+	from := " FROM '" + testFile + "' "
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "element access with out-of-range indexes",
+			query: "SELECT id, tags[0] AS first, tags[-1] AS last, tags[2] AS third, tags[3] AS past" + from + "ORDER BY id",
+			want: []map[string]interface{}{
+				{"id": int64(1), "first": "engineer", "last": "senior", "third": nil, "past": nil},
+				{"id": int64(2), "first": "engineer", "last": "engineer", "third": nil, "past": nil},
+				{"id": int64(3), "first": "manager", "last": "remote", "third": "remote", "past": nil},
+				{"id": int64(4), "first": nil, "last": nil, "third": nil, "past": nil},
+			},
+		},
+		{
+			name:  "one row per element",
+			query: "SELECT id, tag" + from + "CROSS JOIN UNNEST(tags) AS tag",
+			want: []map[string]interface{}{
+				{"id": int64(1), "tag": "engineer"}, {"id": int64(1), "tag": "senior"},
+				{"id": int64(2), "tag": "engineer"},
+				{"id": int64(3), "tag": "manager"}, {"id": int64(3), "tag": "senior"}, {"id": int64(3), "tag": "remote"},
+			},
+		},
+		{
+			name:  "counting tags",
+			query: "SELECT tag, COUNT(*) AS n" + from + "CROSS JOIN UNNEST(tags) tag GROUP BY tag ORDER BY n DESC, tag",
+			want: []map[string]interface{}{
+				{"tag": "engineer", "n": int64(2)}, {"tag": "senior", "n": int64(2)},
+				{"tag": "manager", "n": int64(1)}, {"tag": "remote", "n": int64(1)},
+			},
+		},
+		{
+			name:  "left join keeps empty arrays",
+			query: "SELECT id, tag" + from + "LEFT JOIN UNNEST(tags) AS tag WHERE id >= 2",
+			want: []map[string]interface{}{
+				{"id": int64(2), "tag": "engineer"},
+				{"id": int64(3), "tag": "manager"}, {"id": int64(3), "tag": "senior"}, {"id": int64(3), "tag": "remote"},
+				{"id": int64(4), "tag": nil},
+			},
+		},
+		{
+			name:  "join condition and default column name",
+			query: "SELECT p.name, unnest" + from + "p JOIN UNNEST(p.tags) ON unnest = 'senior'",
+			want:  []map[string]interface{}{{"p.name": "Alice", "unnest": "senior"}, {"p.name": "Charlie", "unnest": "senior"}},
+		},
+		{
+			name:    "not an array",
+			query:   "SELECT *" + from + "CROSS JOIN UNNEST(name) AS n",
+			wantErr: "UNNEST needs an array, got Alice (string)",
+		},
+		{
+			name:    "column clash",
+			query:   "SELECT *" + from + "CROSS JOIN UNNEST(tags) AS name",
+			wantErr: "UNNEST column name is already a column",
+		},
+		{
+			name:    "right join",
+			query:   "SELECT *" + from + "RIGHT JOIN UNNEST(tags) AS tag ON tag = 'x'",
+			wantErr: "UNNEST can only be CROSS, INNER or LEFT joined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			rows, err := ExecuteQuery(q, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExecuteQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("ExecuteQuery() = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
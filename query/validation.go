@@ -21,6 +21,10 @@ const (
 
 	// MaxTableNameLength is the maximum length for a table name
 	MaxTableNameLength = 4096 // Allow long file paths
+
+	// DefaultMaxIntermediateRows is the default for
+	// ExecutionContext.MaxIntermediateRows
+	DefaultMaxIntermediateRows = 10_000_000
 )
 
 var (
@@ -41,6 +45,10 @@ var (
 
 	// ErrEmptyTableName is returned when table name is empty
 	ErrEmptyTableName = errors.New("table name cannot be empty")
+
+	// ErrTooManyRows is returned when a JOIN or aggregation produces more
+	// rows than ExecutionContext.MaxIntermediateRows
+	ErrTooManyRows = errors.New("too many intermediate rows")
 )
 
 // ValidateQuery performs security validation on query input
@@ -33,10 +33,18 @@ func hasSubqueryInExpression(expr Expression) bool {
 	switch e := expr.(type) {
 	case *InSubqueryExpr:
 		return true
+	case *QuantifiedSubqueryExpr:
+		return true
 	case *ExistsExpr:
 		return true
 	case *BinaryExpr:
 		return hasSubqueryInExpression(e.Left) || hasSubqueryInExpression(e.Right)
+	case *NotExpr:
+		return hasSubqueryInExpression(e.Expr)
+	case *ExpressionComparisonExpr:
+		return hasScalarSubquery(e.Left) || hasScalarSubquery(e.Right)
+	case *DistinctExpr:
+		return hasScalarSubquery(e.Left) || hasScalarSubquery(e.Right)
 	default:
 		return false
 	}
@@ -69,6 +77,14 @@ func hasScalarSubquery(expr SelectExpression) bool {
 			}
 		}
 		return false
+	case *IndexExpr:
+		return hasScalarSubquery(e.Expr) || hasScalarSubquery(e.Index)
+	case *ExtractExpr:
+		return hasScalarSubquery(e.Expr)
+	case *ArithmeticExpr:
+		return hasScalarSubquery(e.Left) || hasScalarSubquery(e.Right)
+	case *ConcatExpr:
+		return hasScalarSubquery(e.Left) || hasScalarSubquery(e.Right)
 	case *CaseExpr:
 		// Check ELSE expression
 		if e.ElseExpr != nil && hasScalarSubquery(e.ElseExpr) {
@@ -234,16 +250,10 @@ func sortPartition(partition []rowInfo, orderBy []OrderByItem) []rowInfo {
 	sorted := make([]rowInfo, len(partition))
 	copy(sorted, partition)
 
-	sort.Slice(sorted, func(i, j int) bool {
+	// Stable, so ties keep their input order and ROWS frames are deterministic
+	sort.SliceStable(sorted, func(i, j int) bool {
 		for _, item := range orderBy {
-			valI := sorted[i].row[item.Column]
-			valJ := sorted[j].row[item.Column]
-
-			cmp := compareValues(valI, valJ)
-			if cmp != 0 {
-				if item.Desc {
-					return cmp > 0
-				}
+			if cmp := compareOrderBy(sorted[i].row[item.Column], sorted[j].row[item.Column], item); cmp != 0 {
 				return cmp < 0
 			}
 		}
@@ -256,6 +266,9 @@ func sortPartition(partition []rowInfo, orderBy []OrderByItem) []rowInfo {
 // computeWindowFunctionForPartition computes a window function for a sorted partition
 func computeWindowFunctionForPartition(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
 	function := windowExpr.Function
+	if windowExpr.Aggregate != nil {
+		return computeWindowAggregate(partition, windowExpr)
+	}
 
 	switch function {
 	case "ROW_NUMBER":
@@ -281,6 +294,120 @@ func computeWindowFunctionForPartition(partition []rowInfo, windowExpr *WindowEx
 	}
 }
 
+// computeWindowAggregate computes an aggregate OVER a window for each row of
+// a sorted partition, over the rows of that row's frame
+func computeWindowAggregate(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
+	rows := make([]map[string]interface{}, len(partition))
+	for i, info := range partition {
+		rows[i] = info.row
+	}
+	peerStart, peerEnd := peerGroups(partition, windowExpr.Window.OrderBy)
+
+	results := make([]interface{}, len(partition))
+	lastStart, lastEnd := -1, -1
+	var last interface{}
+	for i := range partition {
+		start, end, err := frameBounds(windowExpr.Window, i, len(partition), peerStart[i], peerEnd[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", windowExpr.Function, err)
+		}
+
+		// Rows sharing a frame, such as peers or a whole-partition frame, share its value
+		if start != lastStart || end != lastEnd {
+			last, err = evaluateAggregate(windowExpr.Aggregate, rows[start:end])
+			if err != nil {
+				return nil, err
+			}
+			lastStart, lastEnd = start, end
+		}
+		results[i] = last
+	}
+
+	return results, nil
+}
+
+// peerGroups returns, for each row of a sorted partition, the index of the
+// first row and one past the last row that tie with it on ORDER BY. Without
+// ORDER BY every row of the partition is a peer.
+func peerGroups(partition []rowInfo, orderBy []OrderByItem) (start, end []int) {
+	start = make([]int, len(partition))
+	end = make([]int, len(partition))
+	for i := range partition {
+		if i > 0 && (len(orderBy) == 0 || rowsEqualOnOrderBy(partition[i-1].row, partition[i].row, orderBy)) {
+			start[i] = start[i-1]
+		} else {
+			start[i] = i
+		}
+	}
+	for i := len(partition) - 1; i >= 0; i-- {
+		if i+1 < len(partition) && start[i+1] == start[i] {
+			end[i] = end[i+1]
+		} else {
+			end[i] = i + 1
+		}
+	}
+	return start, end
+}
+
+// frameBounds returns the half-open range [start, end) of partition rows in
+// the frame of row i. Without a frame clause the frame is the whole partition,
+// or with ORDER BY the rows up to and including the current row's peers
+// (RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW).
+func frameBounds(spec *WindowSpec, i, n, peerStart, peerEnd int) (int, int, error) {
+	frame := spec.Frame
+	if frame == nil {
+		if len(spec.OrderBy) == 0 {
+			return 0, n, nil
+		}
+		return 0, peerEnd, nil
+	}
+
+	if frame.Start.Type == BoundUnboundedFollowing {
+		return 0, 0, fmt.Errorf("window frame cannot start at UNBOUNDED FOLLOWING")
+	}
+	if frame.End.Type == BoundUnboundedPreceding {
+		return 0, 0, fmt.Errorf("window frame cannot end at UNBOUNDED PRECEDING")
+	}
+	if frame.Type == FrameTypeRange {
+		for _, bound := range []FrameBound{frame.Start, frame.End} {
+			if bound.Type == BoundOffsetPreceding || bound.Type == BoundOffsetFollowing {
+				return 0, 0, fmt.Errorf("RANGE frames with an offset are not supported, use ROWS")
+			}
+		}
+	}
+
+	// position returns the first row of the frame a bound describes; for the
+	// end bound, the caller adds one to make the range half-open
+	position := func(bound FrameBound, isEnd bool) int {
+		switch bound.Type {
+		case BoundUnboundedPreceding:
+			return 0
+		case BoundOffsetPreceding:
+			return i - int(bound.Offset)
+		case BoundOffsetFollowing:
+			return i + int(bound.Offset)
+		case BoundUnboundedFollowing:
+			return n - 1
+		default: // BoundCurrentRow
+			if frame.Type == FrameTypeRange {
+				if isEnd {
+					return peerEnd - 1
+				}
+				return peerStart
+			}
+			return i
+		}
+	}
+
+	start := max(position(frame.Start, false), 0)
+	end := min(position(frame.End, true)+1, n)
+	if start >= end {
+		// Empty frame, e.g. 3 PRECEDING AND 2 PRECEDING on the first rows
+		return 0, 0, nil
+	}
+	return start, end, nil
+}
+
 // computeRowNumber computes ROW_NUMBER() for a partition
 func computeRowNumber(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
 	results := make([]interface{}, len(partition))
@@ -420,57 +547,26 @@ func computeNTile(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, e
 	return results, nil
 }
 
-// computeFirstValue computes FIRST_VALUE(expr) for a partition
+// computeFirstValue computes FIRST_VALUE(expr), the value of the first row of each row's frame
 func computeFirstValue(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
-	if len(partition) == 0 {
-		return []interface{}{}, nil
-	}
-
 	if len(windowExpr.Args) != 1 {
 		return nil, fmt.Errorf("FIRST_VALUE requires exactly one argument")
 	}
-
-	// Evaluate the expression on the first row
-	firstValue, err := windowExpr.Args[0].EvaluateSelect(partition[0].row)
-	if err != nil {
-		return nil, fmt.Errorf("FIRST_VALUE: failed to evaluate argument: %w", err)
-	}
-
-	// Return the first value for all rows
-	results := make([]interface{}, len(partition))
-	for i := range results {
-		results[i] = firstValue
-	}
-
-	return results, nil
+	return computeFrameValue(partition, windowExpr, func(start, end int) int { return start })
 }
 
-// computeLastValue computes LAST_VALUE(expr) for a partition
+// computeLastValue computes LAST_VALUE(expr), the value of the last row of
+// each row's frame. With ORDER BY and no frame clause that is the current row
+// or its last tie, not the end of the partition.
 func computeLastValue(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
-	if len(partition) == 0 {
-		return []interface{}{}, nil
-	}
-
 	if len(windowExpr.Args) != 1 {
 		return nil, fmt.Errorf("LAST_VALUE requires exactly one argument")
 	}
-
-	// Evaluate the expression on the last row
-	lastValue, err := windowExpr.Args[0].EvaluateSelect(partition[len(partition)-1].row)
-	if err != nil {
-		return nil, fmt.Errorf("LAST_VALUE: failed to evaluate argument: %w", err)
-	}
-
-	// Return the last value for all rows
-	results := make([]interface{}, len(partition))
-	for i := range results {
-		results[i] = lastValue
-	}
-
-	return results, nil
+	return computeFrameValue(partition, windowExpr, func(start, end int) int { return end - 1 })
 }
 
-// computeNthValue computes NTH_VALUE(expr, n) for a partition
+// computeNthValue computes NTH_VALUE(expr, n), the value of the nth row
+// (1-indexed) of each row's frame, or NULL if the frame has fewer rows
 func computeNthValue(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
 	if len(partition) == 0 {
 		return []interface{}{}, nil
@@ -491,90 +587,70 @@ func computeNthValue(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}
 		return nil, fmt.Errorf("NTH_VALUE: position argument must be a number")
 	}
 	n := int(nFloat)
-
-	if n <= 0 || n > len(partition) {
-		// Return NULL for all rows if n is out of range
-		results := make([]interface{}, len(partition))
-		return results, nil
-	}
-
-	// Evaluate the expression on the nth row (1-indexed)
-	nthValue, err := windowExpr.Args[0].EvaluateSelect(partition[n-1].row)
-	if err != nil {
-		return nil, fmt.Errorf("NTH_VALUE: failed to evaluate argument: %w", err)
-	}
-
-	// Return the nth value for all rows
-	results := make([]interface{}, len(partition))
-	for i := range results {
-		results[i] = nthValue
+	if n <= 0 {
+		return nil, fmt.Errorf("NTH_VALUE: position must be positive, got %d", n)
 	}
 
-	return results, nil
+	return computeFrameValue(partition, windowExpr, func(start, end int) int {
+		if start+n > end {
+			return -1
+		}
+		return start + n - 1
+	})
 }
 
-// computeLag computes LAG(expr, offset, default) for a partition
-func computeLag(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
-	if len(partition) == 0 {
-		return []interface{}{}, nil
-	}
-
-	if len(windowExpr.Args) < 1 || len(windowExpr.Args) > 3 {
-		return nil, fmt.Errorf("LAG requires 1-3 arguments")
-	}
+// computeFrameValue evaluates the first argument of a window function on one
+// row of each row's frame, chosen by pick from the frame's [start, end) range.
+// Rows whose frame is empty, or for which pick returns -1, get NULL.
+func computeFrameValue(partition []rowInfo, windowExpr *WindowExpr, pick func(start, end int) int) ([]interface{}, error) {
+	peerStart, peerEnd := peerGroups(partition, windowExpr.Window.OrderBy)
 
-	// Get offset (default 1)
-	offset := int64(1)
-	if len(windowExpr.Args) >= 2 {
-		offsetArg, err := windowExpr.Args[1].EvaluateSelect(partition[0].row)
+	results := make([]interface{}, len(partition))
+	for i := range partition {
+		start, end, err := frameBounds(windowExpr.Window, i, len(partition), peerStart[i], peerEnd[i])
 		if err != nil {
-			return nil, fmt.Errorf("LAG: failed to evaluate offset argument: %w", err)
+			return nil, fmt.Errorf("%s: %w", windowExpr.Function, err)
 		}
-		offsetFloat, ok := toFloat64(offsetArg)
-		if !ok {
-			return nil, fmt.Errorf("LAG: offset argument must be a number")
+		if start >= end {
+			continue
 		}
-		offset = int64(offsetFloat)
-		if offset < 0 {
-			return nil, fmt.Errorf("LAG: offset must be non-negative, got %d", offset)
+		target := pick(start, end)
+		if target < 0 {
+			continue
 		}
-	}
 
-	// Get default value (default NULL)
-	var defaultValue interface{} = nil
-	if len(windowExpr.Args) == 3 {
-		var err error
-		defaultValue, err = windowExpr.Args[2].EvaluateSelect(partition[0].row)
+		value, err := windowExpr.Args[0].EvaluateSelect(partition[target].row)
 		if err != nil {
-			return nil, fmt.Errorf("LAG: failed to evaluate default argument: %w", err)
-		}
-	}
-
-	results := make([]interface{}, len(partition))
-	for i := range partition {
-		lagIndex := int64(i) - offset
-		if lagIndex < 0 {
-			results[i] = defaultValue
-		} else {
-			value, err := windowExpr.Args[0].EvaluateSelect(partition[lagIndex].row)
-			if err != nil {
-				return nil, fmt.Errorf("LAG: failed to evaluate expression: %w", err)
-			}
-			results[i] = value
+			return nil, fmt.Errorf("%s: failed to evaluate argument: %w", windowExpr.Function, err)
 		}
+		results[i] = value
 	}
 
 	return results, nil
 }
 
+// computeLag computes LAG(expr, offset, default) for a partition
+func computeLag(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
+	return computeOffsetValue(partition, windowExpr, -1)
+}
+
 // computeLead computes LEAD(expr, offset, default) for a partition
 func computeLead(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, error) {
+	return computeOffsetValue(partition, windowExpr, 1)
+}
+
+// computeOffsetValue returns expr evaluated offset rows before (direction -1)
+// or after (direction 1) each row of a partition. The offset defaults to 1.
+// Rows whose target falls outside the partition get the default argument,
+// evaluated on the current row, or nil without one.
+func computeOffsetValue(partition []rowInfo, windowExpr *WindowExpr, direction int64) ([]interface{}, error) {
+	name := windowExpr.Function
 	if len(partition) == 0 {
 		return []interface{}{}, nil
 	}
 
 	if len(windowExpr.Args) < 1 || len(windowExpr.Args) > 3 {
-		return nil, fmt.Errorf("LEAD requires 1-3 arguments")
+		return nil, fmt.Errorf("%s requires 1-3 arguments", name)
 	}
 
 	// Get offset (default 1)
@@ -582,40 +658,37 @@ func computeLead(partition []rowInfo, windowExpr *WindowExpr) ([]interface{}, er
 	if len(windowExpr.Args) >= 2 {
 		offsetArg, err := windowExpr.Args[1].EvaluateSelect(partition[0].row)
 		if err != nil {
-			return nil, fmt.Errorf("LEAD: failed to evaluate offset argument: %w", err)
+			return nil, fmt.Errorf("%s: failed to evaluate offset argument: %w", name, err)
 		}
 		offsetFloat, ok := toFloat64(offsetArg)
 		if !ok {
-			return nil, fmt.Errorf("LEAD: offset argument must be a number")
+			return nil, fmt.Errorf("%s: offset argument must be a number", name)
 		}
 		offset = int64(offsetFloat)
 		if offset < 0 {
-			return nil, fmt.Errorf("LEAD: offset must be non-negative, got %d", offset)
-		}
-	}
-
-	// Get default value (default NULL)
-	var defaultValue interface{} = nil
-	if len(windowExpr.Args) == 3 {
-		var err error
-		defaultValue, err = windowExpr.Args[2].EvaluateSelect(partition[0].row)
-		if err != nil {
-			return nil, fmt.Errorf("LEAD: failed to evaluate default argument: %w", err)
+			return nil, fmt.Errorf("%s: offset must be non-negative, got %d", name, offset)
 		}
 	}
 
 	results := make([]interface{}, len(partition))
 	for i := range partition {
-		leadIndex := int64(i) + offset
-		if leadIndex >= int64(len(partition)) {
-			results[i] = defaultValue
-		} else {
-			value, err := windowExpr.Args[0].EvaluateSelect(partition[leadIndex].row)
-			if err != nil {
-				return nil, fmt.Errorf("LEAD: failed to evaluate expression: %w", err)
+		target := int64(i) + direction*offset
+		if target < 0 || target >= int64(len(partition)) {
+			if len(windowExpr.Args) == 3 {
+				value, err := windowExpr.Args[2].EvaluateSelect(partition[i].row)
+				if err != nil {
+					return nil, fmt.Errorf("%s: failed to evaluate default argument: %w", name, err)
+				}
+				results[i] = value
 			}
-			results[i] = value
+			continue
+		}
+
+		value, err := windowExpr.Args[0].EvaluateSelect(partition[target].row)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to evaluate expression: %w", name, err)
 		}
+		results[i] = value
 	}
 
 	return results, nil
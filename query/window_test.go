@@ -1,6 +1,7 @@
 package query
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -230,7 +231,15 @@ func TestLAST_VALUE(t *testing.T) {
 		{Expr: &WindowExpr{
 			Function: "LAST_VALUE",
 			Args:     []SelectExpression{&ColumnRef{Column: "salary"}},
-			Window:   &WindowSpec{OrderBy: []OrderByItem{{Column: "salary", Desc: false}}},
+			Window: &WindowSpec{
+				OrderBy: []OrderByItem{{Column: "salary", Desc: false}},
+				// The default frame ends at the current row
+				Frame: &WindowFrame{
+					Type:  FrameTypeRows,
+					Start: FrameBound{Type: BoundUnboundedPreceding},
+					End:   FrameBound{Type: BoundUnboundedFollowing},
+				},
+			},
 		}, Alias: "last_salary"},
 	}
 
@@ -263,7 +272,15 @@ func TestNTH_VALUE(t *testing.T) {
 				&ColumnRef{Column: "salary"},
 				&LiteralExpr{Value: int64(2)},
 			},
-			Window: &WindowSpec{OrderBy: []OrderByItem{{Column: "salary", Desc: false}}},
+			Window: &WindowSpec{
+				OrderBy: []OrderByItem{{Column: "salary", Desc: false}},
+				// The default frame ends at the current row
+				Frame: &WindowFrame{
+					Type:  FrameTypeRows,
+					Start: FrameBound{Type: BoundUnboundedPreceding},
+					End:   FrameBound{Type: BoundUnboundedFollowing},
+				},
+			},
 		}, Alias: "second_salary"},
 	}
 
@@ -281,6 +298,87 @@ func TestNTH_VALUE(t *testing.T) {
 	}
 }
 
+func TestFrameValueFunctions(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(3), "v": "c"},
+		{"id": int64(1), "v": "a"},
+		{"id": int64(5), "v": "e"},
+		{"id": int64(2), "v": "b"},
+		{"id": int64(4), "v": "d"},
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want []interface{} // per input row, in input order
+	}{
+		{
+			name: "NTILE(2) of 5 rows gives buckets of 3 and 2",
+			expr: "NTILE(2) OVER (ORDER BY id)",
+			want: []interface{}{int64(1), int64(1), int64(2), int64(1), int64(2)},
+		},
+		{
+			name: "FIRST_VALUE",
+			expr: "FIRST_VALUE(v) OVER (ORDER BY id)",
+			want: []interface{}{"a", "a", "a", "a", "a"},
+		},
+		{
+			name: "LAST_VALUE with the default frame is the current row",
+			expr: "LAST_VALUE(v) OVER (ORDER BY id)",
+			want: []interface{}{"c", "a", "e", "b", "d"},
+		},
+		{
+			name: "LAST_VALUE over the whole partition",
+			expr: "LAST_VALUE(v) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)",
+			want: []interface{}{"e", "e", "e", "e", "e"},
+		},
+		{
+			name: "LAST_VALUE without ORDER BY covers the partition",
+			expr: "LAST_VALUE(id) OVER ()",
+			want: []interface{}{int64(4), int64(4), int64(4), int64(4), int64(4)},
+		},
+		{
+			name: "FIRST_VALUE of a sliding frame",
+			expr: "FIRST_VALUE(v) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)",
+			want: []interface{}{"b", "a", "d", "a", "c"},
+		},
+		{
+			name: "NTH_VALUE is NULL until the frame has n rows",
+			expr: "NTH_VALUE(v, 2) OVER (ORDER BY id)",
+			want: []interface{}{"b", nil, "b", "b", "b"},
+		},
+		{
+			name: "NTH_VALUE past the frame",
+			expr: "NTH_VALUE(v, 3) OVER (ORDER BY id ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)",
+			want: []interface{}{nil, nil, nil, nil, nil},
+		},
+		{
+			name: "empty frame",
+			expr: "FIRST_VALUE(v) OVER (ORDER BY id ROWS BETWEEN 2 FOLLOWING AND 3 FOLLOWING)",
+			want: []interface{}{"e", "c", nil, "d", nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("SELECT " + tt.expr + " AS result FROM test.parquet")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			result, err := ApplyWindowFunctions(rows, q.SelectList)
+			if err != nil {
+				t.Fatalf("ApplyWindowFunctions() error = %v", err)
+			}
+			for i, row := range result {
+				if got := row["result"]; got != tt.want[i] {
+					t.Errorf("row %d: result = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestLAG(t *testing.T) {
 	rows := []map[string]interface{}{
 		{"date": "2024-01-01", "value": 100},
@@ -375,6 +473,98 @@ func TestLEAD(t *testing.T) {
 	}
 }
 
+func TestLagLeadOffsets(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"grp": "a", "seq": int64(1), "value": int64(10)},
+		{"grp": "b", "seq": int64(1), "value": int64(100)},
+		{"grp": "a", "seq": int64(2), "value": int64(20)},
+		{"grp": "a", "seq": int64(3), "value": int64(30)},
+		{"grp": "b", "seq": int64(2), "value": int64(200)},
+	}
+
+	value := &ColumnRef{Column: "value"}
+	ordered := &WindowSpec{PartitionBy: []string{"grp"}, OrderBy: []OrderByItem{{Column: "seq"}}}
+
+	tests := []struct {
+		name    string
+		fn      string
+		args    []SelectExpression
+		want    []interface{} // per input row, in input order
+		wantErr bool
+	}{
+		{
+			name: "LAG defaults to an offset of 1",
+			fn:   "LAG",
+			args: []SelectExpression{value},
+			want: []interface{}{nil, nil, int64(10), int64(20), int64(100)},
+		},
+		{
+			name: "LEAD defaults to an offset of 1",
+			fn:   "LEAD",
+			args: []SelectExpression{value},
+			want: []interface{}{int64(20), int64(200), int64(30), nil, nil},
+		},
+		{
+			name: "LAG with offset 2",
+			fn:   "LAG",
+			args: []SelectExpression{value, &LiteralExpr{Value: int64(2)}},
+			want: []interface{}{nil, nil, nil, int64(10), nil},
+		},
+		{
+			name: "LEAD with offset 0 is the current row",
+			fn:   "LEAD",
+			args: []SelectExpression{value, &LiteralExpr{Value: int64(0)}},
+			want: []interface{}{int64(10), int64(100), int64(20), int64(30), int64(200)},
+		},
+		{
+			name: "LAG with a default value",
+			fn:   "LAG",
+			args: []SelectExpression{value, &LiteralExpr{Value: int64(1)}, &LiteralExpr{Value: int64(0)}},
+			want: []interface{}{int64(0), int64(0), int64(10), int64(20), int64(100)},
+		},
+		{
+			name: "LEAD default is evaluated on the current row",
+			fn:   "LEAD",
+			args: []SelectExpression{value, &LiteralExpr{Value: int64(1)}, value},
+			want: []interface{}{int64(20), int64(200), int64(30), int64(30), int64(200)},
+		},
+		{
+			name:    "negative offset",
+			fn:      "LAG",
+			args:    []SelectExpression{value, &LiteralExpr{Value: int64(-1)}},
+			wantErr: true,
+		},
+		{
+			name:    "too many arguments",
+			fn:      "LEAD",
+			args:    []SelectExpression{value, value, value, value},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectList := []SelectItem{
+				{Expr: &WindowExpr{Function: tt.fn, Args: tt.args, Window: ordered}, Alias: "result"},
+			}
+
+			result, err := ApplyWindowFunctions(rows, selectList)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyWindowFunctions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for i, row := range result {
+				if got := row["result"]; got != tt.want[i] {
+					t.Errorf("row %d: result = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestWindowWithPartition(t *testing.T) {
 	rows := []map[string]interface{}{
 		{"dept": "Sales", "name": "Alice", "salary": 50000},
@@ -427,6 +617,107 @@ func TestWindowWithPartition(t *testing.T) {
 	}
 }
 
+func TestWindowAggregates(t *testing.T) {
+	// Input rows are deliberately not in ORDER BY order
+	rows := []map[string]interface{}{
+		{"dept": "a", "id": int64(2), "salary": int64(20)},
+		{"dept": "b", "id": int64(1), "salary": int64(100)},
+		{"dept": "a", "id": int64(1), "salary": int64(10)},
+		{"dept": "a", "id": int64(3), "salary": int64(30)},
+		{"dept": "b", "id": int64(2), "salary": nil},
+		{"dept": "a", "id": int64(3), "salary": int64(5)},
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    []interface{} // per input row, in input order
+		wantErr string
+	}{
+		{
+			name: "running total with ROWS frame",
+			expr: "SUM(salary) OVER (PARTITION BY dept ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+			want: []interface{}{30.0, 100.0, 10.0, 60.0, 100.0, 65.0},
+		},
+		{
+			name: "default frame with ORDER BY includes peers",
+			expr: "SUM(salary) OVER (PARTITION BY dept ORDER BY id)",
+			want: []interface{}{30.0, 100.0, 10.0, 65.0, 100.0, 65.0},
+		},
+		{
+			name: "no ORDER BY covers the whole partition",
+			expr: "COUNT(*) OVER (PARTITION BY dept)",
+			want: []interface{}{int64(4), int64(2), int64(4), int64(4), int64(2), int64(4)},
+		},
+		{
+			name: "COUNT skips nulls",
+			expr: "COUNT(salary) OVER (PARTITION BY dept)",
+			want: []interface{}{int64(4), int64(1), int64(4), int64(4), int64(1), int64(4)},
+		},
+		{
+			name: "moving average over offsets",
+			expr: "AVG(salary) OVER (PARTITION BY dept ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)",
+			want: []interface{}{20.0, 100.0, 15.0, 55.0 / 3, 100.0, 17.5},
+		},
+		{
+			name: "single bound ends at the current row",
+			expr: "MIN(salary) OVER (PARTITION BY dept ORDER BY id ROWS 1 PRECEDING)",
+			want: []interface{}{10.0, 100.0, 10.0, 20.0, 100.0, 5.0},
+		},
+		{
+			name: "frame to the end of the partition",
+			expr: "MAX(salary) OVER (PARTITION BY dept ORDER BY id ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING)",
+			want: []interface{}{30.0, 100.0, 30.0, 30.0, nil, 5.0},
+		},
+		{
+			name: "RANGE current row includes peers at the start",
+			expr: "SUM(salary) OVER (PARTITION BY dept ORDER BY id RANGE BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING)",
+			want: []interface{}{55.0, 100.0, 65.0, 35.0, nil, 35.0},
+		},
+		{
+			name: "empty frame",
+			expr: "COUNT(*) OVER (PARTITION BY dept ORDER BY id ROWS BETWEEN 3 PRECEDING AND 2 PRECEDING)",
+			want: []interface{}{int64(0), int64(0), int64(0), int64(1), int64(0), int64(2)},
+		},
+		{
+			name:    "RANGE with an offset",
+			expr:    "SUM(salary) OVER (ORDER BY id RANGE BETWEEN 1 PRECEDING AND CURRENT ROW)",
+			wantErr: "RANGE frames with an offset are not supported",
+		},
+		{
+			name:    "frame ending before it starts",
+			expr:    "SUM(salary) OVER (ORDER BY id ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING)",
+			wantErr: "cannot end at UNBOUNDED PRECEDING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("SELECT " + tt.expr + " AS result FROM test.parquet")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			result, err := ApplyWindowFunctions(rows, q.SelectList)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyWindowFunctions() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyWindowFunctions() error = %v", err)
+			}
+
+			for i, row := range result {
+				if got := row["result"]; got != tt.want[i] {
+					t.Errorf("row %d: result = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestParseWindowFunction(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -448,6 +739,31 @@ func TestParseWindowFunction(t *testing.T) {
 			query:   "SELECT date, value, LAG(value, 1) OVER (ORDER BY date) as prev FROM test.parquet",
 			wantErr: false,
 		},
+		{
+			name:    "QUALIFY on window result",
+			query:   "SELECT name, ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) rn FROM test.parquet QUALIFY rn <= 3",
+			wantErr: false,
+		},
+		{
+			name:    "QUALIFY without window function should fail",
+			query:   "SELECT name FROM test.parquet QUALIFY age > 30",
+			wantErr: true,
+		},
+		{
+			name:    "aggregate with OVER",
+			query:   "SELECT name, SUM(salary) OVER (PARTITION BY dept ORDER BY id ROWS 2 PRECEDING) AS total FROM test.parquet",
+			wantErr: false,
+		},
+		{
+			name:    "COUNT(*) with empty OVER",
+			query:   "SELECT name, COUNT(*) OVER () AS n FROM test.parquet",
+			wantErr: false,
+		},
+		{
+			name:    "aggregate window in HAVING should fail",
+			query:   "SELECT dept, COUNT(*) AS n FROM test.parquet GROUP BY dept HAVING SUM(salary) OVER () > 1",
+			wantErr: true,
+		},
 		{
 			name:    "Window function without OVER clause should fail",
 			query:   "SELECT name, ROW_NUMBER() FROM test.parquet",
@@ -0,0 +1,45 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// supportedCodecs holds the compression codecs parquet-go can decode. LZO and
+// the deprecated Hadoop-framed LZ4 codec are not among them.
+var supportedCodecs = codecSet(
+	&parquet.Uncompressed,
+	&parquet.Snappy,
+	&parquet.Gzip,
+	&parquet.Brotli,
+	&parquet.Zstd,
+	&parquet.Lz4Raw,
+)
+
+// codecSet returns the format codes of codecs
+func codecSet(codecs ...compress.Codec) map[format.CompressionCodec]bool {
+	set := make(map[format.CompressionCodec]bool, len(codecs))
+	for _, codec := range codecs {
+		set[codec.CompressionCodec()] = true
+	}
+	return set
+}
+
+// checkCodecs returns an error naming the first column chunk compressed with
+// a codec that can't be decoded. Reading such a file would otherwise fail
+// with a generic page decoding error partway through.
+func checkCodecs(metadata *format.FileMetaData) error {
+	for _, rowGroup := range metadata.RowGroups {
+		for _, chunk := range rowGroup.Columns {
+			codec := chunk.MetaData.Codec
+			if !supportedCodecs[codec] {
+				return fmt.Errorf("unsupported compression codec %s in column %s", codec, strings.Join(chunk.MetaData.PathInSchema, "."))
+			}
+		}
+	}
+	return nil
+}
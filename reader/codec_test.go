@@ -0,0 +1,109 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+func TestReadAll_CompressionCodecs(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	codecs := []compress.Codec{
+		&parquet.Uncompressed,
+		&parquet.Snappy,
+		&parquet.Gzip,
+		&parquet.Brotli,
+		&parquet.Zstd,
+		&parquet.Lz4Raw,
+	}
+
+	for _, codec := range codecs {
+		t.Run(codec.String(), func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "test.parquet")
+			f, err := os.Create(testFile)
+			if err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+			writer := parquet.NewGenericWriter[Row](f, parquet.Compression(codec))
+			if _, err := writer.Write([]Row{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}}); err != nil {
+				t.Fatalf("failed to write test data: %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("failed to close writer: %v", err)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatalf("failed to close file: %v", err)
+			}
+
+			r, err := NewReader(testFile)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			// Make sure the writer really used the codec under test
+			if got := r.pqFile.Metadata().RowGroups[0].Columns[0].MetaData.Codec; got != codec.CompressionCodec() {
+				t.Fatalf("column codec = %s, want %s", got, codec.CompressionCodec())
+			}
+
+			rows, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if len(rows) != 2 || rows[1]["name"] != "bob" {
+				t.Errorf("ReadAll() = %v, want alice and bob", rows)
+			}
+		})
+	}
+}
+
+func TestCheckCodecs(t *testing.T) {
+	chunk := func(codec format.CompressionCodec, path ...string) format.ColumnChunk {
+		return format.ColumnChunk{MetaData: format.ColumnMetaData{Codec: codec, PathInSchema: path}}
+	}
+
+	tests := []struct {
+		name    string
+		columns []format.ColumnChunk
+		wantErr string
+	}{
+		{
+			name:    "supported codecs",
+			columns: []format.ColumnChunk{chunk(format.Snappy, "id"), chunk(format.Lz4Raw, "name")},
+		},
+		{
+			name:    "LZO",
+			columns: []format.ColumnChunk{chunk(format.Snappy, "id"), chunk(format.LZO, "name")},
+			wantErr: "unsupported compression codec LZO in column name",
+		},
+		{
+			name:    "deprecated LZ4 in nested column",
+			columns: []format.ColumnChunk{chunk(format.Lz4, "address", "city")},
+			wantErr: "unsupported compression codec LZ4 in column address.city",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := &format.FileMetaData{RowGroups: []format.RowGroup{{Columns: tt.columns}}}
+			err := checkCodecs(metadata)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkCodecs() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkCodecs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package reader
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// valueConverter converts a raw value decoded by parquet-go into the value
+// returned to callers. Converters must pass nil through unchanged.
+type valueConverter func(value interface{}) interface{}
+
+// columnConverters returns converters for the top-level columns of a schema
+// whose raw values are not useful as-is, keyed by column name.
+//
+// Nested fields are left untouched.
+func columnConverters(schema *parquet.Schema) map[string]valueConverter {
+	converters := make(map[string]valueConverter)
+
+	for _, field := range schema.Fields() {
+		if len(field.Fields()) > 0 || field.Type() == nil {
+			continue
+		}
+
+		fieldType := field.Type()
+		if fieldType.Kind() == parquet.Int64 {
+			if logicalType := fieldType.LogicalType(); logicalType != nil && logicalType.Timestamp != nil {
+				converters[field.Name()] = timestampConverter(logicalType.Timestamp)
+			} else if logicalType != nil && logicalType.Integer != nil && !logicalType.Integer.IsSigned {
+				// Likewise unsigned 64-bit columns decode as int64
+				converters[field.Name()] = uint64Converter
+			}
+			continue
+		}
+		if fieldType.Kind() == parquet.Int32 {
+			// Unsigned 32-bit columns decode as int32, so large values would
+			// come back negative without reinterpreting the bits
+			if logicalType := fieldType.LogicalType(); logicalType != nil && logicalType.Integer != nil && !logicalType.Integer.IsSigned {
+				converters[field.Name()] = uint32ToInt64
+			}
+			continue
+		}
+		if fieldType.Kind() != parquet.FixedLenByteArray {
+			continue
+		}
+
+		if logicalType := fieldType.LogicalType(); logicalType != nil && logicalType.UUID != nil {
+			converters[field.Name()] = uuidToString
+		} else {
+			converters[field.Name()] = bytesToHex
+		}
+	}
+
+	return converters
+}
+
+// convertRow applies column converters to a row in place and widens all
+// other integer values to int64
+func convertRow(row map[string]interface{}, converters map[string]valueConverter) {
+	for col, value := range row {
+		if value == nil {
+			continue
+		}
+		if convert, ok := converters[col]; ok {
+			row[col] = convert(value)
+		} else {
+			row[col] = widenInts(value)
+		}
+	}
+}
+
+// widenInts converts integers narrower than 64 bits to int64, looking inside
+// lists and groups, so that values don't depend on the width of the parquet
+// integer type (INT32 columns and INT(8)/INT(16) logical types decode as
+// int32, int8, ...). uint64 values are left as-is since they may not fit.
+func widenInts(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = widenInts(elem)
+		}
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = widenInts(elem)
+		}
+	}
+	return value
+}
+
+// uint32ToInt64 reinterprets an int32 decoded from an unsigned column and
+// widens it to int64
+func uint32ToInt64(value interface{}) interface{} {
+	if v, ok := value.(int32); ok {
+		return int64(uint32(v))
+	}
+	return widenInts(value)
+}
+
+// uint64Converter reinterprets an int64 decoded from an unsigned column.
+// Values that fit stay int64 like other integers; larger ones are returned
+// as uint64.
+func uint64Converter(value interface{}) interface{} {
+	if v, ok := value.(int64); ok && v < 0 {
+		return uint64(v)
+	}
+	return widenInts(value)
+}
+
+// timestampConverter returns a converter from the int64 stored in a
+// TIMESTAMP column to a UTC time.Time, according to the column's unit
+func timestampConverter(ts *format.TimestampType) valueConverter {
+	toTime := time.UnixMicro
+	switch {
+	case ts.Unit.Millis != nil:
+		toTime = time.UnixMilli
+	case ts.Unit.Nanos != nil:
+		toTime = func(v int64) time.Time { return time.Unix(0, v) }
+	}
+	return func(value interface{}) interface{} {
+		v, ok := value.(int64)
+		if !ok {
+			return widenInts(value)
+		}
+		return toTime(v).UTC()
+	}
+}
+
+// uuidToString formats a 16-byte UUID in canonical form (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)
+func uuidToString(value interface{}) interface{} {
+	b, ok := value.([]byte)
+	if !ok || len(b) != 16 {
+		return bytesToHex(value)
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// bytesToHex formats a byte array as a lowercase hex string
+func bytesToHex(value interface{}) interface{} {
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	return hex.EncodeToString(b)
+}
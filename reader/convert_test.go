@@ -0,0 +1,172 @@
+package reader
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// writeAndReadAll writes rows to a temporary parquet file and reads them back with ReadAll
+func writeAndReadAll[T any](t *testing.T, rows []T) []map[string]interface{} {
+	t.Helper()
+
+	testFile := filepath.Join(t.TempDir(), "test.parquet")
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	writer := parquet.NewGenericWriter[T](f)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	r, err := NewReader(testFile)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	return result
+}
+
+func TestReadAll_FixedByteArrayConversion(t *testing.T) {
+	type Row struct {
+		ID       [16]byte `parquet:"id,uuid"`
+		Checksum [4]byte  `parquet:"checksum"`
+	}
+
+	id := [16]byte{0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4, 0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00}
+	rows := []Row{
+		{ID: id, Checksum: [4]byte{0xde, 0xad, 0xbe, 0xef}},
+		{ID: [16]byte{}, Checksum: [4]byte{0x00, 0x01, 0x02, 0x0a}},
+	}
+
+	result := writeAndReadAll(t, rows)
+	if len(result) != 2 {
+		t.Fatalf("ReadAll() returned %d rows, want 2", len(result))
+	}
+
+	tests := []struct {
+		row    int
+		column string
+		want   interface{}
+	}{
+		{0, "id", "550e8400-e29b-41d4-a716-446655440000"},
+		{0, "checksum", "deadbeef"},
+		{1, "id", "00000000-0000-0000-0000-000000000000"},
+		{1, "checksum", "0001020a"},
+	}
+
+	for _, tt := range tests {
+		if got := result[tt.row][tt.column]; got != tt.want {
+			t.Errorf("row %d column %q = %#v, want %#v", tt.row, tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestReadAll_IntegerWidening(t *testing.T) {
+	type Row struct {
+		Int32  int32   `parquet:"int32"`
+		Int16  int32   `parquet:"int16,int(16)"`
+		Int8   int32   `parquet:"int8,int(8)"`
+		Uint32 uint32  `parquet:"uint32"`
+		Int64  int64   `parquet:"int64"`
+		Uint64 uint64  `parquet:"uint64"`
+		Opt    *int32  `parquet:"opt,optional"`
+		List   []int32 `parquet:"list,list"`
+	}
+
+	opt := int32(-5)
+	result := writeAndReadAll(t, []Row{
+		{Int32: 1 << 30, Int16: -300, Int8: 7, Uint32: 1 << 31, Int64: 1 << 40, Uint64: 9, Opt: &opt, List: []int32{1, 2}},
+		{Int32: -1, Uint64: math.MaxUint64},
+	})
+	if len(result) != 2 {
+		t.Fatalf("ReadAll() returned %d rows, want 2", len(result))
+	}
+
+	tests := []struct {
+		row    int
+		column string
+		want   interface{}
+	}{
+		{0, "int32", int64(1 << 30)},
+		{0, "int16", int64(-300)},
+		{0, "int8", int64(7)},
+		{0, "uint32", int64(1 << 31)},
+		{0, "int64", int64(1 << 40)},
+		{0, "uint64", int64(9)},
+		{0, "opt", int64(-5)},
+		{1, "int32", int64(-1)},
+		{1, "uint64", uint64(math.MaxUint64)},
+		{1, "opt", nil},
+	}
+
+	for _, tt := range tests {
+		if got := result[tt.row][tt.column]; got != tt.want {
+			t.Errorf("row %d column %q = %#v, want %#v", tt.row, tt.column, got, tt.want)
+		}
+	}
+
+	list, ok := result[0]["list"].([]interface{})
+	if !ok || len(list) != 2 || list[0] != int64(1) || list[1] != int64(2) {
+		t.Errorf("list = %#v, want []interface{}{int64(1), int64(2)}", result[0]["list"])
+	}
+}
+
+func TestReadAll_TimestampConversion(t *testing.T) {
+	type Row struct {
+		Nanos  time.Time  `parquet:"nanos"`
+		Millis time.Time  `parquet:"millis,timestamp(millisecond)"`
+		Micros time.Time  `parquet:"micros,timestamp(microsecond)"`
+		Opt    *time.Time `parquet:"opt,optional"`
+		Plain  int64      `parquet:"plain"`
+	}
+
+	ts := time.Date(2024, 3, 15, 10, 30, 45, 123456789, time.UTC)
+	result := writeAndReadAll(t, []Row{
+		{Nanos: ts, Millis: ts, Micros: ts, Opt: &ts, Plain: 42},
+		{Nanos: ts, Millis: ts, Micros: ts},
+	})
+	if len(result) != 2 {
+		t.Fatalf("ReadAll() returned %d rows, want 2", len(result))
+	}
+
+	tests := []struct {
+		row    int
+		column string
+		want   interface{}
+	}{
+		{0, "nanos", ts},
+		{0, "millis", ts.Truncate(time.Millisecond)},
+		{0, "micros", ts.Truncate(time.Microsecond)},
+		{0, "opt", ts},
+		{0, "plain", int64(42)},
+		{1, "opt", nil},
+	}
+
+	for _, tt := range tests {
+		got := result[tt.row][tt.column]
+		if want, ok := tt.want.(time.Time); ok {
+			if gotTime, isTime := got.(time.Time); !isTime || !gotTime.Equal(want) || gotTime.Location() != time.UTC {
+				t.Errorf("row %d column %q = %#v, want %v", tt.row, tt.column, got, want)
+			}
+		} else if got != tt.want {
+			t.Errorf("row %d column %q = %#v, want %#v", tt.row, tt.column, got, tt.want)
+		}
+	}
+}
@@ -23,6 +23,27 @@
 //	    fmt.Printf("%v\n", row)
 //	}
 //
+// NewReaderFromReaderAt opens a file held in memory or any other
+// io.ReaderAt, and NewReader("-") reads one from standard input.
+//
+// # Streaming Rows
+//
+// ReadAll loads the whole file into memory. Rows reads one row at a time
+// instead, reusing the same map for every row:
+//
+//	rows, err := reader.Rows()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer rows.Close()
+//
+//	for rows.Next() {
+//	    fmt.Printf("%v\n", rows.Row())
+//	}
+//	if err := rows.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
 // # Multi-file Operations
 //
 // Reading multiple files using glob patterns:
@@ -37,6 +58,31 @@
 //	    fmt.Printf("From %s: %v\n", row["_file"], row)
 //	}
 //
+// ReadMultipleFilesParallel reads several files at once and returns the rows
+// in the same order. StreamMultipleFiles passes each file's rows to a
+// callback instead, bounding memory to a few files at a time.
+//
+// # Preprocessing Rows
+//
+// ReadOptions.Transform rewrites, drops or rejects rows as they are read:
+//
+//	rows, err := reader.ReadMultipleFilesWithOptions("data/*.parquet", reader.ReadOptions{
+//	    Transform: func(row map[string]interface{}) (map[string]interface{}, error) {
+//	        delete(row, "ssn")
+//	        return row, nil
+//	    },
+//	})
+//
+// Set ReadOptions.Range to skip row groups and pages that the file's
+// statistics show hold no value of a column within a range. Whole pages are
+// returned, so filter the rows afterwards.
+//
+// Set ReadOptions.InferTypes to convert string columns that only hold
+// numbers, booleans or timestamps, as is common in files converted from CSV.
+//
+// Set ReadOptions.Context to stop a read once the context is done, for
+// example when a deadline passes.
+//
 // # Schema Introspection
 //
 // Accessing parquet file schema:
@@ -53,6 +99,12 @@
 //	    fmt.Printf("%s: %s\n", field.Name(), field.Type())
 //	}
 //
+// ExtractSchemaInfo flattens the schema into one SchemaInfo per leaf column,
+// including the min, max and null count recorded in the column statistics.
+//
+// Metadata returns the row counts, writer and key/value metadata stored in
+// the file footer, also without reading any data.
+//
 // # Resource Management
 //
 // Always call Close() when done reading to release file handles:
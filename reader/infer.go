@@ -0,0 +1,156 @@
+package reader
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inferTimeLayouts are the timestamp formats recognized when inferring types
+var inferTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// inferredType parses a string into a typed value, reporting whether it could
+type inferredType func(s string) (interface{}, bool)
+
+// inferredTypes lists the candidate types in order of preference. A column of
+// integers also parses as floats, so integers are tried first.
+var inferredTypes = []inferredType{
+	parseInferredInt,
+	parseInferredFloat,
+	parseInferredBool,
+	parseInferredTime,
+}
+
+// inferTypes converts string columns whose values are all numbers, booleans
+// or timestamps to that type, in place. A column is converted only when every
+// non-empty string value parses as one type, so no value is lost; its empty
+// strings become nil. Columns holding any non-string value, and the _file
+// column, are left untouched.
+func inferTypes(rows []map[string]interface{}) {
+	for _, col := range stringColumns(rows) {
+		parse := inferColumnType(rows, col)
+		if parse == nil {
+			continue
+		}
+
+		for _, row := range rows {
+			str, ok := row[col].(string)
+			if !ok {
+				continue
+			}
+			// Only empty strings fail to parse in a converted column
+			if value, ok := parse(strings.TrimSpace(str)); ok {
+				row[col] = value
+			} else {
+				row[col] = nil
+			}
+		}
+	}
+}
+
+// stringColumns returns the columns whose non-nil values are all strings
+func stringColumns(rows []map[string]interface{}) []string {
+	isString := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		for col, value := range row {
+			if value == nil || col == "_file" {
+				continue
+			}
+			_, ok := value.(string)
+			if seen, exists := isString[col]; exists {
+				isString[col] = seen && ok
+				continue
+			}
+			isString[col] = ok
+			columns = append(columns, col)
+		}
+	}
+
+	result := columns[:0]
+	for _, col := range columns {
+		if isString[col] {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// inferColumnType returns the parser for the first type that all of the
+// column's non-empty values parse as, or nil if none does
+func inferColumnType(rows []map[string]interface{}, col string) inferredType {
+	candidates := make([]bool, len(inferredTypes))
+	for i := range candidates {
+		candidates[i] = true
+	}
+
+	found := false
+	for _, row := range rows {
+		str, ok := row[col].(string)
+		if !ok {
+			continue
+		}
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+
+		found = true
+		for i, parse := range inferredTypes {
+			if !candidates[i] {
+				continue
+			}
+			if _, ok := parse(str); !ok {
+				candidates[i] = false
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	for i, parse := range inferredTypes {
+		if candidates[i] {
+			return parse
+		}
+	}
+	return nil
+}
+
+func parseInferredInt(s string) (interface{}, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+func parseInferredFloat(s string) (interface{}, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	// Words like "nan" and "infinity" parse as floats but are rarely meant as numbers
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	return f, true
+}
+
+func parseInferredBool(s string) (interface{}, bool) {
+	switch {
+	case strings.EqualFold(s, "true"):
+		return true, true
+	case strings.EqualFold(s, "false"):
+		return false, true
+	}
+	return nil, false
+}
+
+func parseInferredTime(s string) (interface{}, bool) {
+	for _, layout := range inferTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return nil, false
+}
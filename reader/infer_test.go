@@ -0,0 +1,152 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestInferTypes(t *testing.T) {
+	column := func(values ...interface{}) []map[string]interface{} {
+		rows := make([]map[string]interface{}, len(values))
+		for i, v := range values {
+			rows[i] = map[string]interface{}{"c": v}
+		}
+		return rows
+	}
+	values := func(rows []map[string]interface{}) []interface{} {
+		result := make([]interface{}, len(rows))
+		for i, row := range rows {
+			result[i] = row["c"]
+		}
+		return result
+	}
+
+	// 19 integers and one stray value that must not be lost
+	mostlyInts := make([]interface{}, 0, 20)
+	for i := 0; i < 19; i++ {
+		mostlyInts = append(mostlyInts, "7")
+	}
+	mostlyInts = append(mostlyInts, "N/A")
+
+	tests := []struct {
+		name string
+		rows []map[string]interface{}
+		want []interface{}
+	}{
+		{
+			name: "integers",
+			rows: column("1", " 200 ", "-3", nil, ""),
+			want: []interface{}{int64(1), int64(200), int64(-3), nil, nil},
+		},
+		{
+			name: "integers and floats become floats",
+			rows: column("1", "2.5", "1e3"),
+			want: []interface{}{1.0, 2.5, 1000.0},
+		},
+		{
+			name: "booleans",
+			rows: column("true", "FALSE", "True"),
+			want: []interface{}{true, false, true},
+		},
+		{
+			name: "timestamps",
+			rows: column("2024-01-15", "2024-01-15 10:30:00", "2024-01-15T10:30:00Z"),
+			want: []interface{}{
+				time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "mostly numeric column with a value that doesn't parse stays string",
+			rows: column(mostlyInts...),
+			want: mostlyInts,
+		},
+		{
+			name: "mixed column stays string",
+			rows: column("1", "2", "three"),
+			want: []interface{}{"1", "2", "three"},
+		},
+		{
+			name: "nan and infinity are not numbers",
+			rows: column("nan", "inf"),
+			want: []interface{}{"nan", "inf"},
+		},
+		{
+			name: "empty strings only",
+			rows: column("", " "),
+			want: []interface{}{"", " "},
+		},
+		{
+			name: "column with non-string values is untouched",
+			rows: column("1", int64(2)),
+			want: []interface{}{"1", int64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inferTypes(tt.rows)
+			if got := values(tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inferTypes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMultipleFilesWithOptions_InferTypes(t *testing.T) {
+	type StringRow struct {
+		Name   string `parquet:"name"`
+		Amount string `parquet:"amount"`
+	}
+
+	dir := t.TempDir()
+	for i, rows := range [][]StringRow{
+		{{Name: "a", Amount: "50"}, {Name: "b", Amount: "150"}},
+		{{Name: "c", Amount: "250"}},
+	} {
+		f, err := os.Create(filepath.Join(dir, []string{"1.parquet", "2.parquet"}[i]))
+		if err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		writer := parquet.NewGenericWriter[StringRow](f)
+		if _, err := writer.Write(rows); err != nil {
+			t.Fatalf("failed to write test data: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("failed to close writer: %v", err)
+		}
+		_ = f.Close()
+	}
+
+	pattern := filepath.Join(dir, "*.parquet")
+	rows, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{InferTypes: true})
+	if err != nil {
+		t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+	}
+
+	for _, row := range rows {
+		if _, ok := row["amount"].(int64); !ok {
+			t.Errorf("amount = %#v, want int64", row["amount"])
+		}
+		if _, ok := row["name"].(string); !ok {
+			t.Errorf("name = %#v, want string", row["name"])
+		}
+		if _, ok := row["_file"].(string); !ok {
+			t.Errorf("_file = %#v, want string", row["_file"])
+		}
+	}
+
+	rows, err = ReadMultipleFilesWithOptions(pattern, ReadOptions{})
+	if err != nil {
+		t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+	}
+	if _, ok := rows[0]["amount"].(string); !ok {
+		t.Errorf("without InferTypes amount = %#v, want string", rows[0]["amount"])
+	}
+}
@@ -0,0 +1,175 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// RowIterator reads the rows of a parquet file one at a time, so files
+// larger than memory can be processed. Rows are converted like those
+// returned by ReadAll.
+//
+// Typical use:
+//
+//	rows, err := r.Rows()
+//	if err != nil {
+//	    return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//	    row := rows.Row()
+//	    ...
+//	}
+//	if err := rows.Err(); err != nil {
+//	    return err
+//	}
+type RowIterator struct {
+	reader     *parquet.Reader
+	converters map[string]valueConverter
+	opts       ReadOptions
+	ranges     []rowRange // rows still to read, in file order
+	next       int64      // index in the file of the next row read
+	limit      int64      // maximum number of rows returned, 0 for no limit
+	returned   int64      // number of rows returned so far
+	reuse      bool       // whether rows are read into the same map each time
+	buf        map[string]interface{}
+	row        map[string]interface{}
+	err        error
+	done       bool
+}
+
+// Rows returns an iterator over the rows of the file.
+//
+// The map returned by Row is reused by the next call to Next, so copy it
+// to keep a row. Returns an error if a column uses an unsupported codec.
+func (r *Reader) Rows() (*RowIterator, error) {
+	return r.rows(ReadOptions{}, 0, true)
+}
+
+// rows returns an iterator applying the sampling, Transform and Range
+// options of opts and stopping after limit rows when limit is positive.
+// If reuse is false every row gets a new map.
+func (r *Reader) rows(opts ReadOptions, limit int64, reuse bool) (*RowIterator, error) {
+	// Checked here rather than in NewReader so the schema of such a file can
+	// still be shown
+	if err := checkCodecs(r.pqFile.Metadata()); err != nil {
+		return nil, err
+	}
+
+	ranges := []rowRange{{start: 0, end: r.pqFile.NumRows()}}
+	if opts.Range != nil && opts.Transform == nil && !opts.InferTypes {
+		ranges, _ = r.candidateRanges(opts.Range)
+	}
+
+	return &RowIterator{
+		reader:     parquet.NewReader(r.pqFile),
+		converters: r.converters,
+		opts:       opts,
+		ranges:     ranges,
+		limit:      limit,
+		reuse:      reuse,
+	}, nil
+}
+
+// Next advances to the next row, returning false when there are no more
+// rows or reading failed. Check Err afterwards to tell the two apart.
+func (it *RowIterator) Next() bool {
+	for !it.done {
+		if it.limit > 0 && it.returned >= it.limit {
+			break
+		}
+
+		// Move to the next range once this one is read
+		for len(it.ranges) > 0 && it.next >= it.ranges[0].end {
+			it.ranges = it.ranges[1:]
+		}
+		if len(it.ranges) == 0 {
+			break
+		}
+
+		// Seeking uses the offset index to jump over skipped pages without decoding them
+		if start := it.ranges[0].start; it.next < start {
+			if err := it.reader.SeekToRow(start); err != nil {
+				it.err = fmt.Errorf("failed to seek to row %d: %w", start, err)
+				break
+			}
+			it.next = start
+		}
+
+		if it.next%cancelCheckInterval == 0 {
+			if err := it.opts.canceled(); err != nil {
+				it.err = err
+				break
+			}
+		}
+
+		row := it.buf
+		if row == nil || !it.reuse {
+			row = make(map[string]interface{})
+		} else {
+			clear(row)
+		}
+		it.buf = row
+
+		err := it.reader.Read(&row)
+		if err != nil {
+			// Use errors.Is for proper EOF detection
+			if !errors.Is(err, io.EOF) {
+				it.err = fmt.Errorf("failed to read row: %w", err)
+			}
+			break
+		}
+		it.next++
+
+		if it.opts.sampling() && it.opts.Rand.Float64() >= it.opts.SampleFraction {
+			continue
+		}
+		convertRow(row, it.converters)
+
+		if it.opts.Transform != nil {
+			row, err = it.opts.Transform(row)
+			if err != nil {
+				it.err = fmt.Errorf("failed to transform row: %w", err)
+				break
+			}
+			if row == nil {
+				continue
+			}
+		}
+
+		it.row = row
+		it.returned++
+		return true
+	}
+
+	_ = it.Close()
+	return false
+}
+
+// Row returns the current row. It is only valid until the next call to
+// Next, which reuses the map for the following row.
+func (it *RowIterator) Row() map[string]interface{} {
+	return it.row
+}
+
+// Err returns the error that stopped iteration, or nil if all rows were
+// read.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Close releases the iterator's resources. Iterators are closed
+// automatically once Next returns false, so Close is only needed when
+// stopping early. It is safe to call Close multiple times.
+func (it *RowIterator) Close() error {
+	if it.done {
+		return nil
+	}
+	it.done = true
+	it.row = nil
+	it.buf = nil
+	return it.reader.Close()
+}
@@ -0,0 +1,111 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRows_Iterate(t *testing.T) {
+	const numRows = 1000
+	path := writeSortedFile(t, numRows)
+
+	r, err := NewReader(path)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	rows, err := r.Rows()
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	count := 0
+	var firstMap uintptr
+	for rows.Next() {
+		row := rows.Row()
+		if id := row["id"]; id != int64(count) {
+			t.Fatalf("row %d: id = %#v, want %d", count, id, count)
+		}
+
+		// Nulls are present as nil even though the map held a value before
+		bonus, ok := row["bonus"]
+		if !ok {
+			t.Fatalf("row %d: missing bonus column", count)
+		}
+		if wantNull := count < numRows/2; (bonus == nil) != wantNull {
+			t.Fatalf("row %d: bonus = %#v, want null %v", count, bonus, wantNull)
+		}
+
+		// The same map is reused for every row rather than accumulating rows
+		ptr := reflect.ValueOf(row).Pointer()
+		if count == 0 {
+			firstMap = ptr
+		} else if ptr != firstMap {
+			t.Fatalf("row %d: Row() returned a new map, want the map reused", count)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if count != numRows {
+		t.Errorf("iterated %d rows, want %d", count, numRows)
+	}
+	if rows.Next() {
+		t.Error("Next() = true after the last row")
+	}
+}
+
+func TestRows_CloseEarly(t *testing.T) {
+	path := writeSortedFile(t, 1000)
+
+	r, err := NewReader(path)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	rows, err := r.Rows()
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	for i := 0; i < 10 && rows.Next(); i++ {
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	if rows.Next() {
+		t.Error("Next() = true after Close")
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("Err() = %v after Close", err)
+	}
+}
+
+func TestReadAll_RowsAreNotReused(t *testing.T) {
+	path := writeSortedFile(t, 100)
+
+	r, err := NewReader(path)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(result) != 100 {
+		t.Fatalf("ReadAll() returned %d rows, want 100", len(result))
+	}
+	for i, row := range result {
+		if row["id"] != int64(i) {
+			t.Fatalf("row %d: id = %#v, want %d", i, row["id"], i)
+		}
+	}
+}
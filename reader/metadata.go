@@ -0,0 +1,44 @@
+package reader
+
+import "fmt"
+
+// FileMetadata summarizes the footer of a parquet file.
+type FileMetadata struct {
+	NumRows      int64             `json:"num_rows"`
+	NumRowGroups int               `json:"num_row_groups"`
+	CreatedBy    string            `json:"created_by"`         // Application that wrote the file, may be empty
+	RowGroupRows []int64           `json:"row_group_rows"`     // Number of rows in each row group, in file order
+	KeyValue     map[string]string `json:"key_value_metadata"` // Key/value metadata stored in the footer
+}
+
+// Metadata returns the row counts, writer and key/value metadata recorded
+// in the file footer. No data is read.
+//
+// Returns an error if the row counts of the row groups don't add up to the
+// total the footer reports, which indicates a corrupt file.
+func (r *Reader) Metadata() (*FileMetadata, error) {
+	footer := r.pqFile.Metadata()
+
+	meta := &FileMetadata{
+		NumRows:      footer.NumRows,
+		NumRowGroups: len(footer.RowGroups),
+		CreatedBy:    footer.CreatedBy,
+		RowGroupRows: make([]int64, len(footer.RowGroups)),
+		KeyValue:     make(map[string]string, len(footer.KeyValueMetadata)),
+	}
+
+	var total int64
+	for i, rowGroup := range footer.RowGroups {
+		meta.RowGroupRows[i] = rowGroup.NumRows
+		total += rowGroup.NumRows
+	}
+	if total != footer.NumRows {
+		return nil, fmt.Errorf("row groups hold %d rows but the footer reports %d", total, footer.NumRows)
+	}
+
+	for _, kv := range footer.KeyValueMetadata {
+		meta.KeyValue[kv.Key] = kv.Value
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,80 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestReader_Metadata(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i)}
+	}
+
+	path := filepath.Join(t.TempDir(), "meta.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	writer := parquet.NewGenericWriter[Row](f,
+		parquet.MaxRowsPerRowGroup(4),
+		parquet.CreatedBy("parcat-test", "1.2.3", "abc"),
+		parquet.KeyValueMetadata("origin", "unit test"),
+		parquet.KeyValueMetadata("owner", "data-team"),
+	)
+	// Write in row group sized batches so every group is flushed at the limit
+	for start := 0; start < len(rows); start += 4 {
+		end := min(start+4, len(rows))
+		if _, err := writer.Write(rows[start:end]); err != nil {
+			t.Fatalf("failed to write test data: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	r, err := NewReader(path)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	meta, err := r.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata() error = %v", err)
+	}
+
+	if meta.NumRows != 10 {
+		t.Errorf("NumRows = %d, want 10", meta.NumRows)
+	}
+	if meta.NumRowGroups != 3 {
+		t.Errorf("NumRowGroups = %d, want 3", meta.NumRowGroups)
+	}
+	if want := []int64{4, 4, 2}; !reflect.DeepEqual(meta.RowGroupRows, want) {
+		t.Errorf("RowGroupRows = %v, want %v", meta.RowGroupRows, want)
+	}
+	if !strings.HasPrefix(meta.CreatedBy, "parcat-test version 1.2.3") {
+		t.Errorf("CreatedBy = %q, want it to name parcat-test 1.2.3", meta.CreatedBy)
+	}
+	if want := map[string]string{"origin": "unit test", "owner": "data-team"}; !reflect.DeepEqual(meta.KeyValue, want) {
+		t.Errorf("KeyValue = %v, want %v", meta.KeyValue, want)
+	}
+
+	// A footer whose total disagrees with its row groups is reported
+	r.pqFile.Metadata().NumRows = 11
+	if _, err := r.Metadata(); err == nil || !strings.Contains(err.Error(), "footer reports 11") {
+		t.Errorf("Metadata() error = %v, want a row count mismatch", err)
+	}
+}
@@ -1,8 +1,12 @@
 package reader
 
 import (
+	"context"
+	"errors"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/parquet-go/parquet-go"
@@ -190,3 +194,242 @@ func TestReadMultipleFiles_SpecificPattern(t *testing.T) {
 		t.Errorf("ReadMultipleFiles() returned %d rows, want 2", len(result))
 	}
 }
+
+func TestReadMultipleFilesWithOptions_OnProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	for i, name := range []string{"part1.parquet", "part2.parquet"} {
+		f, err := os.Create(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+
+		writer := parquet.NewGenericWriter[Row](f)
+		if _, err := writer.Write([]Row{{ID: int64(i*2 + 1), Name: "a"}, {ID: int64(i*2 + 2), Name: "b"}}); err != nil {
+			t.Fatalf("failed to write test data to %s: %v", name, err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("failed to close writer for %s: %v", name, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("failed to close file %s: %v", name, err)
+		}
+	}
+
+	type update struct {
+		filesDone, filesTotal int
+		rowsRead              int64
+	}
+	var updates []update
+
+	opts := ReadOptions{
+		OnProgress: func(filesDone, filesTotal int, rowsRead int64) {
+			updates = append(updates, update{filesDone, filesTotal, rowsRead})
+		},
+	}
+
+	result, err := ReadMultipleFilesWithOptions(filepath.Join(tmpDir, "*.parquet"), opts)
+	if err != nil {
+		t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+	}
+	if len(result) != 4 {
+		t.Fatalf("ReadMultipleFilesWithOptions() returned %d rows, want 4", len(result))
+	}
+
+	want := []update{{1, 2, 2}, {2, 2, 4}}
+	if len(updates) != len(want) {
+		t.Fatalf("got %d progress updates, want %d: %v", len(updates), len(want), updates)
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("progress update %d = %+v, want %+v", i, updates[i], want[i])
+		}
+	}
+}
+
+func TestReadMultipleFilesWithOptions_MaxRowsAndSampling(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	for _, name := range []string{"a.parquet", "b.parquet", "c.parquet"} {
+		f, err := os.Create(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+
+		rows := make([]Row, 100)
+		for i := range rows {
+			rows[i] = Row{ID: int64(i)}
+		}
+
+		writer := parquet.NewGenericWriter[Row](f)
+		if _, err := writer.Write(rows); err != nil {
+			t.Fatalf("failed to write test data to %s: %v", name, err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("failed to close writer for %s: %v", name, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("failed to close file %s: %v", name, err)
+		}
+	}
+
+	pattern := filepath.Join(tmpDir, "*.parquet")
+
+	t.Run("max rows spans files", func(t *testing.T) {
+		result, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{MaxRows: 150})
+		if err != nil {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+		}
+		if len(result) != 150 {
+			t.Errorf("got %d rows, want 150", len(result))
+		}
+		if result[149]["_file"] != filepath.Join(tmpDir, "b.parquet") {
+			t.Errorf("last row should come from b.parquet, got %v", result[149]["_file"])
+		}
+	})
+
+	t.Run("max rows on single file", func(t *testing.T) {
+		result, err := ReadMultipleFilesWithOptions(filepath.Join(tmpDir, "a.parquet"), ReadOptions{MaxRows: 5})
+		if err != nil {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+		}
+		if len(result) != 5 {
+			t.Errorf("got %d rows, want 5", len(result))
+		}
+	})
+
+	t.Run("seeded sampling is reproducible", func(t *testing.T) {
+		read := func() []map[string]interface{} {
+			result, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{
+				SampleFraction: 0.2,
+				Rand:           rand.New(rand.NewSource(7)),
+			})
+			if err != nil {
+				t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+			}
+			return result
+		}
+
+		first, second := read(), read()
+		if len(first) == 0 || len(first) == 300 {
+			t.Fatalf("expected a partial sample, got %d rows", len(first))
+		}
+		if len(first) != len(second) {
+			t.Fatalf("same seed gave different sample sizes: %d and %d", len(first), len(second))
+		}
+		for i := range first {
+			if first[i]["id"] != second[i]["id"] || first[i]["_file"] != second[i]["_file"] {
+				t.Fatalf("row %d differs between seeded samples", i)
+			}
+		}
+	})
+
+	t.Run("canceled context stops the read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{Context: ctx})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ReadMultipleFilesWithOptions() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestReadMultipleFilesWithOptions_Transform(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	type Row struct {
+		ID    int64  `parquet:"id"`
+		Email string `parquet:"email"`
+	}
+
+	for i, name := range []string{"a.parquet", "b.parquet"} {
+		f, err := os.Create(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+
+		writer := parquet.NewGenericWriter[Row](f)
+		rows := []Row{{ID: int64(i*2 + 1), Email: "alice@example.com"}, {ID: int64(i*2 + 2), Email: "bob@example.com"}}
+		if _, err := writer.Write(rows); err != nil {
+			t.Fatalf("failed to write test data to %s: %v", name, err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("failed to close writer for %s: %v", name, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("failed to close file %s: %v", name, err)
+		}
+	}
+
+	pattern := filepath.Join(tmpDir, "*.parquet")
+
+	t.Run("redact column", func(t *testing.T) {
+		result, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{
+			Transform: func(row map[string]interface{}) (map[string]interface{}, error) {
+				row["email"] = "<redacted>"
+				row["odd"] = row["id"].(int64)%2 == 1
+				return row, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+		}
+		if len(result) != 4 {
+			t.Fatalf("got %d rows, want 4", len(result))
+		}
+		for _, row := range result {
+			if row["email"] != "<redacted>" {
+				t.Errorf("email = %v, want <redacted>", row["email"])
+			}
+			if row["odd"] != (row["id"].(int64)%2 == 1) {
+				t.Errorf("derived odd = %v for id %v", row["odd"], row["id"])
+			}
+			if row["_file"] == nil {
+				t.Errorf("transformed row lost its _file tag: %v", row)
+			}
+		}
+	})
+
+	t.Run("nil drops row before max rows", func(t *testing.T) {
+		result, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{
+			MaxRows: 2,
+			Transform: func(row map[string]interface{}) (map[string]interface{}, error) {
+				if row["id"].(int64)%2 == 1 {
+					return nil, nil
+				}
+				return row, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+		}
+		if len(result) != 2 || result[0]["id"] != int64(2) || result[1]["id"] != int64(4) {
+			t.Errorf("got %v, want ids 2 and 4", result)
+		}
+	})
+
+	t.Run("error aborts read", func(t *testing.T) {
+		calls := 0
+		_, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{
+			Transform: func(row map[string]interface{}) (map[string]interface{}, error) {
+				calls++
+				return nil, errors.New("bad row")
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), "bad row") {
+			t.Fatalf("ReadMultipleFilesWithOptions() error = %v, want transform error", err)
+		}
+		if calls != 1 {
+			t.Errorf("Transform called %d times, want the read to stop after the first error", calls)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package reader
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// ReadOptions configures how rows are read from parquet files.
+//
+// The zero value reads every row of every file with no callbacks, which
+// matches the behavior of ReadMultipleFiles.
+type ReadOptions struct {
+	// OnProgress, if set, is called as files are read. filesDone is the
+	// number of files fully read so far, filesTotal the number of files
+	// matched, and rowsRead the running total of rows across all files.
+	// It is called periodically while a file is being read and once after
+	// each file completes.
+	OnProgress func(filesDone, filesTotal int, rowsRead int64)
+
+	// OnFiles, if set, is called once per read with the pattern and the
+	// files it resolved to, before any of them are opened.
+	OnFiles func(pattern string, files []string)
+
+	// MaxRows, if positive, stops reading once this many rows have been
+	// returned in total. Remaining files of a glob are not opened.
+	MaxRows int64
+
+	// SampleFraction, if between 0 and 1 (exclusive), includes each row
+	// independently with this probability (Bernoulli sampling). Any other
+	// value reads every row.
+	SampleFraction float64
+
+	// Rand is the random source used for SampleFraction. If nil, a source
+	// seeded from the current time is used, so pass a seeded source for
+	// reproducible samples.
+	Rand *rand.Rand
+
+	// Transform, if set, is applied to each row as it is read, after values
+	// are converted and before the row is tagged with _file. It may modify
+	// and return the row it is given or return a new one. Returning a nil
+	// row drops it, and it does not count toward MaxRows. An error aborts
+	// the read.
+	Transform func(row map[string]interface{}) (map[string]interface{}, error)
+
+	// InferTypes, if set, converts string columns whose values are all
+	// integers, floats, booleans (true/false) or timestamps (RFC 3339,
+	// "2006-01-02 15:04:05" or "2006-01-02") to that type once all rows are
+	// read. A column is converted only when every non-empty value parses as
+	// the same type, and its empty strings become nil. Other columns,
+	// including mixed ones, stay strings.
+	InferTypes bool
+
+	// Range, if set, skips row groups and pages of the file whose
+	// statistics show they hold no value of Range.Column within its
+	// bounds. Rows are still returned a whole page at a time, so the
+	// caller's predicate must be applied to the result. Sampling and MaxRows apply to the rows read.
+	// Range is ignored when Transform or InferTypes is set, since they may
+	// change the values the bounds refer to.
+	Range *RangeFilter
+
+	// Workers, if greater than 1, reads up to that many files of a glob
+	// at the same time. Rows are still returned in file order. Transform
+	// must then be safe for concurrent use; OnProgress calls are
+	// serialized. With MaxRows set, files after the limit may still be
+	// read, but their rows are dropped.
+	Workers int
+
+	// Context, if set, stops the read once it is done. It is checked every
+	// cancelCheckInterval rows, and the read fails with the cause of the
+	// cancellation (see context.Cause).
+	Context context.Context
+}
+
+// progressInterval is the number of rows between OnProgress calls while a
+// single file is being read.
+const progressInterval = 10000
+
+// cancelCheckInterval is the number of rows read between checks of Context
+const cancelCheckInterval = 1024
+
+// canceled returns the cause of Context's cancellation once it is done,
+// or nil
+func (o ReadOptions) canceled() error {
+	if o.Context == nil || o.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(o.Context)
+}
+
+// fileProgress returns a per-row callback reporting progress while the file
+// at index filesDone is read, or nil if no OnProgress callback is set.
+func (o ReadOptions) fileProgress(filesDone, filesTotal int, rowsBefore int64) func(int64) {
+	if o.OnProgress == nil {
+		return nil
+	}
+	return func(rowsRead int64) {
+		o.OnProgress(filesDone, filesTotal, rowsBefore+rowsRead)
+	}
+}
+
+// sampling reports whether per-row sampling is enabled
+func (o ReadOptions) sampling() bool {
+	return o.SampleFraction > 0 && o.SampleFraction < 1
+}
+
+// withDefaults fills in a random source when sampling without one
+func (o ReadOptions) withDefaults() ReadOptions {
+	if o.sampling() && o.Rand == nil {
+		o.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return o
+}
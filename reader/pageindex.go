@@ -0,0 +1,207 @@
+package reader
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// RangeFilter restricts a read to the row groups and pages of a column whose
+// values may fall within [Min, Max], using the min/max statistics stored in
+// the file: first those of each row group, then the column index (page-level
+// statistics) of the row groups that may match.
+//
+// Filtering is conservative: every row of a candidate page is returned, so
+// callers must still apply their predicate to the rows. Row groups and pages
+// holding only nulls are skipped. Row groups without statistics are checked
+// page by page, those without a page index either are read in full, and so
+// are columns whose type can't be compared with the bounds.
+type RangeFilter struct {
+	Column string      // Top-level column name
+	Min    interface{} // Inclusive lower bound (int64, float64 or string), or nil for none
+	Max    interface{} // Inclusive upper bound (int64, float64 or string), or nil for none
+}
+
+// rowRange is a half-open range [start, end) of row indexes within a file
+type rowRange struct {
+	start, end int64
+}
+
+// pageStats counts the row groups and pages of the filtered column
+// considered by a RangeFilter
+type pageStats struct {
+	rowGroups     int // row groups in the file
+	skippedGroups int // row groups whose statistics rule out a match
+	pages         int // pages with a column index entry, in row groups not skipped
+	skipped       int // pages whose rows were not read
+}
+
+// candidateRanges returns the rows of the file that may match filter, in
+// order, along with how many pages were skipped
+func (r *Reader) candidateRanges(filter *RangeFilter) ([]rowRange, pageStats) {
+	var stats pageStats
+	all := []rowRange{{start: 0, end: r.pqFile.NumRows()}}
+
+	leaf, ok := r.pqFile.Schema().Lookup(filter.Column)
+	if !ok || leaf.MaxRepetitionLevel > 0 || !prunableType(leaf.Node.Type()) {
+		return all, stats
+	}
+	// Converted values don't order like the raw values the index describes
+	if _, converted := r.converters[filter.Column]; converted {
+		return all, stats
+	}
+
+	var ranges []rowRange
+	add := func(start, end int64) {
+		if n := len(ranges); n > 0 && ranges[n-1].end == start {
+			ranges[n-1].end = end
+			return
+		}
+		ranges = append(ranges, rowRange{start: start, end: end})
+	}
+
+	base := int64(0)
+	for _, rowGroup := range r.pqFile.RowGroups() {
+		numRows := rowGroup.NumRows()
+		chunk := rowGroup.ColumnChunks()[leaf.ColumnIndex]
+
+		stats.rowGroups++
+		if !chunkMayMatch(chunk, filter) {
+			stats.skippedGroups++
+			base += numRows
+			continue
+		}
+
+		columnIndex, ciErr := chunk.ColumnIndex()
+		offsetIndex, oiErr := chunk.OffsetIndex()
+		if ciErr != nil || oiErr != nil || columnIndex.NumPages() != offsetIndex.NumPages() {
+			// No usable page index: fall back to reading the whole row group
+			add(base, base+numRows)
+			base += numRows
+			continue
+		}
+
+		numPages := columnIndex.NumPages()
+		for i := 0; i < numPages; i++ {
+			stats.pages++
+			if !pageMayMatch(columnIndex, i, filter) {
+				stats.skipped++
+				continue
+			}
+
+			end := numRows
+			if i+1 < numPages {
+				end = offsetIndex.FirstRowIndex(i + 1)
+			}
+			add(base+offsetIndex.FirstRowIndex(i), base+end)
+		}
+		base += numRows
+	}
+
+	return ranges, stats
+}
+
+// prunableType reports whether page statistics of a column type order the
+// same way as the values the reader returns for it. Floating point columns
+// are excluded: a NaN in a page corrupts the min/max parquet-go records for
+// it (e.g. both become +Inf), and readers can't tell such pages apart.
+func prunableType(t parquet.Type) bool {
+	logicalType := t.LogicalType()
+	switch t.Kind() {
+	case parquet.Int32, parquet.Int64:
+		// Signed plain integers only; dates, timestamps and decimals are
+		// returned in forms the bounds can't be compared with
+		return logicalType == nil || (logicalType.Integer != nil && logicalType.Integer.IsSigned)
+	case parquet.ByteArray:
+		return logicalType != nil && logicalType.UTF8 != nil
+	default:
+		return false
+	}
+}
+
+// chunkMayMatch reports whether the row group statistics of a column chunk
+// allow a value within the filter's bounds. Chunks without statistics may.
+func chunkMayMatch(chunk parquet.ColumnChunk, filter *RangeFilter) bool {
+	fileChunk, ok := chunk.(*parquet.FileColumnChunk)
+	if !ok {
+		return true
+	}
+	if numValues := fileChunk.NumValues(); numValues > 0 && fileChunk.NullCount() == numValues {
+		return false
+	}
+	min, max, ok := fileChunk.Bounds()
+	if !ok {
+		return true
+	}
+	return boundsMayMatch(min, max, filter)
+}
+
+// pageMayMatch reports whether page i of a column index may hold a value
+// within the filter's bounds
+func pageMayMatch(index parquet.ColumnIndex, i int, filter *RangeFilter) bool {
+	if index.NullPage(i) {
+		return false
+	}
+	return boundsMayMatch(index.MinValue(i), index.MaxValue(i), filter)
+}
+
+// boundsMayMatch reports whether values between min and max may fall within
+// the filter's bounds
+func boundsMayMatch(min, max parquet.Value, filter *RangeFilter) bool {
+	if filter.Min != nil {
+		if order, ok := compareBound(pageValue(max), filter.Min); ok && order < 0 {
+			return false
+		}
+	}
+	if filter.Max != nil {
+		if order, ok := compareBound(pageValue(min), filter.Max); ok && order > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pageValue converts a page statistic to the Go value the reader returns for
+// it, or nil if it can't be used for pruning
+func pageValue(v parquet.Value) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Kind() {
+	case parquet.Int32:
+		return int64(v.Int32())
+	case parquet.Int64:
+		return v.Int64()
+	case parquet.ByteArray:
+		return string(v.ByteArray())
+	default:
+		return nil
+	}
+}
+
+// compareBound compares a page statistic with a filter bound, reporting
+// false if the two can't be compared
+func compareBound(value, bound interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int64:
+		switch b := bound.(type) {
+		case int64:
+			return cmp.Compare(v, b), true
+		case float64:
+			return cmp.Compare(float64(v), b), true
+		}
+	case float64:
+		switch b := bound.(type) {
+		case int64:
+			return cmp.Compare(v, float64(b)), true
+		case float64:
+			return cmp.Compare(v, b), true
+		}
+	case string:
+		if b, ok := bound.(string); ok {
+			return strings.Compare(v, b), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,281 @@
+package reader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// sortedRow is a fixture row whose id, score and name all increase with the row number
+type sortedRow struct {
+	ID     int64   `parquet:"id"`
+	Score  float64 `parquet:"score"`
+	Name   string  `parquet:"name"`
+	Active bool    `parquet:"active"`
+	Bonus  *int64  `parquet:"bonus,optional"`
+}
+
+// writeSortedFile writes numRows sortedRows split into small pages and
+// several row groups, so that the page index has many entries
+func writeSortedFile(tb testing.TB, numRows int) string {
+	tb.Helper()
+
+	rows := make([]sortedRow, numRows)
+	for i := range rows {
+		rows[i] = sortedRow{ID: int64(i), Score: float64(i) / 2, Name: fmt.Sprintf("name%06d", i), Active: i%2 == 0}
+		// The second half has bonuses, so pages of the first half are all null
+		if i >= numRows/2 {
+			bonus := int64(i)
+			rows[i].Bonus = &bonus
+		}
+	}
+
+	path := filepath.Join(tb.TempDir(), "sorted.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		tb.Fatalf("failed to create test file: %v", err)
+	}
+	writer := parquet.NewGenericWriter[sortedRow](f, parquet.PageBufferSize(1024), parquet.MaxRowsPerRowGroup(int64(numRows/4)))
+	if _, err := writer.Write(rows); err != nil {
+		tb.Fatalf("failed to write test data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		tb.Fatalf("failed to close writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		tb.Fatalf("failed to close file: %v", err)
+	}
+	return path
+}
+
+func TestReadAll_RangeFilter(t *testing.T) {
+	const numRows = 10000
+	path := writeSortedFile(t, numRows)
+
+	tests := []struct {
+		name     string
+		filter   RangeFilter
+		match    func(id int64) bool // rows the filter must return
+		wantSkip bool
+	}{
+		{
+			name:     "integer range",
+			filter:   RangeFilter{Column: "id", Min: int64(4000), Max: int64(4100)},
+			match:    func(id int64) bool { return id >= 4000 && id <= 4100 },
+			wantSkip: true,
+		},
+		{
+			name:     "lower bound only",
+			filter:   RangeFilter{Column: "id", Min: int64(9990)},
+			match:    func(id int64) bool { return id >= 9990 },
+			wantSkip: true,
+		},
+		{
+			name:     "float bound on integer column",
+			filter:   RangeFilter{Column: "id", Max: 10.5},
+			match:    func(id int64) bool { return id <= 10 },
+			wantSkip: true,
+		},
+		{
+			name:   "float column reads everything",
+			filter: RangeFilter{Column: "score", Min: int64(100), Max: int64(200)},
+			match:  func(id int64) bool { return true },
+		},
+		{
+			name:     "string column",
+			filter:   RangeFilter{Column: "name", Min: "name007000", Max: "name007000"},
+			match:    func(id int64) bool { return id == 7000 },
+			wantSkip: true,
+		},
+		{
+			name:     "no page matches",
+			filter:   RangeFilter{Column: "id", Min: int64(numRows)},
+			match:    func(id int64) bool { return false },
+			wantSkip: true,
+		},
+		{
+			name:     "null pages are skipped",
+			filter:   RangeFilter{Column: "bonus", Min: int64(0)},
+			match:    func(id int64) bool { return id >= numRows/2 },
+			wantSkip: true,
+		},
+		{
+			name:   "bound of another type reads everything",
+			filter: RangeFilter{Column: "id", Min: "9990"},
+			match:  func(id int64) bool { return true },
+		},
+		{
+			name:   "unsupported column type reads everything",
+			filter: RangeFilter{Column: "active", Min: int64(1)},
+			match:  func(id int64) bool { return true },
+		},
+		{
+			name:   "missing column reads everything",
+			filter: RangeFilter{Column: "missing", Min: int64(1)},
+			match:  func(id int64) bool { return true },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(path)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			filter := tt.filter
+			_, stats := r.candidateRanges(&filter)
+			if skipped := stats.skippedGroups > 0 || stats.skipped > 0; skipped != tt.wantSkip {
+				t.Errorf("skipped %d of %d row groups and %d of %d pages, want skipping = %v",
+					stats.skippedGroups, stats.rowGroups, stats.skipped, stats.pages, tt.wantSkip)
+			}
+
+			rows, err := r.readAll(ReadOptions{Range: &filter}, 0, nil)
+			if err != nil {
+				t.Fatalf("readAll() error = %v", err)
+			}
+
+			// Every matching row must be returned, in order
+			seen := make(map[int64]bool, len(rows))
+			last := int64(-1)
+			for _, row := range rows {
+				id := row["id"].(int64)
+				if id <= last {
+					t.Fatalf("rows out of order: %d after %d", id, last)
+				}
+				last = id
+				seen[id] = true
+			}
+			for id := int64(0); id < numRows; id++ {
+				if tt.match(id) && !seen[id] {
+					t.Fatalf("row %d matches the filter but was not returned", id)
+				}
+			}
+			if tt.wantSkip && len(rows) == numRows {
+				t.Errorf("expected fewer than %d rows to be read", numRows)
+			}
+		})
+	}
+}
+
+func TestReadAll_RowGroupStatistics(t *testing.T) {
+	// Four row groups of 250 rows, with ids 0-249, 250-499, ...
+	const numRows = 1000
+	path := writeSortedFile(t, numRows)
+
+	tests := []struct {
+		name          string
+		filter        RangeFilter
+		noStatistics  bool
+		wantRows      int
+		wantSkipGroup int
+	}{
+		{name: "groups below the range", filter: RangeFilter{Column: "id", Min: int64(600)}, wantRows: 500, wantSkipGroup: 2},
+		{name: "groups above the range", filter: RangeFilter{Column: "id", Max: int64(100)}, wantRows: 250, wantSkipGroup: 3},
+		{name: "range inside one group", filter: RangeFilter{Column: "id", Min: int64(300), Max: int64(310)}, wantRows: 250, wantSkipGroup: 3},
+		{name: "all-null groups", filter: RangeFilter{Column: "bonus", Min: int64(0)}, wantRows: 500, wantSkipGroup: 2},
+		{name: "no group matches", filter: RangeFilter{Column: "name", Min: "zzz"}, wantRows: 0, wantSkipGroup: 4},
+		{name: "without statistics", filter: RangeFilter{Column: "id", Min: int64(600)}, noStatistics: true, wantRows: numRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(path)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			// Drop the page index, and optionally the statistics, so only
+			// row group statistics can skip rows
+			for i := range r.pqFile.Metadata().RowGroups {
+				for j := range r.pqFile.Metadata().RowGroups[i].Columns {
+					chunk := &r.pqFile.Metadata().RowGroups[i].Columns[j]
+					chunk.ColumnIndexOffset = 0
+					chunk.OffsetIndexOffset = 0
+					if tt.noStatistics {
+						chunk.MetaData.Statistics.MinValue = nil
+						chunk.MetaData.Statistics.MaxValue = nil
+						chunk.MetaData.Statistics.NullCount = 0
+					}
+				}
+			}
+
+			filter := tt.filter
+			_, stats := r.candidateRanges(&filter)
+			if stats.pages != 0 {
+				t.Fatalf("considered %d pages, want the page index to be unused", stats.pages)
+			}
+			if stats.skippedGroups != tt.wantSkipGroup {
+				t.Errorf("skipped %d of %d row groups, want %d", stats.skippedGroups, stats.rowGroups, tt.wantSkipGroup)
+			}
+
+			// Skipped row groups are never read, so fewer rows come back
+			rows, err := r.readAll(ReadOptions{Range: &filter}, 0, nil)
+			if err != nil {
+				t.Fatalf("readAll() error = %v", err)
+			}
+			if len(rows) != tt.wantRows {
+				t.Errorf("read %d rows, want %d", len(rows), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestReadAll_RangeFilterWithLimit(t *testing.T) {
+	path := writeSortedFile(t, 10000)
+
+	rows, err := ReadMultipleFilesWithOptions(path, ReadOptions{
+		Range:   &RangeFilter{Column: "id", Min: int64(5000)},
+		MaxRows: 3,
+	})
+	if err != nil {
+		t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if id := rows[0]["id"].(int64); id > 5000 {
+		t.Errorf("first row id = %d, want the page holding 5000", id)
+	}
+}
+
+func BenchmarkReadRange(b *testing.B) {
+	path := writeSortedFile(b, 100000)
+	filter := &RangeFilter{Column: "id", Min: int64(50000), Max: int64(50999)}
+
+	for _, bm := range []struct {
+		name string
+		opts ReadOptions
+	}{
+		{name: "full", opts: ReadOptions{}},
+		{name: "page-index", opts: ReadOptions{Range: filter}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			r, err := NewReader(path)
+			if err != nil {
+				b.Fatalf("NewReader() error = %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rows, err := r.readAll(bm.opts, 0, nil)
+				if err != nil {
+					b.Fatalf("readAll() error = %v", err)
+				}
+				b.ReportMetric(float64(len(rows)), "rows")
+			}
+
+			if bm.opts.Range != nil {
+				_, stats := r.candidateRanges(bm.opts.Range)
+				b.ReportMetric(float64(stats.skipped), "pages-skipped")
+				b.ReportMetric(float64(stats.pages), "pages")
+			}
+		})
+	}
+}
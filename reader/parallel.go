@@ -0,0 +1,231 @@
+package reader
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ReadMultipleFilesParallel reads all rows from the files matching a glob
+// pattern like ReadMultipleFiles, reading up to workers files at the same
+// time. If workers is 0 or less, one worker per CPU is used.
+//
+// Rows are returned in the same order as ReadMultipleFiles returns them,
+// whichever file finishes first. An error names the file it came from.
+func ReadMultipleFilesParallel(pattern string, workers int) ([]map[string]interface{}, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return ReadMultipleFilesWithOptions(pattern, ReadOptions{Workers: workers})
+}
+
+// StreamMultipleFiles reads the files matching a pattern like
+// ReadMultipleFilesWithOptions, but calls fn with the rows of each file in
+// file order instead of returning every row at once. At most opts.Workers
+// files (one if unset) are read or waiting for fn at a time, so a glob
+// larger than memory can be processed as long as each file fits.
+//
+// Reading stops at the first error, including one returned by fn. InferTypes
+// needs every row before converting any, so it can't be used.
+func StreamMultipleFiles(pattern string, opts ReadOptions, fn func(file string, rows []map[string]interface{}) error) error {
+	if opts.InferTypes {
+		return errors.New("InferTypes can't be used when streaming files")
+	}
+
+	if !isGlobPattern(pattern) {
+		rows, err := ReadMultipleFilesWithOptions(pattern, opts)
+		if err != nil {
+			return err
+		}
+		return fn(pattern, rows)
+	}
+
+	opts = opts.withDefaults()
+	matches, err := ExpandPattern(pattern)
+	if err != nil {
+		return err
+	}
+	if opts.OnFiles != nil {
+		opts.OnFiles(pattern, matches)
+	}
+
+	return readFiles(matches, opts, max(opts.Workers, 1), fn)
+}
+
+// readAllParallel reads files with opts.Workers workers and returns all of
+// their rows in file order
+func readAllParallel(files []string, opts ReadOptions) ([]map[string]interface{}, error) {
+	var allRows []map[string]interface{}
+	err := readFiles(files, opts, opts.Workers, func(_ string, rows []map[string]interface{}) error {
+		allRows = append(allRows, rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Infer across all files so a column gets the same type in every row
+	if opts.InferTypes {
+		inferTypes(allRows)
+	}
+
+	return allRows, nil
+}
+
+// fileResult is the outcome of reading one file
+type fileResult struct {
+	rows []map[string]interface{}
+	err  error
+}
+
+// readFiles reads files with up to workers of them at a time and calls emit
+// with the rows of each, tagged with _file, in file order.
+//
+// A file's slot is only freed once its rows are emitted, so a slow file
+// holds back new reads rather than letting finished files pile up. This
+// bounds memory to the rows of workers files.
+func readFiles(files []string, opts ReadOptions, workers int, emit func(file string, rows []map[string]interface{}) error) error {
+	results := make([]chan fileResult, len(files))
+	for i := range results {
+		results[i] = make(chan fileResult, 1)
+	}
+	slots := make(chan struct{}, workers)
+	stop := make(chan struct{})
+	progress := &parallelProgress{report: opts.OnProgress, filesTotal: len(files)}
+	var emitted atomic.Int64
+
+	// Wait for reads in flight before returning so no callback runs after
+	var wg sync.WaitGroup
+	limitReached := false
+	defer func() {
+		close(stop)
+		wg.Wait()
+
+		// Skipped files count as done so progress reaches the total
+		if limitReached {
+			progress.finish()
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, file := range files {
+			select {
+			case slots <- struct{}{}:
+			case <-stop:
+				return
+			}
+
+			// Rows emitted so far bound how many this file can still add.
+			// Once the limit is reached the consumer stops waiting for files.
+			limit := int64(0)
+			if opts.MaxRows > 0 {
+				limit = opts.MaxRows - emitted.Load()
+				if limit <= 0 {
+					return
+				}
+			}
+
+			// rand.Rand isn't safe for concurrent use, so each file samples
+			// with its own source. Seeds are drawn in file order to keep
+			// seeded samples reproducible.
+			fileOpts := opts
+			if opts.sampling() {
+				fileOpts.Rand = rand.New(rand.NewSource(opts.Rand.Int63()))
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fp := &fileProgress{progress: progress}
+				rows, err := readFile(file, fileOpts, limit, fp.onRow())
+				if err == nil {
+					fp.done(len(rows))
+				}
+				results[i] <- fileResult{rows: rows, err: err}
+			}()
+		}
+	}()
+
+	var total int64
+	for i, file := range files {
+		result := <-results[i]
+		<-slots
+		if result.err != nil {
+			return result.err
+		}
+
+		rows := result.rows
+		if opts.MaxRows > 0 && total+int64(len(rows)) > opts.MaxRows {
+			rows = rows[:opts.MaxRows-total]
+		}
+		total += int64(len(rows))
+		emitted.Store(total)
+
+		if err := emit(file, rows); err != nil {
+			return err
+		}
+
+		if opts.MaxRows > 0 && total >= opts.MaxRows {
+			limitReached = true
+			break
+		}
+	}
+
+	return nil
+}
+
+// parallelProgress serializes OnProgress calls from files read at the same
+// time, reporting the rows read across all of them
+type parallelProgress struct {
+	mu         sync.Mutex
+	report     func(filesDone, filesTotal int, rowsRead int64)
+	filesTotal int
+	filesDone  int
+	rowsRead   int64
+}
+
+// add records finished files and newly read rows and reports the totals
+func (p *parallelProgress) add(files int, rows int64) {
+	if p.report == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.filesDone += files
+	p.rowsRead += rows
+	p.report(p.filesDone, p.filesTotal, p.rowsRead)
+}
+
+// finish reports every file as done, for when the remaining files are
+// skipped. It must not be called while files are still being read.
+func (p *parallelProgress) finish() {
+	p.add(p.filesTotal-p.filesDone, 0)
+}
+
+// fileProgress tracks how many rows of one file have been added to a
+// parallelProgress
+type fileProgress struct {
+	progress *parallelProgress
+	counted  int64
+}
+
+// onRow returns the per-row callback passed to readAll, or nil if no
+// OnProgress callback is set
+func (f *fileProgress) onRow() func(rowsRead int64) {
+	if f.progress.report == nil {
+		return nil
+	}
+	return func(rowsRead int64) {
+		f.progress.add(0, rowsRead-f.counted)
+		f.counted = rowsRead
+	}
+}
+
+// done counts the file as read, adding rows not reported yet
+func (f *fileProgress) done(rows int) {
+	f.progress.add(1, int64(rows)-f.counted)
+}
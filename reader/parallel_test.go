@@ -0,0 +1,184 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// writeManyFiles writes numFiles files named part-NNNN.parquet to a new
+// directory, file i holding rowsPerFile+i%3 rows, and returns a glob
+// matching them
+func writeManyFiles(tb testing.TB, numFiles, rowsPerFile int) string {
+	tb.Helper()
+
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	dir := tb.TempDir()
+	for i := 0; i < numFiles; i++ {
+		rows := make([]Row, rowsPerFile+i%3)
+		for j := range rows {
+			rows[j] = Row{ID: int64(i*1000 + j), Name: fmt.Sprintf("file%d-row%d", i, j)}
+		}
+		if err := parquet.WriteFile(filepath.Join(dir, fmt.Sprintf("part-%04d.parquet", i)), rows); err != nil {
+			tb.Fatalf("failed to write test file %d: %v", i, err)
+		}
+	}
+	return filepath.Join(dir, "*.parquet")
+}
+
+func TestReadMultipleFilesParallel(t *testing.T) {
+	pattern := writeManyFiles(t, 20, 50)
+
+	want, err := ReadMultipleFiles(pattern)
+	if err != nil {
+		t.Fatalf("ReadMultipleFiles() error = %v", err)
+	}
+
+	for _, workers := range []int{0, 1, 4, 50} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			got, err := ReadMultipleFilesParallel(pattern, workers)
+			if err != nil {
+				t.Fatalf("ReadMultipleFilesParallel() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadMultipleFilesParallel() rows differ from ReadMultipleFiles (%d and %d rows)", len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestReadMultipleFilesParallel_ErrorNamesFile(t *testing.T) {
+	pattern := writeManyFiles(t, 10, 20)
+	corrupt := filepath.Join(filepath.Dir(pattern), "part-0005.parquet")
+	if err := os.WriteFile(corrupt, []byte("not a parquet file"), 0644); err != nil {
+		t.Fatalf("failed to write corrupt file: %v", err)
+	}
+
+	_, err := ReadMultipleFilesParallel(pattern, 4)
+	if err == nil {
+		t.Fatal("ReadMultipleFilesParallel() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), corrupt) {
+		t.Errorf("error %q doesn't name %s", err, corrupt)
+	}
+}
+
+func TestReadMultipleFilesWithOptions_WorkersMaxRows(t *testing.T) {
+	pattern := writeManyFiles(t, 10, 30)
+
+	var mu sync.Mutex
+	var lastDone, lastTotal int
+	result, err := ReadMultipleFilesWithOptions(pattern, ReadOptions{
+		MaxRows: 75,
+		Workers: 4,
+		OnProgress: func(filesDone, filesTotal int, rowsRead int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			if filesDone < lastDone {
+				t.Errorf("filesDone went from %d to %d", lastDone, filesDone)
+			}
+			lastDone, lastTotal = filesDone, filesTotal
+		},
+	})
+	if err != nil {
+		t.Fatalf("ReadMultipleFilesWithOptions() error = %v", err)
+	}
+
+	// Files hold 30, 31 and 32 rows, so the limit falls in the third file
+	if len(result) != 75 {
+		t.Fatalf("got %d rows, want 75", len(result))
+	}
+	if want := filepath.Join(filepath.Dir(pattern), "part-0002.parquet"); result[74]["_file"] != want {
+		t.Errorf("last row from %v, want %s", result[74]["_file"], want)
+	}
+	if lastDone != 10 || lastTotal != 10 {
+		t.Errorf("final progress = %d/%d files, want 10/10", lastDone, lastTotal)
+	}
+}
+
+func TestStreamMultipleFiles(t *testing.T) {
+	pattern := writeManyFiles(t, 12, 10)
+	dir := filepath.Dir(pattern)
+
+	t.Run("files arrive in order", func(t *testing.T) {
+		var files []string
+		err := StreamMultipleFiles(pattern, ReadOptions{Workers: 3}, func(file string, rows []map[string]interface{}) error {
+			files = append(files, file)
+			for _, row := range rows {
+				if row["_file"] != file {
+					t.Fatalf("row from %v passed with %s", row["_file"], file)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamMultipleFiles() error = %v", err)
+		}
+		if len(files) != 12 {
+			t.Fatalf("got %d files, want 12", len(files))
+		}
+		for i, file := range files {
+			if want := filepath.Join(dir, fmt.Sprintf("part-%04d.parquet", i)); file != want {
+				t.Errorf("file %d = %s, want %s", i, file, want)
+			}
+		}
+	})
+
+	t.Run("callback error stops reading", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		calls := 0
+		err := StreamMultipleFiles(pattern, ReadOptions{Workers: 3}, func(string, []map[string]interface{}) error {
+			calls++
+			if calls == 2 {
+				return stopErr
+			}
+			return nil
+		})
+		if !errors.Is(err, stopErr) {
+			t.Errorf("StreamMultipleFiles() error = %v, want %v", err, stopErr)
+		}
+		if calls != 2 {
+			t.Errorf("callback called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("infer types is rejected", func(t *testing.T) {
+		err := StreamMultipleFiles(pattern, ReadOptions{InferTypes: true}, func(string, []map[string]interface{}) error {
+			return nil
+		})
+		if err == nil {
+			t.Error("StreamMultipleFiles() with InferTypes: expected error, got nil")
+		}
+	})
+}
+
+func BenchmarkReadMultipleFiles(b *testing.B) {
+	pattern := writeManyFiles(b, 200, 500)
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ReadMultipleFiles(pattern); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ReadMultipleFilesParallel(pattern, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
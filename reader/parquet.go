@@ -5,6 +5,7 @@
 package reader
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -15,13 +16,18 @@ import (
 	"github.com/parquet-go/parquet-go"
 )
 
+// StdinPath is the path NewReader reads from standard input.
+const StdinPath = "-"
+
 // Reader reads parquet files and returns rows as maps.
 //
 // It maintains both an OS file handle and a parquet file handle to enable
 // proper resource cleanup.
 type Reader struct {
-	file   *os.File
-	pqFile *parquet.File
+	file       *os.File      // nil unless opened from a path
+	buf        *bytes.Reader // data buffered from standard input, if any
+	pqFile     *parquet.File
+	converters map[string]valueConverter
 }
 
 // NewReader creates a new parquet reader for the specified file path.
@@ -29,6 +35,11 @@ type Reader struct {
 // The file is opened and validated as a parquet file. Returns an error if
 // the file doesn't exist or is not a valid parquet file.
 //
+// If path is StdinPath ("-"), the file is read from standard input instead.
+// Parquet needs random access to find its footer, so all of standard input
+// is buffered in memory until Close; use a path for files that don't fit.
+// Standard input can only be read once per process.
+//
 // Example:
 //
 //	reader, err := NewReader("data.parquet")
@@ -37,6 +48,10 @@ type Reader struct {
 //	}
 //	defer reader.Close()
 func NewReader(path string) (*Reader, error) {
+	if path == StdinPath {
+		return newBufferedReader(os.Stdin)
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -48,42 +63,103 @@ func NewReader(path string) (*Reader, error) {
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	pqFile, err := parquet.OpenFile(file, stat.Size())
+	r, err := NewReaderFromReaderAt(file, stat.Size())
 	if err != nil {
 		_ = file.Close()
+		return nil, err
+	}
+	r.file = file
+
+	return r, nil
+}
+
+// NewReaderFromReaderAt creates a parquet reader over the first size bytes
+// of ra, for files that are not on disk, such as in memory or in object
+// storage.
+//
+// The caller keeps ownership of ra: Close doesn't close it, and it must stay
+// readable until the Reader is closed.
+func NewReaderFromReaderAt(ra io.ReaderAt, size int64) (*Reader, error) {
+	pqFile, err := parquet.OpenFile(ra, size)
+	if err != nil {
 		return nil, fmt.Errorf("failed to open parquet file: %w", err)
 	}
 
 	return &Reader{
-		file:   file,
-		pqFile: pqFile,
+		pqFile:     pqFile,
+		converters: columnConverters(pqFile.Schema()),
 	}, nil
 }
 
+// newBufferedReader reads all of in into memory and opens it as a parquet
+// file. Close releases the buffer.
+func newBufferedReader(in io.Reader) (*Reader, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standard input: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("no parquet data on standard input (it can only be read once)")
+	}
+
+	buf := bytes.NewReader(data)
+	r, err := NewReaderFromReaderAt(buf, buf.Size())
+	if err != nil {
+		return nil, err
+	}
+	r.buf = buf
+
+	return r, nil
+}
+
 // ReadAll reads all rows from the parquet file into memory.
 //
 // Each row is returned as a map where keys are column names and values are
-// the column values. The entire file is loaded into memory, so this method
-// may not be suitable for very large files.
+// the column values. The entire file is loaded into memory, so use Rows to
+// process very large files one row at a time.
+//
+// Integers are returned as int64 whatever their parquet width (UINT_64
+// values above the int64 range as uint64), UUID columns as canonical UUID
+// strings and other fixed-length byte arrays as hex strings.
 //
-// Returns an error if any row fails to read.
+// Columns may be compressed with any codec except LZO and the deprecated
+// LZ4 (use LZ4_RAW). Returns an error naming the codec and column for those,
+// or if any row fails to read.
 func (r *Reader) ReadAll() ([]map[string]interface{}, error) {
-	rows := make([]map[string]interface{}, 0)
+	return r.readAll(ReadOptions{}, 0, nil)
+}
 
-	reader := parquet.NewReader(r.pqFile)
-	defer func() { _ = reader.Close() }()
+// ReadN reads at most n rows from the start of the file, converted like
+// those of ReadAll. Reading stops once n rows are read, so the rest of the
+// file isn't decoded. If n is 0 or less, no rows are read.
+func (r *Reader) ReadN(n int) ([]map[string]interface{}, error) {
+	if n <= 0 {
+		return []map[string]interface{}{}, nil
+	}
+	return r.readAll(ReadOptions{}, int64(n), nil)
+}
 
-	for {
-		row := make(map[string]interface{})
-		err := reader.Read(&row)
-		if err != nil {
-			// Use errors.Is for proper EOF detection
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return nil, fmt.Errorf("failed to read row: %w", err)
+// readAll reads rows from the file, keeping only sampled rows when opts
+// enables sampling and stopping after limit rows when limit is positive.
+// onRow, if set, is called with the running row count every
+// progressInterval rows.
+func (r *Reader) readAll(opts ReadOptions, limit int64, onRow func(rowsRead int64)) ([]map[string]interface{}, error) {
+	it, err := r.rows(opts, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = it.Close() }()
+
+	rows := make([]map[string]interface{}, 0)
+	for it.Next() {
+		rows = append(rows, it.Row())
+
+		if onRow != nil && len(rows)%progressInterval == 0 {
+			onRow(int64(len(rows)))
 		}
-		rows = append(rows, row)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return rows, nil
@@ -102,6 +178,9 @@ func (r *Reader) Schema() *parquet.Schema {
 // Should be called when done reading to avoid resource leaks. It is safe
 // to call Close multiple times.
 func (r *Reader) Close() error {
+	if r.buf != nil {
+		r.buf.Reset(nil)
+	}
 	if r.file != nil {
 		return r.file.Close()
 	}
@@ -124,20 +203,40 @@ func (r *Reader) Close() error {
 // Each row is tagged with a "_file" column containing the source file path.
 // Returns an error if no files match the pattern or if any file fails to read.
 func ReadMultipleFiles(pattern string) ([]map[string]interface{}, error) {
+	return ReadMultipleFilesWithOptions(pattern, ReadOptions{})
+}
+
+// ReadMultipleFilesWithOptions reads all rows from the files matching a glob
+// pattern, like ReadMultipleFiles, applying the given read options.
+func ReadMultipleFilesWithOptions(pattern string, opts ReadOptions) ([]map[string]interface{}, error) {
+	opts = opts.withDefaults()
+
 	// Check if pattern contains glob wildcards
-	if !strings.ContainsAny(pattern, "*?[]{}") {
+	if !isGlobPattern(pattern) {
 		// Not a glob pattern, read single file
+		if opts.OnFiles != nil {
+			opts.OnFiles(pattern, []string{pattern})
+		}
+
 		r, err := NewReader(pattern)
 		if err != nil {
 			return nil, err
 		}
 		defer func() { _ = r.Close() }()
 
-		rows, err := r.ReadAll()
+		rows, err := r.readAll(opts, opts.MaxRows, opts.fileProgress(0, 1, 0))
 		if err != nil {
 			return nil, err
 		}
 
+		if opts.OnProgress != nil {
+			opts.OnProgress(1, 1, int64(len(rows)))
+		}
+
+		if opts.InferTypes {
+			inferTypes(rows)
+		}
+
 		// Only tag rows with _file if reading multiple files (glob pattern)
 		// Don't add _file for single file reads to avoid changing output shape
 		// and potentially overwriting existing _file column
@@ -145,49 +244,113 @@ func ReadMultipleFiles(pattern string) ([]map[string]interface{}, error) {
 		return rows, nil
 	}
 
-	// Expand glob pattern
-	matches, err := filepath.Glob(pattern)
+	matches, err := ExpandPattern(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("invalid glob pattern: %w", err)
+		return nil, err
 	}
 
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no files match pattern: %s", pattern)
+	if opts.OnFiles != nil {
+		opts.OnFiles(pattern, matches)
 	}
 
-	// Limit number of files to prevent resource exhaustion
-	const maxFiles = 1000
-	if len(matches) > maxFiles {
-		return nil, fmt.Errorf("glob pattern matched too many files (%d), maximum is %d", len(matches), maxFiles)
+	if opts.Workers > 1 {
+		return readAllParallel(matches, opts)
 	}
 
 	// Read all matching files
 	var allRows []map[string]interface{}
-	for _, filePath := range matches {
-		r, err := NewReader(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	for i, filePath := range matches {
+		// Stop opening files once the row limit has been reached
+		limit := int64(0)
+		if opts.MaxRows > 0 {
+			limit = opts.MaxRows - int64(len(allRows))
+			if limit <= 0 {
+				// Skipped files count as done so progress reaches the total
+				if opts.OnProgress != nil {
+					opts.OnProgress(len(matches), len(matches), int64(len(allRows)))
+				}
+				break
+			}
 		}
 
-		rows, readErr := r.ReadAll()
-		closeErr := r.Close()
-
-		// Preserve the first error encountered
-		if readErr != nil {
-			return nil, fmt.Errorf("failed to read rows from %s: %w", filePath, readErr)
-		}
-		if closeErr != nil {
-			return nil, fmt.Errorf("failed to close %s: %w", filePath, closeErr)
+		rows, err := readFile(filePath, opts, limit, opts.fileProgress(i, len(matches), int64(len(allRows))))
+		if err != nil {
+			return nil, err
 		}
 
-		// Tag each row with the source file (only for multi-file reads)
-		// Always set _file column to track source file
-		for i := range rows {
-			rows[i]["_file"] = filePath
+		allRows = append(allRows, rows...)
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(i+1, len(matches), int64(len(allRows)))
 		}
+	}
 
-		allRows = append(allRows, rows...)
+	// Infer across all files so a column gets the same type in every row
+	if opts.InferTypes {
+		inferTypes(allRows)
 	}
 
 	return allRows, nil
 }
+
+// readFile reads up to limit rows of one file of a glob (all rows if limit
+// is 0), tagging each row with the file's path in the _file column
+func readFile(filePath string, opts ReadOptions, limit int64, onRow func(rowsRead int64)) ([]map[string]interface{}, error) {
+	r, err := NewReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	rows, readErr := r.readAll(opts, limit, onRow)
+	closeErr := r.Close()
+
+	// Preserve the first error encountered
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read rows from %s: %w", filePath, readErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", filePath, closeErr)
+	}
+
+	// Tag each row with the source file (only for multi-file reads)
+	for i := range rows {
+		rows[i]["_file"] = filePath
+	}
+
+	return rows, nil
+}
+
+// maxGlobFiles limits the number of files a glob pattern may match to
+// prevent resource exhaustion
+const maxGlobFiles = 1000
+
+// isGlobPattern reports whether pattern contains glob wildcards
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[]{}")
+}
+
+// ExpandPattern returns the files a path or glob pattern refers to, in the
+// order ReadMultipleFiles reads them.
+//
+// A path without wildcards is returned as-is without checking that it
+// exists. Returns an error if a glob matches no files or too many files.
+func ExpandPattern(pattern string) ([]string, error) {
+	if !isGlobPattern(pattern) {
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match pattern: %s", pattern)
+	}
+
+	if len(matches) > maxGlobFiles {
+		return nil, fmt.Errorf("glob pattern matched too many files (%d), maximum is %d", len(matches), maxGlobFiles)
+	}
+
+	return matches, nil
+}
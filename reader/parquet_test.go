@@ -0,0 +1,106 @@
+package reader
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewReaderFromReaderAt(t *testing.T) {
+	data, err := os.ReadFile(writeSortedFile(t, 100))
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+
+	r, err := NewReaderFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReaderFromReaderAt() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(rows) != 100 {
+		t.Fatalf("ReadAll() returned %d rows, want 100", len(rows))
+	}
+	if rows[42]["id"] != int64(42) {
+		t.Errorf("row 42: id = %#v, want 42", rows[42]["id"])
+	}
+
+	// Data that isn't a parquet file is reported
+	garbage := []byte("not a parquet file")
+	if _, err := NewReaderFromReaderAt(bytes.NewReader(garbage), int64(len(garbage))); err == nil {
+		t.Error("NewReaderFromReaderAt() on invalid data: expected error, got nil")
+	}
+}
+
+func TestNewBufferedReader(t *testing.T) {
+	data, err := os.ReadFile(writeSortedFile(t, 100))
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+
+	in := bytes.NewBuffer(data)
+	r, err := newBufferedReader(in)
+	if err != nil {
+		t.Fatalf("newBufferedReader() error = %v", err)
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(rows) != 100 {
+		t.Errorf("ReadAll() returned %d rows, want 100", len(rows))
+	}
+
+	// Close releases the buffered copy of the input
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if size := r.buf.Size(); size != 0 {
+		t.Errorf("buffer holds %d bytes after Close, want 0", size)
+	}
+
+	// The input has been drained, as standard input would be
+	if _, err := newBufferedReader(in); err == nil || !strings.Contains(err.Error(), "only be read once") {
+		t.Errorf("second newBufferedReader() error = %v, want an empty input error", err)
+	}
+}
+
+func TestReader_ReadN(t *testing.T) {
+	r, err := NewReader(writeSortedFile(t, 100))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 10, want: 10},
+		{n: 100, want: 100},
+		{n: 500, want: 100},
+		{n: 0, want: 0},
+		{n: -1, want: 0},
+	}
+
+	for _, tt := range tests {
+		rows, err := r.ReadN(tt.n)
+		if err != nil {
+			t.Fatalf("ReadN(%d) error = %v", tt.n, err)
+		}
+		if len(rows) != tt.want {
+			t.Errorf("ReadN(%d) returned %d rows, want %d", tt.n, len(rows), tt.want)
+		}
+		for i, row := range rows {
+			if row["id"] != int64(i) {
+				t.Fatalf("ReadN(%d) row %d: id = %#v, want %d", tt.n, i, row["id"], i)
+			}
+		}
+	}
+}
@@ -15,6 +15,13 @@ type SchemaInfo struct {
 	Required     bool   `json:"required"`
 	Optional     bool   `json:"optional"`
 	Repeated     bool   `json:"repeated"`
+
+	// Statistics aggregated over the row groups' column chunk statistics.
+	// Each is nil when a row group doesn't record it.
+	Min           interface{} `json:"min"`
+	Max           interface{} `json:"max"`
+	NullCount     *int64      `json:"null_count"`
+	DistinctCount *int64      `json:"distinct_count"` // Only known for files with a single row group
 }
 
 // ExtractSchemaInfo extracts schema information from a Parquet file.
@@ -23,6 +30,9 @@ type SchemaInfo struct {
 // name, type information, and whether the field is required/optional/repeated.
 //
 // For nested types, field names use dot notation (e.g., "address.street").
+//
+// Min, max and null counts come from the column chunk statistics in the
+// footer, so no data is read.
 func ExtractSchemaInfo(path string) ([]SchemaInfo, error) {
 	reader, err := NewReader(path)
 	if err != nil {
@@ -30,7 +40,12 @@ func ExtractSchemaInfo(path string) ([]SchemaInfo, error) {
 	}
 	defer func() { _ = reader.Close() }()
 
-	schema := reader.Schema()
+	return reader.schemaInfo(), nil
+}
+
+// schemaInfo returns the schema information of the reader's file
+func (r *Reader) schemaInfo() []SchemaInfo {
+	schema := r.Schema()
 	fields := schema.Fields()
 
 	var schemaInfos []SchemaInfo
@@ -38,7 +53,118 @@ func ExtractSchemaInfo(path string) ([]SchemaInfo, error) {
 		schemaInfos = append(schemaInfos, extractFieldInfo(field, "")...)
 	}
 
-	return schemaInfos, nil
+	// Leaf fields are listed in the same depth-first order as the columns
+	// of each row group
+	rowGroups := r.pqFile.RowGroups()
+	for i := range schemaInfos {
+		chunks := make([]parquet.ColumnChunk, 0, len(rowGroups))
+		for _, rowGroup := range rowGroups {
+			if columns := rowGroup.ColumnChunks(); i < len(columns) {
+				chunks = append(chunks, columns[i])
+			}
+		}
+		if len(chunks) == len(rowGroups) {
+			addColumnStats(&schemaInfos[i], chunks, r.converters[schemaInfos[i].Name])
+		}
+	}
+
+	return schemaInfos
+}
+
+// addColumnStats fills in the statistics of info from the chunks of its
+// column in every row group. convert is the column's converter, if any.
+func addColumnStats(info *SchemaInfo, chunks []parquet.ColumnChunk, convert valueConverter) {
+	if len(chunks) == 0 {
+		return
+	}
+
+	var min, max parquet.Value
+	var nullCount int64
+	boundsKnown, nullsKnown := true, true
+	for _, chunk := range chunks {
+		fileChunk, ok := chunk.(*parquet.FileColumnChunk)
+		if !ok {
+			return
+		}
+		if fileChunk.NumValues() == 0 {
+			continue
+		}
+		chunkMin, chunkMax, hasBounds := fileChunk.Bounds()
+		nulls := fileChunk.NullCount()
+
+		// Writers that skip statistics leave the null count at zero too
+		if !hasBounds && nulls == 0 {
+			nullsKnown = false
+		}
+		nullCount += nulls
+
+		switch {
+		case hasBounds:
+			compare := fileChunk.Type().Compare
+			if min.IsNull() || compare(chunkMin, min) < 0 {
+				min = chunkMin
+			}
+			if max.IsNull() || compare(chunkMax, max) > 0 {
+				max = chunkMax
+			}
+		case nulls != fileChunk.NumValues():
+			boundsKnown = false
+		}
+	}
+
+	if boundsKnown {
+		info.Min = statValue(min, convert)
+		info.Max = statValue(max, convert)
+	}
+	if nullsKnown {
+		info.NullCount = &nullCount
+	}
+
+	// Distinct counts can't be added up across row groups
+	if len(chunks) == 1 {
+		fileChunk := chunks[0].(*parquet.FileColumnChunk)
+		stats := fileChunk.File().Metadata().RowGroups[0].Columns[fileChunk.Column()].MetaData.Statistics
+		if stats.DistinctCount > 0 {
+			distinct := stats.DistinctCount
+			info.DistinctCount = &distinct
+		}
+	}
+}
+
+// statValue converts a column chunk statistic to the Go value the reader
+// returns for the column, or nil if it is null or has no such value
+func statValue(v parquet.Value, convert valueConverter) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+
+	var value interface{}
+	switch v.Kind() {
+	case parquet.Boolean:
+		value = v.Boolean()
+	case parquet.Int32:
+		value = v.Int32()
+	case parquet.Int64:
+		value = v.Int64()
+	case parquet.Float:
+		value = v.Float()
+	case parquet.Double:
+		value = v.Double()
+	case parquet.ByteArray:
+		return string(v.ByteArray())
+	case parquet.FixedLenByteArray:
+		value = v.ByteArray()
+		if convert == nil {
+			convert = bytesToHex
+		}
+	default:
+		return nil
+	}
+
+	if convert != nil {
+		return convert(value)
+	}
+	return widenInts(value)
 }
 
 // extractFieldInfo recursively extracts schema information from a field.
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
 )
 
 func TestExtractSchemaInfo_PrimitiveTypes(t *testing.T) {
@@ -1234,3 +1236,99 @@ func TestGetUserFriendlyType(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSchemaInfo_Statistics(t *testing.T) {
+	type Row struct {
+		ID        int64     `parquet:"id"`
+		Name      string    `parquet:"name"`
+		Score     *float64  `parquet:"score,optional"`
+		Missing   *int32    `parquet:"missing,optional"`
+		CreatedAt time.Time `parquet:"created_at,timestamp(millisecond)"`
+	}
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{
+			ID:        int64(10 - i), // 10 down to 1, so each row group has its own bounds
+			Name:      string(rune('a' + i)),
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+		}
+		if i%3 != 0 {
+			score := float64(i) / 2
+			rows[i].Score = &score
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "stats.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	writer := parquet.NewGenericWriter[Row](f, parquet.MaxRowsPerRowGroup(4))
+	for start := 0; start < len(rows); start += 4 {
+		if _, err := writer.Write(rows[start:min(start+4, len(rows))]); err != nil {
+			t.Fatalf("failed to write test data: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	infos, err := ExtractSchemaInfo(path)
+	if err != nil {
+		t.Fatalf("ExtractSchemaInfo() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		min, max  interface{}
+		nullCount int64
+	}{
+		{name: "id", min: int64(1), max: int64(10), nullCount: 0},
+		{name: "name", min: "a", max: "j", nullCount: 0},
+		{name: "score", min: 0.5, max: 4.0, nullCount: 4},
+		{name: "missing", min: nil, max: nil, nullCount: 10},
+		{name: "created_at", min: base, max: base.Add(9 * time.Hour), nullCount: 0},
+	}
+	if len(infos) != len(tests) {
+		t.Fatalf("got %d fields, want %d", len(infos), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := infos[i]
+			if info.Name != tt.name {
+				t.Fatalf("field %d is %q, want %q", i, info.Name, tt.name)
+			}
+			if info.Min != tt.min {
+				t.Errorf("Min = %#v, want %#v", info.Min, tt.min)
+			}
+			if info.Max != tt.max {
+				t.Errorf("Max = %#v, want %#v", info.Max, tt.max)
+			}
+			if info.NullCount == nil || *info.NullCount != tt.nullCount {
+				t.Errorf("NullCount = %v, want %d", info.NullCount, tt.nullCount)
+			}
+		})
+	}
+
+	// A file whose chunks have no statistics leaves the fields nil
+	r, err := NewReader(path)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	for i := range r.pqFile.Metadata().RowGroups {
+		for j := range r.pqFile.Metadata().RowGroups[i].Columns {
+			r.pqFile.Metadata().RowGroups[i].Columns[j].MetaData.Statistics = format.Statistics{}
+		}
+	}
+	for _, info := range r.schemaInfo() {
+		if info.Min != nil || info.Max != nil || info.NullCount != nil {
+			t.Errorf("%s: Min = %#v, Max = %#v, NullCount = %v, want nil without statistics", info.Name, info.Min, info.Max, info.NullCount)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package writer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+
+	"github.com/vegasq/parcat/reader"
+)
+
+// CompactOptions configures how Compact writes its output file.
+type CompactOptions struct {
+	// RowGroupSize is the maximum number of rows per output row group, or 0
+	// for the parquet-go default
+	RowGroupSize int64
+	// Compression names the codec used for every column: snappy, gzip, zstd,
+	// lz4, brotli or none. Empty means snappy.
+	Compression string
+}
+
+// compressionCodecs maps the names accepted by CompactOptions.Compression to codecs
+var compressionCodecs = map[string]compress.Codec{
+	"none":         &parquet.Uncompressed,
+	"uncompressed": &parquet.Uncompressed,
+	"snappy":       &parquet.Snappy,
+	"gzip":         &parquet.Gzip,
+	"zstd":         &parquet.Zstd,
+	"lz4":          &parquet.Lz4Raw,
+	"brotli":       &parquet.Brotli,
+}
+
+// CompressionNames returns the codec names accepted by CompactOptions.Compression, sorted.
+func CompressionNames() []string {
+	names := make([]string, 0, len(compressionCodecs))
+	for name := range compressionCodecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compact merges the parquet files matched by pattern (a path or glob) into
+// a single file at outPath and returns the number of rows written.
+//
+// The output schema is the union of the top-level columns of every input.
+// Rows of a file lacking a column get nulls for it, so such columns are
+// written as optional. A column that has different types in two files, or
+// is repeated in one and not in another, is an error. Rows are copied a row
+// group at a time rather than loaded into memory.
+//
+// Columns are written in the order they first appear in the inputs. The
+// output is written to a temporary file next to outPath and renamed into
+// place, so outPath is left untouched if compaction fails. It gets the
+// permissions of the file it replaces, or 0644 for a new file.
+func Compact(pattern, outPath string, opts CompactOptions) (int64, error) {
+	codec, err := lookupCodec(opts.Compression)
+	if err != nil {
+		return 0, err
+	}
+	if opts.RowGroupSize < 0 {
+		return 0, fmt.Errorf("row group size must be non-negative, got %d", opts.RowGroupSize)
+	}
+
+	paths, err := reader.ExpandPattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+	for _, path := range paths {
+		if sameFile(path, outPath) {
+			return 0, fmt.Errorf("output file %s is also an input", outPath)
+		}
+	}
+
+	inputs := make([]*inputFile, 0, len(paths))
+	defer func() {
+		for _, in := range inputs {
+			_ = in.file.Close()
+		}
+	}()
+	for _, path := range paths {
+		in, err := openInput(path)
+		if err != nil {
+			return 0, err
+		}
+		inputs = append(inputs, in)
+	}
+
+	schema, err := mergeSchemas(inputs, codec)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(outPath), ".parcat-compact-*.parquet")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create output file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		// Only still present if compaction failed before the rename
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	options := []parquet.WriterOption{schema, parquet.Compression(codec)}
+	if opts.RowGroupSize > 0 {
+		options = append(options, parquet.MaxRowsPerRowGroup(opts.RowGroupSize))
+	}
+	w := parquet.NewWriter(tmp, options...)
+
+	var written int64
+	for _, in := range inputs {
+		n, err := copyFile(w, schema, in)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return written, fmt.Errorf("failed to finish output file: %w", err)
+	}
+	// CreateTemp makes the file readable by its owner only
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(outPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return written, fmt.Errorf("failed to set output file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return written, fmt.Errorf("failed to close output file: %w", err)
+	}
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		return written, fmt.Errorf("failed to write output file: %w", err)
+	}
+	return written, nil
+}
+
+// inputFile is an open input of Compact
+type inputFile struct {
+	path   string
+	file   *os.File
+	pqFile *parquet.File
+}
+
+func openInput(path string) (*inputFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+
+	pqFile, err := parquet.OpenFile(file, stat.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to open parquet file %s: %w", path, err)
+	}
+
+	return &inputFile{path: path, file: file, pqFile: pqFile}, nil
+}
+
+// copyFile appends every row of in to w, converted to schema
+func copyFile(w *parquet.Writer, schema *parquet.Schema, in *inputFile) (int64, error) {
+	conv, err := parquet.Convert(schema, in.pqFile.Schema())
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s to the merged schema: %w", in.path, err)
+	}
+
+	var written int64
+	for _, rowGroup := range in.pqFile.RowGroups() {
+		rows := parquet.ConvertRowGroup(rowGroup, conv).Rows()
+		n, err := parquet.CopyRows(w, rows)
+		_ = rows.Close()
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("failed to copy rows from %s: %w", in.path, err)
+		}
+	}
+	return written, nil
+}
+
+// mergedColumn tracks a top-level column while the input schemas are merged
+type mergedColumn struct {
+	node     parquet.Field
+	path     string // first file the column was seen in
+	required bool   // required in every file seen so far
+	files    int    // number of files that have the column
+}
+
+// mergeSchemas returns the union of the top-level columns of the inputs, in
+// the order they first appear, compressed with codec. A column stays
+// required only if every file has it as required.
+func mergeSchemas(inputs []*inputFile, codec compress.Codec) (*parquet.Schema, error) {
+	columns := make(map[string]*mergedColumn)
+	var order []*mergedColumn
+	for _, in := range inputs {
+		for _, field := range in.pqFile.Schema().Fields() {
+			col, ok := columns[field.Name()]
+			if !ok {
+				col = &mergedColumn{node: field, path: in.path, required: field.Required(), files: 1}
+				columns[field.Name()] = col
+				order = append(order, col)
+				continue
+			}
+			if !sameColumnType(col.node, field) {
+				return nil, fmt.Errorf("column %s has type %s in %s but %s in %s",
+					field.Name(), describeNode(col.node), col.path, describeNode(field), in.path)
+			}
+			col.required = col.required && field.Required()
+			col.files++
+		}
+	}
+
+	group := orderedGroup{Group: make(parquet.Group, len(order))}
+	for _, col := range order {
+		// Columns of an input file carry the codec they were written with
+		var node parquet.Node = recompressedField{Field: col.node, codec: codec}
+		switch {
+		case col.node.Repeated():
+		case col.required && col.files == len(inputs):
+			node = parquet.Required(node)
+		default:
+			node = parquet.Optional(node)
+		}
+		group.Group[col.node.Name()] = node
+		group.fields = append(group.fields, namedField{Node: node, name: col.node.Name()})
+	}
+	return parquet.NewSchema("schema", group), nil
+}
+
+// orderedGroup is a parquet.Group whose fields keep the order they were
+// added in, where parquet.Group sorts them by name
+type orderedGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g orderedGroup) Fields() []parquet.Field { return g.fields }
+
+// namedField is a field of an orderedGroup
+type namedField struct {
+	parquet.Node
+	name string
+}
+
+func (f namedField) Name() string { return f.name }
+
+// Value looks the field up in a map, as the fields of a parquet.Group do
+func (f namedField) Value(base reflect.Value) reflect.Value {
+	if base.Kind() == reflect.Interface {
+		if base.IsNil() {
+			return reflect.ValueOf(nil)
+		}
+		if base = base.Elem(); base.Kind() == reflect.Pointer && base.IsNil() {
+			return reflect.ValueOf(nil)
+		}
+	}
+	return base.MapIndex(reflect.ValueOf(f.name))
+}
+
+// recompressedField replaces the codec of every leaf column under a field
+type recompressedField struct {
+	parquet.Field
+	codec compress.Codec
+}
+
+func (f recompressedField) Compression() compress.Codec {
+	if f.Leaf() {
+		return f.codec
+	}
+	return nil
+}
+
+func (f recompressedField) Fields() []parquet.Field {
+	fields := f.Field.Fields()
+	wrapped := make([]parquet.Field, len(fields))
+	for i, field := range fields {
+		wrapped[i] = recompressedField{Field: field, codec: f.codec}
+	}
+	return wrapped
+}
+
+// sameColumnType reports whether two columns hold the same type of values,
+// ignoring whether they are optional or required
+func sameColumnType(a, b parquet.Node) bool {
+	if a.Repeated() != b.Repeated() || a.Leaf() != b.Leaf() {
+		return false
+	}
+	if a.Leaf() {
+		return parquet.EqualTypes(a.Type(), b.Type())
+	}
+	if a.Repeated() {
+		return parquet.EqualNodes(a, b)
+	}
+	return parquet.EqualNodes(parquet.Required(a), parquet.Required(b))
+}
+
+// describeNode names a column type for error messages, e.g. "INT64" or
+// "repeated BYTE_ARRAY (STRING)"
+func describeNode(node parquet.Node) string {
+	desc := "group"
+	if node.Leaf() {
+		desc = node.Type().Kind().String()
+		if logicalType := node.Type().LogicalType(); logicalType != nil {
+			desc += " (" + logicalType.String() + ")"
+		}
+	}
+	if node.Repeated() {
+		desc = "repeated " + desc
+	}
+	return desc
+}
+
+// lookupCodec returns the codec for a CompactOptions.Compression name
+func lookupCodec(name string) (compress.Codec, error) {
+	if name == "" {
+		return &parquet.Snappy, nil
+	}
+	codec, ok := compressionCodecs[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression %q (valid: %s)", name, strings.Join(CompressionNames(), ", "))
+	}
+	return codec, nil
+}
+
+// sameFile reports whether two paths name the same existing file
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,205 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/vegasq/parcat/reader"
+)
+
+// writeFile writes rows to dir/name as a parquet file
+func writeFile[T any](t *testing.T, dir, name string, rows []T) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	w := parquet.NewGenericWriter[T](f)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+	return path
+}
+
+type userRow struct {
+	ID   int64  `parquet:"id"`
+	Name string `parquet:"name"`
+}
+
+type userWithEmailRow struct {
+	ID    int64    `parquet:"id"`
+	Email string   `parquet:"email"`
+	Tags  []string `parquet:"tags,list"`
+}
+
+type userStringIDRow struct {
+	ID string `parquet:"id"`
+}
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "1.parquet", []userRow{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}})
+	writeFile(t, dir, "2.parquet", []userWithEmailRow{{ID: 3, Email: "carol@example.com", Tags: []string{"a", "b"}}})
+
+	out := filepath.Join(dir, "merged.parquet")
+	n, err := Compact(filepath.Join(dir, "*.parquet"), out, CompactOptions{RowGroupSize: 2, Compression: "zstd"})
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Compact() wrote %d rows, want 3", n)
+	}
+
+	r, err := reader.NewReader(out)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"id": int64(1), "name": "alice", "email": nil, "tags": nil},
+		{"id": int64(2), "name": "bob", "email": nil, "tags": nil},
+		{"id": int64(3), "name": nil, "email": "carol@example.com", "tags": []interface{}{"a", "b"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("merged rows = %v, want %v", rows, want)
+	}
+
+	schema := r.Schema()
+	var names []string
+	for _, field := range schema.Fields() {
+		names = append(names, field.Name())
+	}
+	if want := []string{"id", "name", "email", "tags"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want input order %v", names, want)
+	}
+	if field, _ := schema.Lookup("id"); !field.Node.Required() {
+		t.Errorf("id should stay required, it is in every input")
+	}
+	if field, _ := schema.Lookup("name"); !field.Node.Optional() {
+		t.Errorf("name should be optional, it is missing from an input")
+	}
+}
+
+func TestCompact_Options(t *testing.T) {
+	dir := t.TempDir()
+	rows := make([]userRow, 10)
+	for i := range rows {
+		rows[i] = userRow{ID: int64(i)}
+	}
+	writeFile(t, dir, "1.parquet", rows[:5])
+	writeFile(t, dir, "2.parquet", rows[5:])
+
+	out := filepath.Join(dir, "merged.parquet")
+	if _, err := Compact(filepath.Join(dir, "*.parquet"), out, CompactOptions{RowGroupSize: 4, Compression: "gzip"}); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	stat, _ := f.Stat()
+	if perm := stat.Mode().Perm(); perm != 0o644 {
+		t.Errorf("output permissions = %v, want -rw-r--r--", perm)
+	}
+	pqFile, err := parquet.OpenFile(f, stat.Size())
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+
+	// Row groups span input files
+	var sizes []int64
+	for _, rg := range pqFile.RowGroups() {
+		sizes = append(sizes, rg.NumRows())
+	}
+	if !reflect.DeepEqual(sizes, []int64{4, 4, 2}) {
+		t.Errorf("row group sizes = %v, want [4 4 2]", sizes)
+	}
+	for _, rg := range pqFile.Metadata().RowGroups {
+		for _, col := range rg.Columns {
+			if codec := col.MetaData.Codec.String(); codec != "GZIP" {
+				t.Errorf("column %v compressed with %s, want GZIP", col.MetaData.PathInSchema, codec)
+			}
+		}
+	}
+}
+
+func TestCompact_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(dir string) (pattern, out string)
+		opts    CompactOptions
+		wantErr string
+	}{
+		{
+			name: "type conflict",
+			setup: func(dir string) (string, string) {
+				writeFile(t, dir, "1.parquet", []userRow{{ID: 1}})
+				writeFile(t, dir, "2.parquet", []userStringIDRow{{ID: "x"}})
+				return filepath.Join(dir, "*.parquet"), filepath.Join(dir, "out.parquet")
+			},
+			wantErr: "column id has type INT64",
+		},
+		{
+			name: "unknown compression",
+			setup: func(dir string) (string, string) {
+				return filepath.Join(dir, "1.parquet"), filepath.Join(dir, "out.parquet")
+			},
+			opts:    CompactOptions{Compression: "lzo"},
+			wantErr: `unknown compression "lzo"`,
+		},
+		{
+			name: "output is an input",
+			setup: func(dir string) (string, string) {
+				path := writeFile(t, dir, "1.parquet", []userRow{{ID: 1}})
+				return filepath.Join(dir, "*.parquet"), path
+			},
+			wantErr: "is also an input",
+		},
+		{
+			name: "no matching files",
+			setup: func(dir string) (string, string) {
+				return filepath.Join(dir, "*.parquet"), filepath.Join(dir, "out.parquet")
+			},
+			wantErr: "no files match pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pattern, out := tt.setup(dir)
+			_, err := Compact(pattern, out, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Compact() error = %v, want containing %q", err, tt.wantErr)
+			}
+
+			// A failed compaction leaves no output or temporary files behind
+			entries, _ := os.ReadDir(dir)
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".parcat-compact-") || (e.Name() == "out.parquet") {
+					t.Errorf("unexpected file %s left after failure", e.Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Package writer writes Apache Parquet files.
+//
+// Compact merges several parquet files, given as a path or glob pattern,
+// into one file whose schema is the union of the inputs' columns:
+//
+//	n, err := writer.Compact("shards/*.parquet", "merged.parquet", writer.CompactOptions{
+//	    RowGroupSize: 100000,
+//	    Compression:  "zstd",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("wrote %d rows\n", n)
+//
+// Columns missing from some inputs are written as optional and filled with
+// nulls. A column whose type differs between inputs is an error.
+package writer